@@ -14,7 +14,7 @@ require (
 	github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider v1.15.5
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.15.6
 	github.com/devpies/saas-core/pkg/log v0.0.0-20231024014211-fe5037179b8a
-	github.com/devpies/saas-core/pkg/msg v0.0.0-20231124084544-6cc96da58539
+	github.com/devpies/saas-core/pkg/msg v0.0.0-00010101000000-000000000000
 	github.com/devpies/saas-core/pkg/web v0.0.0-20231124084544-6cc96da58539
 	github.com/go-chi/chi/v5 v5.0.10
 	github.com/go-chi/cors v1.2.1
@@ -79,3 +79,7 @@ require (
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// pkg/msg carries the board events published by the project service, which are not
+// released yet, so it is built from the local copy.
+replace github.com/devpies/saas-core/pkg/msg => ./pkg/msg
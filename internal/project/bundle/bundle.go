@@ -0,0 +1,252 @@
+// Package bundle defines the portable format projects are exported to and imported from.
+//
+// A bundle is a versioned JSON document holding a project board with its columns,
+// labels, tasks and comments. Tasks refer to columns by position and to labels by
+// name. People are identified by a handle, an email or username at the source or a
+// user id in bundles exported by the project service, which the import maps to users
+// of the tenant. Custom fields are exported with their task values keyed by field name,
+// but are not imported. Trello and Jira exports are converted into bundles before import.
+package bundle
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Version is the version of the bundle format written by this package.
+const Version = 1
+
+// Limits of the board a bundle describes, matching what the project service stores.
+const (
+	MaxProjectName = 22
+	MaxColumns     = 10
+	MaxTasks       = 5000
+	MaxTitle       = 48
+	MaxColumnName  = 24
+	MaxLabelName   = 30
+)
+
+// priorities are the task priorities of the project service.
+var priorities = map[string]bool{"": true, "none": true, "low": true, "medium": true, "high": true, "urgent": true}
+
+var (
+	// ErrVersion is returned for bundles written by an unsupported version of the format.
+	ErrVersion = errors.New("bundle: unsupported version")
+	// ErrInvalid is returned for bundles describing a board the project service cannot hold.
+	ErrInvalid = errors.New("bundle: invalid board")
+)
+
+// Bundle is a project board in portable form.
+type Bundle struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Project    Project   `json:"project"`
+	Columns    []Column  `json:"columns"`
+	Labels     []Label   `json:"labels"`
+	Fields     []Field   `json:"fields,omitempty"`
+	Tasks      []Task    `json:"tasks"`
+}
+
+// Project is the project of a bundle.
+type Project struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Column is a board column. Columns are listed in board order.
+type Column struct {
+	Title    string `json:"title"`
+	WIPLimit *int   `json:"wipLimit,omitempty"`
+}
+
+// Label is a project label with a #rrggbb color.
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Field is a custom field of the project. Select and multi-select fields list their
+// options.
+type Field struct {
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Options []string `json:"options,omitempty"`
+}
+
+// Task is a task of the board. Column is the position of its column, Labels are label
+// names and Fields maps custom field names to their JSON values. Key is the key the task
+// had at its source and is not kept on import.
+type Task struct {
+	Key       string                     `json:"key"`
+	Title     string                     `json:"title"`
+	Content   string                     `json:"content"`
+	Column    int                        `json:"column"`
+	Priority  string                     `json:"priority"`
+	Points    int                        `json:"points"`
+	Assignee  string                     `json:"assignee"`
+	Reporter  string                     `json:"reporter"`
+	Labels    []string                   `json:"labels"`
+	Fields    map[string]json.RawMessage `json:"fields,omitempty"`
+	StartAt   *time.Time                 `json:"startAt"`
+	DueAt     *time.Time                 `json:"dueAt"`
+	CreatedAt time.Time                  `json:"createdAt"`
+	Comments  []Comment                  `json:"comments"`
+}
+
+// Comment is a comment on a task.
+type Comment struct {
+	Author    string    `json:"author"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Decode reads a JSON bundle and checks it.
+func Decode(r io.Reader) (Bundle, error) {
+	var b Bundle
+
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return b, fmt.Errorf("bundle: decoding: %w", err)
+	}
+	if b.Version < 1 || b.Version > Version {
+		return b, ErrVersion
+	}
+
+	return b, b.Check()
+}
+
+// Check checks the board of a bundle can be imported: it has between one and MaxColumns
+// columns, label names are unique, and every task refers to one of the columns and to
+// labels of the bundle.
+func (b Bundle) Check() error {
+	if len(b.Columns) == 0 || len(b.Columns) > MaxColumns || len(b.Tasks) > MaxTasks {
+		return ErrInvalid
+	}
+	if strings.TrimSpace(b.Project.Name) == "" {
+		return ErrInvalid
+	}
+
+	labels := make(map[string]bool, len(b.Labels))
+	for _, l := range b.Labels {
+		name := strings.ToLower(l.Name)
+		if name == "" || labels[name] {
+			return ErrInvalid
+		}
+		labels[name] = true
+	}
+
+	for _, t := range b.Tasks {
+		if t.Column < 0 || t.Column >= len(b.Columns) || strings.TrimSpace(t.Title) == "" || !priorities[t.Priority] {
+			return ErrInvalid
+		}
+		for _, name := range t.Labels {
+			if !labels[strings.ToLower(name)] {
+				return ErrInvalid
+			}
+		}
+	}
+
+	return nil
+}
+
+// csvHeader is the header of the task CSV written by WriteCSV.
+var csvHeader = []string{
+	"key", "title", "column", "priority", "points", "assignee", "reporter",
+	"labels", "start_at", "due_at", "created_at", "content",
+}
+
+// WriteCSV writes the tasks of a bundle as CSV, one row per task with its labels
+// separated by semicolons. Custom fields follow as field:<name> columns, with the
+// options of multi-select values separated by semicolons too. Text that a spreadsheet
+// would run as a formula is escaped.
+func (b Bundle) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := append([]string(nil), csvHeader...)
+	for _, f := range b.Fields {
+		header = append(header, "field:"+f.Name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, t := range b.Tasks {
+		column := ""
+		if t.Column >= 0 && t.Column < len(b.Columns) {
+			column = b.Columns[t.Column].Title
+		}
+
+		row := []string{
+			escapeCell(t.Key),
+			escapeCell(t.Title),
+			escapeCell(column),
+			escapeCell(t.Priority),
+			strconv.Itoa(t.Points),
+			escapeCell(t.Assignee),
+			escapeCell(t.Reporter),
+			escapeCell(strings.Join(t.Labels, ";")),
+			formatTime(t.StartAt),
+			formatTime(t.DueAt),
+			t.CreatedAt.UTC().Format(time.RFC3339),
+			escapeCell(t.Content),
+		}
+		for _, f := range b.Fields {
+			row = append(row, formatValue(t.Fields[f.Name]))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatValue formats a JSON custom field value as CSV text. Numbers are kept as they
+// are, so negative ones stay numbers.
+func formatValue(raw json.RawMessage) string {
+	var (
+		s    string
+		list []string
+	)
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return ""
+	case json.Unmarshal(raw, &s) == nil:
+		return escapeCell(s)
+	case json.Unmarshal(raw, &list) == nil:
+		return escapeCell(strings.Join(list, ";"))
+	}
+	return string(raw)
+}
+
+// escapeCell prefixes text starting like a formula with a quote, so spreadsheets opening
+// the CSV show it instead of running it.
+func escapeCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,251 @@
+package bundle_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/bundle"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBundle() bundle.Bundle {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return bundle.Bundle{
+		Version: bundle.Version,
+		Project: bundle.Project{Name: "Website"},
+		Columns: []bundle.Column{{Title: "To Do"}, {Title: "Done"}},
+		Labels:  []bundle.Label{{Name: "Bug", Color: "#eb5a46"}},
+		Tasks: []bundle.Task{
+			{
+				Key:       "WEB-1",
+				Title:     "Fix login, again",
+				Column:    1,
+				Priority:  "high",
+				Points:    3,
+				Labels:    []string{"Bug"},
+				DueAt:     &due,
+				CreatedAt: time.Date(2026, 2, 1, 9, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestBundle_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(b *bundle.Bundle)
+		err      error
+	}{
+		{
+			name:     "valid",
+			modifier: func(b *bundle.Bundle) {},
+		},
+		{
+			name: "no columns",
+			modifier: func(b *bundle.Bundle) {
+				b.Columns = nil
+			},
+			err: bundle.ErrInvalid,
+		},
+		{
+			name: "too many columns",
+			modifier: func(b *bundle.Bundle) {
+				b.Columns = make([]bundle.Column, bundle.MaxColumns+1)
+			},
+			err: bundle.ErrInvalid,
+		},
+		{
+			name: "unknown column",
+			modifier: func(b *bundle.Bundle) {
+				b.Tasks[0].Column = 2
+			},
+			err: bundle.ErrInvalid,
+		},
+		{
+			name: "unknown label",
+			modifier: func(b *bundle.Bundle) {
+				b.Tasks[0].Labels = []string{"Feature"}
+			},
+			err: bundle.ErrInvalid,
+		},
+		{
+			name: "duplicate label",
+			modifier: func(b *bundle.Bundle) {
+				b.Labels = append(b.Labels, bundle.Label{Name: "bug", Color: "#000000"})
+			},
+			err: bundle.ErrInvalid,
+		},
+		{
+			name: "unknown priority",
+			modifier: func(b *bundle.Bundle) {
+				b.Tasks[0].Priority = "asap"
+			},
+			err: bundle.ErrInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := testBundle()
+			tc.modifier(&b)
+
+			assert.Equal(t, tc.err, b.Check())
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := bundle.Decode(strings.NewReader(`{"version": 2}`))
+		assert.Equal(t, bundle.ErrVersion, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := bundle.Decode(strings.NewReader(`{"version":`))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		b, err := bundle.Decode(strings.NewReader(`{
+			"version": 1,
+			"project": {"name": "Website"},
+			"columns": [{"title": "To Do", "wipLimit": 3}],
+			"tasks": [{"title": "Write copy", "column": 0, "priority": "none"}]
+		}`))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, *b.Columns[0].WIPLimit)
+		assert.Equal(t, "Write copy", b.Tasks[0].Title)
+	})
+}
+
+func TestBundle_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := testBundle().WriteCSV(&buf)
+	assert.Nil(t, err)
+
+	expected := "key,title,column,priority,points,assignee,reporter,labels,start_at,due_at,created_at,content\n" +
+		"WEB-1,\"Fix login, again\",Done,high,3,,,Bug,,2026-03-01T00:00:00Z,2026-02-01T09:30:00Z,\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestBundle_WriteCSV_Fields(t *testing.T) {
+	var buf bytes.Buffer
+
+	b := testBundle()
+	b.Fields = []bundle.Field{{Name: "Estimate", Kind: "number"}, {Name: "Platforms", Kind: "multi_select", Options: []string{"web", "ios"}}}
+	b.Tasks[0].Fields = map[string]json.RawMessage{"Estimate": json.RawMessage(`2.5`), "Platforms": json.RawMessage(`["web","ios"]`)}
+
+	err := b.WriteCSV(&buf)
+	assert.Nil(t, err)
+
+	expected := "key,title,column,priority,points,assignee,reporter,labels,start_at,due_at,created_at,content,field:Estimate,field:Platforms\n" +
+		"WEB-1,\"Fix login, again\",Done,high,3,,,Bug,,2026-03-01T00:00:00Z,2026-02-01T09:30:00Z,,2.5,web;ios\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestBundle_WriteCSV_Formulas(t *testing.T) {
+	var buf bytes.Buffer
+
+	b := testBundle()
+	b.Fields = []bundle.Field{{Name: "Formula", Kind: "text"}, {Name: "Delta", Kind: "number"}}
+	b.Tasks[0].Title = "=HYPERLINK(\"https://evil.example\")"
+	b.Tasks[0].Content = "@SUM(A1:A2)"
+	b.Tasks[0].Assignee = "+1"
+	b.Tasks[0].Labels = []string{"-bug"}
+	b.Tasks[0].Fields = map[string]json.RawMessage{"Formula": json.RawMessage(`"=1+1"`), "Delta": json.RawMessage(`-2`)}
+
+	err := b.WriteCSV(&buf)
+	assert.Nil(t, err)
+
+	expected := "key,title,column,priority,points,assignee,reporter,labels,start_at,due_at,created_at,content,field:Formula,field:Delta\n" +
+		"WEB-1,\"'=HYPERLINK(\"\"https://evil.example\"\")\",Done,high,3,'+1,,'-bug,,2026-03-01T00:00:00Z,2026-02-01T09:30:00Z,'@SUM(A1:A2),'=1+1,-2\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestFromTrello(t *testing.T) {
+	board := `{
+		"name": "Roadmap",
+		"lists": [
+			{"id": "l2", "name": "Doing", "pos": 2},
+			{"id": "l1", "name": "Ideas", "pos": 1},
+			{"id": "l3", "name": "Old", "pos": 3, "closed": true}
+		],
+		"labels": [
+			{"id": "lb1", "name": "", "color": "red"},
+			{"id": "lb2", "name": "Design", "color": "purple_dark"}
+		],
+		"members": [{"id": "m1", "username": "ada"}],
+		"cards": [
+			{"id": "5f5b1a00aaaaaaaaaaaaaaaa", "name": "Sketch", "idList": "l2", "idLabels": ["lb2"], "idMembers": ["m1"], "pos": 1},
+			{"id": "5f5b1a00bbbbbbbbbbbbbbbb", "name": "Archived", "idList": "l1", "closed": true},
+			{"id": "5f5b1a00cccccccccccccccc", "name": "Hidden", "idList": "l3"},
+			{"id": "5f5b1a00dddddddddddddddd", "name": "Crash", "idList": "l1", "idLabels": ["lb1"], "pos": 1}
+		],
+		"actions": [
+			{"type": "commentCard", "date": "2026-01-02T00:00:00Z", "idMemberCreator": "m1", "data": {"text": "second", "card": {"id": "5f5b1a00aaaaaaaaaaaaaaaa"}}},
+			{"type": "commentCard", "date": "2026-01-01T00:00:00Z", "idMemberCreator": "m1", "data": {"text": "first", "card": {"id": "5f5b1a00aaaaaaaaaaaaaaaa"}}},
+			{"type": "createCard", "date": "2026-01-01T00:00:00Z", "idMemberCreator": "m1", "data": {"card": {"id": "5f5b1a00aaaaaaaaaaaaaaaa"}}}
+		]
+	}`
+
+	b, err := bundle.FromTrello(strings.NewReader(board))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Roadmap", b.Project.Name)
+	assert.Equal(t, []bundle.Column{{Title: "Ideas"}, {Title: "Doing"}}, b.Columns)
+	assert.Equal(t, []bundle.Label{{Name: "red", Color: "#eb5a46"}, {Name: "Design", Color: "#c377e0"}}, b.Labels)
+	require.Len(t, b.Tasks, 2)
+
+	sketch := b.Tasks[0]
+	assert.Equal(t, "Sketch", sketch.Title)
+	assert.Equal(t, 1, sketch.Column)
+	assert.Equal(t, "ada", sketch.Assignee)
+	assert.Equal(t, "ada", sketch.Reporter)
+	assert.Equal(t, []string{"Design"}, sketch.Labels)
+	assert.Equal(t, time.Unix(0x5f5b1a00, 0).UTC(), sketch.CreatedAt)
+	require.Len(t, sketch.Comments, 2)
+	assert.Equal(t, "first", sketch.Comments[0].Content)
+
+	assert.Equal(t, []string{"red"}, b.Tasks[1].Labels)
+}
+
+func TestFromJira(t *testing.T) {
+	export := "Summary,Issue key,Status,Priority,Assignee,Reporter,Created,Labels,Labels,Comment,Project name,Custom field (Story Points)\n" +
+		"Ship it,APP-2,Done,Highest,ada@example.com,bob@example.com,05/Jan/26 10:00 AM,release,,\"06/Jan/26 9:00 AM;ada@example.com;shipped\",App,2.5\n" +
+		"Plan it,APP-1,To Do,Minor,,bob@example.com,04/Jan/26 10:00 AM,release,planning,,App,\n" +
+		"Check it,APP-3,QA,,,,,,,,App,\n"
+
+	b, err := bundle.FromJira(strings.NewReader(export))
+	require.NoError(t, err)
+
+	assert.Equal(t, "App", b.Project.Name)
+	assert.Equal(t, []bundle.Column{{Title: "To Do"}, {Title: "QA"}, {Title: "Done"}}, b.Columns)
+	assert.Len(t, b.Labels, 2)
+	require.Len(t, b.Tasks, 3)
+
+	ship := b.Tasks[0]
+	assert.Equal(t, "APP-2", ship.Key)
+	assert.Equal(t, 2, ship.Column)
+	assert.Equal(t, "urgent", ship.Priority)
+	assert.Equal(t, 3, ship.Points)
+	assert.Equal(t, "ada@example.com", ship.Assignee)
+	assert.Equal(t, time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), ship.CreatedAt)
+	require.Len(t, ship.Comments, 1)
+	assert.Equal(t, bundle.Comment{Author: "ada@example.com", Content: "shipped", CreatedAt: time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC)}, ship.Comments[0])
+
+	assert.Equal(t, []string{"release", "planning"}, b.Tasks[1].Labels)
+	assert.Equal(t, "low", b.Tasks[1].Priority)
+	assert.Equal(t, 1, b.Tasks[2].Column)
+	assert.Equal(t, "none", b.Tasks[2].Priority)
+
+	t.Run("missing columns", func(t *testing.T) {
+		_, err := bundle.FromJira(strings.NewReader("Key,Title\nA-1,x\n"))
+		assert.ErrorIs(t, err, bundle.ErrInvalid)
+	})
+}
@@ -0,0 +1,212 @@
+package bundle
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jiraTime is the default date format of Jira CSV exports.
+const jiraTime = "02/Jan/06 3:04 PM"
+
+// jiraPriorities maps the default Jira priorities to task priorities.
+var jiraPriorities = map[string]string{
+	"highest":  "urgent",
+	"blocker":  "urgent",
+	"high":     "high",
+	"critical": "high",
+	"medium":   "medium",
+	"major":    "medium",
+	"low":      "low",
+	"lowest":   "low",
+	"minor":    "low",
+	"trivial":  "low",
+}
+
+// jiraStatuses orders the default Jira statuses on the board. Other statuses are
+// placed between work in progress and done, in the order they are first seen.
+var jiraStatuses = map[string]int{
+	"backlog":                  0,
+	"open":                     1,
+	"new":                      1,
+	"to do":                    1,
+	"selected for development": 2,
+	"in progress":              3,
+	"in review":                4,
+	"review":                   4,
+	"done":                     9,
+	"resolved":                 9,
+	"closed":                   9,
+}
+
+// FromJira converts a Jira CSV export into a bundle named after the exported project.
+// Statuses become columns, and repeated Labels and Comment columns are merged. People
+// are identified by the values of the Assignee and Reporter columns, which are emails
+// when the export is configured to include them.
+func FromJira(r io.Reader) (Bundle, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return Bundle{}, fmt.Errorf("bundle: reading jira header: %w", err)
+	}
+
+	fields := make(map[string][]int)
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		fields[name] = append(fields[name], i)
+	}
+	if len(fields["summary"]) == 0 || len(fields["status"]) == 0 {
+		return Bundle{}, fmt.Errorf("bundle: jira export needs Summary and Status columns: %w", ErrInvalid)
+	}
+
+	get := func(row []string, name string) string {
+		for _, i := range fields[name] {
+			if i < len(row) && strings.TrimSpace(row[i]) != "" {
+				return strings.TrimSpace(row[i])
+			}
+		}
+		return ""
+	}
+	all := func(row []string, name string) []string {
+		var vs []string
+		for _, i := range fields[name] {
+			if i < len(row) && strings.TrimSpace(row[i]) != "" {
+				vs = append(vs, strings.TrimSpace(row[i]))
+			}
+		}
+		return vs
+	}
+
+	b := Bundle{
+		Version: Version,
+		Columns: make([]Column, 0),
+		Labels:  make([]Label, 0),
+		Tasks:   make([]Task, 0),
+	}
+
+	var (
+		statuses []string
+		status   = make(map[string]bool)
+		labels   = make(map[string]bool)
+		// taskStatus holds the status of each task until the column order is known.
+		taskStatus []string
+	)
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Bundle{}, fmt.Errorf("bundle: reading jira row: %w", err)
+		}
+
+		if b.Project.Name == "" {
+			b.Project.Name = truncate(get(row, "project name"), MaxProjectName)
+		}
+
+		s := truncate(get(row, "status"), MaxColumnName)
+		if !status[strings.ToLower(s)] {
+			status[strings.ToLower(s)] = true
+			statuses = append(statuses, s)
+		}
+
+		t := Task{
+			Key:      get(row, "issue key"),
+			Title:    truncate(get(row, "summary"), MaxTitle),
+			Content:  get(row, "description"),
+			Priority: jiraPriorities[strings.ToLower(get(row, "priority"))],
+			Assignee: get(row, "assignee"),
+			Reporter: get(row, "reporter"),
+			Labels:   make([]string, 0),
+			StartAt:  jiraDate(get(row, "start date")),
+			DueAt:    jiraDate(get(row, "due date")),
+			Comments: make([]Comment, 0),
+		}
+		if t.Priority == "" {
+			t.Priority = "none"
+		}
+		if created := jiraDate(get(row, "created")); created != nil {
+			t.CreatedAt = *created
+		}
+		points := get(row, "custom field (story points)")
+		if points == "" {
+			points = get(row, "custom field (story point estimate)")
+		}
+		if p, err := strconv.ParseFloat(points, 64); err == nil && p > 0 {
+			t.Points = int(p + 0.5)
+		}
+
+		added := make(map[string]bool)
+		for _, l := range all(row, "labels") {
+			name := truncate(l, MaxLabelName)
+			if !labels[strings.ToLower(name)] {
+				labels[strings.ToLower(name)] = true
+				b.Labels = append(b.Labels, Label{Name: name, Color: "#0052cc"})
+			}
+			if !added[strings.ToLower(name)] {
+				added[strings.ToLower(name)] = true
+				t.Labels = append(t.Labels, name)
+			}
+		}
+
+		for _, c := range all(row, "comment") {
+			t.Comments = append(t.Comments, jiraComment(c))
+		}
+
+		taskStatus = append(taskStatus, strings.ToLower(s))
+		b.Tasks = append(b.Tasks, t)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return jiraRank(statuses[i]) < jiraRank(statuses[j])
+	})
+	columns := make(map[string]int, len(statuses))
+	for i, s := range statuses {
+		columns[strings.ToLower(s)] = i
+		b.Columns = append(b.Columns, Column{Title: s})
+	}
+	for i := range b.Tasks {
+		b.Tasks[i].Column = columns[taskStatus[i]]
+	}
+	if b.Project.Name == "" {
+		b.Project.Name = "Jira import"
+	}
+
+	return b, b.Check()
+}
+
+func jiraRank(status string) int {
+	if r, ok := jiraStatuses[strings.ToLower(status)]; ok {
+		return r
+	}
+	return 5
+}
+
+// jiraDate parses a Jira date, returning nil when it is empty or malformed.
+func jiraDate(s string) *time.Time {
+	for _, layout := range []string{jiraTime, "02/Jan/06", time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// jiraComment parses a Jira comment cell, "date;author;text", keeping the whole cell as
+// the text when it is in another form.
+func jiraComment(cell string) Comment {
+	parts := strings.SplitN(cell, ";", 3)
+	if len(parts) == 3 {
+		if at := jiraDate(parts[0]); at != nil {
+			return Comment{CreatedAt: *at, Author: parts[1], Content: parts[2]}
+		}
+	}
+	return Comment{Content: cell}
+}
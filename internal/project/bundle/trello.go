@@ -0,0 +1,218 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trelloColors maps the named label colors of Trello to hex colors.
+var trelloColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+	Lists []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+	} `json:"lists"`
+	Cards []struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		Desc      string     `json:"desc"`
+		IDList    string     `json:"idList"`
+		Closed    bool       `json:"closed"`
+		IDLabels  []string   `json:"idLabels"`
+		IDMembers []string   `json:"idMembers"`
+		Start     *time.Time `json:"start"`
+		Due       *time.Time `json:"due"`
+		Pos       float64    `json:"pos"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Actions []struct {
+		Type            string    `json:"type"`
+		Date            time.Time `json:"date"`
+		IDMemberCreator string    `json:"idMemberCreator"`
+		Data            struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+	} `json:"actions"`
+}
+
+// FromTrello converts the JSON export of a Trello board into a bundle. Open lists become
+// columns and open cards become tasks. Trello exports carry no emails, so people are
+// identified by their Trello username.
+func FromTrello(r io.Reader) (Bundle, error) {
+	var board trelloBoard
+
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return Bundle{}, fmt.Errorf("bundle: decoding trello board: %w", err)
+	}
+
+	b := Bundle{
+		Version: Version,
+		Project: Project{Name: truncate(board.Name, MaxProjectName), Description: board.Desc},
+		Columns: make([]Column, 0),
+		Labels:  make([]Label, 0),
+		Tasks:   make([]Task, 0),
+	}
+
+	if b.Project.Name == "" {
+		b.Project.Name = "Trello import"
+	}
+
+	lists := board.Lists[:0:0]
+	for _, l := range board.Lists {
+		if !l.Closed {
+			lists = append(lists, l)
+		}
+	}
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+
+	columns := make(map[string]int, len(lists))
+	for i, l := range lists {
+		columns[l.ID] = i
+		b.Columns = append(b.Columns, Column{Title: truncate(l.Name, MaxColumnName)})
+	}
+
+	// Unnamed Trello labels are named after their color, and labels sharing a name merge.
+	labels := make(map[string]string, len(board.Labels))
+	seen := make(map[string]bool, len(board.Labels))
+	for _, l := range board.Labels {
+		name := truncate(l.Name, MaxLabelName)
+		if name == "" {
+			name = l.Color
+		}
+		if name == "" {
+			continue
+		}
+		labels[l.ID] = name
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		color, ok := trelloColors[strings.SplitN(l.Color, "_", 2)[0]]
+		if !ok {
+			color = "#b3bac5"
+		}
+		b.Labels = append(b.Labels, Label{Name: name, Color: color})
+	}
+
+	members := make(map[string]string, len(board.Members))
+	for _, m := range board.Members {
+		members[m.ID] = m.Username
+	}
+
+	var (
+		reporters = make(map[string]string)
+		comments  = make(map[string][]Comment)
+	)
+	for _, a := range board.Actions {
+		switch a.Type {
+		case "createCard":
+			reporters[a.Data.Card.ID] = members[a.IDMemberCreator]
+		case "commentCard":
+			comments[a.Data.Card.ID] = append(comments[a.Data.Card.ID], Comment{
+				Author:    members[a.IDMemberCreator],
+				Content:   a.Data.Text,
+				CreatedAt: a.Date.UTC(),
+			})
+		}
+	}
+
+	cards := board.Cards[:0:0]
+	for _, c := range board.Cards {
+		if _, ok := columns[c.IDList]; ok && !c.Closed {
+			cards = append(cards, c)
+		}
+	}
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+
+	for _, c := range cards {
+		t := Task{
+			Title:     truncate(c.Name, MaxTitle),
+			Content:   c.Desc,
+			Column:    columns[c.IDList],
+			Priority:  "none",
+			Reporter:  reporters[c.ID],
+			Labels:    make([]string, 0),
+			StartAt:   utc(c.Start),
+			DueAt:     utc(c.Due),
+			CreatedAt: trelloCreatedAt(c.ID),
+			Comments:  make([]Comment, 0),
+		}
+		if len(c.IDMembers) > 0 {
+			t.Assignee = members[c.IDMembers[0]]
+		}
+
+		added := make(map[string]bool)
+		for _, id := range c.IDLabels {
+			name, ok := labels[id]
+			if !ok || added[strings.ToLower(name)] {
+				continue
+			}
+			added[strings.ToLower(name)] = true
+			t.Labels = append(t.Labels, name)
+		}
+
+		// Trello lists actions newest first.
+		cs := comments[c.ID]
+		for i := len(cs) - 1; i >= 0; i-- {
+			t.Comments = append(t.Comments, cs[i])
+		}
+
+		b.Tasks = append(b.Tasks, t)
+	}
+
+	return b, b.Check()
+}
+
+// trelloCreatedAt returns the creation time embedded in the first four bytes of a
+// Trello id, or the zero time when the id is malformed.
+func trelloCreatedAt(id string) time.Time {
+	if len(id) < 8 {
+		return time.Time{}
+	}
+	sec, err := strconv.ParseInt(id[:8], 16, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
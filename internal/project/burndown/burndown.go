@@ -0,0 +1,110 @@
+// Package burndown rebuilds the daily progress of a sprint from task history.
+//
+// The current state of every task that was ever in the sprint is rewound through
+// its recorded changes, newest first, and measured at the end of each sprint day.
+package burndown
+
+import (
+	"sort"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+)
+
+const day = 24 * time.Hour
+
+// Task is the current state of a task that is, or once was, in the sprint.
+type Task struct {
+	ID       string
+	Points   int
+	ColumnID string
+	SprintID string
+}
+
+// Change is a recorded task change, holding the values the task had before it.
+// Fields the change did not touch are nil.
+type Change struct {
+	TaskID   string
+	At       time.Time
+	Points   *int
+	ColumnID *string
+	SprintID *string
+}
+
+// Series returns a point for every day from start to end, in UTC. Tasks count toward
+// the sprint while they belong to it, and are completed while in the done column.
+// Days after now are left out.
+func Series(sprintID, doneColumnID string, start, end, now time.Time, tasks []Task, changes []Change) []model.BurndownPoint {
+	state := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		state[t.ID] = t
+	}
+
+	sorted := make([]Change, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].At.After(sorted[j].At)
+	})
+
+	first := truncate(start)
+	last := truncate(end)
+	if n := truncate(now); n.Before(last) {
+		last = n
+	}
+	if last.Before(first) {
+		return make([]model.BurndownPoint, 0)
+	}
+
+	points := make([]model.BurndownPoint, 0, int(last.Sub(first)/day)+1)
+	next := 0
+
+	for d := last; !d.Before(first); d = d.Add(-day) {
+		boundary := d.Add(day)
+		if boundary.After(now) {
+			boundary = now
+		}
+
+		// Undo every change made after the end of the day.
+		for ; next < len(sorted) && sorted[next].At.After(boundary); next++ {
+			c := sorted[next]
+			t, ok := state[c.TaskID]
+			if !ok {
+				continue
+			}
+			if c.Points != nil {
+				t.Points = *c.Points
+			}
+			if c.ColumnID != nil {
+				t.ColumnID = *c.ColumnID
+			}
+			if c.SprintID != nil {
+				t.SprintID = *c.SprintID
+			}
+			state[c.TaskID] = t
+		}
+
+		p := model.BurndownPoint{Date: d.Format("2006-01-02")}
+		for _, t := range state {
+			if t.SprintID != sprintID {
+				continue
+			}
+			p.Total += t.Points
+			if t.ColumnID == doneColumnID {
+				p.Completed += t.Points
+			}
+		}
+		p.Remaining = p.Total - p.Completed
+
+		points = append(points, p)
+	}
+
+	// Points were collected newest first.
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points
+}
+
+func truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(day)
+}
@@ -0,0 +1,100 @@
+package burndown_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/burndown"
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	sprint = "sprint"
+	todo   = "todo"
+	done   = "done"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSeries(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	end := start.Add(3 * 24 * time.Hour)
+	at := func(days int, hour int) time.Time {
+		return time.Date(2024, 3, 4+days, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		tasks    []burndown.Task
+		changes  []burndown.Change
+		expected []model.BurndownPoint
+	}{
+		{
+			name: "nothing changed",
+			now:  at(5, 0),
+			tasks: []burndown.Task{
+				{ID: "a", Points: 3, ColumnID: todo, SprintID: sprint},
+				{ID: "b", Points: 5, ColumnID: todo, SprintID: sprint},
+			},
+			expected: []model.BurndownPoint{
+				{Date: "2024-03-04", Total: 8, Completed: 0, Remaining: 8},
+				{Date: "2024-03-05", Total: 8, Completed: 0, Remaining: 8},
+				{Date: "2024-03-06", Total: 8, Completed: 0, Remaining: 8},
+				{Date: "2024-03-07", Total: 8, Completed: 0, Remaining: 8},
+			},
+		},
+		{
+			name: "tasks are finished, re-estimated, added and removed",
+			now:  at(5, 0),
+			tasks: []burndown.Task{
+				{ID: "a", Points: 3, ColumnID: done, SprintID: sprint},
+				{ID: "b", Points: 8, ColumnID: todo, SprintID: sprint},
+				{ID: "c", Points: 2, ColumnID: todo, SprintID: sprint},
+				{ID: "d", Points: 1, ColumnID: todo, SprintID: ""},
+			},
+			changes: []burndown.Change{
+				{TaskID: "a", At: at(1, 10), ColumnID: ptr(todo)},
+				{TaskID: "b", At: at(2, 11), Points: ptr(5)},
+				{TaskID: "c", At: at(2, 12), SprintID: ptr("")},
+				{TaskID: "d", At: at(3, 9), SprintID: ptr(sprint)},
+			},
+			expected: []model.BurndownPoint{
+				{Date: "2024-03-04", Total: 9, Completed: 0, Remaining: 9},
+				{Date: "2024-03-05", Total: 9, Completed: 3, Remaining: 6},
+				{Date: "2024-03-06", Total: 14, Completed: 3, Remaining: 11},
+				{Date: "2024-03-07", Total: 13, Completed: 3, Remaining: 10},
+			},
+		},
+		{
+			name: "days after now are left out",
+			now:  at(1, 12),
+			tasks: []burndown.Task{
+				{ID: "a", Points: 3, ColumnID: done, SprintID: sprint},
+			},
+			changes: []burndown.Change{
+				{TaskID: "a", At: at(1, 10), ColumnID: ptr(todo)},
+			},
+			expected: []model.BurndownPoint{
+				{Date: "2024-03-04", Total: 3, Completed: 0, Remaining: 3},
+				{Date: "2024-03-05", Total: 3, Completed: 3, Remaining: 0},
+			},
+		},
+		{
+			name:     "not started yet",
+			now:      at(-1, 12),
+			expected: []model.BurndownPoint{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := burndown.Series(sprint, done, start, end, tc.now, tc.tasks, tc.changes)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
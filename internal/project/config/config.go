@@ -30,6 +30,70 @@ type Config struct {
 		Port       int    `conf:"default:5432,noprint"`
 		Name       string `conf:"default:project,noprint"`
 		DisableTLS bool   `conf:"default:false"`
+		// Siloed tenants get a dedicated database on SiloHost named after the tenant. It is
+		// created and migrated by SiloAdminUser, which must be allowed to create databases.
+		SiloHost          string        `conf:"noprint"`
+		SiloPort          int           `conf:"default:5432,noprint"`
+		SiloAdminUser     string        `conf:"default:postgres,noprint"`
+		SiloAdminPassword string        `conf:"required,noprint"`
+		SiloMaxOpenConns  int           `conf:"default:5"`
+		SiloMaxIdleConns  int           `conf:"default:2"`
+		SiloLookupTTL     time.Duration `conf:"default:1m"`
+		// Replicas lists optional read replica urls separated by semicolons. After writing, a
+		// client reads from the primary for ReadYourWritesWindow, as long as it sends back the
+		// X-Primary-Until header.
+		Replicas             []string      `conf:"noprint"`
+		ReplicaMaxLag        time.Duration `conf:"default:2s"`
+		ReplicaCheckInterval time.Duration `conf:"default:1s"`
+		ReadYourWritesWindow time.Duration `conf:"default:5s"`
+	}
+	// Tenants bounds the requests spanning every tenant of the user: how many tenants are
+	// queried at once, and how long each of them is given.
+	Tenants struct {
+		Concurrency int           `conf:"default:8"`
+		Timeout     time.Duration `conf:"default:2s"`
+	}
+	// Trash configures how long projects and tasks stay in the trash before they are purged.
+	Trash struct {
+		Retention     time.Duration `conf:"default:720h"`
+		PurgeInterval time.Duration `conf:"default:1h"`
+	}
+	// Recurrence configures how often the scheduler looks for due recurring tasks.
+	Recurrence struct {
+		Interval time.Duration `conf:"default:1m"`
+	}
+	// Notifications configures how often task events are turned into notifications, and
+	// how many events of a tenant are turned at once.
+	Notifications struct {
+		Interval  time.Duration `conf:"default:5s"`
+		BatchSize int           `conf:"default:100"`
+	}
+	// Imports bounds the project imports run in the background: Workers run at once, and
+	// up to Queue more wait for a worker.
+	Imports struct {
+		Workers int `conf:"default:2"`
+		Queue   int `conf:"default:16"`
+	}
+	// Stream configures the board change streams pushed to clients.
+	Stream struct {
+		Buffer    int           `conf:"default:64"`
+		History   int           `conf:"default:256"`
+		Heartbeat time.Duration `conf:"default:25s"`
+	}
+	// Share configures the links sharing project boards. Every token may make RateLimit
+	// requests a minute, and up to Burst at once. Every client, told apart by the address
+	// the ingress puts in ClientIPHeader, may make ClientRateLimit requests a minute over
+	// all tokens, and up to ClientBurst at once.
+	Share struct {
+		RateLimit       int    `conf:"default:60"`
+		Burst           int    `conf:"default:20"`
+		ClientRateLimit int    `conf:"default:120"`
+		ClientBurst     int    `conf:"default:40"`
+		ClientIPHeader  string `conf:"default:X-Real-Ip"`
+	}
+	Nats struct {
+		Address string `conf:"default:127.0.0.1"`
+		Port    string `conf:"default:4222"`
 	}
 }
 
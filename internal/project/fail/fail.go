@@ -1,7 +1,11 @@
 // Package fail contains common known errors.
 package fail
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	// ErrNotFound represents a resource not found.
@@ -10,6 +14,113 @@ var (
 	ErrInvalidID = errors.New("id provided was not a valid UUID")
 	// ErrNoTenant represents a failure to retrieve the tenant.
 	ErrNoTenant = errors.New("missing tenant id")
+	// ErrNotAuthorized represents an action the requesting user is not allowed to perform.
+	ErrNotAuthorized = errors.New("not authorized")
+	// ErrColumnLimit represents a project board that already has the maximum number of columns.
+	ErrColumnLimit = errors.New("project has the maximum number of columns")
+	// ErrLastColumn represents an attempt to remove the only column of a project board.
+	ErrLastColumn = errors.New("project must keep at least one column")
+	// ErrInvalidColumnOrder represents a column order that does not list every project column once.
+	ErrInvalidColumnOrder = errors.New("column order must list every project column once")
+	// ErrInvalidMove represents a task move to a position that does not exist in the target column.
+	ErrInvalidMove = errors.New("task cannot be placed between the given tasks")
+	// ErrInvalidSearch represents search parameters that cannot be parsed or are out of range.
+	ErrInvalidSearch = errors.New("invalid search parameters")
+	// ErrInvalidPage represents pagination parameters that cannot be parsed or are out of range.
+	ErrInvalidPage = errors.New("invalid page parameters")
+	// ErrDuplicateLabel represents a label name already used in the project.
+	ErrDuplicateLabel = errors.New("label name already exists in project")
+	// ErrInvalidLabel represents a label that does not belong to the project of the task.
+	ErrInvalidLabel = errors.New("label does not belong to the task project")
+	// ErrInvalidSchedule represents a task that would start after it is due.
+	ErrInvalidSchedule = errors.New("task cannot start after it is due")
+	// ErrInvalidFilter represents listing filters that cannot be parsed or are not supported.
+	ErrInvalidFilter = errors.New("invalid filter parameters")
+	// ErrSprintState represents a sprint action that its current status does not allow.
+	ErrSprintState = errors.New("sprint status does not allow this action")
+	// ErrSprintActive represents a sprint started while another sprint of the project is active.
+	ErrSprintActive = errors.New("project already has an active sprint")
+	// ErrSprintEnd represents a sprint that would end before it starts.
+	ErrSprintEnd = errors.New("sprint must end after it starts")
+	// ErrSprintCapacity represents tasks whose points exceed the capacity of the sprint.
+	ErrSprintCapacity = errors.New("tasks exceed the sprint capacity")
+	// ErrSprintProject represents a task or sprint that belongs to another project.
+	ErrSprintProject = errors.New("sprint and tasks must belong to the same project")
+	// ErrInvalidLink represents a task linked to itself or made its own ancestor.
+	ErrInvalidLink = errors.New("task cannot be linked to itself")
+	// ErrDuplicateLink represents a link that already exists between two tasks.
+	ErrDuplicateLink = errors.New("tasks are already linked")
+	// ErrLinkCycle represents a link or parent that would make tasks depend on themselves.
+	ErrLinkCycle = errors.New("link would create a cycle")
+	// ErrTaskBlocked represents a task finished while tasks blocking it are not.
+	ErrTaskBlocked = errors.New("task is blocked by unfinished tasks")
+	// ErrInvalidTemplate represents template tasks referring to unknown columns or labels.
+	ErrInvalidTemplate = errors.New("template tasks must refer to template columns and labels")
+	// ErrDuplicateTemplate represents a template name already used by the tenant.
+	ErrDuplicateTemplate = errors.New("template name already exists")
+	// ErrBuiltinTemplate represents a change to a built-in template.
+	ErrBuiltinTemplate = errors.New("built-in templates cannot be changed")
+	// ErrInvalidExport represents an export format other than json or csv.
+	ErrInvalidExport = errors.New("export format must be json or csv")
+	// ErrInvalidImport represents an import source that cannot be read as a project board.
+	ErrInvalidImport = errors.New("import source is not a valid board")
+	// ErrLastOwner represents a change that would leave a project without an owner.
+	ErrLastOwner = errors.New("project must keep at least one owner")
+	// ErrInvalidTeam represents a project assigned a team that does not exist.
+	ErrInvalidTeam = errors.New("team does not exist")
+	// ErrDuplicateTeam represents a team name already used by the tenant.
+	ErrDuplicateTeam = errors.New("team name already exists")
+	// ErrColumnPolicy represents a task entering a column whose policies it breaks.
+	ErrColumnPolicy = errors.New("task breaks the policies of the column")
+	// ErrInvalidQuery represents a task query that cannot be parsed.
+	ErrInvalidQuery = errors.New("invalid task query")
+	// ErrDuplicateFilter represents a saved filter name the user already uses.
+	ErrDuplicateFilter = errors.New("filter name already exists")
+	// ErrInvalidExpiry represents a share link that would expire before it is created.
+	ErrInvalidExpiry = errors.New("share link must expire in the future")
+	// ErrDuplicateField represents a custom field name already used in the project.
+	ErrDuplicateField = errors.New("field name already exists in project")
+	// ErrFieldLimit represents a project that already has the maximum number of custom fields.
+	ErrFieldLimit = errors.New("project has the maximum number of custom fields")
+	// ErrFieldOptions represents options given to a custom field that is not a select field.
+	ErrFieldOptions = errors.New("only select fields have options")
+	// ErrInvalidFieldValue represents custom field values that do not suit their fields.
+	ErrInvalidFieldValue = errors.New("custom field values must suit their fields")
+	// ErrTimerRunning represents a timer started while the user runs another one.
+	ErrTimerRunning = errors.New("user already has a running timer")
+	// ErrInvalidRule represents a recurrence rule that cannot be parsed.
+	ErrInvalidRule = errors.New("invalid recurrence rule")
+	// ErrRateLimited represents a client making requests faster than it is allowed to.
+	ErrRateLimited = errors.New("too many requests")
+	// ErrImportsBusy represents an import started while the import queue is full.
+	ErrImportsBusy = errors.New("too many imports are running, try again later")
 	// ErrConnectionFailed represents a failed connection attempt.
 	ErrConnectionFailed = errors.New("connection failed")
 )
+
+// Violation represents a column policy broken by a task.
+type Violation struct {
+	Policy  string
+	Message string
+}
+
+// PolicyError represents a task entering a column whose policies it breaks. It matches
+// ErrColumnPolicy.
+type PolicyError struct {
+	ColumnID   string
+	Violations []Violation
+}
+
+// Error returns the string error.
+func (e *PolicyError) Error() string {
+	policies := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		policies = append(policies, v.Policy)
+	}
+	return fmt.Sprintf("%s %s: %s", ErrColumnPolicy, e.ColumnID, strings.Join(policies, ", "))
+}
+
+// Is reports whether target is ErrColumnPolicy.
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrColumnPolicy
+}
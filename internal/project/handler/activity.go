@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// ActivityHandler handles the task activity requests.
+type ActivityHandler struct {
+	logger          *zap.Logger
+	activityService activityService
+}
+
+// NewActivityHandler returns a new activity handler.
+func NewActivityHandler(
+	logger *zap.Logger,
+	activityService activityService,
+) *ActivityHandler {
+	return &ActivityHandler{
+		logger:          logger,
+		activityService: activityService,
+	}
+}
+
+// ListByTask handles task activity requests.
+func (ah *ActivityHandler) ListByTask(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	page, err := parseActivityPage(r)
+	if err != nil {
+		return web.NewRequestError(fail.ErrInvalidPage, http.StatusBadRequest)
+	}
+
+	list, err := ah.activityService.ListByTask(r.Context(), tid, page)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing activity for task %q: %w", tid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// ListByProject handles project activity feed requests.
+func (ah *ActivityHandler) ListByProject(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	page, err := parseActivityPage(r)
+	if err != nil {
+		return web.NewRequestError(fail.ErrInvalidPage, http.StatusBadRequest)
+	}
+
+	list, err := ah.activityService.ListByProject(r.Context(), pid, page)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing activity for project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// parseActivityPage reads and validates the limit and offset query parameters.
+func parseActivityPage(r *http.Request) (model.ActivityPage, error) {
+	var err error
+
+	q := r.URL.Query()
+	page := model.ActivityPage{Limit: model.DefaultActivityLimit}
+
+	if v := q.Get("limit"); v != "" {
+		if page.Limit, err = strconv.Atoi(v); err != nil {
+			return page, err
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if page.Offset, err = strconv.Atoi(v); err != nil {
+			return page, err
+		}
+	}
+
+	return page, page.Validate()
+}
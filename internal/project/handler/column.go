@@ -35,7 +35,14 @@ func (ch *ColumnHandler) List(w http.ResponseWriter, r *http.Request) error {
 	pid := chi.URLParam(r, "pid")
 	list, err := ch.service.List(r.Context(), pid)
 	if err != nil {
-		return err
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing columns of project %q: %w", pid, err)
+		}
 	}
 
 	return web.Respond(r.Context(), w, list, http.StatusOK)
@@ -52,6 +59,8 @@ func (ch *ColumnHandler) Retrieve(w http.ResponseWriter, r *http.Request) error
 			return web.NewRequestError(err, http.StatusNotFound)
 		case fail.ErrInvalidID:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
 		default:
 			return fmt.Errorf("error looking for columns %q: %w", id, err)
 		}
@@ -60,58 +69,109 @@ func (ch *ColumnHandler) Retrieve(w http.ResponseWriter, r *http.Request) error
 	return web.Respond(r.Context(), w, col, http.StatusOK)
 }
 
-// Create handles column creation requests.
+// Create handles requests that add a column to a project board.
 func (ch *ColumnHandler) Create(w http.ResponseWriter, r *http.Request) error {
-	var nc model.NewColumn
+	pid := chi.URLParam(r, "pid")
 
-	if err := web.Decode(r, &nc); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	var ac model.AddColumn
+	if err := web.Decode(r, &ac); err != nil {
 		return err
 	}
 
-	col, err := ch.service.Create(r.Context(), nc, time.Now())
+	col, err := ch.service.AddColumn(r.Context(), pid, ac, time.Now())
 	if err != nil {
-		return err
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrColumnLimit:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error adding column to project %q: %w", pid, err)
+		}
 	}
 
 	return web.Respond(r.Context(), w, col, http.StatusCreated)
 }
 
-// Update handles column update requests.
+// Update handles column rename requests.
 func (ch *ColumnHandler) Update(w http.ResponseWriter, r *http.Request) error {
-	id := chi.URLParam(r, "id")
+	cid := chi.URLParam(r, "cid")
 
 	var update model.UpdateColumn
 	if err := web.Decode(r, &update); err != nil {
-		return fmt.Errorf("error decoding column update: %w", err)
+		return err
 	}
 
-	if _, err := ch.service.Update(r.Context(), id, update, time.Now()); err != nil {
+	col, err := ch.service.Update(r.Context(), cid, update, time.Now())
+	if err != nil {
 		switch err {
 		case fail.ErrNotFound:
 			return web.NewRequestError(err, http.StatusNotFound)
 		case fail.ErrInvalidID:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
 		default:
-			return fmt.Errorf("error updating column %q: %w", id, err)
+			return fmt.Errorf("error updating column %q: %w", cid, err)
 		}
 	}
 
-	return web.Respond(r.Context(), w, nil, http.StatusOK)
+	return web.Respond(r.Context(), w, col, http.StatusOK)
 }
 
-// Delete handles column delete requests.
+// Delete handles requests that remove a column from a project board. Tasks are moved
+// to the column given by the moveTo query parameter, or moved to the trash.
 func (ch *ColumnHandler) Delete(w http.ResponseWriter, r *http.Request) error {
-	id := chi.URLParam(r, "id")
+	pid := chi.URLParam(r, "pid")
+	cid := chi.URLParam(r, "cid")
+
+	rc := model.RemoveColumn{MoveTo: r.URL.Query().Get("moveTo")}
+	if err := rc.Validate(); err != nil {
+		return web.NewRequestError(fail.ErrInvalidID, http.StatusBadRequest)
+	}
 
-	if err := ch.service.Delete(r.Context(), id); err != nil {
+	if err := ch.service.RemoveColumn(r.Context(), pid, cid, rc, time.Now()); err != nil {
+		if perr, ok := policyError(err); ok {
+			return perr
+		}
 		switch err {
-		case fail.ErrInvalidID:
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrLastColumn:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
 		default:
-			return fmt.Errorf("error deleting column %q: %w", id, err)
+			return fmt.Errorf("error deleting column %q: %w", cid, err)
 		}
 	}
 
 	return web.Respond(r.Context(), w, nil, http.StatusOK)
 }
+
+// Reorder handles column reorder requests.
+func (ch *ColumnHandler) Reorder(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var rc model.ReorderColumns
+	if err := web.Decode(r, &rc); err != nil {
+		return err
+	}
+
+	if err := ch.service.ReorderColumns(r.Context(), pid, rc, time.Now()); err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrInvalidColumnOrder:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error reordering columns of project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, rc, http.StatusOK)
+}
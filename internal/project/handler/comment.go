@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// CommentHandler handles the task comment requests.
+type CommentHandler struct {
+	logger         *zap.Logger
+	commentService commentService
+}
+
+// NewCommentHandler returns a new comment handler.
+func NewCommentHandler(
+	logger *zap.Logger,
+	commentService commentService,
+) *CommentHandler {
+	return &CommentHandler{
+		logger:         logger,
+		commentService: commentService,
+	}
+}
+
+// List handles list comment requests.
+func (ch *CommentHandler) List(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	list, err := ch.commentService.List(r.Context(), tid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing comments for task %q: %w", tid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Create handles create comment requests.
+func (ch *CommentHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	var nc model.NewComment
+	if err := web.Decode(r, &nc); err != nil {
+		return err
+	}
+
+	c, err := ch.commentService.Create(r.Context(), nc, tid, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error creating comment for task %q: %w", tid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, c, http.StatusCreated)
+}
+
+// Update handles update comment requests.
+func (ch *CommentHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	cmid := chi.URLParam(r, "cmid")
+
+	var uc model.UpdateComment
+	if err := web.Decode(r, &uc); err != nil {
+		return err
+	}
+
+	c, err := ch.commentService.Update(r.Context(), tid, cmid, uc, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error updating comment %q: %w", cmid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, c, http.StatusOK)
+}
+
+// Delete handles delete comment requests.
+func (ch *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	cmid := chi.URLParam(r, "cmid")
+
+	if err := ch.commentService.Delete(r.Context(), tid, cmid); err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error deleting comment %q: %w", cmid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
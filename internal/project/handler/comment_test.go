@@ -0,0 +1,135 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestCommentHandler_Update(t *testing.T) {
+	path := "/projects/tasks/" + testutils.MockUUID + "/comments/" + testutils.MockUUID
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupCommentRouter()
+
+		uc := model.UpdateComment{Liked: aws.Bool(true)}
+		comment := model.Comment{ID: testutils.MockUUID, Likes: 1, Liked: true}
+
+		b, err := json.Marshal(&uc)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader(b))
+		w := httptest.NewRecorder()
+
+		deps.commentService.On("Update", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, testutils.MockUUID, uc, mock.AnythingOfType("time.Time")).Return(comment, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&comment)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.commentService.AssertExpectations(t)
+	})
+
+	t.Run("error 403 not the author", func(t *testing.T) {
+		handle, deps := setupCommentRouter()
+
+		uc := model.UpdateComment{Content: aws.String("Edited")}
+		response := web.ErrorResponse{
+			Error: fail.ErrNotAuthorized.Error(),
+		}
+
+		b, err := json.Marshal(&uc)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader(b))
+		w := httptest.NewRecorder()
+
+		deps.commentService.On("Update", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, testutils.MockUUID, uc, mock.AnythingOfType("time.Time")).Return(model.Comment{}, fail.ErrNotAuthorized)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		deps.commentService.AssertExpectations(t)
+	})
+}
+
+func TestCommentHandler_Delete(t *testing.T) {
+	path := "/projects/tasks/" + testutils.MockUUID + "/comments/" + testutils.MockUUID
+
+	t.Run("error 403 not the author", func(t *testing.T) {
+		handle, deps := setupCommentRouter()
+
+		r := httptest.NewRequest(http.MethodDelete, path, nil)
+		w := httptest.NewRecorder()
+
+		deps.commentService.On("Delete", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, testutils.MockUUID).Return(fail.ErrNotAuthorized)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		deps.commentService.AssertExpectations(t)
+	})
+
+	t.Run("error 404 comment on another task", func(t *testing.T) {
+		handle, deps := setupCommentRouter()
+
+		tid := "a4b4f5b4-3c5a-4c4e-9e3a-6d2f1b7c8e90"
+		r := httptest.NewRequest(http.MethodDelete, "/projects/tasks/"+tid+"/comments/"+testutils.MockUUID, nil)
+		w := httptest.NewRecorder()
+
+		deps.commentService.On("Delete", mock.AnythingOfType("*context.valueCtx"), tid, testutils.MockUUID).Return(fail.ErrNotFound)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.commentService.AssertExpectations(t)
+	})
+}
+
+type commentHandlerDeps struct {
+	logger         *zap.Logger
+	commentService *mocks.CommentService
+}
+
+func setupCommentRouter() (http.Handler, commentHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	commentService := &mocks.CommentService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	comments := handler.NewCommentHandler(logger, commentService)
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/comments/{cmid}", comments.Update)
+	app.Handle(http.MethodDelete, "/projects/tasks/{tid}/comments/{cmid}", comments.Delete)
+
+	return router, commentHandlerDeps{logger, commentService}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// FieldHandler handles the project custom field requests.
+type FieldHandler struct {
+	logger       *zap.Logger
+	fieldService fieldService
+}
+
+// NewFieldHandler returns a new custom field handler.
+func NewFieldHandler(
+	logger *zap.Logger,
+	fieldService fieldService,
+) *FieldHandler {
+	return &FieldHandler{
+		logger:       logger,
+		fieldService: fieldService,
+	}
+}
+
+// List handles list custom field requests.
+func (fh *FieldHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := fh.fieldService.List(r.Context(), pid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing custom fields for project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Create handles create custom field requests.
+func (fh *FieldHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var nf model.NewCustomField
+	if err := web.Decode(r, &nf); err != nil {
+		return err
+	}
+
+	f, err := fh.fieldService.Create(r.Context(), pid, nf, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		case fail.ErrDuplicateField, fail.ErrFieldLimit:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error creating custom field for project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusCreated)
+}
+
+// Update handles update custom field requests.
+func (fh *FieldHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	cfid := chi.URLParam(r, "cfid")
+
+	var uf model.UpdateCustomField
+	if err := web.Decode(r, &uf); err != nil {
+		return err
+	}
+
+	f, err := fh.fieldService.Update(r.Context(), pid, cfid, uf, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrFieldOptions:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		case fail.ErrDuplicateField:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error updating custom field %q: %w", cfid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusOK)
+}
+
+// Delete handles delete custom field requests.
+func (fh *FieldHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	cfid := chi.URLParam(r, "cfid")
+
+	if err := fh.fieldService.Delete(r.Context(), pid, cfid); err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error deleting custom field %q: %w", cfid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
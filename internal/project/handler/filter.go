@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// FilterHandler handles the saved task filter requests.
+type FilterHandler struct {
+	logger        *zap.Logger
+	filterService filterService
+}
+
+// NewFilterHandler returns a new filter handler.
+func NewFilterHandler(
+	logger *zap.Logger,
+	filterService filterService,
+) *FilterHandler {
+	return &FilterHandler{
+		logger:        logger,
+		filterService: filterService,
+	}
+}
+
+// List handles list filter requests, optionally for a single project.
+func (fh *FilterHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := r.URL.Query().Get("project")
+
+	list, err := fh.filterService.List(r.Context(), pid)
+	if err != nil {
+		switch err {
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing filters: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Retrieve handles retrieve filter requests.
+func (fh *FilterHandler) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	fid := chi.URLParam(r, "fid")
+
+	f, err := fh.filterService.Retrieve(r.Context(), fid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error retrieving filter %q: %w", fid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusOK)
+}
+
+// Create handles create filter requests.
+func (fh *FilterHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	var nf model.NewSavedFilter
+	if err := web.Decode(r, &nf); err != nil {
+		return err
+	}
+
+	f, err := fh.filterService.Create(r.Context(), nf, time.Now())
+	if err != nil {
+		if qerr, ok := queryError(err, "query"); ok {
+			return qerr
+		}
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateFilter:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error creating filter %q: %w", nf.Name, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusCreated)
+}
+
+// Update handles update filter requests.
+func (fh *FilterHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	fid := chi.URLParam(r, "fid")
+
+	var uf model.UpdateSavedFilter
+	if err := web.Decode(r, &uf); err != nil {
+		return err
+	}
+
+	f, err := fh.filterService.Update(r.Context(), fid, uf, time.Now())
+	if err != nil {
+		if qerr, ok := queryError(err, "query"); ok {
+			return qerr
+		}
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		case fail.ErrDuplicateFilter:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error updating filter %q: %w", fid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, f, http.StatusOK)
+}
+
+// Delete handles delete filter requests.
+func (fh *FilterHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	fid := chi.URLParam(r, "fid")
+
+	if err := fh.filterService.Delete(r.Context(), fid); err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error deleting filter %q: %w", fid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -4,22 +4,156 @@ import (
 	"context"
 	"time"
 
+	"github.com/devpies/saas-core/internal/project/bundle"
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/stream"
 )
 
 type columnService interface {
 	Create(ctx context.Context, nc model.NewColumn, now time.Time) (model.Column, error)
-	CreateColumns(ctx context.Context, pid string, now time.Time) error
 	List(ctx context.Context, projectID string) ([]model.Column, error)
 	Retrieve(ctx context.Context, columnID string) (model.Column, error)
 	Update(ctx context.Context, columnID string, update model.UpdateColumn, now time.Time) (model.Column, error)
 	Delete(ctx context.Context, columnID string) error
+	AddColumn(ctx context.Context, projectID string, ac model.AddColumn, now time.Time) (model.Column, error)
+	RemoveColumn(ctx context.Context, projectID string, columnID string, rc model.RemoveColumn, now time.Time) error
+	ReorderColumns(ctx context.Context, projectID string, rc model.ReorderColumns, now time.Time) error
 }
 
 type taskService interface {
-	Create(ctx context.Context, task model.NewTask, projectID string, now time.Time) (model.Task, error)
-	List(ctx context.Context, projectID string) ([]model.Task, error)
+	Create(ctx context.Context, task model.NewTask, projectID string, columnID string, now time.Time) (model.Task, error)
+	List(ctx context.Context, projectID string, filter model.TaskFilter) ([]model.Task, error)
 	Retrieve(ctx context.Context, taskID string) (model.Task, error)
+	RetrieveByKey(ctx context.Context, projectID string, key string) (model.Task, error)
 	Update(ctx context.Context, taskID string, update model.UpdateTask, now time.Time) (model.Task, error)
-	Delete(ctx context.Context, taskID string) error
+	Delete(ctx context.Context, taskID string, now time.Time) error
+	Restore(ctx context.Context, taskID string, now time.Time) (model.Task, error)
+	Archive(ctx context.Context, taskID string, now time.Time) (model.Task, error)
+	Unarchive(ctx context.Context, taskID string, now time.Time) (model.Task, error)
+	Move(ctx context.Context, taskID string, mt model.MoveTask, now time.Time) (model.Task, error)
+	Search(ctx context.Context, search model.TaskSearch, all bool) (model.TaskSearchResults, error)
+	Query(ctx context.Context, tq model.TaskQuery, now time.Time) ([]model.Task, error)
+	SetParent(ctx context.Context, taskID string, sp model.SetParent, now time.Time) (model.Task, error)
+	Link(ctx context.Context, taskID string, nl model.NewTaskLink, now time.Time) (model.Task, error)
+	Unlink(ctx context.Context, taskID string, linkID string, now time.Time) error
+}
+
+type commentService interface {
+	Create(ctx context.Context, nc model.NewComment, taskID string, now time.Time) (model.Comment, error)
+	List(ctx context.Context, taskID string) ([]model.Comment, error)
+	Retrieve(ctx context.Context, commentID string) (model.Comment, error)
+	Update(ctx context.Context, taskID, commentID string, update model.UpdateComment, now time.Time) (model.Comment, error)
+	Delete(ctx context.Context, taskID, commentID string) error
+}
+
+type labelService interface {
+	List(ctx context.Context, projectID string) ([]model.Label, error)
+	Create(ctx context.Context, projectID string, nl model.NewLabel, now time.Time) (model.Label, error)
+	Update(ctx context.Context, labelID string, update model.UpdateLabel, now time.Time) (model.Label, error)
+	Delete(ctx context.Context, labelID string) error
+}
+
+type fieldService interface {
+	List(ctx context.Context, projectID string) ([]model.CustomField, error)
+	Create(ctx context.Context, projectID string, nf model.NewCustomField, now time.Time) (model.CustomField, error)
+	Update(ctx context.Context, projectID string, fieldID string, update model.UpdateCustomField, now time.Time) (model.CustomField, error)
+	Delete(ctx context.Context, projectID string, fieldID string) error
+}
+
+type worklogService interface {
+	List(ctx context.Context, taskID string) ([]model.Worklog, error)
+	Create(ctx context.Context, taskID string, nw model.NewWorklog, now time.Time) (model.Worklog, error)
+	Update(ctx context.Context, taskID string, worklogID string, update model.UpdateWorklog, now time.Time) (model.Worklog, error)
+	Delete(ctx context.Context, taskID string, worklogID string) error
+	Timer(ctx context.Context) (model.Timer, error)
+	StartTimer(ctx context.Context, taskID string, now time.Time) (model.Timer, error)
+	StopTimer(ctx context.Context, st model.StopTimer, now time.Time) (model.Worklog, error)
+	DiscardTimer(ctx context.Context) error
+	Report(ctx context.Context, filter model.WorklogFilter) (model.TimeReport, error)
+	Entries(ctx context.Context, filter model.WorklogFilter) ([]model.WorklogEntry, error)
+}
+
+type recurrenceService interface {
+	List(ctx context.Context, projectID string) ([]model.RecurringTask, error)
+	Create(ctx context.Context, projectID string, nr model.NewRecurringTask, now time.Time) (model.RecurringTask, error)
+	Update(ctx context.Context, recurrenceID string, update model.UpdateRecurringTask, now time.Time) (model.RecurringTask, error)
+	Delete(ctx context.Context, recurrenceID string) error
+}
+
+type notificationService interface {
+	List(ctx context.Context, page model.InboxPage) (model.Inbox, error)
+	Update(ctx context.Context, notificationID string, update model.UpdateNotification, now time.Time) (model.Notification, error)
+	ReadAll(ctx context.Context, now time.Time) (int, error)
+	Preferences(ctx context.Context) (model.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, update model.UpdateNotificationPreferences, now time.Time) (model.NotificationPreferences, error)
+	Watchers(ctx context.Context, taskID string) ([]model.Watcher, error)
+	Watch(ctx context.Context, taskID string, now time.Time) (model.Watcher, error)
+	Unwatch(ctx context.Context, taskID string) error
+}
+
+type activityService interface {
+	ListByTask(ctx context.Context, taskID string, page model.ActivityPage) ([]model.TaskEvent, error)
+	ListByProject(ctx context.Context, projectID string, page model.ActivityPage) ([]model.TaskEvent, error)
+}
+
+type sprintService interface {
+	Retrieve(ctx context.Context, projectID string, sprintID string) (model.Sprint, error)
+	List(ctx context.Context, projectID string) ([]model.Sprint, error)
+	Create(ctx context.Context, projectID string, ns model.NewSprint, now time.Time) (model.Sprint, error)
+	Start(ctx context.Context, projectID string, sprintID string, start model.StartSprint, now time.Time) (model.Sprint, error)
+	Complete(ctx context.Context, projectID string, sprintID string, cs model.CompleteSprint, now time.Time) (model.Sprint, error)
+	AddTasks(ctx context.Context, projectID string, sprintID string, st model.SprintTasks, now time.Time) (model.Sprint, error)
+	RemoveTask(ctx context.Context, projectID string, sprintID string, taskID string, now time.Time) error
+	Burndown(ctx context.Context, projectID string, sprintID string, now time.Time) (model.Burndown, error)
+}
+
+type templateService interface {
+	List(ctx context.Context) ([]model.Template, error)
+	Retrieve(ctx context.Context, templateID string) (model.Template, error)
+	Create(ctx context.Context, nt model.NewTemplate, now time.Time) (model.Template, error)
+	SaveProject(ctx context.Context, projectID string, st model.SaveTemplate, now time.Time) (model.Template, error)
+	Delete(ctx context.Context, templateID string) error
+	Apply(ctx context.Context, projectID string, t model.Template, now time.Time) error
+}
+
+type transferService interface {
+	Export(ctx context.Context, projectID string, now time.Time) (bundle.Bundle, error)
+	Import(ctx context.Context, ni model.NewImport, now time.Time) (model.Import, error)
+	RetrieveImport(ctx context.Context, importID string) (model.Import, error)
+}
+
+type memberService interface {
+	List(ctx context.Context, projectID string) ([]model.ProjectMember, error)
+	Add(ctx context.Context, projectID string, nm model.NewProjectMember, now time.Time) (model.ProjectMember, error)
+	Update(ctx context.Context, projectID string, userID string, um model.UpdateProjectMember, now time.Time) (model.ProjectMember, error)
+	Remove(ctx context.Context, projectID string, userID string, now time.Time) error
+	AssignTeam(ctx context.Context, projectID string, at model.AssignTeam, now time.Time) (model.Project, error)
+}
+
+type teamService interface {
+	List(ctx context.Context) ([]model.Team, error)
+	Retrieve(ctx context.Context, teamID string) (model.Team, error)
+	Create(ctx context.Context, nt model.NewTeam, now time.Time) (model.Team, error)
+	Delete(ctx context.Context, teamID string, now time.Time) error
+	AddMember(ctx context.Context, teamID string, nm model.NewTeamMember, now time.Time) (model.Team, error)
+	RemoveMember(ctx context.Context, teamID string, userID string, now time.Time) error
+}
+
+type filterService interface {
+	Retrieve(ctx context.Context, filterID string) (model.SavedFilter, error)
+	List(ctx context.Context, projectID string) ([]model.SavedFilter, error)
+	Create(ctx context.Context, nf model.NewSavedFilter, now time.Time) (model.SavedFilter, error)
+	Update(ctx context.Context, filterID string, update model.UpdateSavedFilter, now time.Time) (model.SavedFilter, error)
+	Delete(ctx context.Context, filterID string) error
+}
+
+type streamService interface {
+	Subscribe(ctx context.Context, projectID string, lastEventID string) (*stream.Subscription, error)
+}
+
+type shareService interface {
+	Create(ctx context.Context, projectID string, ns model.NewShareLink, now time.Time) (model.ShareLink, error)
+	List(ctx context.Context, projectID string) ([]model.ShareLink, error)
+	Revoke(ctx context.Context, projectID string, shareID string, now time.Time) (model.ShareLink, error)
+	Board(ctx context.Context, token string, now time.Time) (model.SharedBoard, error)
 }
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// LabelHandler handles the project label requests.
+type LabelHandler struct {
+	logger       *zap.Logger
+	labelService labelService
+}
+
+// NewLabelHandler returns a new label handler.
+func NewLabelHandler(
+	logger *zap.Logger,
+	labelService labelService,
+) *LabelHandler {
+	return &LabelHandler{
+		logger:       logger,
+		labelService: labelService,
+	}
+}
+
+// List handles list label requests.
+func (lh *LabelHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := lh.labelService.List(r.Context(), pid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing labels for project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Create handles create label requests.
+func (lh *LabelHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var nl model.NewLabel
+	if err := web.Decode(r, &nl); err != nil {
+		return err
+	}
+
+	l, err := lh.labelService.Create(r.Context(), pid, nl, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateLabel:
+			return web.NewRequestError(err, http.StatusConflict)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error creating label for project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, l, http.StatusCreated)
+}
+
+// Update handles update label requests.
+func (lh *LabelHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	lid := chi.URLParam(r, "lid")
+
+	var ul model.UpdateLabel
+	if err := web.Decode(r, &ul); err != nil {
+		return err
+	}
+
+	l, err := lh.labelService.Update(r.Context(), lid, ul, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateLabel:
+			return web.NewRequestError(err, http.StatusConflict)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error updating label %q: %w", lid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, l, http.StatusOK)
+}
+
+// Delete handles delete label requests.
+func (lh *LabelHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	lid := chi.URLParam(r, "lid")
+
+	if err := lh.labelService.Delete(r.Context(), lid); err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error deleting label %q: %w", lid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
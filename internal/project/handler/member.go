@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// MemberHandler handles the project member requests.
+type MemberHandler struct {
+	logger        *zap.Logger
+	memberService memberService
+}
+
+// NewMemberHandler returns a new member handler.
+func NewMemberHandler(
+	logger *zap.Logger,
+	memberService memberService,
+) *MemberHandler {
+	return &MemberHandler{
+		logger:        logger,
+		memberService: memberService,
+	}
+}
+
+// List handles list project member requests.
+func (mh *MemberHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := mh.memberService.List(r.Context(), pid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing members of project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Add handles add project member requests.
+func (mh *MemberHandler) Add(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var nm model.NewProjectMember
+	if err := web.Decode(r, &nm); err != nil {
+		return err
+	}
+
+	m, err := mh.memberService.Add(r.Context(), pid, nm, time.Now())
+	if err != nil {
+		return memberError(err, fmt.Sprintf("error adding member to project %q", pid))
+	}
+
+	return web.Respond(r.Context(), w, m, http.StatusCreated)
+}
+
+// Update handles update project member requests.
+func (mh *MemberHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := chi.URLParam(r, "uid")
+
+	var um model.UpdateProjectMember
+	if err := web.Decode(r, &um); err != nil {
+		return err
+	}
+
+	m, err := mh.memberService.Update(r.Context(), pid, uid, um, time.Now())
+	if err != nil {
+		return memberError(err, fmt.Sprintf("error updating member %q of project %q", uid, pid))
+	}
+
+	return web.Respond(r.Context(), w, m, http.StatusOK)
+}
+
+// Remove handles remove project member requests.
+func (mh *MemberHandler) Remove(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	uid := chi.URLParam(r, "uid")
+
+	if err := mh.memberService.Remove(r.Context(), pid, uid, time.Now()); err != nil {
+		return memberError(err, fmt.Sprintf("error removing member %q of project %q", uid, pid))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// AssignTeam handles assign team requests.
+func (mh *MemberHandler) AssignTeam(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var at model.AssignTeam
+	if err := web.Decode(r, &at); err != nil {
+		return err
+	}
+
+	p, err := mh.memberService.AssignTeam(r.Context(), pid, at, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrInvalidTeam:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return memberError(err, fmt.Sprintf("error assigning team to project %q", pid))
+		}
+	}
+
+	return web.Respond(r.Context(), w, p, http.StatusOK)
+}
+
+// memberError maps the errors of membership changes to responses.
+func memberError(err error, msg string) error {
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	case fail.ErrLastOwner:
+		return web.NewRequestError(err, http.StatusConflict)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// NotificationHandler handles the notification and task watcher requests.
+type NotificationHandler struct {
+	logger              *zap.Logger
+	notificationService notificationService
+}
+
+// NewNotificationHandler returns a new notification handler.
+func NewNotificationHandler(
+	logger *zap.Logger,
+	notificationService notificationService,
+) *NotificationHandler {
+	return &NotificationHandler{
+		logger:              logger,
+		notificationService: notificationService,
+	}
+}
+
+// List handles inbox requests.
+func (nh *NotificationHandler) List(w http.ResponseWriter, r *http.Request) error {
+	page, err := parseInboxPage(r)
+	if err != nil {
+		return web.NewRequestError(fail.ErrInvalidPage, http.StatusBadRequest)
+	}
+
+	inbox, err := nh.notificationService.List(r.Context(), page)
+	if err != nil {
+		return notificationError(err, "error listing notifications")
+	}
+
+	return web.Respond(r.Context(), w, inbox, http.StatusOK)
+}
+
+// Update handles requests marking a notification as read or unread.
+func (nh *NotificationHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	nid := chi.URLParam(r, "nid")
+
+	var un model.UpdateNotification
+	if err := web.Decode(r, &un); err != nil {
+		return err
+	}
+
+	n, err := nh.notificationService.Update(r.Context(), nid, un, time.Now())
+	if err != nil {
+		return notificationError(err, fmt.Sprintf("error updating notification %q", nid))
+	}
+
+	return web.Respond(r.Context(), w, n, http.StatusOK)
+}
+
+// ReadAll handles requests marking every notification as read.
+func (nh *NotificationHandler) ReadAll(w http.ResponseWriter, r *http.Request) error {
+	n, err := nh.notificationService.ReadAll(r.Context(), time.Now())
+	if err != nil {
+		return notificationError(err, "error reading notifications")
+	}
+
+	return web.Respond(r.Context(), w, model.ReadNotifications{Read: n}, http.StatusOK)
+}
+
+// Preferences handles notification preferences requests.
+func (nh *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) error {
+	np, err := nh.notificationService.Preferences(r.Context())
+	if err != nil {
+		return notificationError(err, "error retrieving notification preferences")
+	}
+
+	return web.Respond(r.Context(), w, np, http.StatusOK)
+}
+
+// UpdatePreferences handles update notification preferences requests.
+func (nh *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) error {
+	var up model.UpdateNotificationPreferences
+	if err := web.Decode(r, &up); err != nil {
+		return err
+	}
+
+	np, err := nh.notificationService.UpdatePreferences(r.Context(), up, time.Now())
+	if err != nil {
+		return notificationError(err, "error updating notification preferences")
+	}
+
+	return web.Respond(r.Context(), w, np, http.StatusOK)
+}
+
+// Watchers handles list task watcher requests.
+func (nh *NotificationHandler) Watchers(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	list, err := nh.notificationService.Watchers(r.Context(), tid)
+	if err != nil {
+		return notificationError(err, fmt.Sprintf("error listing watchers of task %q", tid))
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Watch handles watch task requests.
+func (nh *NotificationHandler) Watch(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	watcher, err := nh.notificationService.Watch(r.Context(), tid, time.Now())
+	if err != nil {
+		return notificationError(err, fmt.Sprintf("error watching task %q", tid))
+	}
+
+	return web.Respond(r.Context(), w, watcher, http.StatusOK)
+}
+
+// Unwatch handles unwatch task requests.
+func (nh *NotificationHandler) Unwatch(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	if err := nh.notificationService.Unwatch(r.Context(), tid); err != nil {
+		return notificationError(err, fmt.Sprintf("error unwatching task %q", tid))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// parseInboxPage reads and validates the unread, limit and offset query parameters.
+func parseInboxPage(r *http.Request) (model.InboxPage, error) {
+	var err error
+
+	q := r.URL.Query()
+	page := model.InboxPage{Limit: model.DefaultNotificationLimit}
+
+	if v := q.Get("unread"); v != "" {
+		if page.Unread, err = strconv.ParseBool(v); err != nil {
+			return page, err
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if page.Limit, err = strconv.Atoi(v); err != nil {
+			return page, err
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if page.Offset, err = strconv.Atoi(v); err != nil {
+			return page, err
+		}
+	}
+
+	return page, page.Validate()
+}
+
+func notificationError(err error, msg string) error {
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
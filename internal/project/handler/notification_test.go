@@ -0,0 +1,141 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestNotificationHandler_List(t *testing.T) {
+	inbox := model.Inbox{
+		Notifications: []model.Notification{
+			{
+				ID:        "1c3a8b4e-5f2d-4c6b-9a7e-0d8f6b2c4a1e",
+				UserID:    testProjects[0].UserID,
+				Reason:    model.ReasonMentioned,
+				EventID:   "6f1d2c3b-4a5e-4f6d-8c7b-9a0e1d2c3b4a",
+				EventKind: model.TaskCommented,
+				TaskKey:   "API-1",
+				TaskTitle: "Ship notifications",
+				CreatedAt: time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		Unread: 1,
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		page   model.InboxPage
+		status int
+	}{
+		{"success", "", model.InboxPage{Limit: model.DefaultNotificationLimit}, http.StatusOK},
+		{"success unread", "?unread=true&limit=10&offset=10", model.InboxPage{Unread: true, Limit: 10, Offset: 10}, http.StatusOK},
+		{"error 400 unread", "?unread=maybe", model.InboxPage{}, http.StatusBadRequest},
+		{"error 400 limit", "?limit=500", model.InboxPage{}, http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handle, deps := setupNotificationRouter()
+
+			r := httptest.NewRequest(http.MethodGet, "/notifications"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			if tc.status == http.StatusOK {
+				deps.notificationService.On("List", mock.AnythingOfType("*context.valueCtx"), tc.page).Return(inbox, nil)
+			}
+
+			handle.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.status != http.StatusOK {
+				deps.notificationService.AssertNotCalled(t, "List")
+				return
+			}
+
+			expected, err := json.Marshal(&inbox)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, w.Body.Bytes())
+			deps.notificationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_Watch(t *testing.T) {
+	tid := "4e3b3f4a-2a8f-4b8e-9a36-64b1b1c2e3d4"
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupNotificationRouter()
+
+		watcher := model.Watcher{TaskID: tid, UserID: testProjects[0].UserID, CreatedAt: time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)}
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/tasks/"+tid+"/watchers", nil)
+		w := httptest.NewRecorder()
+
+		deps.notificationService.On("Watch", mock.AnythingOfType("*context.valueCtx"), tid, mock.AnythingOfType("time.Time")).Return(watcher, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&watcher)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.notificationService.AssertExpectations(t)
+	})
+
+	t.Run("error 404", func(t *testing.T) {
+		handle, deps := setupNotificationRouter()
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/tasks/"+tid+"/watchers", nil)
+		w := httptest.NewRecorder()
+
+		deps.notificationService.On("Watch", mock.AnythingOfType("*context.valueCtx"), tid, mock.AnythingOfType("time.Time")).Return(model.Watcher{}, fail.ErrNotFound)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.notificationService.AssertExpectations(t)
+	})
+}
+
+type notificationHandlerDeps struct {
+	logger              *zap.Logger
+	notificationService *mocks.NotificationService
+}
+
+func setupNotificationRouter() (http.Handler, notificationHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	notificationService := &mocks.NotificationService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	notifications := handler.NewNotificationHandler(logger, notificationService)
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodGet, "/notifications", notifications.List)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/watchers", notifications.Watch)
+
+	return router, notificationHandlerDeps{logger, notificationService}
+}
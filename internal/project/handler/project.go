@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,50 +18,64 @@ import (
 )
 
 type projectService interface {
-	List(ctx context.Context, all bool) ([]model.Project, error)
+	List(ctx context.Context, all bool, archived bool) (model.ProjectList, error)
 	Retrieve(ctx context.Context, projectID string) (model.Project, error)
 	Create(ctx context.Context, project model.NewProject, now time.Time) (model.Project, error)
 	Update(ctx context.Context, projectID string, update model.UpdateProject, now time.Time) (model.Project, error)
-	Delete(ctx context.Context, projectID string) error
+	Delete(ctx context.Context, projectID string, now time.Time) error
+	Restore(ctx context.Context, projectID string, now time.Time) (model.Project, error)
+	Trash(ctx context.Context) (model.Trash, error)
 }
 
 // ProjectHandler handles the project requests.
 type ProjectHandler struct {
-	logger         *zap.Logger
-	projectService projectService
-	columnService  columnService
-	taskService    taskService
+	logger          *zap.Logger
+	projectService  projectService
+	templateService templateService
+	taskService     taskService
 }
 
 // NewProjectHandler returns a new project handler.
 func NewProjectHandler(
 	logger *zap.Logger,
 	projectService projectService,
-	columnService columnService,
+	templateService templateService,
 	taskService taskService,
 ) *ProjectHandler {
 	return &ProjectHandler{
-		logger:         logger,
-		projectService: projectService,
-		columnService:  columnService,
-		taskService:    taskService,
+		logger:          logger,
+		projectService:  projectService,
+		templateService: templateService,
+		taskService:     taskService,
 	}
 }
 
-// List handles project list requests.
+// List handles project list requests. Archived projects are listed when the archived
+// query parameter is true. Projects listed across tenants come with the tenants that
+// failed, while the projects of a single tenant are listed as they are.
 func (ph *ProjectHandler) List(w http.ResponseWriter, r *http.Request) error {
-	var all bool
+	var all, archived bool
 
 	path := r.Header.Get("BasePath")
 	if strings.ToLower(path) == "projects" {
 		all = true
 	}
 
-	list, err := ph.projectService.List(r.Context(), all)
+	if v := r.URL.Query().Get("archived"); v != "" {
+		var err error
+		if archived, err = strconv.ParseBool(v); err != nil {
+			return web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+		}
+	}
+
+	list, err := ph.projectService.List(r.Context(), all, archived)
 	if err != nil {
 		return err
 	}
 
+	if !all {
+		return web.Respond(r.Context(), w, list.Projects, http.StatusOK)
+	}
 	return web.Respond(r.Context(), w, list, http.StatusOK)
 }
 
@@ -94,14 +109,28 @@ func (ph *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) error {
 		return web.NewRequestError(err, http.StatusBadRequest)
 	}
 
+	tmpl, err := ph.templateService.Retrieve(r.Context(), np.TemplateID)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error retrieving template %q :%w", np.TemplateID, err)
+		}
+	}
+
 	project, err := ph.projectService.Create(r.Context(), np, time.Now())
 	if err != nil {
 		return err
 	}
-	err = ph.columnService.CreateColumns(r.Context(), project.ID, time.Now())
+	err = ph.templateService.Apply(r.Context(), project.ID, tmpl, time.Now())
 	if err != nil {
 		return err
 	}
+	project.ColumnOrder = tmpl.ColumnOrder()
+
 	return web.Respond(r.Context(), w, project, http.StatusCreated)
 }
 
@@ -120,8 +149,10 @@ func (ph *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) error {
 		switch err {
 		case fail.ErrNotFound:
 			return web.NewRequestError(err, http.StatusNotFound)
-		case fail.ErrInvalidID:
+		case fail.ErrInvalidID, fail.ErrInvalidColumnOrder:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
 		default:
 			return fmt.Errorf("error updating project %q: %w", pid, err)
 		}
@@ -130,18 +161,18 @@ func (ph *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) error {
 	return web.Respond(r.Context(), w, up, http.StatusOK)
 }
 
-// Delete handles project delete requests.
+// Delete handles project delete requests, moving the project to the trash.
 func (ph *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) error {
-	var err error
 	pid := chi.URLParam(r, "pid")
 
-	if _, err = ph.projectService.Retrieve(r.Context(), pid); err != nil {
-		return err
-	}
-	if err = ph.projectService.Delete(r.Context(), pid); err != nil {
+	if err := ph.projectService.Delete(r.Context(), pid, time.Now()); err != nil {
 		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
 		case fail.ErrInvalidID:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
 		default:
 			return fmt.Errorf("error deleting project %q: %w", pid, err)
 		}
@@ -149,3 +180,34 @@ func (ph *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) error {
 
 	return web.Respond(r.Context(), w, nil, http.StatusOK)
 }
+
+// Restore handles requests taking a project out of the trash.
+func (ph *ProjectHandler) Restore(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	project, err := ph.projectService.Restore(r.Context(), pid, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error restoring project %q: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, project, http.StatusOK)
+}
+
+// Trash handles requests listing the projects and tasks in the trash.
+func (ph *ProjectHandler) Trash(w http.ResponseWriter, r *http.Request) error {
+	trash, err := ph.projectService.Trash(r.Context())
+	if err != nil {
+		return fmt.Errorf("error listing trash: %w", err)
+	}
+
+	return web.Respond(r.Context(), w, trash, http.StatusOK)
+}
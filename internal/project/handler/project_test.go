@@ -8,6 +8,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/devpies/saas-core/internal/project/fail"
 	"github.com/devpies/saas-core/internal/project/handler"
 	"github.com/devpies/saas-core/internal/project/mocks"
 	"github.com/devpies/saas-core/internal/project/model"
@@ -23,6 +24,7 @@ import (
 
 func TestProjectHandler_Create(t *testing.T) {
 	basePath := "/projects"
+	template := model.BuiltinTemplates()[0]
 
 	t.Run("success", func(t *testing.T) {
 		handle, deps := setupProjectRouter()
@@ -41,17 +43,19 @@ func TestProjectHandler_Create(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPost, basePath, bytes.NewReader(b))
 		w := httptest.NewRecorder()
 
+		deps.templateService.On("Retrieve", mock.AnythingOfType("*context.valueCtx"), "").Return(template, nil)
 		deps.projectService.On("Create", mock.AnythingOfType("*context.valueCtx"), np, mock.AnythingOfType("time.Time")).Return(project, nil)
-		deps.columnService.On("CreateColumns", mock.AnythingOfType("*context.valueCtx"), project.ID, mock.AnythingOfType("time.Time")).Return(nil)
+		deps.templateService.On("Apply", mock.AnythingOfType("*context.valueCtx"), project.ID, template, mock.AnythingOfType("time.Time")).Return(nil)
 
 		handle.ServeHTTP(w, r)
 
+		project.ColumnOrder = []string{"column-1", "column-2", "column-3", "column-4"}
 		expectedProject, err := json.Marshal(&project)
 		assert.Nil(t, err)
 		assert.Equal(t, expectedProject, w.Body.Bytes())
 		assert.Equal(t, http.StatusCreated, w.Code)
 		deps.projectService.AssertExpectations(t)
-		deps.columnService.AssertExpectations(t)
+		deps.templateService.AssertExpectations(t)
 	})
 
 	t.Run("error 400", func(t *testing.T) {
@@ -92,6 +96,10 @@ func TestProjectHandler_Create(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPost, basePath, bytes.NewReader(b))
 		w := httptest.NewRecorder()
 
+		deps.templateService.
+			On("Retrieve", mock.AnythingOfType("*context.valueCtx"), "").
+			Return(template, nil)
+
 		deps.projectService.
 			On("Create", mock.AnythingOfType("*context.valueCtx"), np, mock.AnythingOfType("time.Time")).
 			Return(model.Project{}, assert.AnError)
@@ -105,7 +113,38 @@ func TestProjectHandler_Create(t *testing.T) {
 		deps.projectService.AssertExpectations(t)
 	})
 
-	t.Run("error 500 column service", func(t *testing.T) {
+	t.Run("error 404 template", func(t *testing.T) {
+		handle, deps := setupProjectRouter()
+
+		np := model.NewProject{
+			Name:       "My Project",
+			TemplateID: testutils.MockUUID,
+		}
+		response := web.ErrorResponse{
+			Error: fail.ErrNotFound.Error(),
+		}
+
+		b, err := json.Marshal(&np)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, basePath, bytes.NewReader(b))
+		w := httptest.NewRecorder()
+
+		deps.templateService.
+			On("Retrieve", mock.AnythingOfType("*context.valueCtx"), np.TemplateID).
+			Return(model.Template{}, fail.ErrNotFound)
+
+		handle.ServeHTTP(w, r)
+
+		expectedResponse, err := json.Marshal(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResponse, w.Body.Bytes())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.projectService.AssertNotCalled(t, "Create")
+		deps.templateService.AssertExpectations(t)
+	})
+
+	t.Run("error 500 template service", func(t *testing.T) {
 		handle, deps := setupProjectRouter()
 
 		np := model.NewProject{
@@ -125,12 +164,16 @@ func TestProjectHandler_Create(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPost, basePath, bytes.NewReader(b))
 		w := httptest.NewRecorder()
 
+		deps.templateService.
+			On("Retrieve", mock.AnythingOfType("*context.valueCtx"), "").
+			Return(template, nil)
+
 		deps.projectService.
 			On("Create", mock.AnythingOfType("*context.valueCtx"), np, mock.AnythingOfType("time.Time")).
 			Return(project, nil)
 
-		deps.columnService.
-			On("CreateColumns", mock.AnythingOfType("*context.valueCtx"), project.ID, mock.AnythingOfType("time.Time")).
+		deps.templateService.
+			On("Apply", mock.AnythingOfType("*context.valueCtx"), project.ID, template, mock.AnythingOfType("time.Time")).
 			Return(assert.AnError)
 
 		handle.ServeHTTP(w, r)
@@ -147,18 +190,19 @@ func TestProjectHandler_List(t *testing.T) {
 	basePath := "/projects"
 
 	t.Run("success", func(t *testing.T) {
-		var projects []model.Project
+		projects := model.ProjectList{Projects: []model.Project{}, Failures: []model.TenantFailure{}}
 
 		handle, deps := setupProjectRouter()
 
 		r := httptest.NewRequest(http.MethodGet, basePath, nil)
 		w := httptest.NewRecorder()
 
-		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), false).Return(projects, nil)
+		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), false, false).Return(projects, nil)
 
 		handle.ServeHTTP(w, r)
 
-		expectedProjects, err := json.Marshal(&projects)
+		// The projects of a single tenant are listed as a plain array.
+		expectedProjects, err := json.Marshal(&projects.Projects)
 		assert.Nil(t, err)
 		assert.Equal(t, expectedProjects, w.Body.Bytes())
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -166,7 +210,10 @@ func TestProjectHandler_List(t *testing.T) {
 	})
 
 	t.Run("success all tenants", func(t *testing.T) {
-		var projects []model.Project
+		projects := model.ProjectList{
+			Projects: []model.Project{{ID: testutils.MockUUID}},
+			Failures: []model.TenantFailure{{TenantID: testutils.MockUUID, CompanyName: "Acme", Error: "timed out"}},
+		}
 
 		handle, deps := setupProjectRouter()
 
@@ -175,7 +222,7 @@ func TestProjectHandler_List(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), true).Return(projects, nil)
+		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), true, false).Return(projects, nil)
 
 		handle.ServeHTTP(w, r)
 
@@ -186,6 +233,37 @@ func TestProjectHandler_List(t *testing.T) {
 		deps.projectService.AssertExpectations(t)
 	})
 
+	t.Run("success archived", func(t *testing.T) {
+		projects := model.ProjectList{Projects: []model.Project{{ID: testutils.MockUUID}}, Failures: []model.TenantFailure{}}
+
+		handle, deps := setupProjectRouter()
+
+		r := httptest.NewRequest(http.MethodGet, basePath+"?archived=true", nil)
+		w := httptest.NewRecorder()
+
+		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), false, true).Return(projects, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expectedProjects, err := json.Marshal(&projects.Projects)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedProjects, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.projectService.AssertExpectations(t)
+	})
+
+	t.Run("error 400 archived", func(t *testing.T) {
+		handle, deps := setupProjectRouter()
+
+		r := httptest.NewRequest(http.MethodGet, basePath+"?archived=maybe", nil)
+		w := httptest.NewRecorder()
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		deps.projectService.AssertExpectations(t)
+	})
+
 	t.Run("error 500", func(t *testing.T) {
 		response := web.ErrorResponse{
 			Error: http.StatusText(http.StatusInternalServerError),
@@ -198,7 +276,7 @@ func TestProjectHandler_List(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), true).Return(nil, assert.AnError)
+		deps.projectService.On("List", mock.AnythingOfType("*context.valueCtx"), true, false).Return(model.ProjectList{}, assert.AnError)
 
 		handle.ServeHTTP(w, r)
 
@@ -210,18 +288,86 @@ func TestProjectHandler_List(t *testing.T) {
 	})
 }
 
+func TestProjectHandler_Delete(t *testing.T) {
+	basePath := "/projects/" + testutils.MockUUID
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "error 403", err: fail.ErrNotAuthorized, status: http.StatusForbidden},
+		{name: "error 404", err: fail.ErrNotFound, status: http.StatusNotFound},
+		{name: "error 500", err: assert.AnError, status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handle, deps := setupProjectRouter()
+
+			r := httptest.NewRequest(http.MethodDelete, basePath, nil)
+			w := httptest.NewRecorder()
+
+			deps.projectService.On("Delete", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, mock.AnythingOfType("time.Time")).Return(tc.err)
+
+			handle.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			deps.projectService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectHandler_Restore(t *testing.T) {
+	basePath := "/projects/" + testutils.MockUUID + "/restore"
+
+	t.Run("success", func(t *testing.T) {
+		project := model.Project{ID: testutils.MockUUID, Active: true}
+
+		handle, deps := setupProjectRouter()
+
+		r := httptest.NewRequest(http.MethodPatch, basePath, nil)
+		w := httptest.NewRecorder()
+
+		deps.projectService.On("Restore", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, mock.AnythingOfType("time.Time")).Return(project, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&project)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.projectService.AssertExpectations(t)
+	})
+
+	t.Run("error 404", func(t *testing.T) {
+		handle, deps := setupProjectRouter()
+
+		r := httptest.NewRequest(http.MethodPatch, basePath, nil)
+		w := httptest.NewRecorder()
+
+		deps.projectService.On("Restore", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, mock.AnythingOfType("time.Time")).Return(model.Project{}, fail.ErrNotFound)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.projectService.AssertExpectations(t)
+	})
+}
+
 type projectHandlerDeps struct {
-	logger         *zap.Logger
-	projectService *mocks.ProjectService
-	columnService  *mocks.ColumnService
-	taskService    *mocks.TaskService
+	logger          *zap.Logger
+	projectService  *mocks.ProjectService
+	templateService *mocks.TemplateService
+	taskService     *mocks.TaskService
 }
 
 func setupProjectRouter() (http.Handler, projectHandlerDeps) {
 	router := chi.NewRouter()
 	logger := zap.NewNop()
 	projectService := &mocks.ProjectService{}
-	columnService := &mocks.ColumnService{}
+	templateService := &mocks.TemplateService{}
 	taskService := &mocks.TaskService{}
 	shutdown := make(chan os.Signal, 1)
 
@@ -231,11 +377,13 @@ func setupProjectRouter() (http.Handler, projectHandlerDeps) {
 		mid.Panics(logger),
 	}
 
-	projects := handler.NewProjectHandler(logger, projectService, columnService, taskService)
+	projects := handler.NewProjectHandler(logger, projectService, templateService, taskService)
 
 	app := web.NewApp(router, shutdown, logger, middleware...)
 	app.Handle(http.MethodPost, "/projects", projects.Create)
 	app.Handle(http.MethodGet, "/projects", projects.List)
+	app.Handle(http.MethodDelete, "/projects/{pid}", projects.Delete)
+	app.Handle(http.MethodPatch, "/projects/{pid}/restore", projects.Restore)
 
-	return router, projectHandlerDeps{logger, projectService, columnService, taskService}
+	return router, projectHandlerDeps{logger, projectService, templateService, taskService}
 }
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/rrule"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// RecurrenceHandler handles the recurring task requests.
+type RecurrenceHandler struct {
+	logger            *zap.Logger
+	recurrenceService recurrenceService
+}
+
+// NewRecurrenceHandler returns a new recurrence handler.
+func NewRecurrenceHandler(
+	logger *zap.Logger,
+	recurrenceService recurrenceService,
+) *RecurrenceHandler {
+	return &RecurrenceHandler{
+		logger:            logger,
+		recurrenceService: recurrenceService,
+	}
+}
+
+// List handles list recurring task requests.
+func (rh *RecurrenceHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := rh.recurrenceService.List(r.Context(), pid)
+	if err != nil {
+		return recurrenceError(err, fmt.Sprintf("error listing recurring tasks of project %q", pid))
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Create handles create recurring task requests.
+func (rh *RecurrenceHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var nr model.NewRecurringTask
+	if err := web.Decode(r, &nr); err != nil {
+		return err
+	}
+
+	rt, err := rh.recurrenceService.Create(r.Context(), pid, nr, time.Now())
+	if err != nil {
+		return recurrenceError(err, fmt.Sprintf("error creating recurring task in project %q", pid))
+	}
+
+	return web.Respond(r.Context(), w, rt, http.StatusCreated)
+}
+
+// Update handles update recurring task requests.
+func (rh *RecurrenceHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	rid := chi.URLParam(r, "rid")
+
+	var ur model.UpdateRecurringTask
+	if err := web.Decode(r, &ur); err != nil {
+		return err
+	}
+
+	rt, err := rh.recurrenceService.Update(r.Context(), rid, ur, time.Now())
+	if err != nil {
+		return recurrenceError(err, fmt.Sprintf("error updating recurring task %q", rid))
+	}
+
+	return web.Respond(r.Context(), w, rt, http.StatusOK)
+}
+
+// Delete handles delete recurring task requests.
+func (rh *RecurrenceHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	rid := chi.URLParam(r, "rid")
+
+	if err := rh.recurrenceService.Delete(r.Context(), rid); err != nil {
+		return recurrenceError(err, fmt.Sprintf("error deleting recurring task %q", rid))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// recurrenceError maps recurring task errors to request errors. Rules that cannot be
+// parsed are reported on the rule field.
+func recurrenceError(err error, msg string) error {
+	var re *rrule.Error
+	if errors.As(err, &re) {
+		fields := []web.FieldError{{Field: "rule", Error: re.Error()}}
+		return &web.Error{Err: fail.ErrInvalidRule, Status: http.StatusBadRequest, Fields: fields}
+	}
+
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -0,0 +1,154 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/rrule"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestRecurrenceHandler_Create(t *testing.T) {
+	pid := testProjects[0].ID
+	nr := model.NewRecurringTask{
+		ColumnID: "a4a420db-c6d6-4209-af96-5e5408a57bfe",
+		Title:    "Rotate certificates",
+		Rule:     "FREQ=MONTHLY;BYMONTHDAY=1",
+		StartsAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupRecurrenceRouter()
+
+		next := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+		rt := model.RecurringTask{
+			ID:        "7bb6c1b4-1c2e-4f3a-9b53-7c7c2f0f6b8e",
+			ProjectID: pid,
+			ColumnID:  nr.ColumnID,
+			Title:     nr.Title,
+			Priority:  model.PriorityNone,
+			Rule:      nr.Rule,
+			StartsAt:  nr.StartsAt,
+			NextRunAt: &next,
+		}
+
+		body, err := json.Marshal(&nr)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/"+pid+"/recurring", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		deps.recurrenceService.On("Create", mock.AnythingOfType("*context.valueCtx"), pid, nr, mock.AnythingOfType("time.Time")).Return(rt, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&rt)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusCreated, w.Code)
+		deps.recurrenceService.AssertExpectations(t)
+	})
+
+	t.Run("error 400 rule", func(t *testing.T) {
+		handle, deps := setupRecurrenceRouter()
+
+		invalid := nr
+		invalid.Rule = "FREQ=HOURLY"
+		_, rerr := rrule.Parse(invalid.Rule)
+		response := web.ErrorResponse{
+			Error:  fail.ErrInvalidRule.Error(),
+			Fields: []web.FieldError{{Field: "rule", Error: "FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY"}},
+		}
+
+		body, err := json.Marshal(&invalid)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/"+pid+"/recurring", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		deps.recurrenceService.On("Create", mock.AnythingOfType("*context.valueCtx"), pid, invalid, mock.AnythingOfType("time.Time")).Return(model.RecurringTask{}, rerr)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		deps.recurrenceService.AssertExpectations(t)
+	})
+
+	t.Run("error 400 missing title", func(t *testing.T) {
+		handle, deps := setupRecurrenceRouter()
+
+		invalid := nr
+		invalid.Title = ""
+
+		body, err := json.Marshal(&invalid)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/"+pid+"/recurring", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		deps.recurrenceService.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("error 403", func(t *testing.T) {
+		handle, deps := setupRecurrenceRouter()
+
+		body, err := json.Marshal(&nr)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/"+pid+"/recurring", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		deps.recurrenceService.On("Create", mock.AnythingOfType("*context.valueCtx"), pid, nr, mock.AnythingOfType("time.Time")).Return(model.RecurringTask{}, fail.ErrNotAuthorized)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		deps.recurrenceService.AssertExpectations(t)
+	})
+}
+
+type recurrenceHandlerDeps struct {
+	logger            *zap.Logger
+	recurrenceService *mocks.RecurrenceService
+}
+
+func setupRecurrenceRouter() (http.Handler, recurrenceHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	recurrenceService := &mocks.RecurrenceService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	recurrences := handler.NewRecurrenceHandler(logger, recurrenceService)
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodPost, "/projects/{pid}/recurring", recurrences.Create)
+
+	return router, recurrenceHandlerDeps{logger, recurrenceService}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/limit"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// ShareTokenHeader is the request header carrying the token of a shared board.
+const ShareTokenHeader = "X-Share-Token"
+
+// ShareHandler handles the project share link requests.
+type ShareHandler struct {
+	logger       *zap.Logger
+	shareService shareService
+	tokens       *limit.Limiter
+	clients      *limit.Limiter
+	ipHeader     string
+}
+
+// NewShareHandler returns a new share handler. The tokens limiter limits the requests of
+// every share token, and the clients limiter those of every client address. The address
+// is read from ipHeader when the ingress sets it, and from the connection otherwise.
+func NewShareHandler(
+	logger *zap.Logger,
+	shareService shareService,
+	tokens *limit.Limiter,
+	clients *limit.Limiter,
+	ipHeader string,
+) *ShareHandler {
+	return &ShareHandler{
+		logger:       logger,
+		shareService: shareService,
+		tokens:       tokens,
+		clients:      clients,
+		ipHeader:     ipHeader,
+	}
+}
+
+// List handles list share link requests.
+func (sh *ShareHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := sh.shareService.List(r.Context(), pid)
+	if err != nil {
+		return shareError(err, fmt.Sprintf("error listing share links of project %q", pid))
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Create handles create share link requests.
+func (sh *ShareHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var ns model.NewShareLink
+	if err := web.Decode(r, &ns); err != nil {
+		return err
+	}
+
+	s, err := sh.shareService.Create(r.Context(), pid, ns, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrInvalidExpiry:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return shareError(err, fmt.Sprintf("error sharing project %q", pid))
+		}
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusCreated)
+}
+
+// Revoke handles revoke share link requests.
+func (sh *ShareHandler) Revoke(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	shid := chi.URLParam(r, "shid")
+
+	s, err := sh.shareService.Revoke(r.Context(), pid, shid, time.Now())
+	if err != nil {
+		return shareError(err, fmt.Sprintf("error revoking share link %q of project %q", shid, pid))
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// Board handles shared board requests. It is not authenticated: the token, read from the
+// ShareTokenHeader, grants read only access to one board. Clients are limited before
+// tokens, and malformed tokens are turned away before they take a place in the token
+// limiter.
+func (sh *ShareHandler) Board(w http.ResponseWriter, r *http.Request) error {
+	token := r.Header.Get(ShareTokenHeader)
+
+	now := time.Now()
+	if !sh.clients.Allow(sh.clientIP(r), now) {
+		return web.NewRequestError(fail.ErrRateLimited, http.StatusTooManyRequests)
+	}
+	if _, ok := model.ShareTokenTenant(token); !ok {
+		return web.NewRequestError(fail.ErrNotFound, http.StatusNotFound)
+	}
+	if !sh.tokens.Allow(token, now) {
+		return web.NewRequestError(fail.ErrRateLimited, http.StatusTooManyRequests)
+	}
+
+	b, err := sh.shareService.Board(r.Context(), token, now)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("error retrieving shared board: %w", err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, b, http.StatusOK)
+}
+
+// clientIP returns the address of the client making a request.
+func (sh *ShareHandler) clientIP(r *http.Request) string {
+	if sh.ipHeader != "" {
+		if ip := r.Header.Get(sh.ipHeader); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// shareError maps the errors of share link requests to responses.
+func shareError(err error, msg string) error {
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/limit"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestShareHandler_Board(t *testing.T) {
+	token := testutils.MockUUID + ".7QWmDIJgdBg1Rbo2ab5UPr0ZqHqHFMyR5ai6iRTa4Ks"
+
+	request := func(token string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/projects/shared", nil)
+		r.Header.Set(handler.ShareTokenHeader, token)
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupShareRouter(10, 10)
+
+		board := model.SharedBoard{
+			Project: model.SharedProject{ID: testProjects[0].ID, Name: testProjects[0].Name},
+			Columns: []model.SharedColumn{},
+			Tasks:   []model.SharedTask{},
+		}
+
+		r := request(token)
+		w := httptest.NewRecorder()
+
+		deps.shareService.On("Board", mock.AnythingOfType("*context.valueCtx"), token, mock.AnythingOfType("time.Time")).Return(board, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&board)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.shareService.AssertExpectations(t)
+	})
+
+	t.Run("error 404 revoked", func(t *testing.T) {
+		handle, deps := setupShareRouter(10, 10)
+
+		r := request(token)
+		w := httptest.NewRecorder()
+
+		deps.shareService.On("Board", mock.AnythingOfType("*context.valueCtx"), token, mock.AnythingOfType("time.Time")).Return(model.SharedBoard{}, fail.ErrNotFound)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.shareService.AssertExpectations(t)
+	})
+
+	t.Run("error 429 rate limited", func(t *testing.T) {
+		handle, deps := setupShareRouter(1, 10)
+
+		deps.shareService.On("Board", mock.AnythingOfType("*context.valueCtx"), token, mock.AnythingOfType("time.Time")).Return(model.SharedBoard{}, nil).Once()
+
+		w := httptest.NewRecorder()
+		handle.ServeHTTP(w, request(token))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		handle.ServeHTTP(w, request(token))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		// Other tokens have limits of their own.
+		other := testutils.MockUUID + ".Xb2mTkq1cZ0yJm8bq3vA9p4Rk7sW5dN6eH1fG2jL0aQ"
+		deps.shareService.On("Board", mock.AnythingOfType("*context.valueCtx"), other, mock.AnythingOfType("time.Time")).Return(model.SharedBoard{}, fail.ErrNotFound)
+
+		w = httptest.NewRecorder()
+		handle.ServeHTTP(w, request(other))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.shareService.AssertExpectations(t)
+	})
+
+	t.Run("error 404 malformed token", func(t *testing.T) {
+		handle, deps := setupShareRouter(10, 10)
+
+		for _, malformed := range []string{"", "secret", "tenant.secret", testutils.MockUUID + ".secret"} {
+			w := httptest.NewRecorder()
+			handle.ServeHTTP(w, request(malformed))
+			assert.Equal(t, http.StatusNotFound, w.Code, malformed)
+		}
+		deps.shareService.AssertNotCalled(t, "Board", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error 429 client rate limited", func(t *testing.T) {
+		handle, deps := setupShareRouter(10, 2)
+
+		fromClient := func(ip string) *http.Request {
+			r := request("malformed")
+			r.Header.Set("X-Real-Ip", ip)
+			return r
+		}
+
+		// Requests of a client count against its limit whatever the token.
+		for i := 0; i < 2; i++ {
+			w := httptest.NewRecorder()
+			handle.ServeHTTP(w, fromClient("203.0.113.7"))
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		}
+
+		w := httptest.NewRecorder()
+		handle.ServeHTTP(w, fromClient("203.0.113.7"))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		// Other clients have limits of their own.
+		w = httptest.NewRecorder()
+		handle.ServeHTTP(w, fromClient("203.0.113.8"))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.shareService.AssertNotCalled(t, "Board", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+type shareHandlerDeps struct {
+	logger       *zap.Logger
+	shareService *mocks.ShareService
+}
+
+func setupShareRouter(tokenBurst, clientBurst int) (http.Handler, shareHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	shareService := &mocks.ShareService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	shares := handler.NewShareHandler(logger, shareService, limit.New(1, tokenBurst), limit.New(1, clientBurst), "X-Real-Ip")
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodGet, "/projects/shared", shares.Board)
+
+	return router, shareHandlerDeps{logger, shareService}
+}
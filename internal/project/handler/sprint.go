@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// SprintHandler handles the project sprint requests.
+type SprintHandler struct {
+	logger        *zap.Logger
+	sprintService sprintService
+}
+
+// NewSprintHandler returns a new sprint handler.
+func NewSprintHandler(
+	logger *zap.Logger,
+	sprintService sprintService,
+) *SprintHandler {
+	return &SprintHandler{
+		logger:        logger,
+		sprintService: sprintService,
+	}
+}
+
+// List handles list sprint requests.
+func (sh *SprintHandler) List(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	list, err := sh.sprintService.List(r.Context(), pid)
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error listing sprints for project %q", pid))
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Retrieve handles retrieve sprint requests.
+func (sh *SprintHandler) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	sid := chi.URLParam(r, "sid")
+
+	s, err := sh.sprintService.Retrieve(r.Context(), pid, sid)
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error looking for sprint %q", sid))
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// Create handles create sprint requests.
+func (sh *SprintHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var ns model.NewSprint
+	if err := web.Decode(r, &ns); err != nil {
+		return err
+	}
+
+	s, err := sh.sprintService.Create(r.Context(), pid, ns, time.Now())
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error creating sprint for project %q", pid))
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusCreated)
+}
+
+// Start handles start sprint requests.
+func (sh *SprintHandler) Start(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	sid := chi.URLParam(r, "sid")
+
+	var ss model.StartSprint
+	if err := web.Decode(r, &ss); err != nil {
+		return err
+	}
+
+	s, err := sh.sprintService.Start(r.Context(), pid, sid, ss, time.Now())
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error starting sprint %q", sid))
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// Complete handles complete sprint requests.
+func (sh *SprintHandler) Complete(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	sid := chi.URLParam(r, "sid")
+
+	var cs model.CompleteSprint
+	if err := web.Decode(r, &cs); err != nil {
+		return err
+	}
+
+	s, err := sh.sprintService.Complete(r.Context(), pid, sid, cs, time.Now())
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error completing sprint %q", sid))
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// AddTasks handles requests that add tasks to a sprint.
+func (sh *SprintHandler) AddTasks(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	sid := chi.URLParam(r, "sid")
+
+	var st model.SprintTasks
+	if err := web.Decode(r, &st); err != nil {
+		return err
+	}
+
+	s, err := sh.sprintService.AddTasks(r.Context(), pid, sid, st, time.Now())
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error adding tasks to sprint %q", sid))
+	}
+
+	return web.Respond(r.Context(), w, s, http.StatusOK)
+}
+
+// RemoveTask handles requests that move a sprint task back to the backlog.
+func (sh *SprintHandler) RemoveTask(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	sid := chi.URLParam(r, "sid")
+	tid := chi.URLParam(r, "tid")
+
+	if err := sh.sprintService.RemoveTask(r.Context(), pid, sid, tid, time.Now()); err != nil {
+		return sprintError(err, fmt.Sprintf("error removing task %q from sprint %q", tid, sid))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// Burndown handles sprint burndown requests.
+func (sh *SprintHandler) Burndown(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	sid := chi.URLParam(r, "sid")
+
+	b, err := sh.sprintService.Burndown(r.Context(), pid, sid, time.Now())
+	if err != nil {
+		return sprintError(err, fmt.Sprintf("error computing burndown of sprint %q", sid))
+	}
+
+	return web.Respond(r.Context(), w, b, http.StatusOK)
+}
+
+// sprintError maps sprint errors to request errors.
+func sprintError(err error, msg string) error {
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID, fail.ErrSprintEnd, fail.ErrSprintProject:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrSprintState, fail.ErrSprintActive, fail.ErrSprintCapacity:
+		return web.NewRequestError(err, http.StatusConflict)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
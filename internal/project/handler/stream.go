@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// streamWriteTimeout bounds every write to a stream, replacing the server write timeout
+// that would otherwise end the stream.
+const streamWriteTimeout = 10 * time.Second
+
+// streamRetry tells clients how many milliseconds to wait before reconnecting.
+const streamRetry = 3000
+
+// StreamHandler handles the board change stream requests.
+type StreamHandler struct {
+	logger        *zap.Logger
+	streamService streamService
+	heartbeat     time.Duration
+}
+
+// NewStreamHandler returns a new stream handler. Idle streams send a comment every
+// heartbeat so that proxies keep them open.
+func NewStreamHandler(
+	logger *zap.Logger,
+	streamService streamService,
+	heartbeat time.Duration,
+) *StreamHandler {
+	return &StreamHandler{
+		logger:        logger,
+		streamService: streamService,
+		heartbeat:     heartbeat,
+	}
+}
+
+// Stream streams the changes of a project board as server-sent events. Clients resume
+// after the Last-Event-ID header, or the lastEventId query parameter, and reload the
+// board on a reset event.
+func (sh *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+
+	sub, err := sh.streamService.Subscribe(r.Context(), pid, lastEventID)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error streaming project %q: %w", pid, err)
+		}
+	}
+	defer sub.Close()
+
+	sw := &sseWriter{w: w, rc: http.NewResponseController(w)}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	web.SetContextStatusCode(r.Context(), http.StatusOK)
+
+	// The response has started, so failures end the stream rather than the request.
+	if err = sh.stream(r, sw, sub.Reset, sub.Replay, sub.C); err != nil {
+		sh.logger.Info("project stream closed", zap.String("projectID", pid), zap.Error(err))
+	}
+	return nil
+}
+
+func (sh *StreamHandler) stream(r *http.Request, sw *sseWriter, reset bool, replay []model.Change, c <-chan model.Change) error {
+	if err := sw.write(fmt.Sprintf("retry: %d\n\n", streamRetry)); err != nil {
+		return err
+	}
+
+	if reset {
+		if err := sw.write("event: reset\ndata: {}\n\n"); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range replay {
+		if err := sw.change(change); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(sh.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case change, ok := <-c:
+			if !ok {
+				return nil
+			}
+			if err := sw.change(change); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := sw.write(": heartbeat\n\n"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sseWriter writes server-sent events, flushing each one.
+type sseWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (sw *sseWriter) change(change model.Change) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	return sw.write(fmt.Sprintf("id: %s\nevent: change\ndata: %s\n\n", change.ID, data))
+}
+
+func (sw *sseWriter) write(event string) error {
+	err := sw.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err = fmt.Fprint(sw.w, event); err != nil {
+		return err
+	}
+	return sw.rc.Flush()
+}
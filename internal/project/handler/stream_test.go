@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/internal/project/stream"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestStreamHandler_Stream(t *testing.T) {
+	path := "/projects/" + testutils.MockUUID + "/stream"
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupStreamRouter()
+
+		hub := stream.NewHub(4, 8)
+		hub.Broadcast(model.Change{ID: "1", TenantID: "tenant", ProjectID: testutils.MockUUID})
+		hub.Broadcast(model.Change{ID: "2", TenantID: "tenant", ProjectID: testutils.MockUUID, Kind: model.ChangeTask})
+
+		sub := hub.Subscribe("tenant", testutils.MockUUID, "1")
+		// Closing the hub ends the stream once the replay is sent.
+		hub.Close()
+
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Last-Event-ID", "1")
+		w := httptest.NewRecorder()
+
+		deps.streamService.On("Subscribe", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, "1").Return(sub, nil)
+
+		handle.ServeHTTP(w, r)
+
+		body := w.Body.String()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(body, "retry: "))
+		assert.Contains(t, body, "id: 2\nevent: change\ndata: {")
+		assert.NotContains(t, body, "id: 1\n")
+		assert.NotContains(t, body, "event: reset")
+		deps.streamService.AssertExpectations(t)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		handle, deps := setupStreamRouter()
+
+		hub := stream.NewHub(4, 8)
+		sub := hub.Subscribe("tenant", testutils.MockUUID, "unknown")
+		hub.Close()
+
+		r := httptest.NewRequest(http.MethodGet, path+"?lastEventId=unknown", nil)
+		w := httptest.NewRecorder()
+
+		deps.streamService.On("Subscribe", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, "unknown").Return(sub, nil)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "event: reset\n")
+		deps.streamService.AssertExpectations(t)
+	})
+
+	t.Run("error 404 not a member", func(t *testing.T) {
+		handle, deps := setupStreamRouter()
+
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+
+		deps.streamService.On("Subscribe", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, "").Return(nil, fail.ErrNotFound)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		deps.streamService.AssertExpectations(t)
+	})
+}
+
+type streamHandlerDeps struct {
+	logger        *zap.Logger
+	streamService *mocks.StreamService
+}
+
+func setupStreamRouter() (http.Handler, streamHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	streamService := &mocks.StreamService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	streams := handler.NewStreamHandler(logger, streamService, time.Minute)
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodGet, "/projects/{pid}/stream", streams.Stream)
+
+	return router, streamHandlerDeps{logger, streamService}
+}
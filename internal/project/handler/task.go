@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/devpies/saas-core/internal/project/fail"
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/query"
 	"github.com/devpies/saas-core/pkg/web"
 
 	"github.com/go-chi/chi/v5"
@@ -15,31 +20,65 @@ import (
 
 // TaskHandler handles the task requests.
 type TaskHandler struct {
-	logger        *zap.Logger
-	taskService   taskService
-	columnService columnService
+	logger      *zap.Logger
+	taskService taskService
 }
 
 // NewTaskHandler returns a new task handler.
 func NewTaskHandler(
 	logger *zap.Logger,
 	taskService taskService,
-	columnService columnService,
 ) *TaskHandler {
 	return &TaskHandler{
-		logger:        logger,
-		taskService:   taskService,
-		columnService: columnService,
+		logger:      logger,
+		taskService: taskService,
 	}
 }
 
-// List handles list task requests.
+// List handles list task requests, optionally filtered by label, priority and overdue
+// status. Archived tasks are listed when the archived query parameter is true.
 func (th *TaskHandler) List(w http.ResponseWriter, r *http.Request) error {
 	pid := chi.URLParam(r, "pid")
 
-	list, err := th.taskService.List(r.Context(), pid)
+	q := r.URL.Query()
+	filter := model.TaskFilter{
+		LabelID:  q.Get("label"),
+		Priority: q.Get("priority"),
+	}
+	if v := q.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			return web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+		}
+		filter.Overdue = overdue
+	}
+	if v := q.Get("archived"); v != "" {
+		archived, err := strconv.ParseBool(v)
+		if err != nil {
+			return web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+		}
+		filter.Archived = archived
+	}
+	for k, vs := range q {
+		if cfid, ok := strings.CutPrefix(k, "field."); ok {
+			if filter.Fields == nil {
+				filter.Fields = make(map[string]string)
+			}
+			filter.Fields[cfid] = vs[0]
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+	}
+
+	list, err := th.taskService.List(r.Context(), pid, filter)
 	if err != nil {
-		return err
+		switch err {
+		case fail.ErrInvalidID, fail.ErrInvalidFilter:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error listing tasks of project %q :%w", pid, err)
+		}
 	}
 
 	return web.Respond(r.Context(), w, list, http.StatusOK)
@@ -64,38 +103,51 @@ func (th *TaskHandler) Retrieve(w http.ResponseWriter, r *http.Request) error {
 	return web.Respond(r.Context(), w, t, http.StatusOK)
 }
 
+// RetrieveByKey handles retrieve task requests by task key.
+func (th *TaskHandler) RetrieveByKey(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+	key := chi.URLParam(r, "key")
+
+	t, err := th.taskService.RetrieveByKey(r.Context(), pid, key)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error looking for task %q :%w", key, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
 // Create handles create task requests.
 func (th *TaskHandler) Create(w http.ResponseWriter, r *http.Request) error {
-	var (
-		err error
-	)
 	pid := chi.URLParam(r, "pid")
 	cid := chi.URLParam(r, "cid")
 
 	var nt model.NewTask
-	if err = web.Decode(r, &nt); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return err
-	}
-
-	task, err := th.taskService.Create(r.Context(), nt, pid, time.Now())
-	if err != nil {
+	if err := web.Decode(r, &nt); err != nil {
 		return err
 	}
 
-	c, err := th.columnService.Retrieve(r.Context(), cid)
+	task, err := th.taskService.Create(r.Context(), nt, pid, cid, time.Now())
 	if err != nil {
-		return err
-	}
-
-	ids := append(c.TaskIDS, task.ID)
-
-	uc := model.UpdateColumn{
-		TaskIDS: ids,
-	}
-
-	if _, err = th.columnService.Update(r.Context(), cid, uc, time.Now()); err != nil {
-		return err
+		if perr, ok := policyError(err); ok {
+			return perr
+		}
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error creating task in column %q :%w", cid, err)
+		}
 	}
 
 	return web.Respond(r.Context(), w, task, http.StatusCreated)
@@ -115,8 +167,10 @@ func (th *TaskHandler) Update(w http.ResponseWriter, r *http.Request) error {
 		switch err {
 		case fail.ErrNotFound:
 			return web.NewRequestError(err, http.StatusNotFound)
-		case fail.ErrInvalidID:
+		case fail.ErrInvalidID, fail.ErrInvalidLabel, fail.ErrInvalidSchedule, fail.ErrInvalidFieldValue:
 			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
 		default:
 			return fmt.Errorf("updating task %v :%w", ut, err)
 		}
@@ -127,37 +181,63 @@ func (th *TaskHandler) Update(w http.ResponseWriter, r *http.Request) error {
 
 // Delete handles delete task requests.
 func (th *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) error {
-	var err error
-
-	cid := chi.URLParam(r, "cid")
 	tid := chi.URLParam(r, "tid")
 
-	c, err := th.columnService.Retrieve(r.Context(), cid)
-	if err != nil {
-		return err
+	if err := th.taskService.Delete(r.Context(), tid, time.Now()); err != nil {
+		switch err {
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error deleting task %q :%w", tid, err)
+		}
 	}
 
-	i := sliceIndex(len(c.TaskIDS), func(i int) bool { return c.TaskIDS[i] == tid })
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// Restore handles requests taking a task out of the trash.
+func (th *TaskHandler) Restore(w http.ResponseWriter, r *http.Request) error {
+	return th.lifecycle(w, r, "restoring", th.taskService.Restore)
+}
 
-	if i >= 0 {
-		newTaskIds := append(c.TaskIDS[:i], c.TaskIDS[i+1:]...)
-		uc := model.UpdateColumn{TaskIDS: newTaskIds}
+// Archive handles archive task requests.
+func (th *TaskHandler) Archive(w http.ResponseWriter, r *http.Request) error {
+	return th.lifecycle(w, r, "archiving", th.taskService.Archive)
+}
 
-		if _, err = th.columnService.Update(r.Context(), cid, uc, time.Now()); err != nil {
-			return err
-		}
+// Unarchive handles unarchive task requests.
+func (th *TaskHandler) Unarchive(w http.ResponseWriter, r *http.Request) error {
+	return th.lifecycle(w, r, "unarchiving", th.taskService.Unarchive)
+}
 
-		if err = th.taskService.Delete(r.Context(), tid); err != nil {
-			switch err {
-			case fail.ErrInvalidID:
-				return web.NewRequestError(err, http.StatusBadRequest)
-			default:
-				return fmt.Errorf("error deleting task %q :%w", tid, err)
-			}
+// lifecycle handles requests moving a task between the board, the archive and the trash.
+func (th *TaskHandler) lifecycle(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	fn func(ctx context.Context, taskID string, now time.Time) (model.Task, error),
+) error {
+	tid := chi.URLParam(r, "tid")
+
+	task, err := fn(r.Context(), tid, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error %s task %q :%w", action, tid, err)
 		}
 	}
 
-	return web.Respond(r.Context(), w, nil, http.StatusOK)
+	return web.Respond(r.Context(), w, task, http.StatusOK)
 }
 
 // Move handles move task requests.
@@ -166,61 +246,243 @@ func (th *TaskHandler) Move(w http.ResponseWriter, r *http.Request) error {
 
 	var mt model.MoveTask
 	if err := web.Decode(r, &mt); err != nil {
-		return fmt.Errorf("decoding task move :%w", err)
+		return err
 	}
 
-	cF, err := th.columnService.Retrieve(r.Context(), mt.From)
+	task, err := th.taskService.Move(r.Context(), tid, mt, time.Now())
 	if err != nil {
+		if perr, ok := policyError(err); ok {
+			return perr
+		}
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrInvalidMove:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrTaskBlocked:
+			return web.NewRequestError(err, http.StatusConflict)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error moving task %q to column %q :%w", tid, mt.To, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, task, http.StatusOK)
+}
+
+// SetParent handles requests making a task a subtask of another task.
+func (th *TaskHandler) SetParent(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	var sp model.SetParent
+	if err := web.Decode(r, &sp); err != nil {
 		return err
 	}
 
-	cT, err := th.columnService.Retrieve(r.Context(), mt.To)
+	task, err := th.taskService.SetParent(r.Context(), tid, sp, time.Now())
 	if err != nil {
-		return err
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrInvalidLink, fail.ErrLinkCycle:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error setting parent of task %q to %q :%w", tid, sp.ParentID, err)
+		}
 	}
 
-	i := sliceIndex(len(cF.TaskIDS), func(i int) bool { return cF.TaskIDS[i] == tid })
+	return web.Respond(r.Context(), w, task, http.StatusOK)
+}
 
-	if i >= 0 {
-		newFromTaskIds := append(cF.TaskIDS[:i], cF.TaskIDS[i+1:]...)
-		foc := model.UpdateColumn{TaskIDS: newFromTaskIds}
+// Link handles requests linking a task to another task.
+func (th *TaskHandler) Link(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
 
-		newToTaskIds := append(cT.TaskIDS, tid)
-		toc := model.UpdateColumn{TaskIDS: newToTaskIds}
+	var nl model.NewTaskLink
+	if err := web.Decode(r, &nl); err != nil {
+		return err
+	}
 
-		_, err = th.columnService.Update(r.Context(), mt.From, foc, time.Now())
-		if err != nil {
-			switch err {
-			case fail.ErrNotFound:
-				return web.NewRequestError(err, http.StatusNotFound)
-			case fail.ErrInvalidID:
-				return web.NewRequestError(err, http.StatusBadRequest)
-			default:
-				return fmt.Errorf("error updating column taskIds from:%q, to:%q : %w", mt.From, mt.To, err)
-			}
+	task, err := th.taskService.Link(r.Context(), tid, nl, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID, fail.ErrInvalidLink, fail.ErrLinkCycle:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateLink:
+			return web.NewRequestError(err, http.StatusConflict)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error linking task %q to %q :%w", tid, nl.TargetID, err)
 		}
+	}
 
-		_, err = th.columnService.Update(r.Context(), mt.To, toc, time.Now())
-		if err != nil {
-			switch err {
-			case fail.ErrNotFound:
-				return web.NewRequestError(err, http.StatusNotFound)
-			case fail.ErrInvalidID:
-				return web.NewRequestError(err, http.StatusBadRequest)
-			default:
-				return fmt.Errorf("error updating column taskIds from:%q, to:%q :%w", mt.From, mt.To, err)
-			}
+	return web.Respond(r.Context(), w, task, http.StatusCreated)
+}
+
+// Unlink handles requests removing a link from a task.
+func (th *TaskHandler) Unlink(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	lkid := chi.URLParam(r, "lkid")
+
+	if err := th.taskService.Unlink(r.Context(), tid, lkid, time.Now()); err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrNotAuthorized:
+			return web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("error removing link %q from task %q :%w", lkid, tid, err)
 		}
 	}
 
 	return web.Respond(r.Context(), w, nil, http.StatusOK)
 }
 
-func sliceIndex(limit int, predicate func(i int) bool) int {
-	for i := 0; i < limit; i++ {
-		if predicate(i) {
-			return i
+// Search handles full-text task search requests. Results span every tenant of the user
+// when the request comes through the cross-tenant base path, and then come with the
+// tenants that failed. Results of a single tenant are listed as they are.
+func (th *TaskHandler) Search(w http.ResponseWriter, r *http.Request) error {
+	var all bool
+
+	if r.Header.Get("BasePath") == "projects" {
+		all = true
+	}
+
+	search, err := parseTaskSearch(r)
+	if err != nil {
+		return web.NewRequestError(fail.ErrInvalidSearch, http.StatusBadRequest)
+	}
+
+	results, err := th.taskService.Search(r.Context(), search, all)
+	if err != nil {
+		return fmt.Errorf("error searching tasks %q :%w", search.Query, err)
+	}
+
+	if !all {
+		return web.Respond(r.Context(), w, results.Results, http.StatusOK)
+	}
+	return web.Respond(r.Context(), w, results, http.StatusOK)
+}
+
+// Query handles requests listing the tasks matching a query of the task query language.
+func (th *TaskHandler) Query(w http.ResponseWriter, r *http.Request) error {
+	tq, err := parseTaskQuery(r)
+	if err != nil {
+		return web.NewRequestError(fail.ErrInvalidQuery, http.StatusBadRequest)
+	}
+
+	list, err := th.taskService.Query(r.Context(), tq, time.Now())
+	if err != nil {
+		if qerr, ok := queryError(err, "q"); ok {
+			return qerr
+		}
+		switch err {
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error querying tasks %q :%w", tq.Query, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// parseTaskQuery reads and validates the task query parameters.
+func parseTaskQuery(r *http.Request) (model.TaskQuery, error) {
+	var err error
+
+	q := r.URL.Query()
+	tq := model.TaskQuery{
+		Query:     q.Get("q"),
+		ProjectID: q.Get("project"),
+		Limit:     model.DefaultSearchLimit,
+	}
+
+	if v := q.Get("limit"); v != "" {
+		if tq.Limit, err = strconv.Atoi(v); err != nil {
+			return tq, err
 		}
 	}
-	return -1
+	if v := q.Get("offset"); v != "" {
+		if tq.Offset, err = strconv.Atoi(v); err != nil {
+			return tq, err
+		}
+	}
+
+	return tq, tq.Validate()
+}
+
+// parseTaskSearch reads and validates the search query parameters.
+func parseTaskSearch(r *http.Request) (model.TaskSearch, error) {
+	var err error
+
+	q := r.URL.Query()
+	search := model.TaskSearch{
+		Query:      q.Get("q"),
+		ProjectID:  q.Get("project"),
+		AssignedTo: q.Get("assignee"),
+		ColumnID:   q.Get("column"),
+		Limit:      model.DefaultSearchLimit,
+	}
+
+	if v := q.Get("limit"); v != "" {
+		if search.Limit, err = strconv.Atoi(v); err != nil {
+			return search, err
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if search.Offset, err = strconv.Atoi(v); err != nil {
+			return search, err
+		}
+	}
+	if v := q.Get("createdAfter"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return search, err
+		}
+		search.CreatedAfter = &t
+	}
+	if v := q.Get("createdBefore"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return search, err
+		}
+		search.CreatedBefore = &t
+	}
+
+	return search, search.Validate()
+}
+
+// policyError maps a broken column policy to a response naming each broken policy.
+func policyError(err error) (error, bool) {
+	var pe *fail.PolicyError
+	if !errors.As(err, &pe) {
+		return nil, false
+	}
+
+	fields := make([]web.FieldError, 0, len(pe.Violations))
+	for _, v := range pe.Violations {
+		fields = append(fields, web.FieldError{Field: v.Policy, Error: v.Message})
+	}
+	return &web.Error{Err: fail.ErrColumnPolicy, Status: http.StatusConflict, Fields: fields}, true
+}
+
+// queryError maps a task query that cannot be parsed to a response explaining why, on
+// the request field holding the query.
+func queryError(err error, field string) (error, bool) {
+	var qe *query.Error
+	if !errors.As(err, &qe) {
+		return nil, false
+	}
+
+	fields := []web.FieldError{{Field: field, Error: qe.Error()}}
+	return &web.Error{Err: fail.ErrInvalidQuery, Status: http.StatusBadRequest, Fields: fields}, true
 }
@@ -0,0 +1,274 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/query"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestTaskHandler_Search(t *testing.T) {
+	path := "/projects/search"
+
+	t.Run("success all tenants", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		search := model.TaskSearch{Query: "design", ProjectID: testutils.MockUUID, Limit: 5, Offset: 10}
+		results := model.TaskSearchResults{
+			Results:  []model.TaskSearchResult{{TaskID: testutils.MockUUID, Title: "Design it", TitleHighlight: "<mark>Design</mark> it"}},
+			Failures: []model.TenantFailure{{TenantID: testutils.MockUUID, CompanyName: "Acme", Error: "unavailable"}},
+		}
+
+		r := httptest.NewRequest(http.MethodGet, path+"?q=design&project="+testutils.MockUUID+"&limit=5&offset=10", nil)
+		r.Header.Set("BasePath", "projects")
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Search", mock.AnythingOfType("*context.valueCtx"), search, true).Return(results, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&results)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		search := model.TaskSearch{Query: "design", Limit: model.DefaultSearchLimit}
+		results := model.TaskSearchResults{
+			Results:  []model.TaskSearchResult{{TaskID: testutils.MockUUID, Title: "Design it", TitleHighlight: "<mark>Design</mark> it"}},
+			Failures: []model.TenantFailure{},
+		}
+
+		r := httptest.NewRequest(http.MethodGet, path+"?q=design", nil)
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Search", mock.AnythingOfType("*context.valueCtx"), search, false).Return(results, nil)
+
+		handle.ServeHTTP(w, r)
+
+		// The results of a single tenant are listed as a plain array.
+		expected, err := json.Marshal(&results.Results)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing query", query: ""},
+		{name: "limit is not a number", query: "?q=design&limit=ten"},
+		{name: "limit too large", query: "?q=design&limit=500"},
+		{name: "negative offset", query: "?q=design&offset=-1"},
+		{name: "offset too large", query: "?q=design&offset=1001"},
+		{name: "created after is not RFC3339", query: "?q=design&createdAfter=yesterday"},
+	}
+
+	for _, tc := range tests {
+		t.Run("error 400 "+tc.name, func(t *testing.T) {
+			handle, deps := setupTaskRouter()
+
+			response := web.ErrorResponse{
+				Error: fail.ErrInvalidSearch.Error(),
+			}
+
+			r := httptest.NewRequest(http.MethodGet, path+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handle.ServeHTTP(w, r)
+
+			expected, err := json.Marshal(&response)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, w.Body.Bytes())
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			deps.taskService.AssertNotCalled(t, "Search")
+		})
+	}
+}
+
+func TestTaskHandler_Move(t *testing.T) {
+	path := "/projects/tasks/" + testutils.MockUUID + "/move"
+	mt := model.MoveTask{To: testutils.MockUUID}
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		task := model.Task{ID: testutils.MockUUID, ColumnID: mt.To}
+
+		b, err := json.Marshal(&mt)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader(b))
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Move", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, mt, mock.AnythingOfType("time.Time")).Return(task, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&task)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+
+	t.Run("error 409 column policies", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		violations := []fail.Violation{
+			{Policy: model.PolicyWIPLimit, Message: "column holds at most 3 tasks"},
+			{Policy: model.RuleAssignee, Message: "tasks entering the column must be assigned"},
+		}
+		response := web.ErrorResponse{
+			Error: fail.ErrColumnPolicy.Error(),
+			Fields: []web.FieldError{
+				{Field: model.PolicyWIPLimit, Error: "column holds at most 3 tasks"},
+				{Field: model.RuleAssignee, Error: "tasks entering the column must be assigned"},
+			},
+		}
+
+		b, err := json.Marshal(&mt)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader(b))
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Move", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, mt, mock.AnythingOfType("time.Time")).
+			Return(model.Task{}, &fail.PolicyError{ColumnID: mt.To, Violations: violations})
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusConflict, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+
+	t.Run("error 403 override by non owner", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		override := model.MoveTask{To: testutils.MockUUID, Override: true}
+
+		b, err := json.Marshal(&override)
+		assert.Nil(t, err)
+
+		r := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader(b))
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Move", mock.AnythingOfType("*context.valueCtx"), testutils.MockUUID, override, mock.AnythingOfType("time.Time")).Return(model.Task{}, fail.ErrNotAuthorized)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+}
+
+func TestTaskHandler_Query(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		tq := model.TaskQuery{Query: "assignee = me and points > 3", Limit: model.DefaultSearchLimit}
+		tasks := []model.Task{{ID: testutils.MockUUID}}
+
+		r := httptest.NewRequest(http.MethodGet, "/projects/tasks?q="+url.QueryEscape(tq.Query), nil)
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Query", mock.AnythingOfType("*context.valueCtx"), tq, mock.AnythingOfType("time.Time")).Return(tasks, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&tasks)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+
+	t.Run("error 400 query", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		tq := model.TaskQuery{Query: "points > three", Limit: model.DefaultSearchLimit}
+		_, qerr := query.Parse(tq.Query)
+		response := web.ErrorResponse{
+			Error:  fail.ErrInvalidQuery.Error(),
+			Fields: []web.FieldError{{Field: "q", Error: `points expects a whole number, got "three" at position 10`}},
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/projects/tasks?q="+url.QueryEscape(tq.Query), nil)
+		w := httptest.NewRecorder()
+
+		deps.taskService.On("Query", mock.AnythingOfType("*context.valueCtx"), tq, mock.AnythingOfType("time.Time")).Return(nil, qerr)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&response)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+
+	t.Run("error 400 limit", func(t *testing.T) {
+		handle, deps := setupTaskRouter()
+
+		r := httptest.NewRequest(http.MethodGet, "/projects/tasks?limit=1000", nil)
+		w := httptest.NewRecorder()
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		deps.taskService.AssertExpectations(t)
+	})
+}
+
+type taskHandlerDeps struct {
+	logger      *zap.Logger
+	taskService *mocks.TaskService
+}
+
+func setupTaskRouter() (http.Handler, taskHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	taskService := &mocks.TaskService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	tasks := handler.NewTaskHandler(logger, taskService)
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodGet, "/projects/search", tasks.Search)
+	app.Handle(http.MethodGet, "/projects/tasks", tasks.Query)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/move", tasks.Move)
+
+	return router, taskHandlerDeps{logger, taskService}
+}
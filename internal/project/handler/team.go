@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// TeamHandler handles the tenant team requests.
+type TeamHandler struct {
+	logger      *zap.Logger
+	teamService teamService
+}
+
+// NewTeamHandler returns a new team handler.
+func NewTeamHandler(
+	logger *zap.Logger,
+	teamService teamService,
+) *TeamHandler {
+	return &TeamHandler{
+		logger:      logger,
+		teamService: teamService,
+	}
+}
+
+// List handles list team requests.
+func (th *TeamHandler) List(w http.ResponseWriter, r *http.Request) error {
+	list, err := th.teamService.List(r.Context())
+	if err != nil {
+		return fmt.Errorf("error listing teams: %w", err)
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Retrieve handles retrieve team requests.
+func (th *TeamHandler) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "tmid")
+
+	t, err := th.teamService.Retrieve(r.Context(), teamID)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error retrieving team %q: %w", teamID, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
+// Create handles create team requests.
+func (th *TeamHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	var nt model.NewTeam
+	if err := web.Decode(r, &nt); err != nil {
+		return err
+	}
+
+	t, err := th.teamService.Create(r.Context(), nt, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateTeam:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error creating team %q: %w", nt.Name, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusCreated)
+}
+
+// Delete handles delete team requests.
+func (th *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "tmid")
+
+	if err := th.teamService.Delete(r.Context(), teamID, time.Now()); err != nil {
+		return teamError(err, fmt.Sprintf("error deleting team %q", teamID))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// AddMember handles add team member requests.
+func (th *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "tmid")
+
+	var nm model.NewTeamMember
+	if err := web.Decode(r, &nm); err != nil {
+		return err
+	}
+
+	t, err := th.teamService.AddMember(r.Context(), teamID, nm, time.Now())
+	if err != nil {
+		return teamError(err, fmt.Sprintf("error adding member to team %q", teamID))
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
+// RemoveMember handles remove team member requests.
+func (th *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "tmid")
+	uid := chi.URLParam(r, "uid")
+
+	if err := th.teamService.RemoveMember(r.Context(), teamID, uid, time.Now()); err != nil {
+		return teamError(err, fmt.Sprintf("error removing member %q of team %q", uid, teamID))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// teamError maps the errors of team changes to responses.
+func teamError(err error, msg string) error {
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
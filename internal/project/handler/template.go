@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// TemplateHandler handles the project template requests.
+type TemplateHandler struct {
+	logger          *zap.Logger
+	templateService templateService
+}
+
+// NewTemplateHandler returns a new template handler.
+func NewTemplateHandler(
+	logger *zap.Logger,
+	templateService templateService,
+) *TemplateHandler {
+	return &TemplateHandler{
+		logger:          logger,
+		templateService: templateService,
+	}
+}
+
+// List handles list template requests.
+func (th *TemplateHandler) List(w http.ResponseWriter, r *http.Request) error {
+	list, err := th.templateService.List(r.Context())
+	if err != nil {
+		return fmt.Errorf("error listing templates: %w", err)
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Retrieve handles retrieve template requests.
+func (th *TemplateHandler) Retrieve(w http.ResponseWriter, r *http.Request) error {
+	tmid := chi.URLParam(r, "tmid")
+
+	t, err := th.templateService.Retrieve(r.Context(), tmid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error retrieving template %q: %w", tmid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusOK)
+}
+
+// Create handles create template requests.
+func (th *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	var nt model.NewTemplate
+	if err := web.Decode(r, &nt); err != nil {
+		return err
+	}
+
+	t, err := th.templateService.Create(r.Context(), nt, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrInvalidID, fail.ErrInvalidTemplate:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateTemplate, fail.ErrDuplicateLabel:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error creating template %q: %w", nt.Name, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusCreated)
+}
+
+// SaveProject handles requests saving a project board as a template.
+func (th *TemplateHandler) SaveProject(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	var st model.SaveTemplate
+	if err := web.Decode(r, &st); err != nil {
+		return err
+	}
+
+	t, err := th.templateService.SaveProject(r.Context(), pid, st, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrDuplicateTemplate:
+			return web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("error saving project %q as template: %w", pid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, t, http.StatusCreated)
+}
+
+// Delete handles delete template requests.
+func (th *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	tmid := chi.URLParam(r, "tmid")
+
+	if err := th.templateService.Delete(r.Context(), tmid); err != nil {
+		switch err {
+		case fail.ErrInvalidID, fail.ErrBuiltinTemplate:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error deleting template %q: %w", tmid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// TransferHandler handles the project export and import requests.
+type TransferHandler struct {
+	logger          *zap.Logger
+	transferService transferService
+}
+
+// NewTransferHandler returns a new transfer handler.
+func NewTransferHandler(
+	logger *zap.Logger,
+	transferService transferService,
+) *TransferHandler {
+	return &TransferHandler{
+		logger:          logger,
+		transferService: transferService,
+	}
+}
+
+// Export handles project export requests. The format query parameter selects the JSON
+// bundle, the default, or a CSV of the tasks.
+func (th *TransferHandler) Export(w http.ResponseWriter, r *http.Request) error {
+	pid := chi.URLParam(r, "pid")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = model.ExportJSON
+	}
+	if format != model.ExportJSON && format != model.ExportCSV {
+		return web.NewRequestError(fail.ErrInvalidExport, http.StatusBadRequest)
+	}
+
+	b, err := th.transferService.Export(r.Context(), pid, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error exporting project %q: %w", pid, err)
+		}
+	}
+
+	if format == model.ExportJSON {
+		return web.Respond(r.Context(), w, b, http.StatusOK)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pid+".csv"))
+	w.WriteHeader(http.StatusOK)
+	web.SetContextStatusCode(r.Context(), http.StatusOK)
+
+	return b.WriteCSV(w)
+}
+
+// Import handles project import requests. The import runs in the background and its
+// status is polled with RetrieveImport.
+func (th *TransferHandler) Import(w http.ResponseWriter, r *http.Request) error {
+	var ni model.NewImport
+	if err := web.Decode(r, &ni); err != nil {
+		return err
+	}
+
+	im, err := th.transferService.Import(r.Context(), ni, time.Now())
+	if err != nil {
+		switch err {
+		case fail.ErrInvalidID, fail.ErrInvalidImport:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case fail.ErrImportsBusy:
+			return web.NewRequestError(err, http.StatusServiceUnavailable)
+		default:
+			return fmt.Errorf("error importing %s project: %w", ni.Format, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, im, http.StatusAccepted)
+}
+
+// RetrieveImport handles retrieve import requests.
+func (th *TransferHandler) RetrieveImport(w http.ResponseWriter, r *http.Request) error {
+	imid := chi.URLParam(r, "imid")
+
+	im, err := th.transferService.RetrieveImport(r.Context(), imid)
+	if err != nil {
+		switch err {
+		case fail.ErrNotFound:
+			return web.NewRequestError(err, http.StatusNotFound)
+		case fail.ErrInvalidID:
+			return web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("error retrieving import %q: %w", imid, err)
+		}
+	}
+
+	return web.Respond(r.Context(), w, im, http.StatusOK)
+}
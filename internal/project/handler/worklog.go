@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// WorklogHandler handles the time tracking requests.
+type WorklogHandler struct {
+	logger         *zap.Logger
+	worklogService worklogService
+}
+
+// NewWorklogHandler returns a new worklog handler.
+func NewWorklogHandler(
+	logger *zap.Logger,
+	worklogService worklogService,
+) *WorklogHandler {
+	return &WorklogHandler{
+		logger:         logger,
+		worklogService: worklogService,
+	}
+}
+
+// List handles list worklog requests.
+func (wh *WorklogHandler) List(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	list, err := wh.worklogService.List(r.Context(), tid)
+	if err != nil {
+		return worklogError(err, fmt.Sprintf("error listing worklogs of task %q", tid))
+	}
+
+	return web.Respond(r.Context(), w, list, http.StatusOK)
+}
+
+// Create handles requests logging time on a task.
+func (wh *WorklogHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	var nw model.NewWorklog
+	if err := web.Decode(r, &nw); err != nil {
+		return err
+	}
+
+	wl, err := wh.worklogService.Create(r.Context(), tid, nw, time.Now())
+	if err != nil {
+		return worklogError(err, fmt.Sprintf("error logging time on task %q", tid))
+	}
+
+	return web.Respond(r.Context(), w, wl, http.StatusCreated)
+}
+
+// Update handles update worklog requests.
+func (wh *WorklogHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	wid := chi.URLParam(r, "wid")
+
+	var uw model.UpdateWorklog
+	if err := web.Decode(r, &uw); err != nil {
+		return err
+	}
+
+	wl, err := wh.worklogService.Update(r.Context(), tid, wid, uw, time.Now())
+	if err != nil {
+		return worklogError(err, fmt.Sprintf("error updating worklog %q", wid))
+	}
+
+	return web.Respond(r.Context(), w, wl, http.StatusOK)
+}
+
+// Delete handles delete worklog requests.
+func (wh *WorklogHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+	wid := chi.URLParam(r, "wid")
+
+	if err := wh.worklogService.Delete(r.Context(), tid, wid); err != nil {
+		return worklogError(err, fmt.Sprintf("error deleting worklog %q", wid))
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// Timer handles requests for the running timer of the user.
+func (wh *WorklogHandler) Timer(w http.ResponseWriter, r *http.Request) error {
+	tm, err := wh.worklogService.Timer(r.Context())
+	if err != nil {
+		return worklogError(err, "error retrieving timer")
+	}
+
+	return web.Respond(r.Context(), w, tm, http.StatusOK)
+}
+
+// StartTimer handles requests starting a timer on a task.
+func (wh *WorklogHandler) StartTimer(w http.ResponseWriter, r *http.Request) error {
+	tid := chi.URLParam(r, "tid")
+
+	tm, err := wh.worklogService.StartTimer(r.Context(), tid, time.Now())
+	if err != nil {
+		return worklogError(err, fmt.Sprintf("error starting timer on task %q", tid))
+	}
+
+	return web.Respond(r.Context(), w, tm, http.StatusCreated)
+}
+
+// StopTimer handles requests stopping the running timer of the user, which logs its time.
+func (wh *WorklogHandler) StopTimer(w http.ResponseWriter, r *http.Request) error {
+	var st model.StopTimer
+	if err := web.Decode(r, &st); err != nil {
+		return err
+	}
+
+	wl, err := wh.worklogService.StopTimer(r.Context(), st, time.Now())
+	if err != nil {
+		return worklogError(err, "error stopping timer")
+	}
+
+	return web.Respond(r.Context(), w, wl, http.StatusCreated)
+}
+
+// DiscardTimer handles requests stopping the running timer of the user without logging
+// time.
+func (wh *WorklogHandler) DiscardTimer(w http.ResponseWriter, r *http.Request) error {
+	if err := wh.worklogService.DiscardTimer(r.Context()); err != nil {
+		return worklogError(err, "error discarding timer")
+	}
+
+	return web.Respond(r.Context(), w, nil, http.StatusOK)
+}
+
+// Report handles time report requests. The from and to query parameters are the first and
+// last days of the report, and project and user optionally narrow it.
+func (wh *WorklogHandler) Report(w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseWorklogFilter(r)
+	if err != nil {
+		return err
+	}
+
+	report, err := wh.worklogService.Report(r.Context(), filter)
+	if err != nil {
+		return worklogError(err, "error reporting time")
+	}
+
+	return web.Respond(r.Context(), w, report, http.StatusOK)
+}
+
+// Export handles requests exporting the worklogs of a time report as CSV for invoicing.
+func (wh *WorklogHandler) Export(w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseWorklogFilter(r)
+	if err != nil {
+		return err
+	}
+
+	entries, err := wh.worklogService.Entries(r.Context(), filter)
+	if err != nil {
+		return worklogError(err, "error exporting worklogs")
+	}
+
+	name := fmt.Sprintf("worklogs-%s-%s.csv", filter.From.Format("2006-01-02"), filter.To.Add(-24*time.Hour).Format("2006-01-02"))
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+	web.SetContextStatusCode(r.Context(), http.StatusOK)
+
+	return model.WriteWorklogCSV(w, entries)
+}
+
+// parseWorklogFilter parses the worklog filter of a time report. The days from and to
+// are both included.
+func parseWorklogFilter(r *http.Request) (model.WorklogFilter, error) {
+	q := r.URL.Query()
+
+	filter := model.WorklogFilter{
+		ProjectID: q.Get("project"),
+		UserID:    q.Get("user"),
+	}
+
+	from, err := time.Parse("2006-01-02", q.Get("from"))
+	if err != nil {
+		return filter, web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+	}
+	to, err := time.Parse("2006-01-02", q.Get("to"))
+	if err != nil {
+		return filter, web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+	}
+	filter.From = from
+	filter.To = to.Add(24 * time.Hour)
+
+	if err = filter.Validate(); err != nil {
+		return filter, web.NewRequestError(fail.ErrInvalidFilter, http.StatusBadRequest)
+	}
+
+	return filter, nil
+}
+
+// worklogError maps time tracking errors to request errors.
+func worklogError(err error, msg string) error {
+	switch err {
+	case fail.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case fail.ErrInvalidID:
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case fail.ErrNotAuthorized:
+		return web.NewRequestError(err, http.StatusForbidden)
+	case fail.ErrTimerRunning:
+		return web.NewRequestError(err, http.StatusConflict)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
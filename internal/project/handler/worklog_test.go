@@ -0,0 +1,168 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/mocks"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+	"github.com/devpies/saas-core/pkg/web/mid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestWorklogHandler_Report(t *testing.T) {
+	filter := model.WorklogFilter{
+		ProjectID: testProjects[0].ID,
+		From:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		handle, deps := setupWorklogRouter()
+
+		report := model.TimeReport{
+			From:    filter.From,
+			To:      filter.To,
+			Minutes: 90,
+			Projects: []model.TimeReportProject{
+				{ProjectID: testProjects[0].ID, ProjectName: testProjects[0].Name, Minutes: 90},
+			},
+			Users: []model.TimeReportUser{{UserID: "ada", Minutes: 90}},
+			Rows: []model.TimeReportRow{
+				{ProjectID: testProjects[0].ID, ProjectName: testProjects[0].Name, UserID: "ada", Date: "2026-03-02", Minutes: 90},
+			},
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/projects/worklogs/report?from=2026-03-01&to=2026-03-31&project="+testProjects[0].ID, nil)
+		w := httptest.NewRecorder()
+
+		deps.worklogService.On("Report", mock.AnythingOfType("*context.valueCtx"), filter).Return(report, nil)
+
+		handle.ServeHTTP(w, r)
+
+		expected, err := json.Marshal(&report)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, w.Body.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code)
+		deps.worklogService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing range", query: ""},
+		{name: "bad date", query: "?from=2026-03-01&to=tomorrow"},
+		{name: "ends before it starts", query: "?from=2026-03-02&to=2026-03-01"},
+		{name: "longer than a year", query: "?from=2025-01-01&to=2026-03-01"},
+		{name: "project is not UUID", query: "?from=2026-03-01&to=2026-03-31&project=website"},
+	}
+
+	for _, tc := range tests {
+		t.Run("error 400 "+tc.name, func(t *testing.T) {
+			handle, deps := setupWorklogRouter()
+
+			r := httptest.NewRequest(http.MethodGet, "/projects/worklogs/report"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handle.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			deps.worklogService.AssertNotCalled(t, "Report")
+		})
+	}
+}
+
+func TestWorklogHandler_Export(t *testing.T) {
+	handle, deps := setupWorklogRouter()
+
+	filter := model.WorklogFilter{
+		UserID: "ada",
+		From:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+	}
+	entries := []model.WorklogEntry{
+		{
+			Worklog: model.Worklog{
+				UserID:    "ada",
+				StartedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+				Minutes:   90,
+				Note:      "Review, fixes",
+			},
+			ProjectName: "Website",
+			TaskKey:     "WEB-1",
+			TaskTitle:   "Fix login",
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/projects/worklogs/export?from=2026-03-01&to=2026-03-02&user=ada", nil)
+	w := httptest.NewRecorder()
+
+	deps.worklogService.On("Entries", mock.AnythingOfType("*context.valueCtx"), filter).Return(entries, nil)
+
+	handle.ServeHTTP(w, r)
+
+	expected := "date,project,task_key,task_title,user_id,started_at,minutes,hours,note\n" +
+		"2026-03-02,Website,WEB-1,Fix login,ada,2026-03-02T09:00:00Z,90,1.50,\"Review, fixes\"\n"
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="worklogs-2026-03-01-2026-03-02.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, expected, w.Body.String())
+	deps.worklogService.AssertExpectations(t)
+}
+
+func TestWorklogHandler_StartTimer(t *testing.T) {
+	tid := "4fd2079c-704f-44ed-af91-0b543c059ba6"
+
+	t.Run("error 409 timer running", func(t *testing.T) {
+		handle, deps := setupWorklogRouter()
+
+		r := httptest.NewRequest(http.MethodPost, "/projects/tasks/"+tid+"/timer", nil)
+		w := httptest.NewRecorder()
+
+		deps.worklogService.On("StartTimer", mock.AnythingOfType("*context.valueCtx"), tid, mock.AnythingOfType("time.Time")).Return(model.Timer{}, fail.ErrTimerRunning)
+
+		handle.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		deps.worklogService.AssertExpectations(t)
+	})
+}
+
+type worklogHandlerDeps struct {
+	logger         *zap.Logger
+	worklogService *mocks.WorklogService
+}
+
+func setupWorklogRouter() (http.Handler, worklogHandlerDeps) {
+	router := chi.NewRouter()
+	logger := zap.NewNop()
+	worklogService := &mocks.WorklogService{}
+	shutdown := make(chan os.Signal, 1)
+
+	middleware := []web.Middleware{
+		mid.Logger(logger),
+		mid.Errors(logger),
+		mid.Panics(logger),
+	}
+
+	worklogs := handler.NewWorklogHandler(logger, worklogService)
+
+	app := web.NewApp(router, shutdown, logger, middleware...)
+	app.Handle(http.MethodGet, "/projects/worklogs/report", worklogs.Report)
+	app.Handle(http.MethodGet, "/projects/worklogs/export", worklogs.Export)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/timer", worklogs.StartTimer)
+
+	return router, worklogHandlerDeps{logger, worklogService}
+}
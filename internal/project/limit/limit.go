@@ -0,0 +1,90 @@
+// Package limit rate limits requests by key.
+//
+// Every key gets a token bucket holding up to burst tokens that refills at a steady rate,
+// and each request takes a token. Buckets live in memory, so every replica limits on its
+// own. Only the most recently used buckets are kept: once there are too many keys, the
+// bucket used least recently is forgotten.
+package limit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// maxKeys is the number of buckets kept.
+const maxKeys = 4096
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// Limiter limits the rate of requests of every key.
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*list.Element
+	// recent orders the buckets from the most to the least recently used.
+	recent *list.List
+}
+
+// New returns a limiter allowing every key perMinute requests a minute on average, and
+// up to burst requests at once.
+func New(perMinute int, burst int) *Limiter {
+	return &Limiter{
+		rate:    float64(perMinute) / time.Minute.Seconds(),
+		burst:   float64(burst),
+		buckets: make(map[string]*list.Element),
+		recent:  list.New(),
+	}
+}
+
+// Allow reports whether a request of the key at the given time is allowed, taking a
+// token when it is.
+func (l *Limiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key, now)
+	b.tokens = l.refill(b, now)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// bucket returns the bucket of the key, marking it as the most recently used. A new
+// bucket starts full and replaces the least recently used one when there are too many.
+func (l *Limiter) bucket(key string, now time.Time) *bucket {
+	if e, ok := l.buckets[key]; ok {
+		l.recent.MoveToFront(e)
+		return e.Value.(*bucket)
+	}
+
+	if l.recent.Len() >= maxKeys {
+		oldest := l.recent.Back()
+		l.recent.Remove(oldest)
+		delete(l.buckets, oldest.Value.(*bucket).key)
+	}
+
+	b := &bucket{key: key, tokens: l.burst, last: now}
+	l.buckets[key] = l.recent.PushFront(b)
+	return b
+}
+
+// refill returns the tokens of a bucket at the given time.
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		tokens += elapsed * l.rate
+	}
+	if tokens > l.burst {
+		tokens = l.burst
+	}
+	return tokens
+}
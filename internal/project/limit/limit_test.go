@@ -0,0 +1,72 @@
+package limit_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/limit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		key     string
+		allowed bool
+	}{
+		{name: "burst 1", key: "a", allowed: true},
+		{name: "burst 2", key: "a", allowed: true},
+		{name: "burst 3", key: "a", allowed: true},
+		{name: "burst spent", key: "a", allowed: false},
+		{name: "other key", key: "b", allowed: true},
+		{name: "not refilled yet", elapsed: 500 * time.Millisecond, key: "a", allowed: false},
+		{name: "refilled one token", elapsed: time.Second, key: "a", allowed: true},
+		{name: "refilled token spent", elapsed: time.Second, key: "a", allowed: false},
+	}
+
+	// 60 requests a minute refill one token a second.
+	l := limit.New(60, 3)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.allowed, l.Allow(tc.key, now.Add(tc.elapsed)))
+		})
+	}
+}
+
+func TestLimiter_AllowRefillsUpToBurst(t *testing.T) {
+	now := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+	l := limit.New(60, 2)
+
+	assert.True(t, l.Allow("a", now))
+	assert.True(t, l.Allow("a", now))
+
+	later := now.Add(time.Hour)
+	assert.True(t, l.Allow("a", later))
+	assert.True(t, l.Allow("a", later))
+	assert.False(t, l.Allow("a", later))
+}
+
+func TestLimiter_AllowForgetsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+	l := limit.New(1, 1)
+
+	assert.True(t, l.Allow("first", now))
+	assert.True(t, l.Allow("recent", now))
+
+	// Filling the limiter with new keys forgets the bucket used least recently.
+	for i := 0; i < 4094; i++ {
+		assert.True(t, l.Allow(strconv.Itoa(i), now))
+	}
+	assert.False(t, l.Allow("recent", now))
+	assert.True(t, l.Allow("new", now))
+
+	// The forgotten bucket starts full again, while the recently used one is kept.
+	assert.True(t, l.Allow("first", now))
+	assert.False(t, l.Allow("recent", now))
+}
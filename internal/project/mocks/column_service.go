@@ -17,6 +17,30 @@ type ColumnService struct {
 	mock.Mock
 }
 
+// AddColumn provides a mock function with given fields: ctx, projectID, ac, now
+func (_m *ColumnService) AddColumn(ctx context.Context, projectID string, ac model.AddColumn, now time.Time) (model.Column, error) {
+	ret := _m.Called(ctx, projectID, ac, now)
+
+	var r0 model.Column
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.AddColumn, time.Time) (model.Column, error)); ok {
+		return rf(ctx, projectID, ac, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.AddColumn, time.Time) model.Column); ok {
+		r0 = rf(ctx, projectID, ac, now)
+	} else {
+		r0 = ret.Get(0).(model.Column)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.AddColumn, time.Time) error); ok {
+		r1 = rf(ctx, projectID, ac, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, nc, now
 func (_m *ColumnService) Create(ctx context.Context, nc model.NewColumn, now time.Time) (model.Column, error) {
 	ret := _m.Called(ctx, nc, now)
@@ -41,20 +65,6 @@ func (_m *ColumnService) Create(ctx context.Context, nc model.NewColumn, now tim
 	return r0, r1
 }
 
-// CreateColumns provides a mock function with given fields: ctx, pid, now
-func (_m *ColumnService) CreateColumns(ctx context.Context, pid string, now time.Time) error {
-	ret := _m.Called(ctx, pid, now)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
-		r0 = rf(ctx, pid, now)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // Delete provides a mock function with given fields: ctx, columnID
 func (_m *ColumnService) Delete(ctx context.Context, columnID string) error {
 	ret := _m.Called(ctx, columnID)
@@ -95,6 +105,34 @@ func (_m *ColumnService) List(ctx context.Context, projectID string) ([]model.Co
 	return r0, r1
 }
 
+// RemoveColumn provides a mock function with given fields: ctx, projectID, columnID, rc, now
+func (_m *ColumnService) RemoveColumn(ctx context.Context, projectID string, columnID string, rc model.RemoveColumn, now time.Time) error {
+	ret := _m.Called(ctx, projectID, columnID, rc, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.RemoveColumn, time.Time) error); ok {
+		r0 = rf(ctx, projectID, columnID, rc, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderColumns provides a mock function with given fields: ctx, projectID, rc, now
+func (_m *ColumnService) ReorderColumns(ctx context.Context, projectID string, rc model.ReorderColumns, now time.Time) error {
+	ret := _m.Called(ctx, projectID, rc, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.ReorderColumns, time.Time) error); ok {
+		r0 = rf(ctx, projectID, rc, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Retrieve provides a mock function with given fields: ctx, columnID
 func (_m *ColumnService) Retrieve(ctx context.Context, columnID string) (model.Column, error) {
 	ret := _m.Called(ctx, columnID)
@@ -41,13 +41,13 @@ func (_m *ProjectService) Create(ctx context.Context, project model.NewProject,
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, projectID
-func (_m *ProjectService) Delete(ctx context.Context, projectID string) error {
-	ret := _m.Called(ctx, projectID)
+// Delete provides a mock function with given fields: ctx, projectID, now
+func (_m *ProjectService) Delete(ctx context.Context, projectID string, now time.Time) error {
+	ret := _m.Called(ctx, projectID, now)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, projectID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, projectID, now)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -55,25 +55,47 @@ func (_m *ProjectService) Delete(ctx context.Context, projectID string) error {
 	return r0
 }
 
-// List provides a mock function with given fields: ctx, all
-func (_m *ProjectService) List(ctx context.Context, all bool) ([]model.Project, error) {
-	ret := _m.Called(ctx, all)
+// List provides a mock function with given fields: ctx, all, archived
+func (_m *ProjectService) List(ctx context.Context, all bool, archived bool) (model.ProjectList, error) {
+	ret := _m.Called(ctx, all, archived)
+
+	var r0 model.ProjectList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool, bool) (model.ProjectList, error)); ok {
+		return rf(ctx, all, archived)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool, bool) model.ProjectList); ok {
+		r0 = rf(ctx, all, archived)
+	} else {
+		r0 = ret.Get(0).(model.ProjectList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool, bool) error); ok {
+		r1 = rf(ctx, all, archived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, projectID, now
+func (_m *ProjectService) Restore(ctx context.Context, projectID string, now time.Time) (model.Project, error) {
+	ret := _m.Called(ctx, projectID, now)
 
-	var r0 []model.Project
+	var r0 model.Project
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]model.Project, error)); ok {
-		return rf(ctx, all)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (model.Project, error)); ok {
+		return rf(ctx, projectID, now)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, bool) []model.Project); ok {
-		r0 = rf(ctx, all)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) model.Project); ok {
+		r0 = rf(ctx, projectID, now)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Project)
-		}
+		r0 = ret.Get(0).(model.Project)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
-		r1 = rf(ctx, all)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, projectID, now)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,6 +127,30 @@ func (_m *ProjectService) Retrieve(ctx context.Context, projectID string) (model
 	return r0, r1
 }
 
+// Trash provides a mock function with given fields: ctx
+func (_m *ProjectService) Trash(ctx context.Context) (model.Trash, error) {
+	ret := _m.Called(ctx)
+
+	var r0 model.Trash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (model.Trash, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) model.Trash); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(model.Trash)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, projectID, update, now
 func (_m *ProjectService) Update(ctx context.Context, projectID string, update model.UpdateProject, now time.Time) (model.Project, error) {
 	ret := _m.Called(ctx, projectID, update, now)
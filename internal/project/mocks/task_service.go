@@ -17,23 +17,23 @@ type TaskService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, task, projectID, now
-func (_m *TaskService) Create(ctx context.Context, task model.NewTask, projectID string, now time.Time) (model.Task, error) {
-	ret := _m.Called(ctx, task, projectID, now)
+// Archive provides a mock function with given fields: ctx, taskID, now
+func (_m *TaskService) Archive(ctx context.Context, taskID string, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, taskID, now)
 
 	var r0 model.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.NewTask, string, time.Time) (model.Task, error)); ok {
-		return rf(ctx, task, projectID, now)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (model.Task, error)); ok {
+		return rf(ctx, taskID, now)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, model.NewTask, string, time.Time) model.Task); ok {
-		r0 = rf(ctx, task, projectID, now)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) model.Task); ok {
+		r0 = rf(ctx, taskID, now)
 	} else {
 		r0 = ret.Get(0).(model.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, model.NewTask, string, time.Time) error); ok {
-		r1 = rf(ctx, task, projectID, now)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, taskID, now)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,13 +41,37 @@ func (_m *TaskService) Create(ctx context.Context, task model.NewTask, projectID
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, taskID
-func (_m *TaskService) Delete(ctx context.Context, taskID string) error {
-	ret := _m.Called(ctx, taskID)
+// Create provides a mock function with given fields: ctx, task, projectID, columnID, now
+func (_m *TaskService) Create(ctx context.Context, task model.NewTask, projectID string, columnID string, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, task, projectID, columnID, now)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.NewTask, string, string, time.Time) (model.Task, error)); ok {
+		return rf(ctx, task, projectID, columnID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.NewTask, string, string, time.Time) model.Task); ok {
+		r0 = rf(ctx, task, projectID, columnID, now)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.NewTask, string, string, time.Time) error); ok {
+		r1 = rf(ctx, task, projectID, columnID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, taskID, now
+func (_m *TaskService) Delete(ctx context.Context, taskID string, now time.Time) error {
+	ret := _m.Called(ctx, taskID, now)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, taskID, now)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -55,25 +79,123 @@ func (_m *TaskService) Delete(ctx context.Context, taskID string) error {
 	return r0
 }
 
-// List provides a mock function with given fields: ctx, projectID
-func (_m *TaskService) List(ctx context.Context, projectID string) ([]model.Task, error) {
-	ret := _m.Called(ctx, projectID)
+// Link provides a mock function with given fields: ctx, taskID, nl, now
+func (_m *TaskService) Link(ctx context.Context, taskID string, nl model.NewTaskLink, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, taskID, nl, now)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.NewTaskLink, time.Time) (model.Task, error)); ok {
+		return rf(ctx, taskID, nl, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.NewTaskLink, time.Time) model.Task); ok {
+		r0 = rf(ctx, taskID, nl, now)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.NewTaskLink, time.Time) error); ok {
+		r1 = rf(ctx, taskID, nl, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, projectID, filter
+func (_m *TaskService) List(ctx context.Context, projectID string, filter model.TaskFilter) ([]model.Task, error) {
+	ret := _m.Called(ctx, projectID, filter)
 
 	var r0 []model.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Task, error)); ok {
-		return rf(ctx, projectID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskFilter) ([]model.Task, error)); ok {
+		return rf(ctx, projectID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Task); ok {
-		r0 = rf(ctx, projectID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TaskFilter) []model.Task); ok {
+		r0 = rf(ctx, projectID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, projectID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.TaskFilter) error); ok {
+		r1 = rf(ctx, projectID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: ctx, taskID, mt, now
+func (_m *TaskService) Move(ctx context.Context, taskID string, mt model.MoveTask, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, taskID, mt, now)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.MoveTask, time.Time) (model.Task, error)); ok {
+		return rf(ctx, taskID, mt, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.MoveTask, time.Time) model.Task); ok {
+		r0 = rf(ctx, taskID, mt, now)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.MoveTask, time.Time) error); ok {
+		r1 = rf(ctx, taskID, mt, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Query provides a mock function with given fields: ctx, tq, now
+func (_m *TaskService) Query(ctx context.Context, tq model.TaskQuery, now time.Time) ([]model.Task, error) {
+	ret := _m.Called(ctx, tq, now)
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskQuery, time.Time) ([]model.Task, error)); ok {
+		return rf(ctx, tq, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskQuery, time.Time) []model.Task); ok {
+		r0 = rf(ctx, tq, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TaskQuery, time.Time) error); ok {
+		r1 = rf(ctx, tq, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, taskID, now
+func (_m *TaskService) Restore(ctx context.Context, taskID string, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, taskID, now)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (model.Task, error)); ok {
+		return rf(ctx, taskID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) model.Task); ok {
+		r0 = rf(ctx, taskID, now)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, taskID, now)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,6 +227,116 @@ func (_m *TaskService) Retrieve(ctx context.Context, taskID string) (model.Task,
 	return r0, r1
 }
 
+// RetrieveByKey provides a mock function with given fields: ctx, projectID, key
+func (_m *TaskService) RetrieveByKey(ctx context.Context, projectID string, key string) (model.Task, error) {
+	ret := _m.Called(ctx, projectID, key)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (model.Task, error)); ok {
+		return rf(ctx, projectID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) model.Task); ok {
+		r0 = rf(ctx, projectID, key)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, projectID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, search, all
+func (_m *TaskService) Search(ctx context.Context, search model.TaskSearch, all bool) (model.TaskSearchResults, error) {
+	ret := _m.Called(ctx, search, all)
+
+	var r0 model.TaskSearchResults
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskSearch, bool) (model.TaskSearchResults, error)); ok {
+		return rf(ctx, search, all)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskSearch, bool) model.TaskSearchResults); ok {
+		r0 = rf(ctx, search, all)
+	} else {
+		r0 = ret.Get(0).(model.TaskSearchResults)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TaskSearch, bool) error); ok {
+		r1 = rf(ctx, search, all)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetParent provides a mock function with given fields: ctx, taskID, sp, now
+func (_m *TaskService) SetParent(ctx context.Context, taskID string, sp model.SetParent, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, taskID, sp, now)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SetParent, time.Time) (model.Task, error)); ok {
+		return rf(ctx, taskID, sp, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SetParent, time.Time) model.Task); ok {
+		r0 = rf(ctx, taskID, sp, now)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.SetParent, time.Time) error); ok {
+		r1 = rf(ctx, taskID, sp, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unarchive provides a mock function with given fields: ctx, taskID, now
+func (_m *TaskService) Unarchive(ctx context.Context, taskID string, now time.Time) (model.Task, error) {
+	ret := _m.Called(ctx, taskID, now)
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (model.Task, error)); ok {
+		return rf(ctx, taskID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) model.Task); ok {
+		r0 = rf(ctx, taskID, now)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, taskID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlink provides a mock function with given fields: ctx, taskID, linkID, now
+func (_m *TaskService) Unlink(ctx context.Context, taskID string, linkID string, now time.Time) error {
+	ret := _m.Called(ctx, taskID, linkID, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, taskID, linkID, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, taskID, update, now
 func (_m *TaskService) Update(ctx context.Context, taskID string, update model.UpdateTask, now time.Time) (model.Task, error) {
 	ret := _m.Called(ctx, taskID, update, now)
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Kinds of board changes.
+const (
+	ChangeTask    = "task"
+	ChangeColumn  = "column"
+	ChangeComment = "comment"
+)
+
+// Actions of board changes.
+const (
+	ActionCreated    = "created"
+	ActionUpdated    = "updated"
+	ActionMoved      = "moved"
+	ActionDeleted    = "deleted"
+	ActionRestored   = "restored"
+	ActionArchived   = "archived"
+	ActionUnarchived = "unarchived"
+	ActionLinked     = "linked"
+	ActionUnlinked   = "unlinked"
+	ActionReordered  = "reordered"
+)
+
+// Change represents a change to a project board pushed to the users watching it. It
+// tells clients what to reload rather than carrying the changed entity. TaskID is set
+// for comments.
+type Change struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenantId"`
+	ProjectID  string    `json:"projectId"`
+	Kind       string    `json:"kind"`
+	Action     string    `json:"action"`
+	EntityID   string    `json:"entityId"`
+	TaskID     string    `json:"taskId,omitempty"`
+	UserID     string    `json:"userId"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
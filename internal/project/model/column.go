@@ -1,11 +1,49 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// MaxColumns is the maximum number of columns on a project board.
+const MaxColumns = 10
+
+// ColumnName returns the name of the column at index i of a new board.
+func ColumnName(i int) string {
+	return fmt.Sprintf("column-%d", i+1)
+}
+
+// Column policies. A column may limit how many tasks it holds, and require tasks entering
+// it to meet entry rules.
+const (
+	PolicyWIPLimit = "wip_limit"
+	RuleAssignee   = "assignee"
+	RulePoints     = "points"
+	RuleDueDate    = "due_date"
+)
+
+// UnmetEntryRules returns the entry rules a task does not meet.
+func UnmetEntryRules(rules []string, t Task) []string {
+	var unmet []string
+	for _, rule := range rules {
+		var met bool
+		switch rule {
+		case RuleAssignee:
+			met = t.AssignedTo != ""
+		case RulePoints:
+			met = t.Points > 0
+		case RuleDueDate:
+			met = t.DueAt != nil
+		}
+		if !met {
+			unmet = append(unmet, rule)
+		}
+	}
+	return unmet
+}
+
 var columnValidator *validator.Validate
 
 func init() {
@@ -13,13 +51,16 @@ func init() {
 	columnValidator = v
 }
 
-// Column represents a Project Column.
+// Column represents a Project Column. TaskCount counts its tasks against WIPLimit.
 type Column struct {
 	ID         string    `db:"column_id" json:"id"`
 	TenantID   string    `db:"tenant_id" json:"tenantID"`
 	Title      string    `db:"title" json:"title"`
 	ColumnName string    `db:"column_name" json:"columnName"`
-	TaskIDS    []string  `db:"task_ids" json:"taskIds"`
+	WIPLimit   *int      `db:"wip_limit" json:"wipLimit"`
+	EntryRules []string  `db:"entry_rules" json:"entryRules"`
+	TaskIDS    []string  `db:"task_ids" json:"taskIds"` // ordered by task rank, read only
+	TaskCount  int       `db:"-" json:"taskCount"`      // read only
 	ProjectID  string    `db:"project_id" json:"projectId"`
 	UpdatedAt  time.Time `db:"updated_at" json:"updatedAt"`
 	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
@@ -37,13 +78,47 @@ func (nc *NewColumn) Validate() error {
 	return columnValidator.Struct(nc)
 }
 
-// UpdateColumn represents a Column update.
+// UpdateColumn represents a Column update. A WIPLimit of 0 removes the limit, and
+// EntryRules replaces the entry rules when given, an empty list removes them all.
 type UpdateColumn struct {
-	Title   *string  `json:"title" validate:"omitempty,max=24"`
-	TaskIDS []string `json:"taskIds"`
+	Title      *string  `json:"title" validate:"omitempty,max=24"`
+	WIPLimit   *int     `json:"wipLimit" validate:"omitempty,min=0"`
+	EntryRules []string `json:"entryRules" validate:"omitempty,unique,dive,oneof=assignee points due_date"`
 }
 
 // Validate validates the UpdateColumn.
 func (uc *UpdateColumn) Validate() error {
 	return columnValidator.Struct(uc)
 }
+
+// AddColumn represents a Column added to a project board.
+type AddColumn struct {
+	Title string `json:"title" validate:"required,max=24"`
+}
+
+// Validate validates an AddColumn.
+func (ac *AddColumn) Validate() error {
+	return columnValidator.Struct(ac)
+}
+
+// RemoveColumn represents a Column removed from a project board. Its tasks are moved
+// to the MoveTo column, or moved to the trash when MoveTo is empty.
+type RemoveColumn struct {
+	MoveTo string `json:"moveTo" validate:"omitempty,uuid"`
+}
+
+// Validate validates a RemoveColumn.
+func (rc *RemoveColumn) Validate() error {
+	return columnValidator.Struct(rc)
+}
+
+// ReorderColumns represents a new column order for a project board. It must list
+// every column of the project exactly once.
+type ReorderColumns struct {
+	ColumnOrder []string `json:"columnOrder" validate:"required,min=1,max=10,unique"`
+}
+
+// Validate validates a ReorderColumns.
+func (rc *ReorderColumns) Validate() error {
+	return columnValidator.Struct(rc)
+}
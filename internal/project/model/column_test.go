@@ -2,6 +2,7 @@ package model_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/devpies/saas-core/internal/project/model"
 
@@ -124,6 +125,43 @@ func TestUpdateColumn_Validate(t *testing.T) {
 			},
 			err: "failed on the 'max' tag",
 		},
+		{
+			name: "policies",
+			modifier: func(uc *model.UpdateColumn) {
+				uc.WIPLimit = aws.Int(3)
+				uc.EntryRules = []string{model.RuleAssignee, model.RulePoints}
+			},
+			err: "",
+		},
+		{
+			name: "wip limit removed",
+			modifier: func(uc *model.UpdateColumn) {
+				uc.WIPLimit = aws.Int(0)
+				uc.EntryRules = []string{}
+			},
+			err: "",
+		},
+		{
+			name: "negative wip limit",
+			modifier: func(uc *model.UpdateColumn) {
+				uc.WIPLimit = aws.Int(-1)
+			},
+			err: "failed on the 'min' tag",
+		},
+		{
+			name: "unknown entry rule",
+			modifier: func(uc *model.UpdateColumn) {
+				uc.EntryRules = []string{"reviewer"}
+			},
+			err: "failed on the 'oneof' tag",
+		},
+		{
+			name: "repeated entry rule",
+			modifier: func(uc *model.UpdateColumn) {
+				uc.EntryRules = []string{model.RulePoints, model.RulePoints}
+			},
+			err: "failed on the 'unique' tag",
+		},
 	}
 
 	for _, tc := range tests {
@@ -147,3 +185,74 @@ func TestUpdateColumn_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmetEntryRules(t *testing.T) {
+	due := time.Now()
+	rules := []string{model.RuleAssignee, model.RulePoints, model.RuleDueDate}
+
+	tests := []struct {
+		name  string
+		task  model.Task
+		unmet []string
+	}{
+		{name: "no rule met", task: model.Task{}, unmet: rules},
+		{name: "assigned", task: model.Task{AssignedTo: "user"}, unmet: []string{model.RulePoints, model.RuleDueDate}},
+		{name: "every rule met", task: model.Task{AssignedTo: "user", Points: 3, DueAt: &due}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.unmet, model.UnmetEntryRules(rules, tc.task))
+		})
+	}
+
+	assert.Empty(t, model.UnmetEntryRules(nil, model.Task{}))
+}
+
+func TestReorderColumns_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []string
+		err   string
+	}{
+		{
+			name:  "valid",
+			order: []string{"column-2", "column-1"},
+			err:   "",
+		},
+		{
+			name:  "empty",
+			order: []string{},
+			err:   "failed on the 'min' tag",
+		},
+		{
+			name:  "duplicate column",
+			order: []string{"column-1", "column-1"},
+			err:   "failed on the 'unique' tag",
+		},
+		{
+			name:  "too many columns",
+			order: []string{"c1", "c2", "c3", "c4", "c5", "c6", "c7", "c8", "c9", "c10", "c11"},
+			err:   "failed on the 'max' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := model.ReorderColumns{ColumnOrder: tc.order}
+
+			err := rc.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
@@ -16,8 +16,11 @@ func init() {
 // Comment represents a comment on a Task.
 type Comment struct {
 	ID        string    `db:"comment_id" json:"commentId"`
+	TaskID    string    `db:"task_id" json:"taskId"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
 	Content   string    `db:"content" json:"content"`
 	UserID    string    `db:"user_id" json:"userId"`
+	Likes     int       `db:"likes" json:"likes"`
 	Liked     bool      `db:"liked" json:"liked"`
 	Edited    bool      `db:"edited" json:"edited"`
 	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
@@ -34,7 +37,8 @@ func (nc *NewComment) Validate() error {
 	return commentValidator.Struct(nc)
 }
 
-// UpdateComment represents a comment update.
+// UpdateComment represents a comment update. Content may only be changed by the author,
+// while Liked likes or unlikes the comment on behalf of the requesting user.
 type UpdateComment struct {
 	Content *string `json:"content" validate:"omitempty,max=500"`
 	Liked   *bool   `json:"liked"`
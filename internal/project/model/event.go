@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var eventValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	eventValidator = v
+}
+
+// Task event kinds.
+const (
+	TaskCreated    = "created"
+	TaskUpdated    = "updated"
+	TaskMoved      = "moved"
+	TaskCommented  = "commented"
+	TaskDeleted    = "deleted"
+	TaskRestored   = "restored"
+	TaskArchived   = "archived"
+	TaskUnarchived = "unarchived"
+	TaskLinked     = "linked"
+	TaskUnlinked   = "unlinked"
+)
+
+// DefaultActivityLimit is the number of events returned when no limit is given.
+const DefaultActivityLimit = 50
+
+// TaskEvent represents a change made to a Task.
+type TaskEvent struct {
+	ID        string                 `db:"event_id" json:"id"`
+	TenantID  string                 `db:"tenant_id" json:"tenantId"`
+	ProjectID string                 `db:"project_id" json:"projectId"`
+	TaskID    string                 `db:"task_id" json:"taskId"`
+	UserID    string                 `db:"user_id" json:"userId"`
+	Kind      string                 `db:"kind" json:"kind"`
+	Changes   map[string]FieldChange `db:"changes" json:"changes"`
+	CreatedAt time.Time              `db:"created_at" json:"createdAt"`
+}
+
+// FieldChange represents the previous and new value of a changed Task field.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// ActivityPage represents a page of events, newest first.
+type ActivityPage struct {
+	Limit  int `validate:"min=1,max=100"`
+	Offset int `validate:"min=0"`
+}
+
+// Validate validates an ActivityPage.
+func (ap *ActivityPage) Validate() error {
+	return eventValidator.Struct(ap)
+}
@@ -0,0 +1,55 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityPage_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		page model.ActivityPage
+		err  string
+	}{
+		{
+			name: "valid",
+			page: model.ActivityPage{Limit: model.DefaultActivityLimit},
+			err:  "",
+		},
+		{
+			name: "limit too small",
+			page: model.ActivityPage{Limit: 0},
+			err:  "failed on the 'min' tag",
+		},
+		{
+			name: "limit too large",
+			page: model.ActivityPage{Limit: 101},
+			err:  "failed on the 'max' tag",
+		},
+		{
+			name: "negative offset",
+			page: model.ActivityPage{Limit: 10, Offset: -1},
+			err:  "failed on the 'min' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.page.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var fieldValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	v.RegisterStructValidation(newCustomFieldOptions, NewCustomField{})
+	fieldValidator = v
+}
+
+// Custom field kinds.
+const (
+	FieldText        = "text"
+	FieldNumber      = "number"
+	FieldSelect      = "select"
+	FieldMultiSelect = "multi_select"
+	FieldDate        = "date"
+	FieldUser        = "user"
+)
+
+// MaxCustomFields is the maximum number of custom fields of a project.
+const MaxCustomFields = 30
+
+// MaxFieldText is the maximum length of the value of a text field.
+const MaxFieldText = 500
+
+// CustomField represents a field a Project adds to its tasks. Select and multi-select
+// fields take their values from Options.
+type CustomField struct {
+	ID        string    `db:"field_id" json:"id"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	Name      string    `db:"name" json:"name"`
+	Kind      string    `db:"kind" json:"kind"`
+	Options   []string  `db:"options" json:"options"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewCustomField represents a new CustomField. Only select and multi-select fields have
+// options, and they must have at least one.
+type NewCustomField struct {
+	Name    string   `json:"name" validate:"required,max=30"`
+	Kind    string   `json:"kind" validate:"required,oneof=text number select multi_select date user"`
+	Options []string `json:"options" validate:"max=50,unique,dive,required,max=30"`
+}
+
+// Validate validates a NewCustomField.
+func (nf *NewCustomField) Validate() error {
+	return fieldValidator.Struct(nf)
+}
+
+func newCustomFieldOptions(sl validator.StructLevel) {
+	nf := sl.Current().Interface().(NewCustomField)
+	if HasOptions(nf.Kind) != (len(nf.Options) > 0) {
+		sl.ReportError(nf.Options, "options", "Options", "options", "")
+	}
+}
+
+// UpdateCustomField represents a CustomField update. The kind of a field cannot change.
+// Values using options that are removed are removed too.
+type UpdateCustomField struct {
+	Name    *string  `json:"name" validate:"omitempty,min=1,max=30"`
+	Options []string `json:"options" validate:"omitempty,max=50,unique,dive,required,max=30"`
+}
+
+// Validate validates an UpdateCustomField.
+func (uf *UpdateCustomField) Validate() error {
+	return fieldValidator.Struct(uf)
+}
+
+// HasOptions reports whether fields of the kind take their values from options.
+func HasOptions(kind string) bool {
+	return kind == FieldSelect || kind == FieldMultiSelect
+}
+
+// FieldValue represents the value of a custom field on a task, stored by type. Only the
+// member matching the kind of the field is set: Text for text and user fields, Number,
+// Date, or Options for select and multi-select fields.
+type FieldValue struct {
+	Text    *string
+	Number  *float64
+	Date    *time.Time
+	Options []string
+}
+
+// FieldValues maps custom field ids to their JSON values on a task.
+type FieldValues map[string]json.RawMessage
+
+// IsNull reports whether a raw field value is JSON null, which clears the field.
+func IsNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// ParseValue parses a JSON value of the field: a string for text and user fields, a
+// number, a date like 2024-01-31, one of the options for select fields and a list of
+// distinct options for multi-select fields. It reports false when the value does not
+// suit the field.
+func (f CustomField) ParseValue(raw json.RawMessage) (FieldValue, bool) {
+	var v FieldValue
+
+	switch f.Kind {
+	case FieldText, FieldUser:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || s == "" {
+			return v, false
+		}
+		if (f.Kind == FieldText && len([]rune(s)) > MaxFieldText) || (f.Kind == FieldUser && len(s) > 36) {
+			return v, false
+		}
+		v.Text = &s
+	case FieldNumber:
+		var n float64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return v, false
+		}
+		v.Number = &n
+	case FieldDate:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return v, false
+		}
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return v, false
+		}
+		v.Date = &d
+	case FieldSelect:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || !f.hasOption(s) {
+			return v, false
+		}
+		v.Options = []string{s}
+	case FieldMultiSelect:
+		var list []string
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return v, false
+		}
+		seen := make(map[string]bool, len(list))
+		for _, s := range list {
+			if !f.hasOption(s) || seen[s] {
+				return v, false
+			}
+			seen[s] = true
+		}
+		v.Options = append(make([]string, 0, len(list)), list...)
+	default:
+		return v, false
+	}
+
+	return v, true
+}
+
+func (f CustomField) hasOption(s string) bool {
+	for _, o := range f.Options {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package model_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCustomField_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nf *model.NewCustomField)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nf *model.NewCustomField) {},
+			err:      "",
+		},
+		{
+			name: "missing name",
+			modifier: func(nf *model.NewCustomField) {
+				nf.Name = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "unknown kind",
+			modifier: func(nf *model.NewCustomField) {
+				nf.Kind = "color"
+			},
+			err: "failed on the 'oneof' tag",
+		},
+		{
+			name: "select without options",
+			modifier: func(nf *model.NewCustomField) {
+				nf.Options = nil
+			},
+			err: "failed on the 'options' tag",
+		},
+		{
+			name: "options of a text field",
+			modifier: func(nf *model.NewCustomField) {
+				nf.Kind = model.FieldText
+			},
+			err: "failed on the 'options' tag",
+		},
+		{
+			name: "duplicate options",
+			modifier: func(nf *model.NewCustomField) {
+				nf.Options = []string{"web", "web"}
+			},
+			err: "failed on the 'unique' tag",
+		},
+		{
+			name: "option too long",
+			modifier: func(nf *model.NewCustomField) {
+				nf.Options = []string{strings.Repeat("a", 31)}
+			},
+			err: "failed on the 'max' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nf := model.NewCustomField{
+				Name:    "Platform",
+				Kind:    model.FieldSelect,
+				Options: []string{"web", "ios"},
+			}
+
+			tc.modifier(&nf)
+
+			err := nf.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestCustomField_ParseValue(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		raw  string
+		ok   bool
+	}{
+		{name: "text", kind: model.FieldText, raw: `"Acme"`, ok: true},
+		{name: "empty text", kind: model.FieldText, raw: `""`},
+		{name: "text too long", kind: model.FieldText, raw: `"` + strings.Repeat("a", model.MaxFieldText+1) + `"`},
+		{name: "number", kind: model.FieldNumber, raw: `2.5`, ok: true},
+		{name: "number as text", kind: model.FieldNumber, raw: `"2.5"`},
+		{name: "date", kind: model.FieldDate, raw: `"2024-01-31"`, ok: true},
+		{name: "date with time", kind: model.FieldDate, raw: `"2024-01-31T10:00:00Z"`},
+		{name: "select", kind: model.FieldSelect, raw: `"ios"`, ok: true},
+		{name: "unknown option", kind: model.FieldSelect, raw: `"desktop"`},
+		{name: "multi-select", kind: model.FieldMultiSelect, raw: `["web", "ios"]`, ok: true},
+		{name: "repeated option", kind: model.FieldMultiSelect, raw: `["web", "web"]`},
+		{name: "user", kind: model.FieldUser, raw: `"0ef64d03-8a91-4513-907c-dd1fcfcfeb46"`, ok: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := model.CustomField{Kind: tc.kind, Options: []string{"web", "ios"}}
+
+			_, ok := f.ParseValue(json.RawMessage(tc.raw))
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
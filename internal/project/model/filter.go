@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var filterValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	filterValidator = v
+}
+
+// SavedFilter represents a named task query. A filter with a ProjectID queries the tasks
+// of that project, otherwise it queries every project of the tenant. Shared filters are
+// visible to everyone in the tenant, but only their creator changes them.
+type SavedFilter struct {
+	ID        string    `db:"filter_id" json:"id"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	Name      string    `db:"name" json:"name"`
+	Query     string    `db:"query" json:"query"`
+	Shared    bool      `db:"shared" json:"shared"`
+	UserID    string    `db:"user_id" json:"userId"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewSavedFilter represents a new SavedFilter.
+type NewSavedFilter struct {
+	Name      string `json:"name" validate:"required,max=50"`
+	Query     string `json:"query" validate:"max=500"`
+	ProjectID string `json:"projectId" validate:"omitempty,uuid"`
+	Shared    bool   `json:"shared"`
+}
+
+// Validate validates a NewSavedFilter.
+func (nf *NewSavedFilter) Validate() error {
+	return filterValidator.Struct(nf)
+}
+
+// UpdateSavedFilter represents a SavedFilter update.
+type UpdateSavedFilter struct {
+	Name   *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Query  *string `json:"query" validate:"omitempty,max=500"`
+	Shared *bool   `json:"shared"`
+}
+
+// Validate validates an UpdateSavedFilter.
+func (uf *UpdateSavedFilter) Validate() error {
+	return filterValidator.Struct(uf)
+}
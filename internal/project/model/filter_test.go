@@ -0,0 +1,77 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSavedFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nf *model.NewSavedFilter)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nf *model.NewSavedFilter) {},
+			err:      "",
+		},
+		{
+			name: "across projects",
+			modifier: func(nf *model.NewSavedFilter) {
+				nf.ProjectID = ""
+			},
+			err: "",
+		},
+		{
+			name: "missing name",
+			modifier: func(nf *model.NewSavedFilter) {
+				nf.Name = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "query too long",
+			modifier: func(nf *model.NewSavedFilter) {
+				nf.Query = strings.Repeat("a", 501)
+			},
+			err: "failed on the 'max' tag",
+		},
+		{
+			name: "project is not UUID",
+			modifier: func(nf *model.NewSavedFilter) {
+				nf.ProjectID = "project"
+			},
+			err: "failed on the 'uuid' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nf := model.NewSavedFilter{
+				Name:      "My bugs",
+				Query:     "assignee = me and label in (bug)",
+				ProjectID: "8695a94f-7e0a-4198-8c0a-d3e12727a5ba",
+			}
+
+			tc.modifier(&nf)
+
+			err := nf.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var labelValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	labelValidator = v
+}
+
+// Label represents a Project Label used to triage tasks.
+type Label struct {
+	ID        string    `db:"label_id" json:"id"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	Name      string    `db:"name" json:"name"`
+	Color     string    `db:"color" json:"color"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewLabel represents a new Label.
+type NewLabel struct {
+	Name  string `json:"name" validate:"required,max=30"`
+	Color string `json:"color" validate:"required,hexcolor,len=7"`
+}
+
+// Validate validates a NewLabel.
+func (nl *NewLabel) Validate() error {
+	return labelValidator.Struct(nl)
+}
+
+// UpdateLabel represents a Label update.
+type UpdateLabel struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=30"`
+	Color *string `json:"color" validate:"omitempty,hexcolor,len=7"`
+}
+
+// Validate validates an UpdateLabel.
+func (ul *UpdateLabel) Validate() error {
+	return labelValidator.Struct(ul)
+}
@@ -0,0 +1,68 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLabel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nl *model.NewLabel)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nl *model.NewLabel) {},
+			err:      "",
+		},
+		{
+			name: "missing name",
+			modifier: func(nl *model.NewLabel) {
+				nl.Name = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "color is not hex",
+			modifier: func(nl *model.NewLabel) {
+				nl.Color = "red"
+			},
+			err: "failed on the 'hexcolor' tag",
+		},
+		{
+			name: "short hex color",
+			modifier: func(nl *model.NewLabel) {
+				nl.Color = "#fff"
+			},
+			err: "failed on the 'len' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nl := model.NewLabel{
+				Name:  "Bug",
+				Color: "#d73a4a",
+			}
+
+			tc.modifier(&nl)
+
+			err := nl.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
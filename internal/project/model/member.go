@@ -0,0 +1,117 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var memberValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	memberValidator = v
+}
+
+// Project roles. Owners manage the project and its members, editors change its board and
+// viewers only read it.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// RoleAllows reports whether role grants at least the permissions of required.
+func RoleAllows(role string, required string) bool {
+	return roleRanks[role] > 0 && roleRanks[role] >= roleRanks[required]
+}
+
+// ProjectMember represents a user given a role in a project.
+type ProjectMember struct {
+	ProjectID string    `db:"project_id" json:"projectId"`
+	UserID    string    `db:"user_id" json:"userId"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	Role      string    `db:"role" json:"role"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Membership represents everyone with access to a project through a role: its members
+// and the members of its team.
+type Membership struct {
+	ProjectID   string          `json:"projectId"`
+	TeamID      string          `json:"teamId"`
+	Members     []ProjectMember `json:"members"`
+	TeamMembers []string        `json:"teamMembers"`
+}
+
+// NewProjectMember represents a user added to a project.
+type NewProjectMember struct {
+	UserID string `json:"userId" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+// Validate validates NewProjectMember.
+func (nm *NewProjectMember) Validate() error {
+	return memberValidator.Struct(nm)
+}
+
+// UpdateProjectMember represents a change to the role of a project member.
+type UpdateProjectMember struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+// Validate validates UpdateProjectMember.
+func (um *UpdateProjectMember) Validate() error {
+	return memberValidator.Struct(um)
+}
+
+// AssignTeam represents the team assigned to a project. An empty TeamID unassigns the
+// current team.
+type AssignTeam struct {
+	TeamID string `json:"teamId" validate:"omitempty,uuid"`
+}
+
+// Validate validates AssignTeam.
+func (at *AssignTeam) Validate() error {
+	return memberValidator.Struct(at)
+}
+
+// Team represents a group of tenant users. The members of a team edit the projects it
+// is assigned to. Only the user who created a team manages it.
+type Team struct {
+	ID        string    `db:"team_id" json:"id"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	Name      string    `db:"name" json:"name"`
+	UserID    string    `db:"user_id" json:"userId"`
+	Members   []string  `db:"members" json:"members"`
+	Projects  []string  `db:"projects" json:"projects"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewTeam represents a new Team.
+type NewTeam struct {
+	Name string `json:"name" validate:"required,max=32"`
+}
+
+// Validate validates NewTeam.
+func (nt *NewTeam) Validate() error {
+	return memberValidator.Struct(nt)
+}
+
+// NewTeamMember represents a user added to a team.
+type NewTeamMember struct {
+	UserID string `json:"userId" validate:"required,uuid"`
+}
+
+// Validate validates NewTeamMember.
+func (nm *NewTeamMember) Validate() error {
+	return memberValidator.Struct(nm)
+}
@@ -0,0 +1,88 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProjectMember_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nm *model.NewProjectMember)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nm *model.NewProjectMember) {},
+			err:      "",
+		},
+		{
+			name: "user id is not UUID",
+			modifier: func(nm *model.NewProjectMember) {
+				nm.UserID = "user"
+			},
+			err: "failed on the 'uuid' tag",
+		},
+		{
+			name: "unknown role",
+			modifier: func(nm *model.NewProjectMember) {
+				nm.Role = "admin"
+			},
+			err: "failed on the 'oneof' tag",
+		},
+		{
+			name: "missing role",
+			modifier: func(nm *model.NewProjectMember) {
+				nm.Role = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nm := model.NewProjectMember{
+				UserID: "0ef64d03-8a91-4513-907c-dd1fcfcfeb46",
+				Role:   model.RoleEditor,
+			}
+
+			tc.modifier(&nm)
+
+			err := nm.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{role: model.RoleOwner, required: model.RoleEditor, expected: true},
+		{role: model.RoleEditor, required: model.RoleEditor, expected: true},
+		{role: model.RoleViewer, required: model.RoleEditor, expected: false},
+		{role: model.RoleViewer, required: model.RoleViewer, expected: true},
+		{role: "", required: model.RoleViewer, expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.role+" as "+tc.required, func(t *testing.T) {
+			assert.Equal(t, tc.expected, model.RoleAllows(tc.role, tc.required))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var notificationValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	notificationValidator = v
+}
+
+// Notification reasons, from the most to the least specific. A user notified of an
+// event for several reasons gets a single notification with the most specific one.
+const (
+	ReasonAssigned  = "assigned"
+	ReasonMentioned = "mentioned"
+	ReasonCommented = "commented"
+	ReasonChanged   = "changed"
+)
+
+// DefaultNotificationLimit is the number of notifications returned when no limit is given.
+const DefaultNotificationLimit = 50
+
+// Notification represents a task event in the inbox of a user. Reason tells why the user
+// is notified, and ActorID is the user who caused the event.
+type Notification struct {
+	ID        string     `db:"notification_id" json:"id"`
+	TenantID  string     `db:"tenant_id" json:"tenantId"`
+	UserID    string     `db:"user_id" json:"userId"`
+	Reason    string     `db:"reason" json:"reason"`
+	EventID   string     `db:"event_id" json:"eventId"`
+	EventKind string     `db:"event_kind" json:"eventKind"`
+	ActorID   string     `db:"actor_id" json:"actorId"`
+	ProjectID string     `db:"project_id" json:"projectId"`
+	TaskID    string     `db:"task_id" json:"taskId"`
+	TaskKey   string     `db:"task_key" json:"taskKey"`
+	TaskTitle string     `db:"task_title" json:"taskTitle"`
+	ReadAt    *time.Time `db:"read_at" json:"readAt"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// Inbox represents a page of the notifications of a user, newest first, along with the
+// number of notifications the user has not read.
+type Inbox struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+}
+
+// InboxPage represents a page of notifications. Unread leaves out read notifications.
+type InboxPage struct {
+	Unread bool
+	Limit  int `validate:"min=1,max=100"`
+	Offset int `validate:"min=0"`
+}
+
+// Validate validates an InboxPage.
+func (ip *InboxPage) Validate() error {
+	return notificationValidator.Struct(ip)
+}
+
+// UpdateNotification represents a notification marked as read or unread.
+type UpdateNotification struct {
+	Read *bool `json:"read" validate:"required"`
+}
+
+// Validate validates an UpdateNotification.
+func (un *UpdateNotification) Validate() error {
+	return notificationValidator.Struct(un)
+}
+
+// ReadNotifications represents the number of notifications marked as read at once.
+type ReadNotifications struct {
+	Read int `json:"read"`
+}
+
+// NotificationPreferences represents the reasons a user wants to be notified for.
+type NotificationPreferences struct {
+	UserID    string    `db:"user_id" json:"userId"`
+	Assigned  bool      `db:"assigned" json:"assigned"`
+	Mentioned bool      `db:"mentioned" json:"mentioned"`
+	Commented bool      `db:"commented" json:"commented"`
+	Changed   bool      `db:"changed" json:"changed"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// DefaultNotificationPreferences returns the preferences of a user who never set any,
+// which allow every notification.
+func DefaultNotificationPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:    userID,
+		Assigned:  true,
+		Mentioned: true,
+		Commented: true,
+		Changed:   true,
+	}
+}
+
+// Allows reports whether the preferences allow notifications for the reason.
+func (np NotificationPreferences) Allows(reason string) bool {
+	switch reason {
+	case ReasonAssigned:
+		return np.Assigned
+	case ReasonMentioned:
+		return np.Mentioned
+	case ReasonCommented:
+		return np.Commented
+	case ReasonChanged:
+		return np.Changed
+	default:
+		return false
+	}
+}
+
+// UpdateNotificationPreferences represents a NotificationPreferences update.
+type UpdateNotificationPreferences struct {
+	Assigned  *bool `json:"assigned"`
+	Mentioned *bool `json:"mentioned"`
+	Commented *bool `json:"commented"`
+	Changed   *bool `json:"changed"`
+}
+
+// Validate validates an UpdateNotificationPreferences.
+func (up *UpdateNotificationPreferences) Validate() error {
+	return notificationValidator.Struct(up)
+}
+
+// Watcher represents a user watching a Task.
+type Watcher struct {
+	TaskID    string    `db:"task_id" json:"taskId"`
+	UserID    string    `db:"user_id" json:"userId"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Dispatched represents the task events turned into notifications by a dispatch.
+type Dispatched struct {
+	Events        int
+	Notifications int
+}
+
+var mentionPattern = regexp.MustCompile(`(?i)(^|[^\w@])@([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})\b`)
+
+// Mentions returns the users mentioned in a text, in order and without duplicates. Users
+// are mentioned by their id, as in @4fd2079c-704f-44ed-af91-0b543c059ba6, which clients
+// display as the name of the user.
+func Mentions(text string) []string {
+	var users []string
+
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		id := strings.ToLower(m[2])
+		if !seen[id] {
+			seen[id] = true
+			users = append(users, id)
+		}
+	}
+	return users
+}
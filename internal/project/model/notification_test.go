@@ -0,0 +1,67 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentions(t *testing.T) {
+	ada := "4fd2079c-704f-44ed-af91-0b543c059ba6"
+	bob := "a4a420db-c6d6-4209-af96-5e5408a57bfe"
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "none", text: "Rotate the certificates", want: nil},
+		{name: "single", text: "@" + ada + " can you look?", want: []string{ada}},
+		{name: "several in order", text: "cc @" + bob + ", @" + ada, want: []string{bob, ada}},
+		{name: "duplicates", text: "@" + ada + " and @" + ada, want: []string{ada}},
+		{name: "upper case", text: "@4FD2079C-704F-44ED-AF91-0B543C059BA6", want: []string{ada}},
+		{name: "email address", text: "mail ops@" + ada, want: nil},
+		{name: "not an id", text: "@ada please review", want: nil},
+		{name: "longer than an id", text: "@" + ada + "ff", want: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, model.Mentions(tc.text))
+		})
+	}
+}
+
+func TestNotificationPreferences_Allows(t *testing.T) {
+	prefs := model.DefaultNotificationPreferences("ada")
+	prefs.Changed = false
+
+	assert.True(t, prefs.Allows(model.ReasonAssigned))
+	assert.True(t, prefs.Allows(model.ReasonMentioned))
+	assert.True(t, prefs.Allows(model.ReasonCommented))
+	assert.False(t, prefs.Allows(model.ReasonChanged))
+	assert.False(t, prefs.Allows("unknown"))
+}
+
+func TestInboxPage_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  model.InboxPage
+		valid bool
+	}{
+		{name: "default", page: model.InboxPage{Limit: model.DefaultNotificationLimit}, valid: true},
+		{name: "unread", page: model.InboxPage{Unread: true, Limit: 10, Offset: 20}, valid: true},
+		{name: "no limit", page: model.InboxPage{Limit: 0}, valid: false},
+		{name: "limit too large", page: model.InboxPage{Limit: 101}, valid: false},
+		{name: "negative offset", page: model.InboxPage{Limit: 10, Offset: -1}, valid: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.page.Validate()
+			assert.Equal(t, tc.valid, err == nil)
+		})
+	}
+}
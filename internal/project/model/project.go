@@ -14,24 +14,46 @@ func init() {
 	projectValidator = v
 }
 
-// Project represents a tenant Project.
+// Project represents a tenant Project. A project is active until it is archived, and
+// stays restorable while it is in the trash. Its members, and the members of its team,
+// see it; public projects are seen by every tenant user.
 type Project struct {
-	ID          string    `db:"project_id" json:"id"`
-	TenantID    string    `db:"tenant_id" json:"tenantId"`
-	Name        string    `db:"name" json:"name"`
-	Prefix      string    `db:"prefix" json:"prefix"`
-	Description string    `db:"description" json:"description"`
-	UserID      string    `db:"user_id" json:"userId"`
-	Active      bool      `db:"active" json:"active"`
-	Public      bool      `db:"public" json:"public"`
-	ColumnOrder []string  `db:"column_order" json:"columnOrder"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-}
-
-// NewProject represents a new Project.
+	ID          string     `db:"project_id" json:"id"`
+	TenantID    string     `db:"tenant_id" json:"tenantId"`
+	Name        string     `db:"name" json:"name"`
+	Prefix      string     `db:"prefix" json:"prefix"`
+	Description string     `db:"description" json:"description"`
+	UserID      string     `db:"user_id" json:"userId"`
+	Active      bool       `db:"active" json:"active"`
+	Public      bool       `db:"public" json:"public"`
+	TeamID      string     `db:"team_id" json:"teamId"`
+	ArchivedAt  *time.Time `db:"archived_at" json:"archivedAt"`
+	DeletedAt   *time.Time `db:"deleted_at" json:"deletedAt"`
+	ColumnOrder []string   `db:"column_order" json:"columnOrder"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// ProjectList represents the projects of the tenant, or of every tenant of the user.
+// Failures lists the tenants whose projects could not be listed.
+type ProjectList struct {
+	Projects []Project       `json:"projects"`
+	Failures []TenantFailure `json:"failures"`
+}
+
+// TenantFailure represents a tenant left out of results gathered across the tenants of
+// the user.
+type TenantFailure struct {
+	TenantID    string `json:"tenantId"`
+	CompanyName string `json:"companyName"`
+	Error       string `json:"error"`
+}
+
+// NewProject represents a new Project. Its board is created from the template TemplateID,
+// or from the default template when it is empty.
 type NewProject struct {
-	Name string `json:"name" validate:"required,max=22"`
+	Name       string `json:"name" validate:"required,max=22"`
+	TemplateID string `json:"templateId" validate:"omitempty,uuid"`
 }
 
 // Validate validates NewProject..
@@ -39,16 +61,30 @@ func (np *NewProject) Validate() error {
 	return projectValidator.Struct(np)
 }
 
-// UpdateProject represents a Project update.
+// UpdateProject represents a Project update. Setting Active to false archives the
+// project and setting it to true unarchives it.
 type UpdateProject struct {
 	Name        *string  `json:"name" validate:"omitempty,min=3,max=22"`
 	Active      *bool    `json:"active"`
 	Public      *bool    `json:"public"`
 	Description *string  `json:"description" validate:"omitempty,max=72"`
-	ColumnOrder []string `json:"columnOrder"`
+	ColumnOrder []string `json:"columnOrder" validate:"omitempty,min=1,max=10,unique"`
 }
 
 // Validate validates UpdateProject.
 func (up *UpdateProject) Validate() error {
 	return projectValidator.Struct(up)
 }
+
+// Trash represents the projects and tasks of a tenant waiting to be purged. Tasks of
+// projects in the trash are not listed separately.
+type Trash struct {
+	Projects []Project `json:"projects"`
+	Tasks    []Task    `json:"tasks"`
+}
+
+// Purged represents the number of projects and tasks removed from the trash for good.
+type Purged struct {
+	Projects int `json:"projects"`
+	Tasks    int `json:"tasks"`
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var recurrenceValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	recurrenceValidator = v
+}
+
+// RecurringTask represents a task template of a project with a recurrence rule. The
+// scheduler creates a task from the template in the column ColumnID at every occurrence
+// of the rule. NextRunAt is the next occurrence, and it is nil once the series has ended.
+type RecurringTask struct {
+	ID         string     `db:"recurrence_id" json:"id"`
+	TenantID   string     `db:"tenant_id" json:"tenantId"`
+	ProjectID  string     `db:"project_id" json:"projectId"`
+	ColumnID   string     `db:"column_id" json:"columnId"`
+	Title      string     `db:"title" json:"title"`
+	Content    string     `db:"content" json:"content"`
+	Priority   string     `db:"priority" json:"priority"`
+	Points     int        `db:"points" json:"points"`
+	AssignedTo string     `db:"assigned_to" json:"assignedTo"`
+	Rule       string     `db:"rule" json:"rule"`
+	StartsAt   time.Time  `db:"starts_at" json:"startsAt"`
+	NextRunAt  *time.Time `db:"next_run_at" json:"nextRunAt"`
+	LastRunAt  *time.Time `db:"last_run_at" json:"lastRunAt"`
+	Paused     bool       `db:"paused" json:"paused"`
+	UserID     string     `db:"user_id" json:"userId"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// NewRecurringTask represents a new RecurringTask. Rule is an RRULE such as
+// FREQ=MONTHLY;BYMONTHDAY=1, and StartsAt sets the start of the series and the time of
+// day of its occurrences.
+type NewRecurringTask struct {
+	ColumnID   string    `json:"columnId" validate:"required,uuid"`
+	Title      string    `json:"title" validate:"required,max=75"`
+	Content    string    `json:"content" validate:"max=1000"`
+	Priority   string    `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
+	Points     int       `json:"points" validate:"min=0,max=1000"`
+	AssignedTo string    `json:"assignedTo" validate:"omitempty,max=36"`
+	Rule       string    `json:"rule" validate:"required,max=500"`
+	StartsAt   time.Time `json:"startsAt" validate:"required"`
+}
+
+// Validate validates a NewRecurringTask.
+func (nr *NewRecurringTask) Validate() error {
+	return recurrenceValidator.Struct(nr)
+}
+
+// UpdateRecurringTask represents a RecurringTask update. Pausing stops the scheduler from
+// creating tasks, and occurrences that pass while paused are skipped.
+type UpdateRecurringTask struct {
+	ColumnID   *string    `json:"columnId" validate:"omitempty,uuid"`
+	Title      *string    `json:"title" validate:"omitempty,min=1,max=75"`
+	Content    *string    `json:"content" validate:"omitempty,max=1000"`
+	Priority   *string    `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
+	Points     *int       `json:"points" validate:"omitempty,min=0,max=1000"`
+	AssignedTo *string    `json:"assignedTo" validate:"omitempty,max=36"`
+	Rule       *string    `json:"rule" validate:"omitempty,min=1,max=500"`
+	StartsAt   *time.Time `json:"startsAt"`
+	Paused     *bool      `json:"paused"`
+}
+
+// Validate validates an UpdateRecurringTask.
+func (ur *UpdateRecurringTask) Validate() error {
+	return recurrenceValidator.Struct(ur)
+}
+
+// RecurrenceRun represents a run of the scheduler for a due RecurringTask. Missed is the
+// number of earlier occurrences that passed without a task, for example while the
+// service was down, which are skipped rather than caught up on.
+type RecurrenceRun struct {
+	RecurrenceID string
+	OccurrenceAt time.Time
+	Missed       int
+	Task         *Task
+}
@@ -0,0 +1,85 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecurringTask_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nr *model.NewRecurringTask)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nr *model.NewRecurringTask) {},
+			err:      "",
+		},
+		{
+			name: "missing column",
+			modifier: func(nr *model.NewRecurringTask) {
+				nr.ColumnID = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "column is not UUID",
+			modifier: func(nr *model.NewRecurringTask) {
+				nr.ColumnID = "todo"
+			},
+			err: "failed on the 'uuid' tag",
+		},
+		{
+			name: "missing rule",
+			modifier: func(nr *model.NewRecurringTask) {
+				nr.Rule = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "missing start",
+			modifier: func(nr *model.NewRecurringTask) {
+				nr.StartsAt = time.Time{}
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "unknown priority",
+			modifier: func(nr *model.NewRecurringTask) {
+				nr.Priority = "critical"
+			},
+			err: "failed on the 'oneof' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nr := model.NewRecurringTask{
+				ColumnID: "a4a420db-c6d6-4209-af96-5e5408a57bfe",
+				Title:    "Rotate certificates",
+				Rule:     "FREQ=MONTHLY",
+				StartsAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+			}
+
+			tc.modifier(&nr)
+
+			err := nr.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
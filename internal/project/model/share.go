@@ -0,0 +1,145 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// shareSecretSize is the number of random bytes in the secret of a share token.
+const shareSecretSize = 32
+
+// ShareLink represents a link giving read-only access to a project board to anyone holding
+// its token. The token is only returned when the link is created.
+type ShareLink struct {
+	ID        string     `db:"share_link_id" json:"id"`
+	TenantID  string     `db:"tenant_id" json:"tenantId"`
+	ProjectID string     `db:"project_id" json:"projectId"`
+	UserID    string     `db:"user_id" json:"userId"`
+	Token     string     `db:"-" json:"token,omitempty"`
+	ExpiresAt *time.Time `db:"expires_at" json:"expiresAt"`
+	RevokedAt *time.Time `db:"revoked_at" json:"revokedAt"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// NewShareToken returns a share token of the tenant. A token starts with the tenant id,
+// telling which database holds its link, followed by a random secret.
+func NewShareToken(tenantID string) (string, error) {
+	secret := make([]byte, shareSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return tenantID + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// ShareTokenTenant returns the tenant of a share token and reports whether the token is
+// well formed.
+func ShareTokenTenant(token string) (string, bool) {
+	tenantID, secret, found := strings.Cut(token, ".")
+	if !found || len(secret) != base64.RawURLEncoding.EncodedLen(shareSecretSize) {
+		return "", false
+	}
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return "", false
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(secret); err != nil {
+		return "", false
+	}
+	return tenantID, true
+}
+
+// NewShareLink represents a new ShareLink. Links without ExpiresAt last until revoked.
+type NewShareLink struct {
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// SharedBoard represents the board of a project seen through a share link. It leaves out
+// who created, owns or works on the project and the attachments of its tasks.
+type SharedBoard struct {
+	Project SharedProject  `json:"project"`
+	Columns []SharedColumn `json:"columns"`
+	Tasks   []SharedTask   `json:"tasks"`
+}
+
+// SharedProject represents a shared Project.
+type SharedProject struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	Description string   `json:"description"`
+	ColumnOrder []string `json:"columnOrder"`
+}
+
+// SharedColumn represents a shared Column.
+type SharedColumn struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	ColumnName string   `json:"columnName"`
+	WIPLimit   *int     `json:"wipLimit"`
+	TaskIDS    []string `json:"taskIds"`
+}
+
+// SharedTask represents a shared Task.
+type SharedTask struct {
+	ID       string      `json:"id"`
+	Key      string      `json:"key"`
+	Title    string      `json:"title"`
+	Content  string      `json:"content"`
+	Points   int         `json:"points"`
+	ColumnID string      `json:"columnId"`
+	Rank     string      `json:"rank"`
+	ParentID string      `json:"parentId"`
+	Subtasks Progress    `json:"subtasks"`
+	Priority string      `json:"priority"`
+	StartAt  *time.Time  `json:"startAt"`
+	DueAt    *time.Time  `json:"dueAt"`
+	Labels   []TaskLabel `json:"labels"`
+}
+
+// NewSharedBoard returns the shared board of a project, its columns and its tasks.
+func NewSharedBoard(p Project, cs []Column, ts []Task) SharedBoard {
+	b := SharedBoard{
+		Project: SharedProject{
+			ID:          p.ID,
+			Name:        p.Name,
+			Prefix:      p.Prefix,
+			Description: p.Description,
+			ColumnOrder: p.ColumnOrder,
+		},
+		Columns: make([]SharedColumn, 0, len(cs)),
+		Tasks:   make([]SharedTask, 0, len(ts)),
+	}
+
+	for _, c := range cs {
+		b.Columns = append(b.Columns, SharedColumn{
+			ID:         c.ID,
+			Title:      c.Title,
+			ColumnName: c.ColumnName,
+			WIPLimit:   c.WIPLimit,
+			TaskIDS:    c.TaskIDS,
+		})
+	}
+
+	for _, t := range ts {
+		b.Tasks = append(b.Tasks, SharedTask{
+			ID:       t.ID,
+			Key:      t.Key,
+			Title:    t.Title,
+			Content:  t.Content,
+			Points:   t.Points,
+			ColumnID: t.ColumnID,
+			Rank:     t.Rank,
+			ParentID: t.ParentID,
+			Subtasks: t.Subtasks,
+			Priority: t.Priority,
+			StartAt:  t.StartAt,
+			DueAt:    t.DueAt,
+			Labels:   t.Labels,
+		})
+	}
+
+	return b
+}
@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSharedBoard(t *testing.T) {
+	p := model.Project{ID: "p1", TenantID: "tenant", Name: "Board", UserID: "owner", TeamID: "team", ColumnOrder: []string{"c1"}}
+	cs := []model.Column{{ID: "c1", TenantID: "tenant", Title: "To Do", TaskIDS: []string{"t1"}}}
+	ts := []model.Task{{
+		ID:          "t1",
+		TenantID:    "tenant",
+		Title:       "Task",
+		UserID:      "author",
+		AssignedTo:  "assignee",
+		ColumnID:    "c1",
+		Attachments: []string{"https://files.devpie.io/secret.pdf"},
+	}}
+
+	b := model.NewSharedBoard(p, cs, ts)
+	require.Len(t, b.Columns, 1)
+	require.Len(t, b.Tasks, 1)
+	assert.Equal(t, "Board", b.Project.Name)
+	assert.Equal(t, []string{"t1"}, b.Columns[0].TaskIDS)
+	assert.Equal(t, "c1", b.Tasks[0].ColumnID)
+
+	data, err := json.Marshal(b)
+	require.NoError(t, err)
+	for _, hidden := range []string{"tenant", "owner", "team", "author", "assignee", "secret.pdf"} {
+		assert.NotContains(t, string(data), hidden)
+	}
+}
+
+func TestShareTokenTenant(t *testing.T) {
+	const tenantID = "ac7b523d-1eb9-43f3-bd33-c3e8106c2e70"
+
+	token, err := model.NewShareToken(tenantID)
+	require.NoError(t, err)
+
+	actual, ok := model.ShareTokenTenant(token)
+	assert.True(t, ok)
+	assert.Equal(t, tenantID, actual)
+
+	for _, malformed := range []string{
+		"",
+		tenantID,
+		"tenant." + token[len(tenantID)+1:],
+		tenantID + ".secret",
+		tenantID + "." + strings.Repeat("*", 43),
+	} {
+		_, ok := model.ShareTokenTenant(malformed)
+		assert.False(t, ok, malformed)
+	}
+}
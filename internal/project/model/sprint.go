@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var sprintValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	sprintValidator = v
+}
+
+// Sprint statuses. A sprint is planned, then active, then completed.
+const (
+	SprintPlanned   = "planned"
+	SprintActive    = "active"
+	SprintCompleted = "completed"
+)
+
+// Sprint represents a time boxed iteration of a Project.
+type Sprint struct {
+	ID          string     `db:"sprint_id" json:"id"`
+	TenantID    string     `db:"tenant_id" json:"tenantId"`
+	ProjectID   string     `db:"project_id" json:"projectId"`
+	Name        string     `db:"name" json:"name"`
+	Goal        string     `db:"goal" json:"goal"`
+	Capacity    int        `db:"capacity" json:"capacity"`
+	Status      string     `db:"status" json:"status"`
+	Points      int        `db:"points" json:"points"` // total points of the sprint tasks, read only
+	StartAt     *time.Time `db:"start_at" json:"startAt"`
+	EndAt       *time.Time `db:"end_at" json:"endAt"`
+	CompletedAt *time.Time `db:"completed_at" json:"completedAt"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// NewSprint represents a new Sprint. A capacity of zero means the sprint has no point limit.
+type NewSprint struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Goal     string `json:"goal" validate:"max=500"`
+	Capacity int    `json:"capacity" validate:"min=0,max=1000"`
+}
+
+// Validate validates a NewSprint.
+func (ns *NewSprint) Validate() error {
+	return sprintValidator.Struct(ns)
+}
+
+// StartSprint represents a planned Sprint being started.
+type StartSprint struct {
+	EndAt time.Time `json:"endAt" validate:"required"`
+}
+
+// Validate validates a StartSprint.
+func (ss *StartSprint) Validate() error {
+	return sprintValidator.Struct(ss)
+}
+
+// CompleteSprint represents an active Sprint being completed. Unfinished tasks are
+// carried over to the planned sprint CarryOverTo, or to the backlog when it is empty.
+type CompleteSprint struct {
+	CarryOverTo string `json:"carryOverTo" validate:"omitempty,uuid"`
+}
+
+// Validate validates a CompleteSprint.
+func (cs *CompleteSprint) Validate() error {
+	return sprintValidator.Struct(cs)
+}
+
+// SprintTasks represents tasks being added to a Sprint.
+type SprintTasks struct {
+	TaskIDs []string `json:"taskIds" validate:"required,min=1,max=100,unique,dive,uuid"`
+}
+
+// Validate validates a SprintTasks payload.
+func (st *SprintTasks) Validate() error {
+	return sprintValidator.Struct(st)
+}
+
+// Burndown represents the daily progress of a Sprint.
+type Burndown struct {
+	SprintID string          `json:"sprintId"`
+	Capacity int             `json:"capacity"`
+	Series   []BurndownPoint `json:"series"`
+}
+
+// BurndownPoint represents the points of a Sprint at the end of a day. Remaining points
+// give the burndown and completed points the burnup.
+type BurndownPoint struct {
+	Date      string `json:"date"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Remaining int    `json:"remaining"`
+}
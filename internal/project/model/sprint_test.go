@@ -0,0 +1,67 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprintTasks_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(st *model.SprintTasks)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(st *model.SprintTasks) {},
+			err:      "",
+		},
+		{
+			name: "no tasks",
+			modifier: func(st *model.SprintTasks) {
+				st.TaskIDs = []string{}
+			},
+			err: "failed on the 'min' tag",
+		},
+		{
+			name: "task id is not UUID",
+			modifier: func(st *model.SprintTasks) {
+				st.TaskIDs = []string{"task"}
+			},
+			err: "failed on the 'uuid' tag",
+		},
+		{
+			name: "duplicate tasks",
+			modifier: func(st *model.SprintTasks) {
+				st.TaskIDs = append(st.TaskIDs, st.TaskIDs[0])
+			},
+			err: "failed on the 'unique' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := model.SprintTasks{
+				TaskIDs: []string{"4fd2079c-704f-44ed-af91-0b543c059ba6", "89056328-20c0-42a9-9806-ffebedc6daef"},
+			}
+
+			tc.modifier(&st)
+
+			err := st.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
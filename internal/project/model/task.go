@@ -8,6 +8,27 @@ import (
 
 var taskValidator *validator.Validate
 
+// Task priorities, lowest first.
+const (
+	PriorityNone   = "none"
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// MaxTaskLabels is the maximum number of labels on a task.
+const MaxTaskLabels = 10
+
+// Task link kinds. The inverse kinds are seen from the target of a link.
+const (
+	LinkBlocks         = "blocks"
+	LinkIsBlockedBy    = "is_blocked_by"
+	LinkRelatesTo      = "relates_to"
+	LinkDuplicates     = "duplicates"
+	LinkIsDuplicatedBy = "is_duplicated_by"
+)
+
 func init() {
 	v := NewValidator()
 	taskValidator = v
@@ -15,24 +36,64 @@ func init() {
 
 // Task represents a Project Task.
 type Task struct {
-	ID          string    `db:"task_id" json:"id"`
-	Key         string    `db:"key" json:"key"`
-	Title       string    `db:"title" json:"title"`
-	TenantID    string    `db:"tenant_id" json:"tenantId"`
-	Points      int       `db:"points" json:"points"`
-	UserID      string    `db:"user_id" json:"userId"`
-	Content     string    `db:"content" json:"content"`
-	ProjectID   string    `db:"project_id" json:"projectId"`
-	AssignedTo  string    `db:"assigned_to" json:"assignedTo"`
-	Attachments []string  `db:"attachments" json:"attachments"`
-	Comments    []string  `db:"comments" json:"comments"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-}
-
-// NewTask represents a new Task.
+	ID                string      `db:"task_id" json:"id"`
+	Key               string      `db:"key" json:"key"`
+	Title             string      `db:"title" json:"title"`
+	TenantID          string      `db:"tenant_id" json:"tenantId"`
+	Points            int         `db:"points" json:"points"`
+	UserID            string      `db:"user_id" json:"userId"`
+	Content           string      `db:"content" json:"content"`
+	ProjectID         string      `db:"project_id" json:"projectId"`
+	ColumnID          string      `db:"column_id" json:"columnId"`
+	Rank              string      `db:"rank" json:"rank"`
+	SprintID          string      `db:"sprint_id" json:"sprintId"`
+	ParentID          string      `db:"parent_id" json:"parentId"`
+	Subtasks          Progress    `db:"subtasks" json:"subtasks"`
+	Links             []TaskLink  `db:"links" json:"links"`
+	AssignedTo        string      `db:"assigned_to" json:"assignedTo"`
+	Priority          string      `db:"priority" json:"priority"`
+	StartAt           *time.Time  `db:"start_at" json:"startAt"`
+	DueAt             *time.Time  `db:"due_at" json:"dueAt"`
+	Attachments       []string    `db:"attachments" json:"attachments"`
+	Labels            []TaskLabel `db:"labels" json:"labels"`
+	Fields            FieldValues `db:"fields" json:"fields"`
+	CommentCount      int         `db:"comment_count" json:"commentCount"`
+	OriginalEstimate  int         `db:"original_estimate" json:"originalEstimate"`   // minutes
+	RemainingEstimate int         `db:"remaining_estimate" json:"remainingEstimate"` // minutes
+	TimeSpent         int         `db:"time_spent" json:"timeSpent"`                 // minutes logged on the task, read only
+	ArchivedAt        *time.Time  `db:"archived_at" json:"archivedAt"`
+	DeletedAt         *time.Time  `db:"deleted_at" json:"deletedAt"`
+	UpdatedAt         time.Time   `db:"updated_at" json:"updatedAt"`
+	CreatedAt         time.Time   `db:"created_at" json:"createdAt"`
+}
+
+// Progress represents how many subtasks of a Task are done.
+type Progress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+// TaskLink represents a link from a Task to another task, seen from the Task.
+type TaskLink struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"`
+	TaskID string `json:"taskId"`
+	Key    string `json:"key"`
+	Title  string `json:"title"`
+}
+
+// TaskLabel represents a Label attached to a Task.
+type TaskLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// NewTask represents a new Task. Project owners may set Override to create it in a column
+// whose policies it breaks.
 type NewTask struct {
-	Title string `json:"title" validate:"required,min=1,max=75"`
+	Title    string `json:"title" validate:"required,min=1,max=75"`
+	Override bool   `json:"override"`
 }
 
 // Validate validates a NewTask.
@@ -40,15 +101,26 @@ func (nt *NewTask) Validate() error {
 	return taskValidator.Struct(nt)
 }
 
-// UpdateTask represents a Task being updated.
+// UpdateTask represents a Task being updated. Labels replaces the labels of the task
+// when given, an empty list removes them all. Fields sets the custom fields it names, and
+// a null value clears a field. ClearStartAt and ClearDueAt remove the dates of the task,
+// which a missing date leaves unchanged.
 type UpdateTask struct {
-	Title       *string   `json:"title" validate:"omitempty,max=75"`
-	Points      *int      `json:"points"`
-	Content     *string   `json:"content" validate:"omitempty,max=1000"`
-	AssignedTo  *string   `json:"assignedTo"`
-	Attachments []string  `json:"attachments"`
-	Comments    []string  `json:"comments"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	Title             *string     `json:"title" validate:"omitempty,max=75"`
+	Points            *int        `json:"points"`
+	Content           *string     `json:"content" validate:"omitempty,max=1000"`
+	AssignedTo        *string     `json:"assignedTo"`
+	Priority          *string     `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
+	StartAt           *time.Time  `json:"startAt"`
+	DueAt             *time.Time  `json:"dueAt"`
+	ClearStartAt      bool        `json:"clearStartAt" validate:"excluded_with=StartAt"`
+	ClearDueAt        bool        `json:"clearDueAt" validate:"excluded_with=DueAt"`
+	Attachments       []string    `json:"attachments"`
+	Labels            []string    `json:"labels" validate:"omitempty,max=10,unique,dive,uuid"`
+	Fields            FieldValues `json:"fields" validate:"omitempty,max=30,dive,keys,uuid,endkeys,required"`
+	OriginalEstimate  *int        `json:"originalEstimate" validate:"omitempty,min=0,max=1000000"`  // minutes
+	RemainingEstimate *int        `json:"remainingEstimate" validate:"omitempty,min=0,max=1000000"` // minutes
+	UpdatedAt         time.Time   `json:"updatedAt"`
 }
 
 // Validate validates an UpdateTask payload.
@@ -56,14 +128,114 @@ func (ut *UpdateTask) Validate() error {
 	return taskValidator.Struct(ut)
 }
 
-// MoveTask represents a Task being moved between columns.
+// TaskFilter represents the optional filters of a task listing. Archived tasks are
+// only listed when Archived is set. Overdue lists the tasks past their due date that are
+// not in the done column. Fields maps ids of custom fields of the project to a value the
+// tasks have, or one of their options for multi-select fields.
+type TaskFilter struct {
+	LabelID  string            `validate:"omitempty,uuid"`
+	Priority string            `validate:"omitempty,oneof=none low medium high urgent"`
+	Fields   map[string]string `validate:"max=10,dive,keys,uuid,endkeys,required,max=500"`
+	Overdue  bool
+	Archived bool
+}
+
+// Validate validates a TaskFilter.
+func (tf *TaskFilter) Validate() error {
+	return taskValidator.Struct(tf)
+}
+
+// NewTaskLink represents a new link from a Task to a target task.
+type NewTaskLink struct {
+	Kind     string `json:"kind" validate:"required,oneof=blocks is_blocked_by relates_to duplicates is_duplicated_by"`
+	TargetID string `json:"targetId" validate:"required,uuid"`
+}
+
+// Validate validates a NewTaskLink.
+func (nl *NewTaskLink) Validate() error {
+	return taskValidator.Struct(nl)
+}
+
+// SetParent represents a Task being made a subtask of another task, or a top level
+// task when ParentID is empty.
+type SetParent struct {
+	ParentID string `json:"parentId" validate:"omitempty,uuid"`
+}
+
+// Validate validates a SetParent payload.
+func (sp *SetParent) Validate() error {
+	return taskValidator.Struct(sp)
+}
+
+// MoveTask represents a Task being moved to a position in a column. The task is placed
+// between the After and Before tasks, and appended to the column when both are empty.
+// Project owners may set Override to move it to a column whose policies it breaks.
 type MoveTask struct {
-	To      string   `json:"to" validate:"required"`
-	From    string   `json:"from" validate:"required"`
-	TaskIds []string `json:"taskIds" validate:"required"`
+	To       string `json:"to" validate:"required,uuid"`
+	After    string `json:"after" validate:"omitempty,uuid"`
+	Before   string `json:"before" validate:"omitempty,uuid"`
+	Override bool   `json:"override"`
 }
 
 // Validate validates a MoveTask payload.
 func (mt *MoveTask) Validate() error {
 	return taskValidator.Struct(mt)
 }
+
+// DefaultSearchLimit is the number of search results returned when no limit is given.
+const DefaultSearchLimit = 20
+
+// TaskSearch represents a full-text search over task titles, content and comments. The
+// offset is bounded since every skipped result is still ranked, so deep pages are refused
+// rather than served slowly.
+type TaskSearch struct {
+	Query         string     `validate:"required,max=200"`
+	ProjectID     string     `validate:"omitempty,uuid"`
+	AssignedTo    string     `validate:"omitempty,max=36"`
+	ColumnID      string     `validate:"omitempty,uuid"`
+	CreatedAfter  *time.Time `validate:"omitempty"`
+	CreatedBefore *time.Time `validate:"omitempty"`
+	Limit         int        `validate:"min=1,max=100"`
+	Offset        int        `validate:"min=0,max=1000"`
+}
+
+// Validate validates a TaskSearch.
+func (ts *TaskSearch) Validate() error {
+	return taskValidator.Struct(ts)
+}
+
+// TaskQuery represents a listing of the tasks matching a query of the task query language,
+// within a project or across the projects of the tenant.
+type TaskQuery struct {
+	Query     string `validate:"max=500"`
+	ProjectID string `validate:"omitempty,uuid"`
+	Limit     int    `validate:"min=1,max=100"`
+	Offset    int    `validate:"min=0"`
+}
+
+// Validate validates a TaskQuery.
+func (tq *TaskQuery) Validate() error {
+	return taskValidator.Struct(tq)
+}
+
+// TaskSearchResult represents a Task matching a search, with its highlighted fragments.
+type TaskSearchResult struct {
+	TaskID           string    `db:"task_id" json:"id"`
+	TenantID         string    `db:"tenant_id" json:"tenantId"`
+	ProjectID        string    `db:"project_id" json:"projectId"`
+	ColumnID         string    `db:"column_id" json:"columnId"`
+	Key              string    `db:"key" json:"key"`
+	Title            string    `db:"title" json:"title"`
+	AssignedTo       string    `db:"assigned_to" json:"assignedTo"`
+	Score            float64   `db:"score" json:"score"`
+	TitleHighlight   string    `db:"title_highlight" json:"titleHighlight"`
+	ContentHighlight string    `db:"content_highlight" json:"contentHighlight"`
+	CreatedAt        time.Time `db:"created_at" json:"createdAt"`
+}
+
+// TaskSearchResults represents a page of the tasks matching a search in the tenant, or in
+// every tenant of the user. Failures lists the tenants that could not be searched.
+type TaskSearchResults struct {
+	Results  []TaskSearchResult `json:"results"`
+	Failures []TenantFailure    `json:"failures"`
+}
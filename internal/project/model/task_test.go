@@ -1,7 +1,9 @@
 package model_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/devpies/saas-core/internal/project/model"
 
@@ -97,6 +99,58 @@ func TestUpdateTask_Validate(t *testing.T) {
 			},
 			err: "failed on the 'max' tag",
 		},
+		{
+			name: "valid priority",
+			modifier: func(ut *model.UpdateTask) {
+				ut.Priority = aws.String(model.PriorityUrgent)
+			},
+			err: "",
+		},
+		{
+			name: "unknown priority",
+			modifier: func(ut *model.UpdateTask) {
+				ut.Priority = aws.String("critical")
+			},
+			err: "failed on the 'oneof' tag",
+		},
+		{
+			name: "clear labels",
+			modifier: func(ut *model.UpdateTask) {
+				ut.Labels = []string{}
+			},
+			err: "",
+		},
+		{
+			name: "label is not UUID",
+			modifier: func(ut *model.UpdateTask) {
+				ut.Labels = []string{"bug"}
+			},
+			err: "failed on the 'uuid' tag",
+		},
+		{
+			name: "duplicate labels",
+			modifier: func(ut *model.UpdateTask) {
+				ut.Labels = []string{"2b0c5c4e-5f4a-4d3b-9a57-0f5e0c7a1d21", "2b0c5c4e-5f4a-4d3b-9a57-0f5e0c7a1d21"}
+			},
+			err: "failed on the 'unique' tag",
+		},
+		{
+			name: "clear dates",
+			modifier: func(ut *model.UpdateTask) {
+				ut.ClearStartAt = true
+				ut.ClearDueAt = true
+			},
+			err: "",
+		},
+		{
+			name: "clear and set due date",
+			modifier: func(ut *model.UpdateTask) {
+				due := time.Now()
+				ut.DueAt = &due
+				ut.ClearDueAt = true
+			},
+			err: "failed on the 'excluded_with' tag",
+		},
 	}
 
 	for _, tc := range tests {
@@ -140,27 +194,35 @@ func TestMoveTask_Validate(t *testing.T) {
 			err: "failed on the 'required' tag",
 		},
 		{
-			name: "missing from",
+			name: "to is not UUID",
 			modifier: func(mt *model.MoveTask) {
-				mt.From = ""
+				mt.To = "to-column"
 			},
-			err: "failed on the 'required' tag",
+			err: "failed on the 'uuid' tag",
 		},
 		{
-			name: "missing taskIds",
+			name: "append to column",
 			modifier: func(mt *model.MoveTask) {
-				mt.TaskIds = nil
+				mt.After = ""
+				mt.Before = ""
 			},
-			err: "failed on the 'required' tag",
+			err: "",
+		},
+		{
+			name: "after is not UUID",
+			modifier: func(mt *model.MoveTask) {
+				mt.After = "task-id"
+			},
+			err: "failed on the 'uuid' tag",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			mt := model.MoveTask{
-				To:      "to-column",
-				From:    "from-column",
-				TaskIds: []string{"task-id"},
+				To:     "0ef64d03-8a91-4513-907c-dd1fcfcfeb46",
+				After:  "4fd2079c-704f-44ed-af91-0b543c059ba6",
+				Before: "89056328-20c0-42a9-9806-ffebedc6daef",
 			}
 
 			tc.modifier(&mt)
@@ -180,3 +242,136 @@ func TestMoveTask_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskSearch_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(ts *model.TaskSearch)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(ts *model.TaskSearch) {},
+			err:      "",
+		},
+		{
+			name: "missing query",
+			modifier: func(ts *model.TaskSearch) {
+				ts.Query = ""
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "query too long",
+			modifier: func(ts *model.TaskSearch) {
+				ts.Query = strings.Repeat("a", 201)
+			},
+			err: "failed on the 'max' tag",
+		},
+		{
+			name: "project is not UUID",
+			modifier: func(ts *model.TaskSearch) {
+				ts.ProjectID = "project"
+			},
+			err: "failed on the 'uuid' tag",
+		},
+		{
+			name: "limit too large",
+			modifier: func(ts *model.TaskSearch) {
+				ts.Limit = 101
+			},
+			err: "failed on the 'max' tag",
+		},
+		{
+			name: "negative offset",
+			modifier: func(ts *model.TaskSearch) {
+				ts.Offset = -1
+			},
+			err: "failed on the 'min' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := model.TaskSearch{
+				Query:     "design",
+				ProjectID: "0ef64d03-8a91-4513-907c-dd1fcfcfeb46",
+				Limit:     model.DefaultSearchLimit,
+			}
+
+			tc.modifier(&ts)
+
+			err := ts.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestNewTaskLink_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nl *model.NewTaskLink)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nl *model.NewTaskLink) {},
+			err:      "",
+		},
+		{
+			name: "inverse kind",
+			modifier: func(nl *model.NewTaskLink) {
+				nl.Kind = model.LinkIsBlockedBy
+			},
+			err: "",
+		},
+		{
+			name: "unknown kind",
+			modifier: func(nl *model.NewTaskLink) {
+				nl.Kind = "depends_on"
+			},
+			err: "failed on the 'oneof' tag",
+		},
+		{
+			name: "invalid target",
+			modifier: func(nl *model.NewTaskLink) {
+				nl.TargetID = "task"
+			},
+			err: "failed on the 'uuid' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nl := model.NewTaskLink{
+				Kind:     model.LinkBlocks,
+				TargetID: "0ef64d03-8a91-4513-907c-dd1fcfcfeb46",
+			}
+
+			tc.modifier(&nl)
+
+			err := nl.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var templateValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	templateValidator = v
+}
+
+// Built-in template ids.
+const (
+	TemplateKanban    = "6d1f3a52-0c4e-4b7a-9d2e-8f1a5b6c7d01"
+	TemplateScrum     = "6d1f3a52-0c4e-4b7a-9d2e-8f1a5b6c7d02"
+	TemplateBugTriage = "6d1f3a52-0c4e-4b7a-9d2e-8f1a5b6c7d03"
+)
+
+// MaxTemplateTasks is the maximum number of seed tasks in a template.
+const MaxTemplateTasks = 100
+
+// Template represents a blueprint for a new Project board. Built-in templates are
+// shared by every tenant and cannot be changed.
+type Template struct {
+	ID          string `db:"template_id" json:"id"`
+	TenantID    string `db:"tenant_id" json:"tenantId"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+	BuiltIn     bool   `json:"builtIn"`
+	TemplateDefinition
+	UserID    string    `db:"user_id" json:"userId"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// TemplateDefinition represents the columns, labels and seed tasks a Template creates.
+type TemplateDefinition struct {
+	Columns []TemplateColumn `json:"columns" validate:"required,min=1,max=10,dive"`
+	Labels  []TemplateLabel  `json:"labels" validate:"max=50,dive"`
+	Tasks   []TemplateTask   `json:"tasks" validate:"max=100,dive"`
+}
+
+// TemplateColumn represents a board column of a Template. A nil WIPLimit means no limit.
+type TemplateColumn struct {
+	Title    string `json:"title" validate:"required,max=24"`
+	WIPLimit *int   `json:"wipLimit" validate:"omitempty,min=1"`
+}
+
+// TemplateLabel represents a label of a Template.
+type TemplateLabel struct {
+	Name  string `json:"name" validate:"required,max=30"`
+	Color string `json:"color" validate:"required,hexcolor,len=7"`
+}
+
+// TemplateTask represents a seed task of a Template. Column is the index of the task
+// column and Labels are names of template labels.
+type TemplateTask struct {
+	Title    string   `json:"title" validate:"required,max=48"`
+	Content  string   `json:"content" validate:"max=1000"`
+	Column   int      `json:"column" validate:"min=0"`
+	Priority string   `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
+	Points   int      `json:"points" validate:"min=0"`
+	Labels   []string `json:"labels" validate:"max=10,unique,dive,required"`
+}
+
+// NewTemplate represents a new tenant Template.
+type NewTemplate struct {
+	Name        string `json:"name" validate:"required,max=36"`
+	Description string `json:"description" validate:"max=72"`
+	TemplateDefinition
+}
+
+// Validate validates a NewTemplate.
+func (nt *NewTemplate) Validate() error {
+	return templateValidator.Struct(nt)
+}
+
+// SaveTemplate represents a Project board being saved as a new tenant Template.
+type SaveTemplate struct {
+	Name        string `json:"name" validate:"required,max=36"`
+	Description string `json:"description" validate:"max=72"`
+}
+
+// Validate validates a SaveTemplate.
+func (st *SaveTemplate) Validate() error {
+	return templateValidator.Struct(st)
+}
+
+// ColumnOrder returns the column names of a board created from the Template.
+func (td TemplateDefinition) ColumnOrder() []string {
+	order := make([]string, len(td.Columns))
+	for i := range td.Columns {
+		order[i] = ColumnName(i)
+	}
+	return order
+}
+
+// BuiltinTemplates returns the templates shared by every tenant. Kanban is the default.
+func BuiltinTemplates() []Template {
+	limit := func(n int) *int { return &n }
+
+	return []Template{
+		{
+			ID:          TemplateKanban,
+			Name:        "Kanban",
+			Description: "Visualise work and limit work in progress.",
+			BuiltIn:     true,
+			TemplateDefinition: TemplateDefinition{
+				Columns: []TemplateColumn{
+					{Title: "To Do"},
+					{Title: "In Progress", WIPLimit: limit(5)},
+					{Title: "Review", WIPLimit: limit(3)},
+					{Title: "Done"},
+				},
+				Labels: []TemplateLabel{},
+				Tasks:  []TemplateTask{},
+			},
+		},
+		{
+			ID:          TemplateScrum,
+			Name:        "Scrum",
+			Description: "Plan work in sprints from a prioritised backlog.",
+			BuiltIn:     true,
+			TemplateDefinition: TemplateDefinition{
+				Columns: []TemplateColumn{
+					{Title: "Backlog"},
+					{Title: "To Do"},
+					{Title: "In Progress"},
+					{Title: "In Review"},
+					{Title: "Done"},
+				},
+				Labels: []TemplateLabel{
+					{Name: "Story", Color: "#0e8a16"},
+					{Name: "Bug", Color: "#d73a4a"},
+					{Name: "Spike", Color: "#5319e7"},
+				},
+				Tasks: []TemplateTask{
+					{Title: "Groom the backlog", Column: 0, Labels: []string{}},
+					{Title: "Plan the first sprint", Column: 0, Labels: []string{}},
+				},
+			},
+		},
+		{
+			ID:          TemplateBugTriage,
+			Name:        "Bug triage",
+			Description: "Triage, fix and verify reported bugs.",
+			BuiltIn:     true,
+			TemplateDefinition: TemplateDefinition{
+				Columns: []TemplateColumn{
+					{Title: "Reported"},
+					{Title: "Triaged"},
+					{Title: "Fixing", WIPLimit: limit(3)},
+					{Title: "Verifying"},
+					{Title: "Closed"},
+				},
+				Labels: []TemplateLabel{
+					{Name: "Critical", Color: "#b60205"},
+					{Name: "Major", Color: "#d93f0b"},
+					{Name: "Minor", Color: "#fbca04"},
+					{Name: "Cannot reproduce", Color: "#cccccc"},
+				},
+				Tasks: []TemplateTask{},
+			},
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nt *model.NewTemplate)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nt *model.NewTemplate) {},
+			err:      "",
+		},
+		{
+			name: "no columns",
+			modifier: func(nt *model.NewTemplate) {
+				nt.Columns = nil
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "too many columns",
+			modifier: func(nt *model.NewTemplate) {
+				nt.Columns = make([]model.TemplateColumn, model.MaxColumns+1)
+				for i := range nt.Columns {
+					nt.Columns[i].Title = "Column"
+				}
+			},
+			err: "failed on the 'max' tag",
+		},
+		{
+			name: "zero wip limit",
+			modifier: func(nt *model.NewTemplate) {
+				zero := 0
+				nt.Columns[0].WIPLimit = &zero
+			},
+			err: "failed on the 'min' tag",
+		},
+		{
+			name: "invalid label color",
+			modifier: func(nt *model.NewTemplate) {
+				nt.Labels[0].Color = "red"
+			},
+			err: "failed on the 'hexcolor' tag",
+		},
+		{
+			name: "invalid task priority",
+			modifier: func(nt *model.NewTemplate) {
+				nt.Tasks[0].Priority = "asap"
+			},
+			err: "failed on the 'oneof' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nt := model.NewTemplate{
+				Name: "Support",
+				TemplateDefinition: model.TemplateDefinition{
+					Columns: []model.TemplateColumn{{Title: "Open"}, {Title: "Closed"}},
+					Labels:  []model.TemplateLabel{{Name: "Urgent", Color: "#b60205"}},
+					Tasks:   []model.TemplateTask{{Title: "Write the runbook", Labels: []string{"Urgent"}}},
+				},
+			}
+
+			tc.modifier(&nt)
+
+			err := nt.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	for _, tmpl := range model.BuiltinTemplates() {
+		nt := model.NewTemplate{Name: tmpl.Name, Description: tmpl.Description, TemplateDefinition: tmpl.TemplateDefinition}
+		assert.Nil(t, nt.Validate(), tmpl.Name)
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var transferValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	transferValidator = v
+}
+
+// Import formats. A bundle is the JSON export of a project.
+const (
+	FormatBundle = "bundle"
+	FormatTrello = "trello"
+	FormatJira   = "jira"
+)
+
+// Export formats.
+const (
+	ExportJSON = "json"
+	ExportCSV  = "csv"
+)
+
+// Import statuses. An import is pending until a worker runs it, then completed or failed.
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// MaxImportSize is the maximum size of an import source in bytes.
+const MaxImportSize = 10 << 20
+
+// Import represents an asynchronous project import. Unassigned lists the keys of the
+// imported tasks whose assignee is not a member of the project.
+type Import struct {
+	ID          string     `db:"import_id" json:"id"`
+	TenantID    string     `db:"tenant_id" json:"tenantId"`
+	UserID      string     `db:"user_id" json:"userId"`
+	Format      string     `db:"format" json:"format"`
+	Status      string     `db:"status" json:"status"`
+	ProjectID   string     `db:"project_id" json:"projectId"`
+	Tasks       int        `db:"tasks" json:"tasks"`
+	Error       string     `db:"error" json:"error"`
+	Unassigned  []string   `db:"unassigned" json:"unassigned"`
+	CompletedAt *time.Time `db:"completed_at" json:"completedAt"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// NewImport represents a new project import. Source is the bundle, Trello board JSON or
+// Jira CSV to import. Users maps the people of the source, by email or handle, to users
+// of the tenant; unmapped authors become the importer. Assignees that are unmapped or not
+// members of the project are dropped, and the import lists the keys of their tasks.
+type NewImport struct {
+	Format string            `json:"format" validate:"required,oneof=bundle trello jira"`
+	Name   string            `json:"name" validate:"omitempty,max=22"`
+	Users  map[string]string `json:"users" validate:"max=1000,dive,keys,required,endkeys,uuid"`
+	Source string            `json:"source" validate:"required,max=10485760"`
+}
+
+// Validate validates a NewImport.
+func (ni *NewImport) Validate() error {
+	return transferValidator.Struct(ni)
+}
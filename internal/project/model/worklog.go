@@ -0,0 +1,192 @@
+package model
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var worklogValidator *validator.Validate
+
+func init() {
+	v := NewValidator()
+	v.RegisterStructValidation(worklogFilterRange, WorklogFilter{})
+	worklogValidator = v
+}
+
+// MaxWorklogMinutes is the most time a single worklog entry records. Timers left running
+// longer log this much.
+const MaxWorklogMinutes = 24 * 60
+
+// MaxReportDays is the longest date range a time report covers.
+const MaxReportDays = 366
+
+// Worklog represents time a user spent on a Task, in minutes.
+type Worklog struct {
+	ID        string    `db:"worklog_id" json:"id"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	TaskID    string    `db:"task_id" json:"taskId"`
+	ProjectID string    `db:"project_id" json:"projectId"`
+	UserID    string    `db:"user_id" json:"userId"`
+	StartedAt time.Time `db:"started_at" json:"startedAt"`
+	Minutes   int       `db:"minutes" json:"minutes"`
+	Note      string    `db:"note" json:"note"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// NewWorklog represents a new Worklog of the requesting user. Logging time lowers the
+// remaining estimate of the task, down to zero.
+type NewWorklog struct {
+	StartedAt time.Time `json:"startedAt" validate:"required"`
+	Minutes   int       `json:"minutes" validate:"required,min=1,max=1440"`
+	Note      string    `json:"note" validate:"max=500"`
+}
+
+// Validate validates a NewWorklog.
+func (nw *NewWorklog) Validate() error {
+	return worklogValidator.Struct(nw)
+}
+
+// UpdateWorklog represents a Worklog being updated. Estimates are left as they are.
+type UpdateWorklog struct {
+	StartedAt *time.Time `json:"startedAt"`
+	Minutes   *int       `json:"minutes" validate:"omitempty,min=1,max=1440"`
+	Note      *string    `json:"note" validate:"omitempty,max=500"`
+}
+
+// Validate validates an UpdateWorklog.
+func (uw *UpdateWorklog) Validate() error {
+	return worklogValidator.Struct(uw)
+}
+
+// Timer represents a Task a user is timing. Stopping the timer logs the time since
+// StartedAt.
+type Timer struct {
+	UserID    string    `db:"user_id" json:"userId"`
+	TenantID  string    `db:"tenant_id" json:"tenantId"`
+	TaskID    string    `db:"task_id" json:"taskId"`
+	StartedAt time.Time `db:"started_at" json:"startedAt"`
+}
+
+// StopTimer represents a Timer being stopped, with the note of the Worklog it logs.
+type StopTimer struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+// Validate validates a StopTimer.
+func (st *StopTimer) Validate() error {
+	return worklogValidator.Struct(st)
+}
+
+// WorklogFilter represents the worklog entries of a time report or export: those started
+// from From up to To, of a project and a user when they are given.
+type WorklogFilter struct {
+	ProjectID string    `validate:"omitempty,uuid"`
+	UserID    string    `validate:"omitempty,max=36"`
+	From      time.Time `validate:"required"`
+	To        time.Time `validate:"required"`
+}
+
+// Validate validates a WorklogFilter.
+func (wf *WorklogFilter) Validate() error {
+	return worklogValidator.Struct(wf)
+}
+
+func worklogFilterRange(sl validator.StructLevel) {
+	wf := sl.Current().Interface().(WorklogFilter)
+	if !wf.To.After(wf.From) || wf.To.Sub(wf.From) > MaxReportDays*24*time.Hour {
+		sl.ReportError(wf.To, "to", "To", "range", "")
+	}
+}
+
+// TimeReport represents the time logged in a date range, in minutes, totalled per
+// project, user and day.
+type TimeReport struct {
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Minutes  int                 `json:"minutes"`
+	Projects []TimeReportProject `json:"projects"`
+	Users    []TimeReportUser    `json:"users"`
+	Rows     []TimeReportRow     `json:"rows"`
+}
+
+// TimeReportRow represents the time a user logged on a project in a day.
+type TimeReportRow struct {
+	ProjectID   string `db:"project_id" json:"projectId"`
+	ProjectName string `db:"project_name" json:"projectName"`
+	UserID      string `db:"user_id" json:"userId"`
+	Date        string `db:"date" json:"date"`
+	Minutes     int    `db:"minutes" json:"minutes"`
+}
+
+// TimeReportProject represents the time logged on a project in a date range.
+type TimeReportProject struct {
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	Minutes     int    `json:"minutes"`
+}
+
+// TimeReportUser represents the time a user logged in a date range.
+type TimeReportUser struct {
+	UserID  string `json:"userId"`
+	Minutes int    `json:"minutes"`
+}
+
+// WorklogEntry represents a Worklog with the project and task it was logged against, as
+// exported for invoicing.
+type WorklogEntry struct {
+	Worklog
+	ProjectName string `db:"project_name" json:"projectName"`
+	TaskKey     string `db:"task_key" json:"taskKey"`
+	TaskTitle   string `db:"task_title" json:"taskTitle"`
+}
+
+// worklogCSVHeader is the header of the worklog CSV written by WriteWorklogCSV.
+var worklogCSVHeader = []string{
+	"date", "project", "task_key", "task_title", "user_id", "started_at", "minutes", "hours", "note",
+}
+
+// WriteWorklogCSV writes worklog entries as CSV for invoicing, one row per entry with its
+// time in minutes and in hours to two decimals. Names, titles and notes starting like a
+// formula are escaped.
+func WriteWorklogCSV(w io.Writer, entries []WorklogEntry) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(worklogCSVHeader); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		row := []string{
+			e.StartedAt.UTC().Format("2006-01-02"),
+			csvText(e.ProjectName),
+			csvText(e.TaskKey),
+			csvText(e.TaskTitle),
+			e.UserID,
+			e.StartedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(e.Minutes),
+			strconv.FormatFloat(float64(e.Minutes)/60, 'f', 2, 64),
+			csvText(e.Note),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText quotes text a spreadsheet would take for a formula, such as =, +, - or @
+// followed by anything, so it opens as the text that was written.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorklog_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(nw *model.NewWorklog)
+		err      string
+	}{
+		{
+			name:     "valid",
+			modifier: func(nw *model.NewWorklog) {},
+			err:      "",
+		},
+		{
+			name: "missing start",
+			modifier: func(nw *model.NewWorklog) {
+				nw.StartedAt = time.Time{}
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "no time",
+			modifier: func(nw *model.NewWorklog) {
+				nw.Minutes = 0
+			},
+			err: "failed on the 'required' tag",
+		},
+		{
+			name: "longer than a day",
+			modifier: func(nw *model.NewWorklog) {
+				nw.Minutes = model.MaxWorklogMinutes + 1
+			},
+			err: "failed on the 'max' tag",
+		},
+		{
+			name: "note too long",
+			modifier: func(nw *model.NewWorklog) {
+				nw.Note = strings.Repeat("a", 501)
+			},
+			err: "failed on the 'max' tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nw := model.NewWorklog{
+				StartedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+				Minutes:   90,
+				Note:      "Review",
+			}
+
+			tc.modifier(&nw)
+
+			err := nw.Validate()
+			if tc.err != "" {
+				if err == nil {
+					t.Errorf("expected: %s, got nil", tc.err)
+					return
+				}
+				assert.Regexp(t, tc.err, err.Error())
+			} else {
+				if err != nil {
+					t.Errorf("expected: nil, got: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteWorklogCSV(t *testing.T) {
+	started := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []model.WorklogEntry{
+		{
+			Worklog:     model.Worklog{UserID: "user", StartedAt: started, Minutes: 90, Note: "Pairing, review"},
+			ProjectName: "Web",
+			TaskKey:     "WEB-1",
+			TaskTitle:   "Fix login",
+		},
+		{
+			Worklog:     model.Worklog{UserID: "user", StartedAt: started, Minutes: 30, Note: "@SUM(A1:A2)"},
+			ProjectName: "+Web",
+			TaskKey:     "WEB-2",
+			TaskTitle:   "=HYPERLINK(\"https://evil.example\")",
+		},
+	}
+
+	var buf strings.Builder
+	assert.Nil(t, model.WriteWorklogCSV(&buf, entries))
+
+	expected := "date,project,task_key,task_title,user_id,started_at,minutes,hours,note\n" +
+		"2026-03-01,Web,WEB-1,Fix login,user,2026-03-01T09:00:00Z,90,1.50,\"Pairing, review\"\n" +
+		"2026-03-01,'+Web,WEB-2,\"'=HYPERLINK(\"\"https://evil.example\"\")\",user,2026-03-01T09:00:00Z,30,0.50,'@SUM(A1:A2)\n"
+	assert.Equal(t, expected, buf.String())
+}
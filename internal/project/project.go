@@ -8,15 +8,21 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/devpies/saas-core/internal/project/config"
 	"github.com/devpies/saas-core/internal/project/db"
 	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/project/limit"
 	"github.com/devpies/saas-core/internal/project/repository"
 	"github.com/devpies/saas-core/internal/project/res"
 	"github.com/devpies/saas-core/internal/project/service"
+	"github.com/devpies/saas-core/internal/project/stream"
 	"github.com/devpies/saas-core/pkg/log"
+	"github.com/devpies/saas-core/pkg/msg"
 
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 )
 
@@ -61,26 +67,137 @@ func Run() error {
 		}
 	}
 
+	js := msg.NewStreamContext(logger, shutdown, cfg.Nats.Address, cfg.Nats.Port)
+
+	_ = js.Create(msg.StreamProjects)
+
 	// Initialize 3-layered architecture.
 	taskRepo := repository.NewTaskRepository(logger, pg)
 	columnRepo := repository.NewColumnRepository(logger, pg)
 	projectRepo := repository.NewProjectRepository(logger, pg)
+	commentRepo := repository.NewCommentRepository(logger, pg)
+	activityRepo := repository.NewActivityRepository(logger, pg)
+	labelRepo := repository.NewLabelRepository(logger, pg)
+	sprintRepo := repository.NewSprintRepository(logger, pg)
+	templateRepo := repository.NewTemplateRepository(logger, pg)
+	transferRepo := repository.NewTransferRepository(logger, pg)
+	memberRepo := repository.NewMemberRepository(logger, pg)
+	teamRepo := repository.NewTeamRepository(logger, pg)
+	shareRepo := repository.NewShareRepository(logger, pg)
+	filterRepo := repository.NewFilterRepository(logger, pg)
+	fieldRepo := repository.NewFieldRepository(logger, pg)
+	worklogRepo := repository.NewWorklogRepository(logger, pg)
+	recurrenceRepo := repository.NewRecurrenceRepository(logger, pg)
+	notificationRepo := repository.NewNotificationRepository(logger, pg)
+	watcherRepo := repository.NewWatcherRepository(logger, pg)
+
+	hub := stream.NewHub(cfg.Stream.Buffer, cfg.Stream.History)
+	fanOut := service.FanOut{Concurrency: cfg.Tenants.Concurrency, Timeout: cfg.Tenants.Timeout}
+
+	streamService := service.NewStreamService(logger, js, hub, projectRepo)
+	taskService := service.NewTaskService(logger, taskRepo, streamService, fanOut)
+	columnService := service.NewColumnService(logger, columnRepo, streamService)
+	projectService := service.NewProjectService(logger, projectRepo, fanOut)
+	commentService := service.NewCommentService(logger, commentRepo, taskRepo, streamService)
+	activityService := service.NewActivityService(logger, activityRepo)
+	labelService := service.NewLabelService(logger, labelRepo)
+	sprintService := service.NewSprintService(logger, sprintRepo)
+	templateService := service.NewTemplateService(logger, templateRepo)
+	transferService := service.NewTransferService(logger, transferRepo, cfg.Imports.Workers, cfg.Imports.Queue)
+	memberService := service.NewMemberService(logger, js, memberRepo)
+	teamService := service.NewTeamService(logger, js, teamRepo, memberRepo)
+	shareService := service.NewShareService(logger, shareRepo)
+	filterService := service.NewFilterService(logger, filterRepo)
+	fieldService := service.NewFieldService(logger, fieldRepo)
+	worklogService := service.NewWorklogService(logger, worklogRepo)
+	recurrenceService := service.NewRecurrenceService(logger, recurrenceRepo)
+	notificationService := service.NewNotificationService(logger, notificationRepo, watcherRepo)
+	siloService := service.NewSiloService(logger, pg)
+	purgeService := service.NewPurgeService(logger, pg, projectRepo, cfg.Trash.Retention)
+	schedulerService := service.NewSchedulerService(logger, pg, recurrenceRepo, streamService)
+	dispatchService := service.NewDispatchService(logger, pg, notificationRepo, cfg.Notifications.BatchSize)
+
+	taskHandler := handler.NewTaskHandler(logger, taskService)
+	columnHandler := handler.NewColumnHandler(logger, columnService)
+	projectHandler := handler.NewProjectHandler(logger, projectService, templateService, taskService)
+	commentHandler := handler.NewCommentHandler(logger, commentService)
+	activityHandler := handler.NewActivityHandler(logger, activityService)
+	labelHandler := handler.NewLabelHandler(logger, labelService)
+	sprintHandler := handler.NewSprintHandler(logger, sprintService)
+	templateHandler := handler.NewTemplateHandler(logger, templateService)
+	transferHandler := handler.NewTransferHandler(logger, transferService)
+	memberHandler := handler.NewMemberHandler(logger, memberService)
+	teamHandler := handler.NewTeamHandler(logger, teamService)
+	streamHandler := handler.NewStreamHandler(logger, streamService, cfg.Stream.Heartbeat)
+	shareHandler := handler.NewShareHandler(
+		logger,
+		shareService,
+		limit.New(cfg.Share.RateLimit, cfg.Share.Burst),
+		limit.New(cfg.Share.ClientRateLimit, cfg.Share.ClientBurst),
+		cfg.Share.ClientIPHeader,
+	)
+	filterHandler := handler.NewFilterHandler(logger, filterService)
+	fieldHandler := handler.NewFieldHandler(logger, fieldService)
+	worklogHandler := handler.NewWorklogHandler(logger, worklogService)
+	recurrenceHandler := handler.NewRecurrenceHandler(logger, recurrenceService)
+	notificationHandler := handler.NewNotificationHandler(logger, notificationService)
 
-	taskService := service.NewTaskService(logger, taskRepo)
-	columnService := service.NewColumnService(logger, columnRepo)
-	projectService := service.NewProjectService(logger, projectRepo)
+	// Route siloed tenants to their dedicated databases.
+	opts := []nats.SubOpt{nats.DeliverAll(), nats.ManualAck()}
 
-	taskHandler := handler.NewTaskHandler(logger, taskService, columnService)
-	columnHandler := handler.NewColumnHandler(logger, columnService)
-	projectHandler := handler.NewProjectHandler(logger, projectService, columnService, taskService)
+	go func() {
+		js.Listen(
+			string(msg.TypeTenantSiloed),
+			msg.SubjectTenantSiloed,
+			"project_silo_consumer",
+			siloService.RegisterSiloFromEvent,
+			opts...,
+		)
+	}()
+
+	// Every replica pushes every board change to its own clients, so each one listens in a
+	// queue group of its own. Only new changes are delivered; clients reload on reset. The
+	// consumers of stopped replicas expire.
+	go func() {
+		js.Listen(
+			string(msg.TypeProjectChanged),
+			msg.SubjectProjectChanged,
+			"project_stream_"+uuid.New().String(),
+			streamService.BroadcastFromEvent,
+			nats.DeliverNew(),
+			nats.ManualAck(),
+			nats.InactiveThreshold(time.Hour),
+		)
+	}()
+
+	// Purge the trash in the background until shutdown.
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+
+	go purgeService.Run(purgeCtx, cfg.Trash.PurgeInterval)
+
+	// Create the tasks of due recurring tasks in the background until shutdown.
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+
+	go schedulerService.Run(schedulerCtx, cfg.Recurrence.Interval)
+
+	// Turn task events into notifications in the background until shutdown.
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	defer stopDispatch()
+
+	go dispatchService.Run(dispatchCtx, cfg.Notifications.Interval)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Web.Port),
 		WriteTimeout: cfg.Web.WriteTimeout,
 		ReadTimeout:  cfg.Web.ReadTimeout,
-		Handler:      Routes(logger, shutdown, taskHandler, columnHandler, projectHandler, cfg),
+		Handler:      Routes(logger, shutdown, taskHandler, columnHandler, projectHandler, commentHandler, activityHandler, labelHandler, sprintHandler, templateHandler, transferHandler, memberHandler, teamHandler, streamHandler, shareHandler, filterHandler, fieldHandler, worklogHandler, recurrenceHandler, notificationHandler, cfg),
 	}
 
+	// End the board streams on shutdown, since they never finish on their own.
+	srv.RegisterOnShutdown(hub.Close)
+
 	go func() {
 		logger.Info(fmt.Sprintf("Starting project service on %s:%s", cfg.Web.Address, cfg.Web.Port))
 		serverErrors <- srv.ListenAndServe()
@@ -102,6 +219,11 @@ func Run() error {
 			err = srv.Close()
 		}
 
+		// Let running imports finish within the same deadline.
+		if ierr := transferService.Shutdown(ctx); ierr != nil {
+			logger.Error("error waiting for project imports", zap.Error(ierr))
+		}
+
 		switch {
 		case sig == syscall.SIGSTOP:
 			logger.Error("error on integrity issue caused shutdown", zap.Error(err))
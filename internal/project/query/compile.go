@@ -0,0 +1,173 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Env holds what a query depends on when it runs.
+type Env struct {
+	// UserID is the user that me stands for.
+	UserID string
+	// Now is the time that now, today and relative times are measured from.
+	Now time.Time
+}
+
+// Statement represents a query compiled to SQL against the tasks table.
+type Statement struct {
+	// Where is a condition on the tasks row, true when the query has no conditions.
+	Where string
+	// OrderBy lists the sort expressions of the query, without the ORDER BY keywords.
+	// Tasks are ordered newest first by default, and by id last for a stable order.
+	OrderBy string
+	// Args holds the values of the parameters of the statement.
+	Args []interface{}
+}
+
+type compiler struct {
+	env    Env
+	offset int
+	args   []interface{}
+}
+
+// Compile compiles the query. Its parameters are numbered after the first offset
+// parameters, which the caller binds itself.
+func (q *Query) Compile(env Env, offset int) Statement {
+	c := &compiler{env: env, offset: offset}
+
+	s := Statement{Where: "true"}
+	if q.where != nil {
+		s.Where = q.where.compile(c)
+	}
+
+	var terms []string
+	for _, o := range q.order {
+		dir := "asc"
+		if o.desc {
+			dir = "desc"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s nulls last", o.field.expr, dir))
+	}
+	if len(terms) == 0 {
+		terms = append(terms, "tasks.created_at desc")
+	}
+	s.OrderBy = strings.Join(append(terms, "tasks.task_id"), ", ")
+
+	s.Args = c.args
+	return s
+}
+
+// arg binds a value to the next parameter and returns its placeholder.
+func (c *compiler) arg(v interface{}) string {
+	switch v := v.(type) {
+	case me:
+		c.args = append(c.args, c.env.UserID)
+	case moment:
+		c.args = append(c.args, c.resolve(v))
+	default:
+		c.args = append(c.args, v)
+	}
+	return fmt.Sprintf("$%d", c.offset+len(c.args))
+}
+
+// list binds the values and returns their placeholders separated by commas, each
+// wrapped by the format.
+func (c *compiler) list(format string, values []interface{}) string {
+	ps := make([]string, len(values))
+	for i, v := range values {
+		ps[i] = fmt.Sprintf(format, c.arg(v))
+	}
+	return strings.Join(ps, ", ")
+}
+
+func (c *compiler) resolve(m moment) time.Time {
+	switch {
+	case m.absolute:
+		return m.at
+	case m.today:
+		return c.env.Now.UTC().Truncate(24 * time.Hour)
+	}
+	return c.env.Now.UTC().Add(m.relative)
+}
+
+func (b *binary) compile(c *compiler) string {
+	return fmt.Sprintf("(%s %s %s)", b.left.compile(c), b.op, b.right.compile(c))
+}
+
+func (n *not) compile(c *compiler) string {
+	return fmt.Sprintf("not coalesce(%s, false)", n.x.compile(c))
+}
+
+func (cd *cond) compile(c *compiler) string {
+	f := cd.field
+
+	switch f.kind {
+	case kindLabel:
+		return cd.related(c, "select 1 from task_labels tl join labels l on l.label_id = tl.label_id where tl.task_id = tasks.task_id", "lower(l.name)")
+	case kindColumn:
+		return cd.related(c, "select 1 from columns c where c.column_id = tasks.column_id", "lower(c.title)")
+	case kindBool:
+		if cd.values[0].(bool) == (cd.op == "=") {
+			return f.expr
+		}
+		return fmt.Sprintf("not coalesce(%s, false)", f.expr)
+	}
+
+	expr := f.expr
+	if f.kind == kindText && cd.op != "~" && cd.op != "!~" {
+		expr = fmt.Sprintf("lower(%s)", expr)
+	}
+	placeholder := "%s"
+	if f.kind == kindText {
+		placeholder = "lower(%s)"
+	}
+
+	switch cd.op {
+	case "is empty", "is not empty":
+		empty := fmt.Sprintf("%s is null", f.expr)
+		if f.kind == kindUser || f.kind == kindText {
+			empty = fmt.Sprintf("coalesce(%s, '') = ''", f.expr)
+		}
+		if cd.op == "is not empty" {
+			return fmt.Sprintf("not (%s)", empty)
+		}
+		return empty
+	case "~":
+		return fmt.Sprintf("%s ilike %s", expr, c.arg(contains(cd.values[0].(string))))
+	case "!~":
+		return fmt.Sprintf("not coalesce(%s ilike %s, false)", expr, c.arg(contains(cd.values[0].(string))))
+	case "in":
+		return fmt.Sprintf("%s in (%s)", expr, c.list(placeholder, cd.values))
+	case "not in":
+		return fmt.Sprintf("not coalesce(%s in (%s), false)", expr, c.list(placeholder, cd.values))
+	case "!=":
+		return fmt.Sprintf("%s is distinct from %s", expr, fmt.Sprintf(placeholder, c.arg(cd.values[0])))
+	}
+
+	return fmt.Sprintf("%s %s %s", expr, cd.op, fmt.Sprintf(placeholder, c.arg(cd.values[0])))
+}
+
+// related compiles a condition on the rows of another table related to the task, which
+// the subquery selects. A task matches = and IN when one of the rows matches, and != and
+// NOT IN when none does.
+func (cd *cond) related(c *compiler, subquery string, expr string) string {
+	switch cd.op {
+	case "is empty":
+		return fmt.Sprintf("not exists(%s)", subquery)
+	case "is not empty":
+		return fmt.Sprintf("exists(%s)", subquery)
+	}
+
+	match := fmt.Sprintf("exists(%s and %s in (%s))", subquery, expr, c.list("lower(%s)", cd.values))
+	if cd.op == "!=" || cd.op == "not in" {
+		return "not " + match
+	}
+	return match
+}
+
+// contains returns the ILIKE pattern matching text containing s.
+func contains(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
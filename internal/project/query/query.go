@@ -0,0 +1,597 @@
+// Package query parses and compiles the task query language.
+//
+// A query is a filter followed by an optional ordering, for example
+//
+//	assignee = me AND label in (bug) AND points > 3 ORDER BY created DESC
+//
+// Conditions compare a field with a value and combine with AND, OR, NOT and parentheses.
+// Besides the comparison operators, fields support IN (...), NOT IN (...), IS EMPTY and
+// IS NOT EMPTY, and text fields support ~ and !~ to match part of the text. Keywords and
+// field names are case-insensitive, and values with spaces are quoted.
+//
+// Only the fields of Fields can be used, each accepting the operators and values of its
+// kind. Queries compile to parameterized SQL against the tasks table, so values never end
+// up in the statement itself.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxDepth is the deepest nesting of conditions a query can have.
+const maxDepth = 32
+
+// maxValues is the largest number of values of an IN list.
+const maxValues = 50
+
+// Error represents a query that cannot be parsed. Pos is the byte offset in the query
+// where the problem was found.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+// Error returns the message of the error along with its position, counted from 1.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+func errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type kind int
+
+const (
+	kindUser kind = iota
+	kindText
+	kindNumber
+	kindPriority
+	kindTime
+	kindLabel
+	kindColumn
+	kindID
+	kindBool
+)
+
+// field describes a field of the language and the SQL expression it reads.
+type field struct {
+	kind     kind
+	expr     string
+	nullable bool
+	sortable bool
+}
+
+// priorities lists the task priorities from lowest to highest.
+var priorities = []string{"none", "low", "medium", "high", "urgent"}
+
+var fields = map[string]field{
+	"assignee": {kind: kindUser, expr: "tasks.assigned_to", nullable: true},
+	"creator":  {kind: kindUser, expr: "tasks.user_id"},
+	"title":    {kind: kindText, expr: "tasks.title", sortable: true},
+	"content":  {kind: kindText, expr: "tasks.content", nullable: true},
+	"key":      {kind: kindText, expr: "tasks.key", sortable: true},
+	"points":   {kind: kindNumber, expr: "coalesce(tasks.points, 0)", sortable: true},
+	"priority": {kind: kindPriority, expr: "array_position(array['none','low','medium','high','urgent']::varchar[], tasks.priority)", sortable: true},
+	"due":      {kind: kindTime, expr: "tasks.due_at", nullable: true, sortable: true},
+	"start":    {kind: kindTime, expr: "tasks.start_at", nullable: true, sortable: true},
+	"created":  {kind: kindTime, expr: "tasks.created_at", sortable: true},
+	"updated":  {kind: kindTime, expr: "tasks.updated_at", sortable: true},
+	"label":    {kind: kindLabel, nullable: true},
+	"column":   {kind: kindColumn},
+	"project":  {kind: kindID, expr: "tasks.project_id"},
+	"sprint":   {kind: kindID, expr: "tasks.sprint_id", nullable: true},
+	"parent":   {kind: kindID, expr: "tasks.parent_id", nullable: true},
+	"done":     {kind: kindBool, expr: "task_done(tasks.project_id, tasks.column_id)"},
+}
+
+// Fields returns the names of the fields a query can use, sorted.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// supports reports whether the field accepts the operator.
+func (f field) supports(op string) bool {
+	switch op {
+	case "=", "!=":
+		return f.kind != kindTime
+	case "in", "not in":
+		return f.kind != kindTime && f.kind != kindBool
+	case "<", "<=", ">", ">=":
+		return f.kind == kindNumber || f.kind == kindPriority || f.kind == kindTime
+	case "~", "!~":
+		return f.kind == kindText
+	case "is empty", "is not empty":
+		return f.nullable
+	}
+	return false
+}
+
+// Query represents a parsed query.
+type Query struct {
+	where expr
+	order []order
+}
+
+type expr interface {
+	compile(c *compiler) string
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+type not struct {
+	x expr
+}
+
+type cond struct {
+	name   string
+	field  field
+	op     string
+	values []interface{}
+}
+
+type order struct {
+	field field
+	desc  bool
+}
+
+// me is the value standing for the user running the query.
+type me struct{}
+
+// moment is a time value, either absolute or relative to when the query runs.
+type moment struct {
+	at       time.Time
+	today    bool
+	relative time.Duration
+	absolute bool
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+const opChars = "=!<>~"
+
+var operators = map[string]bool{"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "~": true, "!~": true}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:+", r)
+}
+
+// lex splits a query into tokens.
+func lex(s string) ([]token, error) {
+	var toks []token
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			toks = append(toks, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			// A quote is escaped by doubling it.
+			var b strings.Builder
+			j := i + 1
+			for {
+				if j >= len(s) {
+					return nil, errorf(i, "unterminated string")
+				}
+				if s[j] == byte(r) {
+					if j+1 < len(s) && s[j+1] == byte(r) {
+						b.WriteByte(s[j])
+						j += 2
+						continue
+					}
+					break
+				}
+				b.WriteByte(s[j])
+				j++
+			}
+			toks = append(toks, token{kind: tokString, text: b.String(), pos: i})
+			i = j + 1
+		case strings.ContainsRune(opChars, r):
+			j := i + 1
+			for j < len(s) && strings.IndexByte(opChars, s[j]) >= 0 {
+				j++
+			}
+			if !operators[s[i:j]] {
+				return nil, errorf(i, "unknown operator %q", s[i:j])
+			}
+			toks = append(toks, token{kind: tokOp, text: s[i:j], pos: i})
+			i = j
+		case isWordRune(r):
+			j := i
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !isWordRune(r) {
+					break
+				}
+				j += size
+			}
+			toks = append(toks, token{kind: tokWord, text: s[i:j], pos: i})
+			i = j
+		default:
+			return nil, errorf(i, "unexpected character %q", r)
+		}
+	}
+
+	return append(toks, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+type parser struct {
+	toks  []token
+	i     int
+	depth int
+}
+
+// Parse parses a query. An empty query matches every task.
+func Parse(s string) (*Query, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	q := &Query{}
+
+	if p.peek().kind != tokEOF && !p.keyword("order") {
+		if q.where, err = p.or(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.keyword("order") {
+		p.next()
+		if !p.keyword("by") {
+			return nil, errorf(p.peek().pos, "expected BY after ORDER, got %s", p.peek())
+		}
+		p.next()
+		if q.order, err = p.orderBy(); err != nil {
+			return nil, err
+		}
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorf(t.pos, "unexpected %s", t)
+	}
+
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the keyword.
+func (p *parser) keyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) or() (expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.depth++; p.depth > maxDepth {
+		return nil, errorf(p.peek().pos, "query is nested too deeply")
+	}
+	defer func() { p.depth-- }()
+
+	if p.keyword("not") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &not{x: x}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		open := p.next()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, errorf(t.pos, "expected \")\" to close the \"(\" at position %d, got %s", open.pos+1, t)
+		}
+		return x, nil
+	}
+
+	return p.cond()
+}
+
+func (p *parser) field() (string, field, error) {
+	t := p.next()
+	if t.kind != tokWord {
+		return "", field{}, errorf(t.pos, "expected a field, got %s", t)
+	}
+	name := strings.ToLower(t.text)
+	f, ok := fields[name]
+	if !ok {
+		return "", field{}, errorf(t.pos, "unknown field %q, expected one of %s", t.text, strings.Join(Fields(), ", "))
+	}
+	return name, f, nil
+}
+
+func (p *parser) cond() (expr, error) {
+	name, f, err := p.field()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	var op string
+	switch {
+	case t.kind == tokOp:
+		op = t.text
+		p.next()
+	case p.keyword("in"):
+		op = "in"
+		p.next()
+	case p.keyword("not"):
+		p.next()
+		if !p.keyword("in") {
+			return nil, errorf(p.peek().pos, "expected IN after NOT, got %s", p.peek())
+		}
+		op = "not in"
+		p.next()
+	case p.keyword("is"):
+		p.next()
+		op = "is empty"
+		if p.keyword("not") {
+			p.next()
+			op = "is not empty"
+		}
+		if !p.keyword("empty") {
+			return nil, errorf(p.peek().pos, "expected EMPTY after IS, got %s", p.peek())
+		}
+		p.next()
+	default:
+		return nil, errorf(t.pos, "expected an operator after %s, got %s", name, t)
+	}
+
+	if !f.supports(op) {
+		return nil, errorf(t.pos, "%s does not support %s", name, strings.ToUpper(op))
+	}
+
+	c := &cond{name: name, field: f, op: op}
+
+	switch op {
+	case "is empty", "is not empty":
+	case "in", "not in":
+		if t := p.next(); t.kind != tokLParen {
+			return nil, errorf(t.pos, "expected \"(\" after %s, got %s", strings.ToUpper(op), t)
+		}
+		for {
+			v, err := p.value(name, f)
+			if err != nil {
+				return nil, err
+			}
+			c.values = append(c.values, v)
+			if len(c.values) > maxValues {
+				return nil, errorf(p.peek().pos, "%s lists more than %d values", strings.ToUpper(op), maxValues)
+			}
+
+			t := p.next()
+			if t.kind == tokRParen {
+				break
+			}
+			if t.kind != tokComma {
+				return nil, errorf(t.pos, "expected \",\" or \")\" in the list of values, got %s", t)
+			}
+		}
+	default:
+		v, err := p.value(name, f)
+		if err != nil {
+			return nil, err
+		}
+		c.values = append(c.values, v)
+	}
+
+	return c, nil
+}
+
+// value parses a value of the field.
+func (p *parser) value(name string, f field) (interface{}, error) {
+	t := p.next()
+	if t.kind != tokWord && t.kind != tokString {
+		return nil, errorf(t.pos, "expected a value for %s, got %s", name, t)
+	}
+
+	switch f.kind {
+	case kindUser:
+		if t.kind == tokWord && strings.EqualFold(t.text, "me") {
+			return me{}, nil
+		}
+		return t.text, nil
+	case kindNumber:
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, errorf(t.pos, "%s expects a whole number, got %s", name, t)
+		}
+		return n, nil
+	case kindPriority:
+		for i, priority := range priorities {
+			if strings.EqualFold(t.text, priority) {
+				return i + 1, nil
+			}
+		}
+		return nil, errorf(t.pos, "%s expects one of %s, got %s", name, strings.Join(priorities, ", "), t)
+	case kindTime:
+		m, ok := parseMoment(t.text)
+		if !ok {
+			return nil, errorf(t.pos, "%s expects a date like 2024-01-31, a time like 2024-01-31T15:04:05Z, now, today or a relative time like -7d, got %s", name, t)
+		}
+		return m, nil
+	case kindID:
+		if _, err := uuid.Parse(t.text); err != nil {
+			return nil, errorf(t.pos, "%s expects an id, got %s", name, t)
+		}
+		return t.text, nil
+	case kindBool:
+		b, err := strconv.ParseBool(t.text)
+		if err != nil {
+			return nil, errorf(t.pos, "%s expects true or false, got %s", name, t)
+		}
+		return b, nil
+	}
+
+	return t.text, nil
+}
+
+// parseMoment parses a time value: a date, an RFC 3339 time, now, today, or a number of
+// hours, days or weeks from now such as -7d or +2w.
+func parseMoment(s string) (moment, bool) {
+	switch strings.ToLower(s) {
+	case "now":
+		return moment{}, true
+	case "today":
+		return moment{today: true}, true
+	}
+
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return moment{at: t, absolute: true}, true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return moment{at: t.UTC(), absolute: true}, true
+	}
+
+	if len(s) < 3 || (s[0] != '-' && s[0] != '+') {
+		return moment{}, false
+	}
+	n, err := strconv.Atoi(s[1 : len(s)-1])
+	if err != nil || n < 0 {
+		return moment{}, false
+	}
+
+	var unit time.Duration
+	switch s[len(s)-1] {
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return moment{}, false
+	}
+
+	d := time.Duration(n) * unit
+	if s[0] == '-' {
+		d = -d
+	}
+	return moment{relative: d}, true
+}
+
+func (p *parser) orderBy() ([]order, error) {
+	var list []order
+
+	for {
+		start := p.peek()
+		name, f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		if !f.sortable {
+			return nil, errorf(start.pos, "cannot order by %s", name)
+		}
+
+		o := order{field: f}
+		switch {
+		case p.keyword("asc"):
+			p.next()
+		case p.keyword("desc"):
+			p.next()
+			o.desc = true
+		}
+		list = append(list, o)
+
+		if p.peek().kind != tokComma {
+			return list, nil
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,118 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/query"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	now := time.Date(2024, 3, 6, 15, 30, 0, 0, time.UTC)
+	env := query.Env{UserID: "user", Now: now}
+
+	tests := []struct {
+		name    string
+		query   string
+		where   string
+		orderBy string
+		args    []interface{}
+	}{
+		{
+			name:    "empty",
+			query:   "",
+			where:   "true",
+			orderBy: "tasks.created_at desc, tasks.task_id",
+		},
+		{
+			name:    "example",
+			query:   "assignee = me AND label in (bug) AND points > 3 ORDER BY created DESC",
+			where:   "((tasks.assigned_to = $3 and exists(select 1 from task_labels tl join labels l on l.label_id = tl.label_id where tl.task_id = tasks.task_id and lower(l.name) in (lower($4)))) and coalesce(tasks.points, 0) > $5)",
+			orderBy: "tasks.created_at desc nulls last, tasks.task_id",
+			args:    []interface{}{"user", "bug", 3},
+		},
+		{
+			name:    "or binds looser than and",
+			query:   `priority >= high or title ~ "50%" and not done = true`,
+			where:   "(array_position(array['none','low','medium','high','urgent']::varchar[], tasks.priority) >= $3 or (tasks.title ilike $4 and not coalesce(task_done(tasks.project_id, tasks.column_id), false)))",
+			orderBy: "tasks.created_at desc, tasks.task_id",
+			args:    []interface{}{4, `%50\%%`},
+		},
+		{
+			name:    "parentheses and negations",
+			query:   `(column != Done or label is empty) and assignee not in (me, 'someone else') and key != "prj-1"`,
+			where:   "(((not exists(select 1 from columns c where c.column_id = tasks.column_id and lower(c.title) in (lower($3))) or not exists(select 1 from task_labels tl join labels l on l.label_id = tl.label_id where tl.task_id = tasks.task_id)) and not coalesce(tasks.assigned_to in ($4, $5), false)) and lower(tasks.key) is distinct from lower($6))",
+			orderBy: "tasks.created_at desc, tasks.task_id",
+			args:    []interface{}{"Done", "user", "someone else", "prj-1"},
+		},
+		{
+			name:    "times",
+			query:   "due < today AND created >= -7d AND updated > 2024-03-01 AND start IS NOT EMPTY ORDER BY due, priority desc",
+			where:   "(((tasks.due_at < $3 and tasks.created_at >= $4) and tasks.updated_at > $5) and not (tasks.start_at is null))",
+			orderBy: "tasks.due_at asc nulls last, array_position(array['none','low','medium','high','urgent']::varchar[], tasks.priority) desc nulls last, tasks.task_id",
+			args: []interface{}{
+				time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC),
+				now.Add(-7 * 24 * time.Hour),
+				time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "ids and empty text",
+			query:   "sprint = 6d1f3a52-0c4e-4b7a-9d2e-8f1a5b6c7d01 and content is empty and done = false",
+			where:   "((tasks.sprint_id = $3 and coalesce(tasks.content, '') = '') and not coalesce(task_done(tasks.project_id, tasks.column_id), false))",
+			orderBy: "tasks.created_at desc, tasks.task_id",
+			args:    []interface{}{"6d1f3a52-0c4e-4b7a-9d2e-8f1a5b6c7d01"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := query.Parse(tc.query)
+			require.NoError(t, err)
+
+			s := q.Compile(env, 2)
+			assert.Equal(t, tc.where, s.Where)
+			assert.Equal(t, tc.orderBy, s.OrderBy)
+			assert.Equal(t, tc.args, s.Args)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{name: "unknown field", query: "status = open", err: `unknown field "status", expected one of assignee, column, content, created, creator, done, due, key, label, parent, points, priority, project, sprint, start, title, updated at position 1`},
+		{name: "missing value", query: "points >", err: "expected a value for points, got end of query at position 9"},
+		{name: "not a number", query: "points > three", err: `points expects a whole number, got "three" at position 10`},
+		{name: "unsupported operator", query: "label > bug", err: "label does not support > at position 7"},
+		{name: "unknown operator", query: "points => 3", err: `unknown operator "=>" at position 8`},
+		{name: "unknown priority", query: "priority = huge", err: `priority expects one of none, low, medium, high, urgent, got "huge" at position 12`},
+		{name: "bad time", query: "due < tomorrow", err: `due expects a date like 2024-01-31, a time like 2024-01-31T15:04:05Z, now, today or a relative time like -7d, got "tomorrow" at position 7`},
+		{name: "bad id", query: "project = abc", err: `project expects an id, got "abc" at position 11`},
+		{name: "not nullable", query: "creator is empty", err: "creator does not support IS EMPTY at position 9"},
+		{name: "unclosed parenthesis", query: "(points > 3", err: `expected ")" to close the "(" at position 1, got end of query at position 12`},
+		{name: "unclosed list", query: "label in (a b)", err: `expected "," or ")" in the list of values, got "b" at position 13`},
+		{name: "unterminated string", query: `title = "open`, err: "unterminated string at position 9"},
+		{name: "dangling keyword", query: "points > 3 and", err: "expected a field, got end of query at position 15"},
+		{name: "trailing tokens", query: "points > 3 points", err: `unexpected "points" at position 12`},
+		{name: "order without by", query: "order created", err: `expected BY after ORDER, got "created" at position 7`},
+		{name: "unsortable field", query: "order by label", err: "cannot order by label at position 10"},
+		{name: "unexpected character", query: "points > 3 & done = true", err: `unexpected character '&' at position 12`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := query.Parse(tc.query)
+
+			var qe *query.Error
+			require.ErrorAs(t, err, &qe)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package rank generates fractional ranks that order tasks within a column.
+//
+// A rank is a base62 string compared byte by byte, so a new rank can always be
+// generated between two existing ones without renumbering their neighbours.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrOrder is returned when the lower rank does not sort before the upper rank.
+var ErrOrder = errors.New("rank: lower rank must sort before upper rank")
+
+// Between returns a rank that sorts after lo and before hi. An empty lo means the
+// start of the column and an empty hi means its end.
+func Between(lo, hi string) (string, error) {
+	if hi != "" && lo >= hi {
+		return "", ErrOrder
+	}
+	if !valid(lo) || !valid(hi) {
+		return "", ErrOrder
+	}
+	return midpoint(lo, hi), nil
+}
+
+// Initial returns the ranks for n tasks placed in an empty column.
+func Initial(n int) []string {
+	ranks := make([]string, n)
+	prev := ""
+	for i := range ranks {
+		prev = midpoint(prev, "")
+		ranks[i] = prev
+	}
+	return ranks
+}
+
+// midpoint returns a string between lo and hi. Neither may end in the zero digit,
+// which keeps room below every rank.
+func midpoint(lo, hi string) string {
+	if hi != "" {
+		// Keep the common prefix, treating a short lo as padded with zero digits.
+		n := 0
+		for n < len(hi) && digitAt(lo, n) == hi[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(lo) {
+				rest = lo[n:]
+			}
+			return hi[:n] + midpoint(rest, hi[n:])
+		}
+	}
+
+	dLo := 0
+	if lo != "" {
+		dLo = strings.IndexByte(digits, lo[0])
+	}
+	dHi := len(digits)
+	if hi != "" {
+		dHi = strings.IndexByte(digits, hi[0])
+	}
+
+	if dHi-dLo > 1 {
+		return string(digits[(dLo+dHi+1)/2])
+	}
+
+	// The leading digits are consecutive.
+	if hi != "" && len(hi) > 1 {
+		return hi[:1]
+	}
+	rest := ""
+	if lo != "" {
+		rest = lo[1:]
+	}
+	return string(digits[dLo]) + midpoint(rest, "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func valid(s string) bool {
+	if s != "" && s[len(s)-1] == digits[0] {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(digits, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package rank_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/rank"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		lo   string
+		hi   string
+		err  error
+	}{
+		{name: "empty column", lo: "", hi: ""},
+		{name: "append", lo: "V", hi: ""},
+		{name: "prepend", lo: "", hi: "V"},
+		{name: "consecutive digits", lo: "V", hi: "W"},
+		{name: "common prefix", lo: "V1", hi: "V2"},
+		{name: "short lower rank", lo: "V", hi: "V01"},
+		{name: "lowest rank", lo: "", hi: "01"},
+		{name: "out of order", lo: "W", hi: "V", err: rank.ErrOrder},
+		{name: "equal", lo: "V", hi: "V", err: rank.ErrOrder},
+		{name: "trailing zero digit", lo: "V0", hi: "", err: rank.ErrOrder},
+		{name: "invalid digit", lo: "V-", hi: "", err: rank.ErrOrder},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := rank.Between(tc.lo, tc.hi)
+			if tc.err != nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Greater(t, r, tc.lo)
+			if tc.hi != "" {
+				assert.Less(t, r, tc.hi)
+			}
+		})
+	}
+}
+
+func TestBetween_RepeatedInserts(t *testing.T) {
+	ranks := rank.Initial(3)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		pos := rng.Intn(len(ranks) + 1)
+		lo, hi := "", ""
+		if pos > 0 {
+			lo = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			hi = ranks[pos]
+		}
+
+		r, err := rank.Between(lo, hi)
+		require.NoError(t, err)
+
+		ranks = append(ranks[:pos], append([]string{r}, ranks[pos:]...)...)
+		require.True(t, sort.StringsAreSorted(ranks))
+	}
+}
@@ -9,9 +9,11 @@ import (
 	"github.com/devpies/saas-core/internal/project/db"
 	"github.com/devpies/saas-core/internal/project/fail"
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/rank"
 	"github.com/devpies/saas-core/pkg/web"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
@@ -37,11 +39,16 @@ func (cr *ColumnRepository) Retrieve(ctx context.Context, cid string) (model.Col
 		err error
 	)
 
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return c, web.CtxErr()
+	}
+
 	if _, err = uuid.Parse(cid); err != nil {
 		return c, fail.ErrInvalidID
 	}
 
-	conn, Close, err := cr.pg.GetConnection(ctx)
+	conn, Close, err := cr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return c, err
 	}
@@ -49,13 +56,14 @@ func (cr *ColumnRepository) Retrieve(ctx context.Context, cid string) (model.Col
 
 	stmt := `
 		select 
-		    column_id, tenant_id, project_id, title, 
-		    column_name, task_ids, updated_at, created_at
+		    column_id, tenant_id, project_id, title, column_name, wip_limit, entry_rules,
+		    array(select t.task_id from tasks t where t.column_id = columns.column_id and t.deleted_at is null and t.archived_at is null order by t.rank) as task_ids,
+		    updated_at, created_at
 		from columns
 		where column_id = $1
 	`
 
-	err = conn.QueryRowxContext(ctx, stmt, cid).Scan(&c.ID, &c.TenantID, &c.ProjectID, &c.Title, &c.ColumnName, (*pq.StringArray)(&c.TaskIDS), &c.UpdatedAt, &c.CreatedAt)
+	err = conn.QueryRowxContext(ctx, stmt, cid).Scan(&c.ID, &c.TenantID, &c.ProjectID, &c.Title, &c.ColumnName, &c.WIPLimit, (*pq.StringArray)(&c.EntryRules), (*pq.StringArray)(&c.TaskIDS), &c.UpdatedAt, &c.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c, fail.ErrNotFound
@@ -63,6 +71,11 @@ func (cr *ColumnRepository) Retrieve(ctx context.Context, cid string) (model.Col
 		return c, err
 	}
 
+	if err = authorize(ctx, conn, c.ProjectID, values.UserID, model.RoleViewer); err != nil {
+		return model.Column{}, err
+	}
+
+	c.TaskCount = len(c.TaskIDS)
 	c.UpdatedAt = c.UpdatedAt.UTC()
 	c.CreatedAt = c.CreatedAt.UTC()
 	return c, nil
@@ -76,19 +89,30 @@ func (cr *ColumnRepository) List(ctx context.Context, pid string) ([]model.Colum
 		err error
 	)
 
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return cs, web.CtxErr()
+	}
+
 	if _, err = uuid.Parse(pid); err != nil {
 		return cs, fail.ErrInvalidID
 	}
 
-	conn, Close, err := cr.pg.GetConnection(ctx)
+	conn, Close, err := cr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return cs, err
 	}
 	defer Close()
 
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
 	stmt := `
 		select 
-			column_id, tenant_id, project_id, title, column_name, task_ids, updated_at, created_at	
+			column_id, tenant_id, project_id, title, column_name, wip_limit, entry_rules,
+			array(select t.task_id from tasks t where t.column_id = columns.column_id and t.deleted_at is null and t.archived_at is null order by t.rank) as task_ids,
+			updated_at, created_at
 		from columns
 		where project_id = $1
 	`
@@ -101,10 +125,11 @@ func (cr *ColumnRepository) List(ctx context.Context, pid string) ([]model.Colum
 		return nil, fmt.Errorf("error selecting columns :%w", err)
 	}
 	for rows.Next() {
-		err = rows.Scan(&c.ID, &c.TenantID, &c.ProjectID, &c.Title, &c.ColumnName, (*pq.StringArray)(&c.TaskIDS), &c.UpdatedAt, &c.CreatedAt)
+		err = rows.Scan(&c.ID, &c.TenantID, &c.ProjectID, &c.Title, &c.ColumnName, &c.WIPLimit, (*pq.StringArray)(&c.EntryRules), (*pq.StringArray)(&c.TaskIDS), &c.UpdatedAt, &c.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row into struct :%w", err)
 		}
+		c.TaskCount = len(c.TaskIDS)
 		c.UpdatedAt = c.UpdatedAt.UTC()
 		c.CreatedAt = c.CreatedAt.UTC()
 		cs = append(cs, c)
@@ -131,11 +156,16 @@ func (cr *ColumnRepository) Create(ctx context.Context, nc model.NewColumn, now
 	}
 	defer Close()
 
+	if err = authorize(ctx, conn, nc.ProjectID, values.UserID, model.RoleOwner); err != nil {
+		return c, err
+	}
+
 	c = model.Column{
 		ID:         uuid.New().String(),
 		TenantID:   values.TenantID,
 		Title:      nc.Title,
 		ColumnName: nc.ColumnName,
+		EntryRules: make([]string, 0),
 		TaskIDS:    make([]string, 0),
 		ProjectID:  nc.ProjectID,
 		UpdatedAt:  now.Round(time.Microsecond).UTC(),
@@ -144,9 +174,9 @@ func (cr *ColumnRepository) Create(ctx context.Context, nc model.NewColumn, now
 
 	stmt := `
 		insert into columns (
-			column_id, tenant_id, title, column_name, task_ids,
+			column_id, tenant_id, title, column_name,
 			project_id, updated_at, created_at
-	 	) values ($1, $2, $3, $4, $5, $6, $7, $8)
+	 	) values ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	if _, err = conn.ExecContext(
@@ -156,24 +186,30 @@ func (cr *ColumnRepository) Create(ctx context.Context, nc model.NewColumn, now
 		c.TenantID,
 		c.Title,
 		c.ColumnName,
-		pq.Array(c.TaskIDS),
 		c.ProjectID,
 		c.UpdatedAt,
 		c.CreatedAt,
 	); err != nil {
 		return c, fmt.Errorf("error inserting column: %+v :%w", nc, err)
 	}
+	db.Wrote(ctx)
 
 	return c, nil
 }
 
-// Update updates a project column from the database.
+// Update updates a project column from the database. Editors rename a column, only
+// project owners change its policies.
 func (cr *ColumnRepository) Update(ctx context.Context, cid string, uc model.UpdateColumn, now time.Time) (model.Column, error) {
 	var (
 		c   model.Column
 		err error
 	)
 
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return c, web.CtxErr()
+	}
+
 	if _, err = uuid.Parse(cid); err != nil {
 		return c, fail.ErrInvalidID
 	}
@@ -184,39 +220,62 @@ func (cr *ColumnRepository) Update(ctx context.Context, cid string, uc model.Upd
 	}
 	defer Close()
 
-	c, err = cr.Retrieve(ctx, cid)
+	c, err = cr.Retrieve(db.Primary(ctx), cid)
 	if err != nil {
 		return c, err
 	}
 
+	required := model.RoleEditor
+	if uc.WIPLimit != nil || uc.EntryRules != nil {
+		required = model.RoleOwner
+	}
+	if err = authorize(ctx, conn, c.ProjectID, values.UserID, required); err != nil {
+		return model.Column{}, err
+	}
+
 	if uc.Title != nil {
 		c.Title = *uc.Title
 	}
-
-	if uc.TaskIDS != nil {
-		c.TaskIDS = uc.TaskIDS
+	if uc.WIPLimit != nil {
+		c.WIPLimit = uc.WIPLimit
+		if *uc.WIPLimit == 0 {
+			c.WIPLimit = nil
+		}
+	}
+	if uc.EntryRules != nil {
+		c.EntryRules = uc.EntryRules
 	}
 
 	stmt := `
 		update columns
 		set
 			title = $1,
-			task_ids = $2,
-			updated_at = $3
-		where column_id = $4
+			wip_limit = $2,
+			entry_rules = $3,
+			updated_at = $4
+		where column_id = $5
 	`
 
-	_, err = conn.ExecContext(ctx, stmt, c.Title, pq.Array(c.TaskIDS), now.Round(time.Microsecond).UTC(), cid)
+	_, err = conn.ExecContext(ctx, stmt, c.Title, c.WIPLimit, pq.Array(c.EntryRules), now.Round(time.Microsecond).UTC(), cid)
 	if err != nil {
 		return c, fmt.Errorf("error updating column :%w", err)
 	}
+	db.Wrote(ctx)
 
 	return c, nil
 }
 
 // Delete deletes a project column from the database.
 func (cr *ColumnRepository) Delete(ctx context.Context, cid string) error {
-	var err error
+	var (
+		pid string
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
 
 	if _, err = uuid.Parse(cid); err != nil {
 		return fail.ErrInvalidID
@@ -228,11 +287,442 @@ func (cr *ColumnRepository) Delete(ctx context.Context, cid string) error {
 	}
 	defer Close()
 
-	stmt := `delete from columns where column_id = $1`
+	stmt := `select project_id from columns where column_id = $1`
+	if err = conn.QueryRowxContext(ctx, stmt, cid).Scan(&pid); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleOwner); err != nil {
+		return err
+	}
+
+	stmt = `delete from columns where column_id = $1`
 
 	if _, err = conn.ExecContext(ctx, stmt, cid); err != nil {
 		return fmt.Errorf("error deleting column %s :%w", cid, err)
 	}
+	db.Wrote(ctx)
+
+	return nil
+}
+
+// Add adds a column to the end of a project board.
+func (cr *ColumnRepository) Add(ctx context.Context, pid string, ac model.AddColumn, now time.Time) (model.Column, error) {
+	var (
+		c   model.Column
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return c, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return c, fail.ErrInvalidID
+	}
 
+	c = model.Column{
+		ID:         uuid.New().String(),
+		TenantID:   values.TenantID,
+		Title:      ac.Title,
+		EntryRules: make([]string, 0),
+		TaskIDS:    make([]string, 0),
+		ProjectID:  pid,
+		UpdatedAt:  now.Round(time.Microsecond).UTC(),
+		CreatedAt:  now.Round(time.Microsecond).UTC(),
+	}
+
+	err = cr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		order, err := lockColumnOrder(ctx, tx, pid)
+		if err != nil {
+			return err
+		}
+		if len(order) >= model.MaxColumns {
+			return fail.ErrColumnLimit
+		}
+
+		// Names of removed columns are reused, so names never outgrow column-10.
+		var next sql.NullInt64
+		stmt := `
+			select min(n)
+			from generate_series(1, $2::int) n
+			where not exists (
+				select 1 from columns where project_id = $1 and column_name = 'column-' || n
+			)
+		`
+		if err = tx.QueryRowxContext(ctx, stmt, pid, model.MaxColumns).Scan(&next); err != nil {
+			return fmt.Errorf("error selecting column name :%w", err)
+		}
+		if !next.Valid {
+			return fail.ErrColumnLimit
+		}
+		c.ColumnName = fmt.Sprintf("column-%d", next.Int64)
+
+		stmt = `
+			insert into columns (
+				column_id, tenant_id, title, column_name,
+				project_id, updated_at, created_at
+			) values ($1, $2, $3, $4, $5, $6, $7)
+		`
+		if _, err = tx.ExecContext(ctx, stmt, c.ID, c.TenantID, c.Title, c.ColumnName, c.ProjectID, c.UpdatedAt, c.CreatedAt); err != nil {
+			return fmt.Errorf("error inserting column: %+v :%w", ac, err)
+		}
+
+		stmt = `update projects set column_order = array_append(column_order, $1), updated_at = $2 where project_id = $3`
+		if _, err = tx.ExecContext(ctx, stmt, c.ColumnName, c.UpdatedAt, pid); err != nil {
+			return fmt.Errorf("error updating column order :%w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Column{}, err
+	}
+
+	return c, nil
+}
+
+// Remove deletes a column from a project board. Its tasks are appended to the
+// moveTo column in their current order, or moved to the trash when moveTo is empty.
+// The tasks moved or trashed are returned.
+func (cr *ColumnRepository) Remove(ctx context.Context, pid string, cid string, moveTo string, now time.Time) ([]model.Task, error) {
+	var (
+		ts  []model.Task
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return nil, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return nil, fail.ErrInvalidID
+	}
+	if _, err = uuid.Parse(cid); err != nil {
+		return nil, fail.ErrInvalidID
+	}
+	if moveTo != "" {
+		if _, err = uuid.Parse(moveTo); err != nil || moveTo == cid {
+			return nil, fail.ErrInvalidID
+		}
+	}
+
+	err = cr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var name string
+
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		order, err := lockColumnOrder(ctx, tx, pid)
+		if err != nil {
+			return err
+		}
+
+		stmt := `select column_name from columns where column_id = $1 and project_id = $2`
+		if err = tx.QueryRowxContext(ctx, stmt, cid, pid).Scan(&name); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return err
+		}
+		if len(order) <= 1 {
+			return fail.ErrLastColumn
+		}
+
+		if moveTo != "" {
+			ts, err = appendTasks(ctx, tx, pid, cid, moveTo, values.UserID, now)
+		} else {
+			ts, err = trashTasks(ctx, tx, cid, values.UserID, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		stmt = `delete from columns where column_id = $1`
+		if _, err = tx.ExecContext(ctx, stmt, cid); err != nil {
+			return fmt.Errorf("error deleting column %s :%w", cid, err)
+		}
+
+		stmt = `update projects set column_order = array_remove(column_order, $1), updated_at = $2 where project_id = $3`
+		if _, err = tx.ExecContext(ctx, stmt, name, now.Round(time.Microsecond).UTC(), pid); err != nil {
+			return fmt.Errorf("error updating column order :%w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// appendTasks moves every task of column from to the end of column to. Tasks on the
+// board must meet the policies of column to, and their moves are recorded like any
+// other. Tasks in the trash follow along, so they are not deleted with the column.
+func appendTasks(ctx context.Context, tx *sqlx.Tx, pid, from, to, userID string, now time.Time) ([]model.Task, error) {
+	var (
+		last  string
+		moved []model.Task
+	)
+
+	if err := lockColumn(ctx, tx, pid, to); err != nil {
+		return nil, err
+	}
+
+	stmt := `select coalesce(max(rank), '') from tasks where column_id = $1`
+	if err := tx.QueryRowxContext(ctx, stmt, to).Scan(&last); err != nil {
+		return nil, err
+	}
+
+	stmt = `
+		select
+			task_id, tenant_id, project_id, column_id, coalesce(assigned_to, ''), coalesce(points, 0), due_at,
+			deleted_at is not null, archived_at is not null
+		from tasks
+		where column_id = $1
+		order by rank
+		for update
+	`
+	rows, err := tx.QueryxContext(ctx, stmt, from)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting tasks of column %s :%w", from, err)
+	}
+
+	type task struct {
+		model.Task
+		deleted  bool
+		archived bool
+	}
+	var ts []task
+	for rows.Next() {
+		var t task
+		if err = rows.Scan(&t.ID, &t.TenantID, &t.ProjectID, &t.ColumnID, &t.AssignedTo, &t.Points, &t.DueAt, &t.deleted, &t.archived); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning row into struct :%w", err)
+		}
+		ts = append(ts, t)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stmt = `update tasks set column_id = $1, rank = $2, updated_at = $3 where task_id = $4`
+	for _, t := range ts {
+		if !t.deleted && !t.archived {
+			if err = checkColumnPolicies(ctx, tx, to, t.Task); err != nil {
+				return nil, err
+			}
+		}
+
+		next, err := rank.Between(last, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err = tx.ExecContext(ctx, stmt, to, next, now.Round(time.Microsecond).UTC(), t.ID); err != nil {
+			return nil, fmt.Errorf("error moving task %s :%w", t.ID, err)
+		}
+		last = next
+
+		if t.deleted {
+			continue
+		}
+		changes := map[string]model.FieldChange{
+			"columnId": {From: from, To: to},
+		}
+		if err = recordEvent(ctx, tx, t.Task, userID, model.TaskMoved, changes, now); err != nil {
+			return nil, err
+		}
+		t.ColumnID = to
+		moved = append(moved, t.Task)
+	}
+	return moved, nil
+}
+
+// trashTasks moves the tasks of a column to the trash, like deleting each of them. The
+// tasks are taken off the column, so they are not deleted with it, and are restored to
+// the first column of the board.
+func trashTasks(ctx context.Context, tx *sqlx.Tx, cid, userID string, now time.Time) ([]model.Task, error) {
+	var trashed []model.Task
+
+	stmt := `
+		update tasks set deleted_at = $1
+		where column_id = $2 and deleted_at is null
+		returning task_id, tenant_id, project_id, key, title
+	`
+	rows, err := tx.QueryxContext(ctx, stmt, now.Round(time.Microsecond).UTC(), cid)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting tasks of column %s :%w", cid, err)
+	}
+	for rows.Next() {
+		var t model.Task
+		if err = rows.Scan(&t.ID, &t.TenantID, &t.ProjectID, &t.Key, &t.Title); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning row into struct :%w", err)
+		}
+		trashed = append(trashed, t)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range trashed {
+		changes := map[string]model.FieldChange{
+			"key":   {From: t.Key},
+			"title": {From: t.Title},
+		}
+		if err = recordEvent(ctx, tx, t, userID, model.TaskDeleted, changes, now); err != nil {
+			return nil, err
+		}
+	}
+
+	stmt = `update tasks set column_id = null where column_id = $1`
+	if _, err = tx.ExecContext(ctx, stmt, cid); err != nil {
+		return nil, fmt.Errorf("error detaching tasks of column %s :%w", cid, err)
+	}
+	return trashed, nil
+}
+
+// Reorder changes the column order of a project board.
+func (cr *ColumnRepository) Reorder(ctx context.Context, pid string, order []string, now time.Time) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	return cr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+		if _, err := lockColumnOrder(ctx, tx, pid); err != nil {
+			return err
+		}
+		if err := checkColumnOrder(ctx, tx, pid, order); err != nil {
+			return err
+		}
+
+		stmt := `update projects set column_order = $1, updated_at = $2 where project_id = $3`
+		if _, err := tx.ExecContext(ctx, stmt, pq.Array(order), now.Round(time.Microsecond).UTC(), pid); err != nil {
+			return fmt.Errorf("error updating column order :%w", err)
+		}
+		return nil
+	})
+}
+
+// lockColumnOrder locks a project row for the rest of the transaction and returns its column order.
+func lockColumnOrder(ctx context.Context, tx *sqlx.Tx, pid string) ([]string, error) {
+	var order []string
+
+	stmt := `select column_order from projects where project_id = $1 for update`
+
+	if err := tx.QueryRowxContext(ctx, stmt, pid).Scan((*pq.StringArray)(&order)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fail.ErrNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// lockColumn locks a column of a project for the rest of the transaction, serializing
+// changes to the order of its tasks.
+func lockColumn(ctx context.Context, tx *sqlx.Tx, pid, cid string) error {
+	var id string
+
+	stmt := `select column_id from columns where column_id = $1 and project_id = $2 for update`
+
+	if err := tx.QueryRowxContext(ctx, stmt, cid, pid).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// entryRuleMessages describes the entry rules of columns.
+var entryRuleMessages = map[string]string{
+	model.RuleAssignee: "tasks entering the column must be assigned",
+	model.RulePoints:   "tasks entering the column must be estimated",
+	model.RuleDueDate:  "tasks entering the column must have a due date",
+}
+
+// checkColumnPolicies checks that a task may enter a column: the column must stay within
+// its wip limit and the task must meet its entry rules. Tasks of the column other than t
+// count against the limit.
+func checkColumnPolicies(ctx context.Context, tx *sqlx.Tx, cid string, t model.Task) error {
+	var (
+		limit sql.NullInt64
+		rules []string
+		count int
+	)
+
+	stmt := `
+		select wip_limit, entry_rules, (
+			select count(*) from tasks
+			where column_id = columns.column_id and deleted_at is null and archived_at is null and task_id <> $2
+		)
+		from columns where column_id = $1
+	`
+	if err := tx.QueryRowxContext(ctx, stmt, cid, t.ID).Scan(&limit, (*pq.StringArray)(&rules), &count); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+
+	var vs []fail.Violation
+	if limit.Valid && int64(count) >= limit.Int64 {
+		vs = append(vs, fail.Violation{
+			Policy:  model.PolicyWIPLimit,
+			Message: fmt.Sprintf("column holds at most %d tasks", limit.Int64),
+		})
+	}
+	for _, rule := range model.UnmetEntryRules(rules, t) {
+		vs = append(vs, fail.Violation{Policy: rule, Message: entryRuleMessages[rule]})
+	}
+
+	if len(vs) > 0 {
+		return &fail.PolicyError{ColumnID: cid, Violations: vs}
+	}
+	return nil
+}
+
+// checkColumnOrder checks that order lists every column of a project exactly once.
+func checkColumnOrder(ctx context.Context, tx *sqlx.Tx, pid string, order []string) error {
+	var names []string
+
+	stmt := `select column_name from columns where project_id = $1`
+
+	if err := tx.SelectContext(ctx, &names, stmt, pid); err != nil {
+		return fmt.Errorf("error selecting column names :%w", err)
+	}
+	if len(names) != len(order) {
+		return fail.ErrInvalidColumnOrder
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = false
+	}
+	for _, name := range order {
+		if done, ok := seen[name]; !ok || done {
+			return fail.ErrInvalidColumnOrder
+		}
+		seen[name] = true
+	}
 	return nil
 }
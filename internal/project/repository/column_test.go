@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -83,7 +85,7 @@ func TestColumnRepository_Retrieve(t *testing.T) {
 		{
 			name:     "success",
 			columnID: expectedColumn.ID,
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedColumn.TenantID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedColumn.TenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected model.Column, actual model.Column, err error) {
 				assert.Nil(t, err)
 				assert.Equal(t, expected, actual)
@@ -92,7 +94,7 @@ func TestColumnRepository_Retrieve(t *testing.T) {
 		{
 			name:     "column id not UUID",
 			columnID: "mock",
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedColumn.TenantID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedColumn.TenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected model.Column, actual model.Column, err error) {
 				assert.NotNil(t, err)
 				assert.Equal(t, fail.ErrInvalidID, err)
@@ -102,7 +104,7 @@ func TestColumnRepository_Retrieve(t *testing.T) {
 		{
 			name:     "data isolation between tenants",
 			columnID: expectedColumn.ID,
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected model.Column, actual model.Column, err error) {
 				assert.NotNil(t, err)
 				assert.Equal(t, fail.ErrNotFound, err)
@@ -112,7 +114,7 @@ func TestColumnRepository_Retrieve(t *testing.T) {
 		{
 			name:     "not found",
 			columnID: testutils.MockUUID,
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedColumn.TenantID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedColumn.TenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected model.Column, actual model.Column, err error) {
 				assert.NotNil(t, err)
 				assert.Equal(t, fail.ErrNotFound, err)
@@ -171,7 +173,7 @@ func TestColumnRepository_List(t *testing.T) {
 		{
 			name:      "success",
 			projectID: expectedProjectID,
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected []model.Column, actual []model.Column, err error) {
 				assert.Nil(t, err)
 				assert.ElementsMatch(t, expected, actual)
@@ -180,7 +182,7 @@ func TestColumnRepository_List(t *testing.T) {
 		{
 			name:      "project id not UUID",
 			projectID: "mock",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected []model.Column, actual []model.Column, err error) {
 				assert.NotNil(t, err)
 				assert.Equal(t, fail.ErrInvalidID, err)
@@ -190,9 +192,10 @@ func TestColumnRepository_List(t *testing.T) {
 		{
 			name:      "data isolation between tenants",
 			projectID: expectedProjectID,
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, expected []model.Column, actual []model.Column, err error) {
-				assert.Nil(t, err)
+				assert.NotNil(t, err)
+				assert.Equal(t, fail.ErrNotFound, err)
 				assert.Equal(t, 0, len(actual))
 			},
 		},
@@ -241,7 +244,7 @@ func TestColumnRepository_Update(t *testing.T) {
 	}{
 		{
 			name:     "successfully updated column title",
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			columnID: expectedColumn.ID,
 			update: model.UpdateColumn{
 				Title: aws.String("Updated"),
@@ -253,23 +256,9 @@ func TestColumnRepository_Update(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
-		{
-			name:     "successfully updated task ids",
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
-			columnID: expectedColumn.ID,
-			update: model.UpdateColumn{
-				TaskIDS: []string{"Updated", "Updated"},
-			},
-			expectations: func(t *testing.T, ctx context.Context, expected model.Column, actual model.Column, err error) {
-				assert.Nil(t, err)
-				assert.NotEqual(t, expected, actual)
-				expected.TaskIDS = actual.TaskIDS
-				assert.Equal(t, expected, actual)
-			},
-		},
 		{
 			name:     "column id not UUID",
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			columnID: "mock",
 			update:   model.UpdateColumn{},
 			expectations: func(t *testing.T, ctx context.Context, expected model.Column, actual model.Column, err error) {
@@ -324,7 +313,7 @@ func TestColumnRepository_Delete(t *testing.T) {
 	}{
 		{
 			name:     "success",
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testutils.MockUUID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			columnID: expectedColumnID,
 			expectations: func(t *testing.T, ctx context.Context, repo *repository.ColumnRepository, err error) {
 				assert.Nil(t, err)
@@ -335,7 +324,7 @@ func TestColumnRepository_Delete(t *testing.T) {
 		},
 		{
 			name:     "column id not UUID",
-			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testutils.MockUUID}),
+			ctx:      web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			columnID: "mock",
 			expectations: func(t *testing.T, ctx context.Context, repo *repository.ColumnRepository, err error) {
 				assert.NotNil(t, err)
@@ -373,3 +362,123 @@ func TestColumnRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestColumnRepository_Board(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewColumnRepository(zap.NewNop(), db)
+	projectRepo := repository.NewProjectRepository(zap.NewNop(), db)
+	activityRepo := repository.NewActivityRepository(zap.NewNop(), db)
+
+	var added model.Column
+
+	t.Run("add appends to the column order", func(t *testing.T) {
+		var err error
+		added, err = repo.Add(ctx, project.ID, model.AddColumn{Title: "Blocked"}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "column-5", added.ColumnName)
+
+		p, err := projectRepo.Retrieve(ctx, project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, append(project.ColumnOrder, "column-5"), p.ColumnOrder)
+	})
+
+	t.Run("add stops at the column limit", func(t *testing.T) {
+		var last model.Column
+		for i := len(project.ColumnOrder) + 1; i < model.MaxColumns; i++ {
+			var err error
+			last, err = repo.Add(ctx, project.ID, model.AddColumn{Title: "More"}, time.Now())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, "column-10", last.ColumnName)
+
+		_, err := repo.Add(ctx, project.ID, model.AddColumn{Title: "Too many"}, time.Now())
+		assert.Equal(t, fail.ErrColumnLimit, err)
+	})
+
+	t.Run("reorder must list every column once", func(t *testing.T) {
+		err := repo.Reorder(ctx, project.ID, []string{"column-2", "column-1"}, time.Now())
+		assert.Equal(t, fail.ErrInvalidColumnOrder, err)
+	})
+
+	t.Run("remove moves tasks", func(t *testing.T) {
+		taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+		taskIDs := []string{testTasks[0].ID, testTasks[1].ID}
+		for _, tid := range taskIDs {
+			_, err := taskRepo.Move(ctx, tid, model.MoveTask{To: added.ID}, time.Now())
+			require.NoError(t, err)
+		}
+
+		to := testColumns[5]
+		moved, err := repo.Remove(ctx, project.ID, added.ID, to.ID, time.Now())
+		require.NoError(t, err)
+		require.Len(t, moved, len(taskIDs))
+		assert.Equal(t, to.ID, moved[0].ColumnID)
+
+		events, err := activityRepo.ListByTask(ctx, taskIDs[0], model.ActivityPage{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.TaskMoved, events[0].Kind)
+		assert.Equal(t, model.FieldChange{From: added.ID, To: to.ID}, events[0].Changes["columnId"])
+
+		c, err := repo.Retrieve(ctx, to.ID)
+		require.NoError(t, err)
+		assert.Equal(t, taskIDs, c.TaskIDS)
+
+		_, err = repo.Retrieve(ctx, added.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		p, err := projectRepo.Retrieve(ctx, project.ID)
+		require.NoError(t, err)
+		assert.NotContains(t, p.ColumnOrder, added.ColumnName)
+	})
+
+	t.Run("add reuses the names of removed columns", func(t *testing.T) {
+		c, err := repo.Add(ctx, project.ID, model.AddColumn{Title: "Blocked again"}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, added.ColumnName, c.ColumnName)
+	})
+
+	t.Run("remove moves tasks to the trash", func(t *testing.T) {
+		from := testColumns[5]
+		trashed, err := repo.Remove(ctx, project.ID, from.ID, "", time.Now())
+		require.NoError(t, err)
+		assert.Len(t, trashed, 2)
+
+		taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+		_, err = taskRepo.Retrieve(ctx, testTasks[0].ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		events, err := activityRepo.ListByTask(ctx, testTasks[0].ID, model.ActivityPage{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.TaskDeleted, events[0].Kind)
+
+		// Restored tasks go to the first column of the board.
+		task, err := taskRepo.Restore(ctx, testTasks[0].ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, testColumns[4].ID, task.ColumnID)
+	})
+
+	t.Run("remove checks the policies of the target column", func(t *testing.T) {
+		first := testColumns[4]
+		_, err := repo.Update(ctx, first.ID, model.UpdateColumn{WIPLimit: aws.Int(1)}, time.Now())
+		require.NoError(t, err)
+
+		c, err := repo.Add(ctx, project.ID, model.AddColumn{Title: "Overflow"}, time.Now())
+		require.NoError(t, err)
+
+		taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+		_, err = taskRepo.Create(ctx, model.NewTask{Title: "Overflowing"}, project.ID, c.ID, time.Now())
+		require.NoError(t, err)
+
+		_, err = repo.Remove(ctx, project.ID, c.ID, first.ID, time.Now())
+		var pe *fail.PolicyError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, model.PolicyWIPLimit, pe.Violations[0].Policy)
+	})
+}
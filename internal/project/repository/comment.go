@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// CommentRepository manages data access to task comments.
+type CommentRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewCommentRepository returns a new CommentRepository.
+func NewCommentRepository(logger *zap.Logger, pg *db.PostgresDatabase) *CommentRepository {
+	return &CommentRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// Retrieve retrieves a specific comment from the database.
+func (cr *CommentRepository) Retrieve(ctx context.Context, cmid string) (model.Comment, error) {
+	var (
+		c   model.Comment
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return c, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(cmid); err != nil {
+		return c, fail.ErrInvalidID
+	}
+
+	conn, Close, err := cr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return c, err
+	}
+	defer Close()
+
+	stmt := `
+		select
+			comment_id, task_id, tenant_id, content, user_id, likes,
+			exists(select 1 from comment_likes l where l.comment_id = comments.comment_id and l.user_id = $2) as liked,
+			edited, updated_at, created_at
+		from comments
+		where comment_id = $1
+	`
+
+	err = conn.QueryRowxContext(ctx, stmt, cmid, values.UserID).Scan(&c.ID, &c.TaskID, &c.TenantID, &c.Content, &c.UserID, &c.Likes, &c.Liked, &c.Edited, &c.UpdatedAt, &c.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c, fail.ErrNotFound
+		}
+		return c, err
+	}
+
+	if err = authorizeTask(ctx, conn, c.TaskID, values.UserID, model.RoleViewer); err != nil {
+		return model.Comment{}, err
+	}
+
+	c.UpdatedAt = c.UpdatedAt.UTC()
+	c.CreatedAt = c.CreatedAt.UTC()
+
+	return c, nil
+}
+
+// List lists all comments on a task, oldest first.
+func (cr *CommentRepository) List(ctx context.Context, tid string) ([]model.Comment, error) {
+	var (
+		c   model.Comment
+		cs  = make([]model.Comment, 0)
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return cs, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(tid); err != nil {
+		return cs, fail.ErrInvalidID
+	}
+
+	conn, Close, err := cr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return cs, err
+	}
+	defer Close()
+
+	if err = authorizeTask(ctx, conn, tid, values.UserID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	stmt := `
+		select
+			comment_id, task_id, tenant_id, content, user_id, likes,
+			exists(select 1 from comment_likes l where l.comment_id = comments.comment_id and l.user_id = $2) as liked,
+			edited, updated_at, created_at
+		from comments
+		where task_id = $1
+		order by created_at
+	`
+
+	rows, err := conn.QueryxContext(ctx, stmt, tid, values.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(
+			&c.ID,
+			&c.TaskID,
+			&c.TenantID,
+			&c.Content,
+			&c.UserID,
+			&c.Likes,
+			&c.Liked,
+			&c.Edited,
+			&c.UpdatedAt,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+
+		c.UpdatedAt = c.UpdatedAt.UTC()
+		c.CreatedAt = c.CreatedAt.UTC()
+
+		cs = append(cs, c)
+	}
+
+	return cs, rows.Err()
+}
+
+// Create creates a task comment in the database.
+func (cr *CommentRepository) Create(ctx context.Context, nc model.NewComment, tid string, now time.Time) (model.Comment, error) {
+	var (
+		c   model.Comment
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return c, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(values.UserID); err != nil {
+		return c, fail.ErrInvalidID
+	}
+
+	// The task must be visible to the tenant; the foreign key alone is not tenant aware.
+	tr := NewTaskRepository(cr.logger, cr.pg)
+	t, err := tr.Retrieve(db.Primary(ctx), tid)
+	if err != nil {
+		return c, err
+	}
+
+	c = model.Comment{
+		ID:        uuid.New().String(),
+		TaskID:    tid,
+		TenantID:  values.TenantID,
+		Content:   nc.Content,
+		UserID:    values.UserID,
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err = cr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		stmt := `
+			insert into comments (
+				comment_id, task_id, tenant_id, content, likes, user_id, edited, updated_at, created_at
+			) values ($1, $2, $3, $4, 0, $5, false, $6, $7)
+		`
+
+		if _, err := tx.ExecContext(
+			ctx,
+			stmt,
+			c.ID,
+			c.TaskID,
+			c.TenantID,
+			c.Content,
+			c.UserID,
+			c.UpdatedAt,
+			c.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("error inserting comment: %v: %w", nc, err)
+		}
+
+		changes := map[string]model.FieldChange{
+			"commentId": {To: c.ID},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskCommented, changes, now)
+	})
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	return c, nil
+}
+
+// Update updates the content of a comment on a task and likes or unlikes it for the
+// requesting user. Viewers like comments, editing the content takes an editor. A comment
+// on another task is not found.
+func (cr *CommentRepository) Update(ctx context.Context, tid, cmid string, update model.UpdateComment, now time.Time) (model.Comment, error) {
+	var (
+		c   model.Comment
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return c, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(values.UserID); err != nil {
+		return c, fail.ErrInvalidID
+	}
+
+	if c, err = cr.Retrieve(db.Primary(ctx), cmid); err != nil {
+		return c, err
+	}
+	if c.TaskID != tid {
+		return model.Comment{}, fail.ErrNotFound
+	}
+
+	err = cr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if update.Content != nil {
+			if err := authorizeTask(ctx, tx, tid, values.UserID, model.RoleEditor); err != nil {
+				return err
+			}
+			stmt := `update comments set content = $1, edited = true, updated_at = $2 where comment_id = $3`
+			if _, err := tx.ExecContext(ctx, stmt, *update.Content, now.Round(time.Microsecond).UTC(), cmid); err != nil {
+				return fmt.Errorf("error updating comment: %s: %w", cmid, err)
+			}
+		}
+		if update.Liked != nil {
+			return like(ctx, tx, cmid, values.TenantID, values.UserID, *update.Liked, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return c, err
+	}
+
+	return cr.Retrieve(db.Primary(ctx), cmid)
+}
+
+// like records or removes the like of a user. The like count only changes when the
+// user's like actually changed, so repeated requests are harmless.
+func like(ctx context.Context, tx *sqlx.Tx, cmid, tenantID, userID string, liked bool, now time.Time) error {
+	var (
+		res   sql.Result
+		delta int
+		err   error
+	)
+
+	if liked {
+		stmt := `
+			insert into comment_likes (comment_id, tenant_id, user_id, created_at)
+			values ($1, $2, $3, $4)
+			on conflict do nothing
+		`
+		res, err = tx.ExecContext(ctx, stmt, cmid, tenantID, userID, now.UTC())
+		delta = 1
+	} else {
+		stmt := `delete from comment_likes where comment_id = $1 and user_id = $2`
+		res, err = tx.ExecContext(ctx, stmt, cmid, userID)
+		delta = -1
+	}
+	if err != nil {
+		return fmt.Errorf("error updating comment likes: %s: %w", cmid, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return err
+	}
+
+	stmt := `update comments set likes = likes + $1 where comment_id = $2`
+	if _, err = tx.ExecContext(ctx, stmt, delta, cmid); err != nil {
+		return fmt.Errorf("error updating comment likes: %s: %w", cmid, err)
+	}
+	return nil
+}
+
+// Delete deletes a specific comment on a task and its likes from the database.
+func (cr *CommentRepository) Delete(ctx context.Context, tid, cmid string) error {
+	var err error
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(tid); err != nil {
+		return fail.ErrInvalidID
+	}
+	if _, err = uuid.Parse(cmid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	conn, Close, err := cr.pg.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer Close()
+
+	if err = authorizeTask(ctx, conn, tid, values.UserID, model.RoleEditor); err != nil {
+		return err
+	}
+
+	stmt := `delete from comments where comment_id = $1 and task_id = $2`
+
+	res, err := conn.ExecContext(ctx, stmt, cmid, tid)
+	if err != nil {
+		return fmt.Errorf("error deleting comment %s: %w", cmid, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fail.ErrNotFound
+	}
+	db.Wrote(ctx)
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCommentRepository_Create(t *testing.T) {
+	expectedTenantID := testProjects[0].TenantID
+	expectedTask := testTasks[0]
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		taskID       string
+		expectations func(t *testing.T, ctx context.Context, repo *repository.CommentRepository, actual model.Comment, err error)
+	}{
+		{
+			name:   "success",
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
+			taskID: expectedTask.ID,
+			expectations: func(t *testing.T, ctx context.Context, repo *repository.CommentRepository, actual model.Comment, err error) {
+				assert.Nil(t, err)
+				expected, err := repo.Retrieve(ctx, actual.ID)
+				assert.Nil(t, err)
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:   "task id is not UUID",
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
+			taskID: "mock",
+			expectations: func(t *testing.T, ctx context.Context, repo *repository.CommentRepository, actual model.Comment, err error) {
+				assert.Equal(t, fail.ErrInvalidID, err)
+			},
+		},
+		{
+			name:   "task of another tenant",
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID, UserID: expectedTask.UserID}),
+			taskID: expectedTask.ID,
+			expectations: func(t *testing.T, ctx context.Context, repo *repository.CommentRepository, actual model.Comment, err error) {
+				assert.Equal(t, fail.ErrNotFound, err)
+			},
+		},
+		{
+			name:   "context error",
+			ctx:    testutils.MockCtx,
+			taskID: expectedTask.ID,
+			expectations: func(t *testing.T, ctx context.Context, repo *repository.CommentRepository, actual model.Comment, err error) {
+				assert.Equal(t, web.CtxErr(), err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, Close := dbConnect.AsNonRoot()
+			defer Close()
+
+			repo := repository.NewCommentRepository(zap.NewNop(), db)
+			nc := model.NewComment{
+				Content: "Testing",
+			}
+			comment, err := repo.Create(tc.ctx, nc, tc.taskID, time.Now())
+			tc.expectations(t, tc.ctx, repo, comment, err)
+		})
+	}
+}
+
+func TestCommentRepository_Update(t *testing.T) {
+	expectedTenantID := testProjects[0].TenantID
+	expectedTask := testTasks[0]
+
+	author := web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID})
+	reader := web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testutils.MockUUID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewCommentRepository(zap.NewNop(), db)
+	memberRepo := repository.NewMemberRepository(zap.NewNop(), db)
+
+	_, err := memberRepo.Set(author, expectedTask.ProjectID, testutils.MockUUID, model.RoleViewer, time.Now())
+	require.NoError(t, err)
+
+	comment, err := repo.Create(author, model.NewComment{Content: "Testing"}, expectedTask.ID, time.Now())
+	require.NoError(t, err)
+
+	t.Run("viewers do not edit", func(t *testing.T) {
+		_, err := repo.Update(reader, expectedTask.ID, comment.ID, model.UpdateComment{Content: aws.String("Mine")}, time.Now())
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		actual, err := repo.Update(author, expectedTask.ID, comment.ID, model.UpdateComment{Content: aws.String("Edited")}, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, "Edited", actual.Content)
+		assert.True(t, actual.Edited)
+	})
+
+	t.Run("like is counted once", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			actual, err := repo.Update(reader, expectedTask.ID, comment.ID, model.UpdateComment{Liked: aws.Bool(true)}, time.Now())
+			assert.Nil(t, err)
+			assert.Equal(t, 1, actual.Likes)
+			assert.True(t, actual.Liked)
+		}
+
+		actual, err := repo.Retrieve(author, comment.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, actual.Likes)
+		assert.False(t, actual.Liked)
+	})
+
+	t.Run("unlike", func(t *testing.T) {
+		actual, err := repo.Update(reader, expectedTask.ID, comment.ID, model.UpdateComment{Liked: aws.Bool(false)}, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, actual.Likes)
+		assert.False(t, actual.Liked)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.Update(author, expectedTask.ID, testutils.MockUUID, model.UpdateComment{Liked: aws.Bool(true)}, time.Now())
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("comment on another task", func(t *testing.T) {
+		_, err := repo.Update(author, testTasks[1].ID, comment.ID, model.UpdateComment{Content: aws.String("Moved")}, time.Now())
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		actual, err := repo.Retrieve(author, comment.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Edited", actual.Content)
+	})
+}
+
+func TestCommentRepository_Delete(t *testing.T) {
+	expectedTenantID := testProjects[0].TenantID
+	expectedTask := testTasks[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewCommentRepository(zap.NewNop(), db)
+
+	comment, err := repo.Create(ctx, model.NewComment{Content: "Testing"}, expectedTask.ID, time.Now())
+	require.NoError(t, err)
+
+	t.Run("comment on another task", func(t *testing.T) {
+		err := repo.Delete(ctx, testTasks[1].ID, comment.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = repo.Retrieve(ctx, comment.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		assert.Nil(t, repo.Delete(ctx, expectedTask.ID, comment.ID))
+
+		_, err := repo.Retrieve(ctx, comment.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
+
+func TestCommentRepository_TaskDeleteCascades(t *testing.T) {
+	expectedTenantID := testProjects[0].TenantID
+	expectedTask := testTasks[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewCommentRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	comment, err := repo.Create(ctx, model.NewComment{Content: "Testing"}, expectedTask.ID, time.Now())
+	require.NoError(t, err)
+
+	task, err := taskRepo.Retrieve(ctx, expectedTask.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.CommentCount)
+
+	require.NoError(t, taskRepo.Delete(ctx, expectedTask.ID, time.Now()))
+
+	_, err = repo.Retrieve(ctx, comment.ID)
+	assert.Equal(t, fail.ErrNotFound, err)
+}
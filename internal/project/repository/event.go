@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// ActivityRepository manages data access to the task event history.
+type ActivityRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewActivityRepository returns a new ActivityRepository.
+func NewActivityRepository(logger *zap.Logger, pg *db.PostgresDatabase) *ActivityRepository {
+	return &ActivityRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// ListByTask lists the events of a task, newest first.
+func (ar *ActivityRepository) ListByTask(ctx context.Context, tid string, page model.ActivityPage) ([]model.TaskEvent, error) {
+	if _, err := uuid.Parse(tid); err != nil {
+		return nil, fail.ErrInvalidID
+	}
+	return ar.list(ctx, "task_id", tid, page)
+}
+
+// ListByProject lists the events of every task in a project, newest first.
+func (ar *ActivityRepository) ListByProject(ctx context.Context, pid string, page model.ActivityPage) ([]model.TaskEvent, error) {
+	if _, err := uuid.Parse(pid); err != nil {
+		return nil, fail.ErrInvalidID
+	}
+	return ar.list(ctx, "project_id", pid, page)
+}
+
+func (ar *ActivityRepository) list(ctx context.Context, column string, id string, page model.ActivityPage) ([]model.TaskEvent, error) {
+	var (
+		e   model.TaskEvent
+		es  = make([]model.TaskEvent, 0)
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return es, web.CtxErr()
+	}
+
+	conn, Close, err := ar.pg.GetReadConnection(ctx)
+	if err != nil {
+		return es, err
+	}
+	defer Close()
+
+	check := authorize
+	if column == "task_id" {
+		check = authorizeTask
+	}
+	if err = check(ctx, conn, id, values.UserID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	stmt := selectEvent + fmt.Sprintf(`
+		where %s = $1
+		order by created_at desc, event_id
+		limit $2 offset $3
+	`, column)
+
+	rows, err := conn.QueryxContext(ctx, stmt, id, page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting task events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if e, err = scanEvent(rows); err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+
+	return es, rows.Err()
+}
+
+// selectEvent selects the columns read by scanEvent.
+const selectEvent = `
+	select event_id, tenant_id, project_id, task_id, user_id, kind, changes, created_at
+	from task_events
+`
+
+// scanEvent scans a task event along with its changes.
+func scanEvent(row interface{ Scan(...interface{}) error }) (model.TaskEvent, error) {
+	var (
+		e       model.TaskEvent
+		changes []byte
+	)
+
+	err := row.Scan(&e.ID, &e.TenantID, &e.ProjectID, &e.TaskID, &e.UserID, &e.Kind, &changes, &e.CreatedAt)
+	if err != nil {
+		return e, fmt.Errorf("error scanning row into struct: %w", err)
+	}
+
+	if err = json.Unmarshal(changes, &e.Changes); err != nil {
+		return e, fmt.Errorf("error decoding task event changes: %w", err)
+	}
+
+	e.CreatedAt = e.CreatedAt.UTC()
+	return e, nil
+}
+
+// recordEvent writes a task event in the transaction of the change it describes, so the
+// history never disagrees with the task.
+func recordEvent(ctx context.Context, tx *sqlx.Tx, t model.Task, userID string, kind string, changes map[string]model.FieldChange, now time.Time) error {
+	if changes == nil {
+		changes = make(map[string]model.FieldChange)
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	stmt := `
+		insert into task_events (
+			event_id, tenant_id, project_id, task_id, user_id, kind, changes, created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if _, err = tx.ExecContext(
+		ctx,
+		stmt,
+		uuid.New().String(),
+		t.TenantID,
+		t.ProjectID,
+		t.ID,
+		userID,
+		kind,
+		b,
+		now.Round(time.Microsecond).UTC(),
+	); err != nil {
+		return fmt.Errorf("error inserting task event: %s: %w", kind, err)
+	}
+	return nil
+}
+
+// created returns the changes of a task being created: its title and column, along with
+// its content and assignee when it has them.
+func created(t model.Task) map[string]model.FieldChange {
+	changes := map[string]model.FieldChange{
+		"title":    {To: t.Title},
+		"columnId": {To: t.ColumnID},
+	}
+	if t.Content != "" {
+		changes["content"] = model.FieldChange{To: t.Content}
+	}
+	if t.AssignedTo != "" {
+		changes["assignedTo"] = model.FieldChange{To: t.AssignedTo}
+	}
+	return changes
+}
+
+// diff returns the fields that differ between two versions of a task.
+func diff(before, after model.Task) map[string]model.FieldChange {
+	changes := make(map[string]model.FieldChange)
+
+	add := func(field string, from, to interface{}) {
+		changes[field] = model.FieldChange{From: from, To: to}
+	}
+
+	if before.Title != after.Title {
+		add("title", before.Title, after.Title)
+	}
+	if before.Content != after.Content {
+		add("content", before.Content, after.Content)
+	}
+	if before.Points != after.Points {
+		add("points", before.Points, after.Points)
+	}
+	if before.AssignedTo != after.AssignedTo {
+		add("assignedTo", before.AssignedTo, after.AssignedTo)
+	}
+	if before.Priority != after.Priority {
+		add("priority", before.Priority, after.Priority)
+	}
+	if !equalTimes(before.StartAt, after.StartAt) {
+		add("startAt", before.StartAt, after.StartAt)
+	}
+	if !equalTimes(before.DueAt, after.DueAt) {
+		add("dueAt", before.DueAt, after.DueAt)
+	}
+	if before.OriginalEstimate != after.OriginalEstimate {
+		add("originalEstimate", before.OriginalEstimate, after.OriginalEstimate)
+	}
+	if before.RemainingEstimate != after.RemainingEstimate {
+		add("remainingEstimate", before.RemainingEstimate, after.RemainingEstimate)
+	}
+	if !slices.Equal(before.Attachments, after.Attachments) {
+		add("attachments", before.Attachments, after.Attachments)
+	}
+	if from, to := labelNames(before.Labels), labelNames(after.Labels); !slices.Equal(from, to) {
+		add("labels", from, to)
+	}
+	if !equalFields(before.Fields, after.Fields) {
+		add("fields", before.Fields, after.Fields)
+	}
+	if before.ColumnID != after.ColumnID {
+		add("columnId", before.ColumnID, after.ColumnID)
+	}
+
+	return changes
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// equalFields reports whether two tasks have the same custom field values. Values are
+// compared as read back from the database, so equal values have equal encodings.
+func equalFields(a, b model.FieldValues) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, v := range a {
+		if w, ok := b[id]; !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func labelNames(ls []model.TaskLabel) []string {
+	names := make([]string, 0, len(ls))
+	for _, l := range ls {
+		names = append(names, l.Name)
+	}
+	return names
+}
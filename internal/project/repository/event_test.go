@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestActivityRepository_History(t *testing.T) {
+	project := testProjects[1]
+	task := testTasks[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	page := model.ActivityPage{Limit: 10}
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewActivityRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	commentRepo := repository.NewCommentRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	_, err := taskRepo.Update(ctx, task.ID, model.UpdateTask{Points: aws.Int(5), AssignedTo: aws.String(testutils.MockUUID)}, now)
+	require.NoError(t, err)
+
+	// An update that changes nothing leaves no trace.
+	_, err = taskRepo.Update(ctx, task.ID, model.UpdateTask{Points: aws.Int(5)}, now.Add(time.Second))
+	require.NoError(t, err)
+
+	_, err = commentRepo.Create(ctx, model.NewComment{Content: "Testing"}, task.ID, now.Add(2*time.Second))
+	require.NoError(t, err)
+
+	require.NoError(t, taskRepo.Delete(ctx, task.ID, now.Add(3*time.Second)))
+
+	t.Run("task history is newest first", func(t *testing.T) {
+		actual, err := repo.ListByTask(ctx, task.ID, page)
+		assert.Nil(t, err)
+		require.Len(t, actual, 3)
+
+		assert.Equal(t, model.TaskDeleted, actual[0].Kind)
+		assert.Equal(t, task.Title, actual[0].Changes["title"].From)
+
+		assert.Equal(t, model.TaskCommented, actual[1].Kind)
+
+		assert.Equal(t, model.TaskUpdated, actual[2].Kind)
+		assert.Equal(t, project.UserID, actual[2].UserID)
+		assert.Equal(t, map[string]model.FieldChange{
+			"points":     {From: float64(task.Points), To: float64(5)},
+			"assignedTo": {From: task.AssignedTo, To: testutils.MockUUID},
+		}, actual[2].Changes)
+	})
+
+	t.Run("project feed is paginated", func(t *testing.T) {
+		actual, err := repo.ListByProject(ctx, project.ID, model.ActivityPage{Limit: 2, Offset: 1})
+		assert.Nil(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, model.TaskCommented, actual[0].Kind)
+		assert.Equal(t, model.TaskUpdated, actual[1].Kind)
+	})
+
+	t.Run("other tenants see nothing", func(t *testing.T) {
+		other := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID})
+		actual, err := repo.ListByTask(other, task.ID, page)
+		assert.Nil(t, err)
+		assert.Len(t, actual, 0)
+	})
+
+	t.Run("task id is not UUID", func(t *testing.T) {
+		_, err := repo.ListByTask(ctx, "mock", page)
+		assert.Equal(t, fail.ErrInvalidID, err)
+	})
+}
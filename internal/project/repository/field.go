@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// FieldRepository manages data access to project custom fields.
+type FieldRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewFieldRepository returns a new FieldRepository.
+func NewFieldRepository(logger *zap.Logger, pg *db.PostgresDatabase) *FieldRepository {
+	return &FieldRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectField = `
+	select field_id, tenant_id, project_id, name, kind, options, updated_at, created_at
+	from custom_fields
+`
+
+// fieldValue selects the value v of a field f as json of the type of the field.
+const fieldValue = `
+	case f.kind
+		when 'number' then to_json(v.number_value)
+		when 'date' then to_json(v.date_value)
+		when 'select' then to_json(v.options[1])
+		when 'multi_select' then to_json(v.options)
+		else to_json(v.text_value)
+	end
+`
+
+// taskFieldValues selects the custom field values of a task as a json object keyed by
+// field id.
+const taskFieldValues = `
+	coalesce((
+		select json_object_agg(v.field_id, ` + fieldValue + `)
+		from task_field_values v join custom_fields f on f.field_id = v.field_id
+		where v.task_id = tasks.task_id
+	), '{}')
+`
+
+func scanField(row interface{ Scan(...interface{}) error }) (model.CustomField, error) {
+	var f model.CustomField
+
+	err := row.Scan(&f.ID, &f.TenantID, &f.ProjectID, &f.Name, &f.Kind, (*pq.StringArray)(&f.Options), &f.UpdatedAt, &f.CreatedAt)
+	if err != nil {
+		return f, err
+	}
+
+	f.UpdatedAt = f.UpdatedAt.UTC()
+	f.CreatedAt = f.CreatedAt.UTC()
+
+	return f, nil
+}
+
+// List lists the custom fields of a project the user can see by name.
+func (fr *FieldRepository) List(ctx context.Context, pid string) ([]model.CustomField, error) {
+	var fs = make([]model.CustomField, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return fs, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return fs, fail.ErrInvalidID
+	}
+
+	conn, Close, err := fr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return fs, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleViewer); err != nil {
+		return fs, err
+	}
+
+	rows, err := conn.QueryxContext(ctx, selectField+` where project_id = $1 order by lower(name)`, pid)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		f, err := scanField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		fs = append(fs, f)
+	}
+
+	return fs, rows.Err()
+}
+
+// Create creates a custom field of a project owned by the user.
+func (fr *FieldRepository) Create(ctx context.Context, pid string, nf model.NewCustomField, now time.Time) (model.CustomField, error) {
+	var f model.CustomField
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return f, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return f, fail.ErrInvalidID
+	}
+
+	f = model.CustomField{
+		ID:        uuid.New().String(),
+		TenantID:  values.TenantID,
+		ProjectID: pid,
+		Name:      nf.Name,
+		Kind:      nf.Kind,
+		Options:   append(make([]string, 0, len(nf.Options)), nf.Options...),
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err := fr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		// Lock the project so concurrent creations cannot exceed the limit.
+		if _, err := tx.ExecContext(ctx, `select 1 from projects where project_id = $1 for update`, pid); err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowxContext(ctx, `select count(*) from custom_fields where project_id = $1`, pid).Scan(&count); err != nil {
+			return err
+		}
+		if count >= model.MaxCustomFields {
+			return fail.ErrFieldLimit
+		}
+
+		stmt := `
+			insert into custom_fields (field_id, tenant_id, project_id, name, kind, options, updated_at, created_at)
+			values ($1, $2, $3, $4, $5, $6, $7, $8)
+		`
+		_, err := tx.ExecContext(ctx, stmt, f.ID, f.TenantID, f.ProjectID, f.Name, f.Kind, pq.Array(f.Options), f.UpdatedAt, f.CreatedAt)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrDuplicateField
+			}
+			return fmt.Errorf("error inserting custom field: %s: %w", nf.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CustomField{}, err
+	}
+
+	return f, nil
+}
+
+// Update updates a custom field of a project owned by the user. Values using options that
+// are removed lose them, and select values are cleared.
+func (fr *FieldRepository) Update(ctx context.Context, pid string, cfid string, update model.UpdateCustomField, now time.Time) (model.CustomField, error) {
+	var f model.CustomField
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return f, web.CtxErr()
+	}
+
+	for _, id := range []string{pid, cfid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return f, fail.ErrInvalidID
+		}
+	}
+
+	err := fr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		var err error
+		f, err = scanField(tx.QueryRowxContext(ctx, selectField+` where field_id = $1 and project_id = $2 for update`, cfid, pid))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return err
+		}
+
+		if update.Name != nil {
+			f.Name = *update.Name
+		}
+		if update.Options != nil {
+			if !model.HasOptions(f.Kind) {
+				return fail.ErrFieldOptions
+			}
+			f.Options = update.Options
+		}
+		f.UpdatedAt = now.Round(time.Microsecond).UTC()
+
+		stmt := `update custom_fields set name = $1, options = $2, updated_at = $3 where field_id = $4`
+		if _, err = tx.ExecContext(ctx, stmt, f.Name, pq.Array(f.Options), f.UpdatedAt, cfid); err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrDuplicateField
+			}
+			return fmt.Errorf("error updating custom field: %s: %w", cfid, err)
+		}
+
+		if update.Options == nil {
+			return nil
+		}
+
+		stmt = `
+			update task_field_values
+			set options = array(select o from unnest(options) o where o = any($2::text[]))
+			where field_id = $1 and not options <@ $2::text[]
+		`
+		if _, err = tx.ExecContext(ctx, stmt, cfid, pq.Array(f.Options)); err != nil {
+			return fmt.Errorf("error removing custom field options: %s: %w", cfid, err)
+		}
+
+		stmt = `delete from task_field_values where field_id = $1 and cardinality(options) = 0`
+		if _, err = tx.ExecContext(ctx, stmt, cfid); err != nil {
+			return fmt.Errorf("error removing custom field values: %s: %w", cfid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CustomField{}, err
+	}
+
+	return f, nil
+}
+
+// Delete deletes a custom field of a project owned by the user, along with its values.
+func (fr *FieldRepository) Delete(ctx context.Context, pid string, cfid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	for _, id := range []string{pid, cfid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return fail.ErrInvalidID
+		}
+	}
+
+	return fr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `delete from custom_fields where field_id = $1 and project_id = $2`, cfid, pid)
+		if err != nil {
+			return fmt.Errorf("error deleting custom field %s: %w", cfid, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fail.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// jsonFields scans the json object of custom field values aggregated in task selects.
+type jsonFields model.FieldValues
+
+// Scan implements the sql.Scanner interface.
+func (jf *jsonFields) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*jf = make(jsonFields)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into json fields", src)
+	}
+
+	fields := make(jsonFields)
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	*jf = fields
+	return nil
+}
+
+// fieldsOf returns the custom field values of a task.
+func fieldsOf(ctx context.Context, tx *sqlx.Tx, tid string) (model.FieldValues, error) {
+	var fv model.FieldValues
+
+	stmt := `select ` + taskFieldValues + ` from tasks where task_id = $1`
+	if err := tx.QueryRowxContext(ctx, stmt, tid).Scan((*jsonFields)(&fv)); err != nil {
+		return nil, fmt.Errorf("error selecting custom field values: %w", err)
+	}
+
+	return fv, nil
+}
+
+// setFields sets the custom field values of a task, clearing those given null. Every field
+// must belong to the project of the task and every value must suit its field.
+func setFields(ctx context.Context, tx *sqlx.Tx, t model.Task, values model.FieldValues) (model.FieldValues, error) {
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+
+	rows, err := tx.QueryxContext(ctx, selectField+` where project_id = $1 and field_id = any($2)`, t.ProjectID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error selecting custom fields: %w", err)
+	}
+	fields := make(map[string]model.CustomField, len(ids))
+	for rows.Next() {
+		f, err := scanField(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		fields[f.ID] = f
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for id, raw := range values {
+		f, ok := fields[id]
+		if !ok {
+			return nil, fail.ErrInvalidFieldValue
+		}
+
+		if model.IsNull(raw) {
+			stmt := `delete from task_field_values where task_id = $1 and field_id = $2`
+			if _, err = tx.ExecContext(ctx, stmt, t.ID, id); err != nil {
+				return nil, fmt.Errorf("error clearing custom field value: %s: %w", id, err)
+			}
+			continue
+		}
+
+		v, ok := f.ParseValue(raw)
+		if !ok {
+			return nil, fail.ErrInvalidFieldValue
+		}
+
+		stmt := `
+			insert into task_field_values (task_id, field_id, tenant_id, text_value, number_value, date_value, options)
+			values ($1, $2, $3, $4, $5, $6, $7)
+			on conflict (task_id, field_id) do update set
+				text_value = excluded.text_value,
+				number_value = excluded.number_value,
+				date_value = excluded.date_value,
+				options = excluded.options
+		`
+		if _, err = tx.ExecContext(ctx, stmt, t.ID, id, f.TenantID, v.Text, v.Number, v.Date, pq.Array(v.Options)); err != nil {
+			return nil, fmt.Errorf("error setting custom field value: %s: %w", id, err)
+		}
+	}
+
+	return fieldsOf(ctx, tx, t.ID)
+}
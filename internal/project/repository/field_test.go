@@ -0,0 +1,156 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFieldRepository(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	task := testTasks[0]
+
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	editorID := uuid.New().String()
+	editor := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: editorID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewFieldRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	memberRepo := repository.NewMemberRepository(zap.NewNop(), db)
+	transferRepo := repository.NewTransferRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	_, err := memberRepo.Set(owner, project.ID, editorID, model.RoleEditor, now)
+	require.NoError(t, err)
+
+	var estimate, tags model.CustomField
+
+	t.Run("only owners define fields", func(t *testing.T) {
+		_, err := repo.Create(editor, project.ID, model.NewCustomField{Name: "Estimate", Kind: model.FieldNumber}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		estimate, err = repo.Create(owner, project.ID, model.NewCustomField{Name: "Estimate", Kind: model.FieldNumber}, now)
+		require.NoError(t, err)
+		assert.Empty(t, estimate.Options)
+
+		tags, err = repo.Create(owner, project.ID, model.NewCustomField{Name: "Tags", Kind: model.FieldMultiSelect, Options: []string{"web", "ios", "android"}}, now)
+		require.NoError(t, err)
+
+		_, err = repo.Create(owner, project.ID, model.NewCustomField{Name: "estimate", Kind: model.FieldText}, now)
+		assert.Equal(t, fail.ErrDuplicateField, err)
+
+		list, err := repo.List(editor, project.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "Estimate", list[0].Name)
+		assert.Equal(t, []string{"web", "ios", "android"}, list[1].Options)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		update := model.UpdateTask{Fields: model.FieldValues{
+			estimate.ID: json.RawMessage(`2.5`),
+			tags.ID:     json.RawMessage(`["web", "ios"]`),
+		}}
+		updated, err := taskRepo.Update(editor, task.ID, update, now)
+		require.NoError(t, err)
+		assert.JSONEq(t, `2.5`, string(updated.Fields[estimate.ID]))
+		assert.JSONEq(t, `["web", "ios"]`, string(updated.Fields[tags.ID]))
+
+		_, err = taskRepo.Update(editor, task.ID, model.UpdateTask{Fields: model.FieldValues{estimate.ID: json.RawMessage(`"soon"`)}}, now)
+		assert.Equal(t, fail.ErrInvalidFieldValue, err)
+
+		_, err = taskRepo.Update(editor, task.ID, model.UpdateTask{Fields: model.FieldValues{tags.ID: json.RawMessage(`["desktop"]`)}}, now)
+		assert.Equal(t, fail.ErrInvalidFieldValue, err)
+
+		_, err = taskRepo.Update(editor, task.ID, model.UpdateTask{Fields: model.FieldValues{uuid.New().String(): json.RawMessage(`1`)}}, now)
+		assert.Equal(t, fail.ErrInvalidFieldValue, err)
+
+		retrieved, err := taskRepo.Retrieve(owner, task.ID)
+		require.NoError(t, err)
+		assert.Len(t, retrieved.Fields, 2)
+	})
+
+	t.Run("list filter", func(t *testing.T) {
+		list, err := taskRepo.List(owner, project.ID, model.TaskFilter{Fields: map[string]string{tags.ID: "ios"}})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, task.ID, list[0].ID)
+
+		list, err = taskRepo.List(owner, project.ID, model.TaskFilter{Fields: map[string]string{estimate.ID: "2.5", tags.ID: "android"}})
+		require.NoError(t, err)
+		assert.Empty(t, list)
+
+		_, err = taskRepo.List(owner, project.ID, model.TaskFilter{Fields: map[string]string{uuid.New().String(): "ios"}})
+		assert.Equal(t, fail.ErrInvalidFilter, err)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		b, err := transferRepo.Export(owner, project.ID, now)
+		require.NoError(t, err)
+		require.Len(t, b.Fields, 2)
+
+		for _, bt := range b.Tasks {
+			if bt.Title == task.Title {
+				assert.JSONEq(t, `["web", "ios"]`, string(bt.Fields["Tags"]))
+			}
+		}
+	})
+
+	t.Run("removed options leave values", func(t *testing.T) {
+		_, err := repo.Update(owner, project.ID, estimate.ID, model.UpdateCustomField{Options: []string{"small"}}, now)
+		assert.Equal(t, fail.ErrFieldOptions, err)
+
+		f, err := repo.Update(owner, project.ID, tags.ID, model.UpdateCustomField{Name: aws.String("Platforms"), Options: []string{"ios", "android"}}, now)
+		require.NoError(t, err)
+		assert.Equal(t, "Platforms", f.Name)
+
+		retrieved, err := taskRepo.Retrieve(owner, task.ID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["ios"]`, string(retrieved.Fields[tags.ID]))
+
+		_, err = repo.Update(owner, project.ID, tags.ID, model.UpdateCustomField{Options: []string{"android"}}, now)
+		require.NoError(t, err)
+
+		retrieved, err = taskRepo.Retrieve(owner, task.ID)
+		require.NoError(t, err)
+		assert.NotContains(t, retrieved.Fields, tags.ID)
+	})
+
+	t.Run("null clears a value", func(t *testing.T) {
+		updated, err := taskRepo.Update(editor, task.ID, model.UpdateTask{Fields: model.FieldValues{estimate.ID: json.RawMessage(`null`)}}, now)
+		require.NoError(t, err)
+		assert.Empty(t, updated.Fields)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := taskRepo.Update(editor, task.ID, model.UpdateTask{Fields: model.FieldValues{estimate.ID: json.RawMessage(`3`)}}, now)
+		require.NoError(t, err)
+
+		err = repo.Delete(editor, project.ID, estimate.ID)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		require.NoError(t, repo.Delete(owner, project.ID, estimate.ID))
+		assert.Equal(t, fail.ErrNotFound, repo.Delete(owner, project.ID, estimate.ID))
+
+		retrieved, err := taskRepo.Retrieve(owner, task.ID)
+		require.NoError(t, err)
+		assert.Empty(t, retrieved.Fields)
+	})
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// FilterRepository manages data access to saved task filters.
+type FilterRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewFilterRepository returns a new FilterRepository.
+func NewFilterRepository(logger *zap.Logger, pg *db.PostgresDatabase) *FilterRepository {
+	return &FilterRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectFilter = `
+	select filter_id, tenant_id, coalesce(project_id, '') as project_id, name, query, shared, user_id, updated_at, created_at
+	from saved_filters
+`
+
+// visibleFilter restricts filter queries to the filters of the user bound to the given
+// parameter and the shared filters, leaving out those of projects the user cannot see.
+func visibleFilter(user int) string {
+	return fmt.Sprintf(`
+	(user_id = $%[1]d or shared)
+	and (project_id is null or exists(
+		select 1 from projects p
+		where p.project_id = saved_filters.project_id and p.deleted_at is null and project_role(p.project_id, $%[1]d) is not null
+	))
+`, user)
+}
+
+func utcFilter(f model.SavedFilter) model.SavedFilter {
+	f.UpdatedAt = f.UpdatedAt.UTC()
+	f.CreatedAt = f.CreatedAt.UTC()
+	return f
+}
+
+// Retrieve retrieves a saved filter the user can see.
+func (fr *FilterRepository) Retrieve(ctx context.Context, fid string) (model.SavedFilter, error) {
+	var f model.SavedFilter
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return f, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(fid); err != nil {
+		return f, fail.ErrInvalidID
+	}
+
+	conn, Close, err := fr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return f, err
+	}
+	defer Close()
+
+	stmt := selectFilter + ` where filter_id = $1 and ` + visibleFilter(2)
+	if err = conn.QueryRowxContext(ctx, stmt, fid, values.UserID).StructScan(&f); err != nil {
+		if err == sql.ErrNoRows {
+			return f, fail.ErrNotFound
+		}
+		return f, err
+	}
+
+	return utcFilter(f), nil
+}
+
+// List lists the saved filters the user can see by name. Given a project, it lists only
+// the filters of the project.
+func (fr *FilterRepository) List(ctx context.Context, pid string) ([]model.SavedFilter, error) {
+	var list = make([]model.SavedFilter, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return list, web.CtxErr()
+	}
+
+	if pid != "" {
+		if _, err := uuid.Parse(pid); err != nil {
+			return list, fail.ErrInvalidID
+		}
+	}
+
+	conn, Close, err := fr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return list, err
+	}
+	defer Close()
+
+	var fs []model.SavedFilter
+	stmt := selectFilter + ` where ($2 = '' or project_id = $2) and ` + visibleFilter(1) + ` order by lower(name)`
+	if err = conn.SelectContext(ctx, &fs, stmt, values.UserID, pid); err != nil {
+		return list, fmt.Errorf("error selecting filters: %w", err)
+	}
+
+	for _, f := range fs {
+		list = append(list, utcFilter(f))
+	}
+	return list, nil
+}
+
+// Create creates a saved filter of the user. A filter of a project needs a project the
+// user can see.
+func (fr *FilterRepository) Create(ctx context.Context, nf model.NewSavedFilter, now time.Time) (model.SavedFilter, error) {
+	var f model.SavedFilter
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return f, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return f, fail.ErrInvalidID
+	}
+
+	f = model.SavedFilter{
+		ID:        uuid.New().String(),
+		TenantID:  values.TenantID,
+		ProjectID: nf.ProjectID,
+		Name:      nf.Name,
+		Query:     nf.Query,
+		Shared:    nf.Shared,
+		UserID:    values.UserID,
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err := fr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var pid *string
+		if f.ProjectID != "" {
+			if err := authorize(ctx, tx, f.ProjectID, values.UserID, model.RoleViewer); err != nil {
+				return err
+			}
+			pid = &f.ProjectID
+		}
+
+		stmt := `
+			insert into saved_filters (filter_id, tenant_id, project_id, name, query, shared, user_id, updated_at, created_at)
+			values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+		_, err := tx.ExecContext(ctx, stmt, f.ID, f.TenantID, pid, f.Name, f.Query, f.Shared, f.UserID, f.UpdatedAt, f.CreatedAt)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrDuplicateFilter
+			}
+			return fmt.Errorf("error inserting filter: %s: %w", nf.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.SavedFilter{}, err
+	}
+
+	return f, nil
+}
+
+// Update updates a saved filter. Only its creator can change it.
+func (fr *FilterRepository) Update(ctx context.Context, fid string, update model.UpdateSavedFilter, now time.Time) (model.SavedFilter, error) {
+	var f model.SavedFilter
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return f, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(fid); err != nil {
+		return f, fail.ErrInvalidID
+	}
+
+	err := fr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		if f, err = lockFilter(ctx, tx, fid, values.UserID); err != nil {
+			return err
+		}
+
+		if update.Name != nil {
+			f.Name = *update.Name
+		}
+		if update.Query != nil {
+			f.Query = *update.Query
+		}
+		if update.Shared != nil {
+			f.Shared = *update.Shared
+		}
+		f.UpdatedAt = now.Round(time.Microsecond).UTC()
+
+		stmt := `update saved_filters set name = $1, query = $2, shared = $3, updated_at = $4 where filter_id = $5`
+		if _, err = tx.ExecContext(ctx, stmt, f.Name, f.Query, f.Shared, f.UpdatedAt, fid); err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrDuplicateFilter
+			}
+			return fmt.Errorf("error updating filter %s: %w", fid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.SavedFilter{}, err
+	}
+
+	return utcFilter(f), nil
+}
+
+// Delete deletes a saved filter. Only its creator can delete it.
+func (fr *FilterRepository) Delete(ctx context.Context, fid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(fid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	return fr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := lockFilter(ctx, tx, fid, values.UserID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `delete from saved_filters where filter_id = $1`, fid); err != nil {
+			return fmt.Errorf("error deleting filter %s: %w", fid, err)
+		}
+		return nil
+	})
+}
+
+// lockFilter locks a saved filter the user can see for a change, which only its creator
+// is authorized to make.
+func lockFilter(ctx context.Context, tx *sqlx.Tx, fid string, userID string) (model.SavedFilter, error) {
+	var f model.SavedFilter
+
+	stmt := selectFilter + ` where filter_id = $1 and ` + visibleFilter(2) + ` for update`
+	if err := tx.QueryRowxContext(ctx, stmt, fid, userID).StructScan(&f); err != nil {
+		if err == sql.ErrNoRows {
+			return f, fail.ErrNotFound
+		}
+		return f, err
+	}
+	if f.UserID != userID {
+		return f, fail.ErrNotAuthorized
+	}
+
+	return f, nil
+}
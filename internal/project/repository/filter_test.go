@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFilterRepository(t *testing.T) {
+	project := testProjects[1]
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	user := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: uuid.New().String()})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewFilterRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	var private, shared, board model.SavedFilter
+
+	t.Run("create", func(t *testing.T) {
+		var err error
+
+		private, err = repo.Create(owner, model.NewSavedFilter{Name: "Mine", Query: "assignee = me"}, now)
+		require.NoError(t, err)
+		assert.Empty(t, private.ProjectID)
+
+		shared, err = repo.Create(owner, model.NewSavedFilter{Name: "Bugs", Query: "label in (bug)", Shared: true}, now)
+		require.NoError(t, err)
+
+		board, err = repo.Create(owner, model.NewSavedFilter{Name: "Board", ProjectID: project.ID, Shared: true}, now)
+		require.NoError(t, err)
+		assert.Equal(t, project.ID, board.ProjectID)
+
+		_, err = repo.Create(owner, model.NewSavedFilter{Name: "mine"}, now)
+		assert.Equal(t, fail.ErrDuplicateFilter, err)
+
+		_, err = repo.Create(user, model.NewSavedFilter{Name: "Board", ProjectID: project.ID}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("visibility", func(t *testing.T) {
+		list, err := repo.List(owner, "")
+		require.NoError(t, err)
+		assert.Len(t, list, 3)
+
+		list, err = repo.List(owner, project.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, board.ID, list[0].ID)
+
+		// Other users see the shared filters of projects they can see.
+		list, err = repo.List(user, "")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, shared.ID, list[0].ID)
+
+		_, err = repo.Retrieve(user, private.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("only creators change filters", func(t *testing.T) {
+		_, err := repo.Update(user, shared.ID, model.UpdateSavedFilter{Name: aws.String("Renamed")}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		f, err := repo.Update(owner, shared.ID, model.UpdateSavedFilter{Query: aws.String("label in (bug, ui)"), Shared: aws.Bool(false)}, now)
+		require.NoError(t, err)
+		assert.Equal(t, "label in (bug, ui)", f.Query)
+		assert.False(t, f.Shared)
+
+		_, err = repo.Retrieve(user, shared.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		assert.Equal(t, fail.ErrNotFound, repo.Delete(user, private.ID))
+		assert.Nil(t, repo.Delete(owner, private.ID))
+
+		_, err = repo.Retrieve(owner, private.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// uniqueViolation is the Postgres error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// LabelRepository manages data access to project labels.
+type LabelRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewLabelRepository returns a new LabelRepository.
+func NewLabelRepository(logger *zap.Logger, pg *db.PostgresDatabase) *LabelRepository {
+	return &LabelRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// Retrieve retrieves a specific project label from the database.
+func (lr *LabelRepository) Retrieve(ctx context.Context, lid string) (model.Label, error) {
+	var (
+		l   model.Label
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return l, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(lid); err != nil {
+		return l, fail.ErrInvalidID
+	}
+
+	conn, Close, err := lr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return l, err
+	}
+	defer Close()
+
+	stmt := `
+		select label_id, tenant_id, project_id, name, color, updated_at, created_at
+		from labels
+		where label_id = $1
+	`
+
+	err = conn.QueryRowxContext(ctx, stmt, lid).Scan(&l.ID, &l.TenantID, &l.ProjectID, &l.Name, &l.Color, &l.UpdatedAt, &l.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return l, fail.ErrNotFound
+		}
+		return l, err
+	}
+
+	if err = authorize(ctx, conn, l.ProjectID, values.UserID, model.RoleViewer); err != nil {
+		return model.Label{}, err
+	}
+
+	l.UpdatedAt = l.UpdatedAt.UTC()
+	l.CreatedAt = l.CreatedAt.UTC()
+
+	return l, nil
+}
+
+// List lists the labels of a project by name.
+func (lr *LabelRepository) List(ctx context.Context, pid string) ([]model.Label, error) {
+	var (
+		l   model.Label
+		ls  = make([]model.Label, 0)
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return ls, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return ls, fail.ErrInvalidID
+	}
+
+	conn, Close, err := lr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return ls, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	stmt := `
+		select label_id, tenant_id, project_id, name, color, updated_at, created_at
+		from labels
+		where project_id = $1
+		order by lower(name)
+	`
+
+	rows, err := conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&l.ID, &l.TenantID, &l.ProjectID, &l.Name, &l.Color, &l.UpdatedAt, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+
+		l.UpdatedAt = l.UpdatedAt.UTC()
+		l.CreatedAt = l.CreatedAt.UTC()
+
+		ls = append(ls, l)
+	}
+
+	return ls, rows.Err()
+}
+
+// Create creates a project label in the database.
+func (lr *LabelRepository) Create(ctx context.Context, pid string, nl model.NewLabel, now time.Time) (model.Label, error) {
+	var (
+		l   model.Label
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return l, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return l, fail.ErrInvalidID
+	}
+
+	conn, Close, err := lr.pg.GetConnection(ctx)
+	if err != nil {
+		return l, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleEditor); err != nil {
+		return l, err
+	}
+
+	l = model.Label{
+		ID:        uuid.New().String(),
+		TenantID:  values.TenantID,
+		ProjectID: pid,
+		Name:      nl.Name,
+		Color:     nl.Color,
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	stmt := `
+		insert into labels (label_id, tenant_id, project_id, name, color, updated_at, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if _, err = conn.ExecContext(ctx, stmt, l.ID, l.TenantID, l.ProjectID, l.Name, l.Color, l.UpdatedAt, l.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return model.Label{}, fail.ErrDuplicateLabel
+		}
+		return model.Label{}, fmt.Errorf("error inserting label: %v: %w", nl, err)
+	}
+	db.Wrote(ctx)
+
+	return l, nil
+}
+
+// Update updates the name or color of a project label in the database.
+func (lr *LabelRepository) Update(ctx context.Context, lid string, update model.UpdateLabel, now time.Time) (model.Label, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Label{}, web.CtxErr()
+	}
+
+	l, err := lr.Retrieve(db.Primary(ctx), lid)
+	if err != nil {
+		return l, err
+	}
+
+	conn, Close, err := lr.pg.GetConnection(ctx)
+	if err != nil {
+		return l, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, l.ProjectID, values.UserID, model.RoleEditor); err != nil {
+		return model.Label{}, err
+	}
+
+	if update.Name != nil {
+		l.Name = *update.Name
+	}
+	if update.Color != nil {
+		l.Color = *update.Color
+	}
+	l.UpdatedAt = now.Round(time.Microsecond).UTC()
+
+	stmt := `update labels set name = $1, color = $2, updated_at = $3 where label_id = $4`
+
+	if _, err = conn.ExecContext(ctx, stmt, l.Name, l.Color, l.UpdatedAt, lid); err != nil {
+		if isUniqueViolation(err) {
+			return model.Label{}, fail.ErrDuplicateLabel
+		}
+		return model.Label{}, fmt.Errorf("error updating label: %s: %w", lid, err)
+	}
+	db.Wrote(ctx)
+
+	return l, nil
+}
+
+// Delete deletes a project label and removes it from every task.
+func (lr *LabelRepository) Delete(ctx context.Context, lid string) error {
+	var (
+		pid string
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(lid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	conn, Close, err := lr.pg.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer Close()
+
+	stmt := `select project_id from labels where label_id = $1`
+	if err = conn.QueryRowxContext(ctx, stmt, lid).Scan(&pid); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleEditor); err != nil {
+		return err
+	}
+
+	stmt = `delete from labels where label_id = $1`
+
+	if _, err = conn.ExecContext(ctx, stmt, lid); err != nil {
+		return fmt.Errorf("error deleting label %s: %w", lid, err)
+	}
+	db.Wrote(ctx)
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// jsonList scans a json array aggregated in task selects, such as labels or
+// links, so a board is read in a single query.
+type jsonList[T any] []T
+
+// Scan implements the sql.Scanner interface.
+func (jl *jsonList[T]) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*jl = make(jsonList[T], 0)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into json list", src)
+	}
+
+	list := make(jsonList[T], 0)
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*jl = list
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLabelRepository_Triage(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewLabelRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	bug := testTasks[0].Labels[0]
+
+	t.Run("names are unique per project ignoring case", func(t *testing.T) {
+		_, err := repo.Create(ctx, project.ID, model.NewLabel{Name: "BUG", Color: "#000000"}, time.Now())
+		assert.Equal(t, fail.ErrDuplicateLabel, err)
+	})
+
+	t.Run("labels of another project are rejected", func(t *testing.T) {
+		other, err := repo.Create(ctx, testProjects[0].ID, model.NewLabel{Name: "Feature", Color: "#0e8a16"}, time.Now())
+		require.NoError(t, err)
+
+		_, err = taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{Labels: []string{other.ID}}, time.Now())
+		assert.Equal(t, fail.ErrInvalidLabel, err)
+	})
+
+	t.Run("start after due is rejected", func(t *testing.T) {
+		due := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		start := due.Add(time.Hour)
+
+		_, err := taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{StartAt: &start, DueAt: &due}, time.Now())
+		assert.Equal(t, fail.ErrInvalidSchedule, err)
+	})
+
+	t.Run("dates are cleared", func(t *testing.T) {
+		start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		due := start.Add(time.Hour)
+
+		updated, err := taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{StartAt: &start, DueAt: &due}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, &start, updated.StartAt)
+		assert.Equal(t, &due, updated.DueAt)
+
+		// A missing date is left unchanged.
+		updated, err = taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{ClearDueAt: true}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, &start, updated.StartAt)
+		assert.Nil(t, updated.DueAt)
+
+		updated, err = taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{ClearStartAt: true}, time.Now())
+		require.NoError(t, err)
+		assert.Nil(t, updated.StartAt)
+		assert.Nil(t, updated.DueAt)
+	})
+
+	t.Run("filter by label, priority and overdue", func(t *testing.T) {
+		due := time.Now().Add(-time.Hour).Round(time.Microsecond).UTC()
+
+		updated, err := taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{
+			Priority: aws.String(model.PriorityHigh),
+			DueAt:    &due,
+			Labels:   []string{bug.ID},
+		}, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, []model.TaskLabel{bug}, updated.Labels)
+		assert.Equal(t, &due, updated.DueAt)
+
+		list, err := taskRepo.List(ctx, project.ID, model.TaskFilter{LabelID: bug.ID})
+		assert.Nil(t, err)
+		assert.Len(t, list, 2)
+
+		list, err = taskRepo.List(ctx, project.ID, model.TaskFilter{Priority: model.PriorityHigh, Overdue: true})
+		assert.Nil(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, testTasks[1].ID, list[0].ID)
+		assert.Equal(t, []model.TaskLabel{bug}, list[0].Labels)
+	})
+
+	t.Run("deleting a label removes it from tasks", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, bug.ID))
+
+		task, err := taskRepo.Retrieve(ctx, testTasks[0].ID)
+		assert.Nil(t, err)
+		assert.Empty(t, task.Labels)
+	})
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// inverseLinks maps the inverse link kinds to the forward kinds they are stored as.
+var inverseLinks = map[string]string{
+	model.LinkIsBlockedBy:    model.LinkBlocks,
+	model.LinkIsDuplicatedBy: model.LinkDuplicates,
+}
+
+// SetParent makes a task a subtask of another task, or a top level task when no parent is given.
+func (tr *TaskRepository) SetParent(ctx context.Context, tid string, sp model.SetParent, now time.Time) (model.Task, error) {
+	var err error
+
+	if _, err = uuid.Parse(tid); err != nil {
+		return model.Task{}, fail.ErrInvalidID
+	}
+	if sp.ParentID == tid {
+		return model.Task{}, fail.ErrInvalidLink
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Task{}, web.CtxErr()
+	}
+
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t, err := lockTask(ctx, tx, tid)
+		if err != nil {
+			return err
+		}
+
+		if err = authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		if sp.ParentID != "" {
+			parent, err := lockTask(ctx, tx, sp.ParentID)
+			if err != nil {
+				return err
+			}
+			if err = authorize(ctx, tx, parent.ProjectID, values.UserID, model.RoleViewer); err != nil {
+				return err
+			}
+
+			// The task cannot become a subtask of one of its own subtasks.
+			var cycle bool
+			stmt := `
+				with recursive ancestors (task_id, parent_id) as (
+					select task_id, parent_id from tasks where task_id = $1
+					union
+					select t.task_id, t.parent_id from tasks t join ancestors a on t.task_id = a.parent_id
+				)
+				select exists(select 1 from ancestors where task_id = $2)
+			`
+			if err = tx.QueryRowxContext(ctx, stmt, sp.ParentID, tid).Scan(&cycle); err != nil {
+				return err
+			}
+			if cycle {
+				return fail.ErrLinkCycle
+			}
+		}
+
+		stmt := `update tasks set parent_id = nullif($1, ''), updated_at = $2 where task_id = $3`
+		if _, err = tx.ExecContext(ctx, stmt, sp.ParentID, now.Round(time.Microsecond).UTC(), tid); err != nil {
+			return fmt.Errorf("error setting parent of task %s: %w", tid, err)
+		}
+
+		if t.ParentID == sp.ParentID {
+			return nil
+		}
+		changes := map[string]model.FieldChange{
+			"parentId": {From: t.ParentID, To: sp.ParentID},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskUpdated, changes, now)
+	})
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return tr.Retrieve(db.Primary(ctx), tid)
+}
+
+// Link links a task to another task of the tenant. Inverse kinds are stored as the forward
+// kind from the target, and blocking or duplicate links may not form a cycle.
+func (tr *TaskRepository) Link(ctx context.Context, tid string, nl model.NewTaskLink, now time.Time) (model.Task, error) {
+	var err error
+
+	for _, id := range []string{tid, nl.TargetID} {
+		if _, err = uuid.Parse(id); err != nil {
+			return model.Task{}, fail.ErrInvalidID
+		}
+	}
+	if nl.TargetID == tid {
+		return model.Task{}, fail.ErrInvalidLink
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Task{}, web.CtxErr()
+	}
+
+	from, to, kind := tid, nl.TargetID, nl.Kind
+	if k, ok := inverseLinks[nl.Kind]; ok {
+		from, to, kind = nl.TargetID, tid, k
+	}
+
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t, err := lockTask(ctx, tx, tid)
+		if err != nil {
+			return err
+		}
+
+		if err = authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		// Row level security hides the tasks of other tenants, so they are not found.
+		target, err := lockTask(ctx, tx, nl.TargetID)
+		if err != nil {
+			return err
+		}
+		if target.TenantID != t.TenantID {
+			return fail.ErrNotFound
+		}
+		if err = authorize(ctx, tx, target.ProjectID, values.UserID, model.RoleViewer); err != nil {
+			return err
+		}
+
+		var exists bool
+		stmt := `
+			select exists(
+				select 1 from task_links
+				where kind = $1 and (
+					(task_id = $2 and target_id = $3) or
+					(kind = 'relates_to' and task_id = $3 and target_id = $2)
+				)
+			)
+		`
+		if err = tx.QueryRowxContext(ctx, stmt, kind, from, to).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fail.ErrDuplicateLink
+		}
+
+		if kind != model.LinkRelatesTo {
+			// Linking from to to closes a cycle when from is already reachable from to.
+			var cycle bool
+			stmt = `
+				with recursive reachable (task_id) as (
+					select target_id from task_links where task_id = $1 and kind = $3
+					union
+					select l.target_id from task_links l join reachable r on l.task_id = r.task_id where l.kind = $3
+				)
+				select exists(select 1 from reachable where task_id = $2)
+			`
+			if err = tx.QueryRowxContext(ctx, stmt, to, from, kind).Scan(&cycle); err != nil {
+				return err
+			}
+			if cycle {
+				return fail.ErrLinkCycle
+			}
+		}
+
+		link := model.TaskLink{
+			ID:     uuid.New().String(),
+			Kind:   nl.Kind,
+			TaskID: target.ID,
+			Key:    target.Key,
+			Title:  target.Title,
+		}
+
+		stmt = `
+			insert into task_links (link_id, tenant_id, task_id, target_id, kind, created_at)
+			values ($1, $2, $3, $4, $5, $6)
+		`
+		if _, err = tx.ExecContext(ctx, stmt, link.ID, t.TenantID, from, to, kind, now.Round(time.Microsecond).UTC()); err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrDuplicateLink
+			}
+			return fmt.Errorf("error inserting task link: %v: %w", nl, err)
+		}
+
+		changes := map[string]model.FieldChange{
+			"links": {To: link},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskLinked, changes, now)
+	})
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return tr.Retrieve(db.Primary(ctx), tid)
+}
+
+// Unlink removes a link from or to a task.
+func (tr *TaskRepository) Unlink(ctx context.Context, tid string, lkid string, now time.Time) error {
+	var err error
+
+	for _, id := range []string{tid, lkid} {
+		if _, err = uuid.Parse(id); err != nil {
+			return fail.ErrInvalidID
+		}
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	return tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t, err := lockTask(ctx, tx, tid)
+		if err != nil {
+			return err
+		}
+
+		if err = authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		link := model.TaskLink{ID: lkid}
+		stmt := `
+			delete from task_links
+			where link_id = $1 and (task_id = $2 or target_id = $2)
+			returning
+				case when task_id = $2 then target_id else task_id end,
+				case when task_id = $2 or kind = 'relates_to' then kind when kind = 'blocks' then 'is_blocked_by' else 'is_duplicated_by' end
+		`
+		if err = tx.QueryRowxContext(ctx, stmt, lkid, tid).Scan(&link.TaskID, &link.Kind); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return fmt.Errorf("error deleting task link %s: %w", lkid, err)
+		}
+
+		changes := map[string]model.FieldChange{
+			"links": {From: link},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskUnlinked, changes, now)
+	})
+}
+
+// lockTask locks a task row for the rest of the transaction.
+func lockTask(ctx context.Context, tx *sqlx.Tx, tid string) (model.Task, error) {
+	t := model.Task{ID: tid}
+
+	stmt := `
+		select tenant_id, project_id, key, title, coalesce(column_id, ''), coalesce(parent_id, '')
+		from tasks where task_id = $1 and deleted_at is null for update
+	`
+	if err := tx.QueryRowxContext(ctx, stmt, tid).Scan(&t.TenantID, &t.ProjectID, &t.Key, &t.Title, &t.ColumnID, &t.ParentID); err != nil {
+		if err == sql.ErrNoRows {
+			return t, fail.ErrNotFound
+		}
+		return t, err
+	}
+
+	return t, nil
+}
+
+// blocked reports whether a task is blocked by tasks that are not done.
+func blocked(ctx context.Context, tx *sqlx.Tx, tid string) (bool, error) {
+	var b bool
+
+	stmt := `
+		select exists(
+			select 1 from task_links l join tasks b on b.task_id = l.task_id
+			where l.target_id = $1 and l.kind = 'blocks' and b.deleted_at is null and not task_done(b.project_id, b.column_id)
+		)
+	`
+	err := tx.QueryRowxContext(ctx, stmt, tid).Scan(&b)
+	return b, err
+}
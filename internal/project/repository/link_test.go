@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTaskRepository_Links(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	var done model.Column
+	for _, c := range testColumns {
+		if c.ProjectID == project.ID && c.ColumnName == project.ColumnOrder[len(project.ColumnOrder)-1] {
+			done = c
+		}
+	}
+	require.NotEmpty(t, done.ID)
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	now := time.Now()
+	a, b := testTasks[0], testTasks[1]
+	c, err := repo.Create(ctx, model.NewTask{Title: "Ship it"}, project.ID, a.ColumnID, now)
+	require.NoError(t, err)
+
+	t.Run("links are summarised from both tasks", func(t *testing.T) {
+		actual, err := repo.Link(ctx, a.ID, model.NewTaskLink{Kind: model.LinkBlocks, TargetID: b.ID}, now)
+		assert.Nil(t, err)
+		require.Len(t, actual.Links, 1)
+		assert.Equal(t, model.LinkBlocks, actual.Links[0].Kind)
+		assert.Equal(t, b.Key, actual.Links[0].Key)
+
+		target, err := repo.Retrieve(ctx, b.ID)
+		require.NoError(t, err)
+		require.Len(t, target.Links, 1)
+		assert.Equal(t, model.LinkIsBlockedBy, target.Links[0].Kind)
+		assert.Equal(t, a.ID, target.Links[0].TaskID)
+	})
+
+	t.Run("invalid links are rejected", func(t *testing.T) {
+		_, err := repo.Link(ctx, a.ID, model.NewTaskLink{Kind: model.LinkRelatesTo, TargetID: a.ID}, now)
+		assert.Equal(t, fail.ErrInvalidLink, err)
+
+		_, err = repo.Link(ctx, b.ID, model.NewTaskLink{Kind: model.LinkIsBlockedBy, TargetID: a.ID}, now)
+		assert.Equal(t, fail.ErrDuplicateLink, err)
+
+		_, err = repo.Link(ctx, b.ID, model.NewTaskLink{Kind: model.LinkBlocks, TargetID: c.ID}, now)
+		require.NoError(t, err)
+
+		_, err = repo.Link(ctx, c.ID, model.NewTaskLink{Kind: model.LinkBlocks, TargetID: a.ID}, now)
+		assert.Equal(t, fail.ErrLinkCycle, err)
+
+		_, err = repo.Link(ctx, a.ID, model.NewTaskLink{Kind: model.LinkIsBlockedBy, TargetID: c.ID}, now)
+		assert.Equal(t, fail.ErrLinkCycle, err)
+	})
+
+	t.Run("blocked tasks cannot be finished", func(t *testing.T) {
+		_, err := repo.Move(ctx, b.ID, model.MoveTask{To: done.ID}, now)
+		assert.Equal(t, fail.ErrTaskBlocked, err)
+
+		_, err = repo.Move(ctx, a.ID, model.MoveTask{To: done.ID}, now)
+		require.NoError(t, err)
+
+		_, err = repo.Move(ctx, b.ID, model.MoveTask{To: done.ID}, now)
+		assert.Nil(t, err)
+	})
+
+	t.Run("subtask progress rolls up", func(t *testing.T) {
+		for _, tid := range []string{b.ID, c.ID} {
+			_, err := repo.SetParent(ctx, tid, model.SetParent{ParentID: a.ID}, now)
+			require.NoError(t, err)
+		}
+
+		actual, err := repo.Retrieve(ctx, a.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, model.Progress{Total: 2, Completed: 1}, actual.Subtasks)
+
+		_, err = repo.SetParent(ctx, a.ID, model.SetParent{ParentID: c.ID}, now)
+		assert.Equal(t, fail.ErrLinkCycle, err)
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		target, err := repo.Retrieve(ctx, b.ID)
+		require.NoError(t, err)
+
+		for _, l := range target.Links {
+			assert.Nil(t, repo.Unlink(ctx, b.ID, l.ID, now))
+		}
+
+		actual, err := repo.Retrieve(ctx, b.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, actual.Links)
+
+		assert.Equal(t, fail.ErrNotFound, repo.Unlink(ctx, b.ID, target.Links[0].ID, now))
+	})
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// MemberRepository manages data access to project members.
+type MemberRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewMemberRepository returns a new MemberRepository.
+func NewMemberRepository(logger *zap.Logger, pg *db.PostgresDatabase) *MemberRepository {
+	return &MemberRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// authorize checks the user has at least the required role in a project that is not in
+// the trash. Projects the user cannot see are not found.
+func authorize(ctx context.Context, q sqlx.QueryerContext, pid string, userID string, required string) error {
+	var role sql.NullString
+
+	stmt := `select project_role(project_id, $2) from projects where project_id = $1 and deleted_at is null`
+	if err := q.QueryRowxContext(ctx, stmt, pid, userID).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+	if !role.Valid {
+		return fail.ErrNotFound
+	}
+	if !model.RoleAllows(role.String, required) {
+		return fail.ErrNotAuthorized
+	}
+
+	return nil
+}
+
+// authorizeTask checks that the user has at least the required role in the project of a
+// task. Tasks the user cannot see are not found.
+func authorizeTask(ctx context.Context, q sqlx.QueryerContext, tid string, userID string, required string) error {
+	var pid string
+
+	stmt := `select project_id from tasks where task_id = $1`
+	if err := q.QueryRowxContext(ctx, stmt, tid).Scan(&pid); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+
+	return authorize(ctx, q, pid, userID, required)
+}
+
+// List lists the members of a project the user can see, owners first.
+func (mr *MemberRepository) List(ctx context.Context, pid string) ([]model.ProjectMember, error) {
+	var ms = make([]model.ProjectMember, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return ms, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return ms, fail.ErrInvalidID
+	}
+
+	conn, Close, err := mr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return ms, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleViewer); err != nil {
+		return ms, err
+	}
+
+	stmt := `
+		select project_id, user_id, tenant_id, role, updated_at, created_at
+		from project_members
+		where project_id = $1
+		order by case role when 'owner' then 1 when 'editor' then 2 else 3 end, created_at
+	`
+	if err = conn.SelectContext(ctx, &ms, stmt, pid); err != nil {
+		return nil, fmt.Errorf("error selecting project members :%w", err)
+	}
+
+	for i := range ms {
+		ms[i].UpdatedAt = ms[i].UpdatedAt.UTC()
+		ms[i].CreatedAt = ms[i].CreatedAt.UTC()
+	}
+
+	return ms, nil
+}
+
+// Set adds a user to a project with a role, or changes the role of a member. Only owners
+// manage members, and a project always keeps an owner.
+func (mr *MemberRepository) Set(ctx context.Context, pid string, uid string, role string, now time.Time) (model.ProjectMember, error) {
+	var m model.ProjectMember
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return m, web.CtxErr()
+	}
+
+	for _, id := range []string{pid, uid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return m, fail.ErrInvalidID
+		}
+	}
+
+	err := mr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		if role != model.RoleOwner {
+			if err := keepOwner(ctx, tx, pid, uid); err != nil {
+				return err
+			}
+		}
+
+		stmt := `
+			insert into project_members (project_id, user_id, tenant_id, role, updated_at, created_at)
+			values ($1, $2, $3, $4, $5, $5)
+			on conflict (project_id, user_id) do update
+			set role = excluded.role, updated_at = excluded.updated_at
+			returning project_id, user_id, tenant_id, role, updated_at, created_at
+		`
+		return tx.QueryRowxContext(ctx, stmt, pid, uid, values.TenantID, role, now.Round(time.Microsecond).UTC()).StructScan(&m)
+	})
+	if err != nil {
+		return model.ProjectMember{}, err
+	}
+
+	m.UpdatedAt = m.UpdatedAt.UTC()
+	m.CreatedAt = m.CreatedAt.UTC()
+
+	return m, nil
+}
+
+// Remove removes a user from a project. Owners remove anyone, other members only
+// themselves, and a project always keeps an owner.
+func (mr *MemberRepository) Remove(ctx context.Context, pid string, uid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	for _, id := range []string{pid, uid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return fail.ErrInvalidID
+		}
+	}
+
+	required := model.RoleOwner
+	if uid == values.UserID {
+		required = model.RoleViewer
+	}
+
+	return mr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, required); err != nil {
+			return err
+		}
+
+		if err := keepOwner(ctx, tx, pid, uid); err != nil {
+			return err
+		}
+
+		stmt := `delete from project_members where project_id = $1 and user_id = $2`
+		res, err := tx.ExecContext(ctx, stmt, pid, uid)
+		if err != nil {
+			return fmt.Errorf("error removing project member %s :%w", uid, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fail.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// keepOwner checks a project still has an owner once the user is no longer one. The
+// owners are locked so concurrent changes cannot remove the last two owners together.
+func keepOwner(ctx context.Context, tx *sqlx.Tx, pid string, uid string) error {
+	var owners []string
+
+	stmt := `select user_id from project_members where project_id = $1 and role = 'owner' for update`
+	if err := tx.SelectContext(ctx, &owners, stmt, pid); err != nil {
+		return fmt.Errorf("error selecting project owners :%w", err)
+	}
+
+	if len(owners) == 1 && owners[0] == uid {
+		return fail.ErrLastOwner
+	}
+
+	return nil
+}
+
+// AssignTeam assigns a team to a project, or unassigns the current team when teamID is
+// empty. Only owners assign teams.
+func (mr *MemberRepository) AssignTeam(ctx context.Context, pid string, teamID string, now time.Time) (model.Project, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Project{}, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return model.Project{}, fail.ErrInvalidID
+	}
+
+	var team sql.NullString
+	if teamID != "" {
+		if _, err := uuid.Parse(teamID); err != nil {
+			return model.Project{}, fail.ErrInvalidID
+		}
+		team = sql.NullString{String: teamID, Valid: true}
+	}
+
+	err := mr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		if team.Valid {
+			var exists bool
+			stmt := `select exists(select 1 from teams where team_id = $1)`
+			if err := tx.QueryRowxContext(ctx, stmt, teamID).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return fail.ErrInvalidTeam
+			}
+		}
+
+		stmt := `update projects set team_id = $1, updated_at = $2 where project_id = $3`
+		if _, err := tx.ExecContext(ctx, stmt, team, now.Round(time.Microsecond).UTC(), pid); err != nil {
+			return fmt.Errorf("error assigning team to project %s :%w", pid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	return NewProjectRepository(mr.logger, mr.pg).Retrieve(db.Primary(ctx), pid)
+}
+
+// Membership returns everyone with access to a project through a role. It does not
+// check the user may see the project and is meant for notifying other services.
+func (mr *MemberRepository) Membership(ctx context.Context, pid string) (model.Membership, error) {
+	var (
+		ms  = model.Membership{ProjectID: pid, Members: make([]model.ProjectMember, 0), TeamMembers: make([]string, 0)}
+		err error
+	)
+
+	conn, Close, err := mr.pg.GetReadConnection(db.Primary(ctx))
+	if err != nil {
+		return ms, err
+	}
+	defer Close()
+
+	stmt := `select coalesce(team_id, '') from projects where project_id = $1`
+	if err = conn.QueryRowxContext(ctx, stmt, pid).Scan(&ms.TeamID); err != nil {
+		if err == sql.ErrNoRows {
+			return ms, fail.ErrNotFound
+		}
+		return ms, err
+	}
+
+	stmt = `
+		select project_id, user_id, tenant_id, role, updated_at, created_at
+		from project_members where project_id = $1 order by created_at
+	`
+	if err = conn.SelectContext(ctx, &ms.Members, stmt, pid); err != nil {
+		return ms, fmt.Errorf("error selecting project members :%w", err)
+	}
+
+	stmt = `
+		select tm.user_id from projects p join team_members tm on tm.team_id = p.team_id
+		where p.project_id = $1 order by tm.created_at
+	`
+	if err = conn.SelectContext(ctx, &ms.TeamMembers, stmt, pid); err != nil {
+		return ms, fmt.Errorf("error selecting team members :%w", err)
+	}
+
+	return ms, nil
+}
@@ -0,0 +1,266 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMemberRepository_Roles(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	userID := uuid.New().String()
+	user := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: userID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewMemberRepository(zap.NewNop(), db)
+	projectRepo := repository.NewProjectRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	t.Run("non members cannot see the project", func(t *testing.T) {
+		_, err := projectRepo.Retrieve(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = taskRepo.Retrieve(user, testTasks[0].ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = repo.List(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("viewers read but do not write", func(t *testing.T) {
+		m, err := repo.Set(owner, project.ID, userID, model.RoleViewer, now)
+		require.NoError(t, err)
+		assert.Equal(t, model.RoleViewer, m.Role)
+
+		_, err = taskRepo.Retrieve(user, testTasks[0].ID)
+		assert.Nil(t, err)
+
+		_, err = taskRepo.Update(user, testTasks[0].ID, model.UpdateTask{Title: aws.String("Viewed")}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		_, err = repo.Set(user, project.ID, userID, model.RoleOwner, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+	})
+
+	t.Run("editors write", func(t *testing.T) {
+		_, err := repo.Set(owner, project.ID, userID, model.RoleEditor, now)
+		require.NoError(t, err)
+
+		_, err = taskRepo.Update(user, testTasks[0].ID, model.UpdateTask{Title: aws.String("Edited")}, now)
+		assert.Nil(t, err)
+
+		err = projectRepo.Delete(user, project.ID, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+	})
+
+	t.Run("a project keeps an owner", func(t *testing.T) {
+		_, err := repo.Set(owner, project.ID, project.UserID, model.RoleEditor, now)
+		assert.Equal(t, fail.ErrLastOwner, err)
+
+		err = repo.Remove(owner, project.ID, project.UserID)
+		assert.Equal(t, fail.ErrLastOwner, err)
+
+		list, err := repo.List(owner, project.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, model.RoleOwner, list[0].Role)
+	})
+
+	t.Run("members leave", func(t *testing.T) {
+		require.NoError(t, repo.Remove(user, project.ID, userID))
+
+		_, err := projectRepo.Retrieve(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("team members edit assigned projects", func(t *testing.T) {
+		teamRepo := repository.NewTeamRepository(zap.NewNop(), db)
+
+		team, err := teamRepo.Create(owner, model.NewTeam{Name: "Design"}, now)
+		require.NoError(t, err)
+
+		_, err = teamRepo.Create(owner, model.NewTeam{Name: "design"}, now)
+		assert.Equal(t, fail.ErrDuplicateTeam, err)
+
+		team, err = teamRepo.AddMember(owner, team.ID, userID, now)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{project.UserID, userID}, team.Members)
+
+		err = teamRepo.Delete(user, team.ID)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		_, err = repo.AssignTeam(owner, project.ID, uuid.New().String(), now)
+		assert.Equal(t, fail.ErrInvalidTeam, err)
+
+		p, err := repo.AssignTeam(owner, project.ID, team.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, team.ID, p.TeamID)
+
+		_, err = taskRepo.Update(user, testTasks[0].ID, model.UpdateTask{Title: aws.String("Team work")}, now)
+		assert.Nil(t, err)
+
+		ms, err := repo.Membership(owner, project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, team.ID, ms.TeamID)
+		assert.Len(t, ms.Members, 1)
+		assert.ElementsMatch(t, []string{project.UserID, userID}, ms.TeamMembers)
+
+		require.NoError(t, teamRepo.RemoveMember(user, team.ID, userID))
+
+		_, err = projectRepo.Retrieve(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
+
+func TestMemberRepository_BoardAccess(t *testing.T) {
+	project := testProjects[1]
+	task := testTasks[0]
+	label := task.Labels[0]
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	userID := uuid.New().String()
+	user := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: userID})
+
+	var column model.Column
+	for _, c := range testColumns {
+		if c.ProjectID == project.ID {
+			column = c
+			break
+		}
+	}
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewMemberRepository(zap.NewNop(), db)
+	columnRepo := repository.NewColumnRepository(zap.NewNop(), db)
+	labelRepo := repository.NewLabelRepository(zap.NewNop(), db)
+	sprintRepo := repository.NewSprintRepository(zap.NewNop(), db)
+	commentRepo := repository.NewCommentRepository(zap.NewNop(), db)
+	activityRepo := repository.NewActivityRepository(zap.NewNop(), db)
+
+	now := time.Now()
+	page := model.ActivityPage{Limit: 10}
+
+	sprint, err := sprintRepo.Create(owner, project.ID, model.NewSprint{Name: "Sprint 1"}, now)
+	require.NoError(t, err)
+	comment, err := commentRepo.Create(owner, model.NewComment{Content: "Testing"}, task.ID, now)
+	require.NoError(t, err)
+
+	t.Run("non members are not found", func(t *testing.T) {
+		_, err := columnRepo.List(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = columnRepo.Retrieve(user, column.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = columnRepo.Update(user, column.ID, model.UpdateColumn{Title: aws.String("Mine")}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = columnRepo.Add(user, project.ID, model.AddColumn{Title: "Mine"}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = columnRepo.Remove(user, project.ID, column.ID, "", now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		err = columnRepo.Reorder(user, project.ID, project.ColumnOrder, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = labelRepo.List(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = labelRepo.Retrieve(user, label.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = labelRepo.Create(user, project.ID, model.NewLabel{Name: "Mine", Color: "#000000"}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = labelRepo.Update(user, label.ID, model.UpdateLabel{Name: aws.String("Mine")}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		err = labelRepo.Delete(user, label.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = sprintRepo.List(user, project.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = sprintRepo.Retrieve(user, project.ID, sprint.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = sprintRepo.Create(user, project.ID, model.NewSprint{Name: "Mine"}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = sprintRepo.Start(user, project.ID, sprint.ID, model.StartSprint{EndAt: now.Add(time.Hour)}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = sprintRepo.AddTasks(user, project.ID, sprint.ID, model.SprintTasks{TaskIDs: []string{task.ID}}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		err = sprintRepo.RemoveTask(user, project.ID, sprint.ID, task.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = sprintRepo.Burndown(user, project.ID, sprint.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = commentRepo.List(user, task.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = commentRepo.Retrieve(user, comment.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = commentRepo.Update(user, task.ID, comment.ID, model.UpdateComment{Liked: aws.Bool(true)}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+		err = commentRepo.Delete(user, task.ID, comment.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = activityRepo.ListByTask(user, task.ID, page)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = activityRepo.ListByProject(user, project.ID, page)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("viewers read but do not write", func(t *testing.T) {
+		_, err := repo.Set(owner, project.ID, userID, model.RoleViewer, now)
+		require.NoError(t, err)
+
+		_, err = columnRepo.List(user, project.ID)
+		assert.Nil(t, err)
+		_, err = labelRepo.List(user, project.ID)
+		assert.Nil(t, err)
+		_, err = sprintRepo.Burndown(user, project.ID, sprint.ID, now)
+		assert.Nil(t, err)
+		_, err = commentRepo.List(user, task.ID)
+		assert.Nil(t, err)
+		_, err = activityRepo.ListByProject(user, project.ID, page)
+		assert.Nil(t, err)
+
+		_, err = columnRepo.Update(user, column.ID, model.UpdateColumn{Title: aws.String("Mine")}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		_, err = labelRepo.Create(user, project.ID, model.NewLabel{Name: "Mine", Color: "#000000"}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		err = labelRepo.Delete(user, label.ID)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		_, err = sprintRepo.Start(user, project.ID, sprint.ID, model.StartSprint{EndAt: now.Add(time.Hour)}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		_, err = sprintRepo.AddTasks(user, project.ID, sprint.ID, model.SprintTasks{TaskIDs: []string{task.ID}}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		err = commentRepo.Delete(user, task.ID, comment.ID)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+	})
+
+	t.Run("editors do not change the board structure", func(t *testing.T) {
+		_, err := repo.Set(owner, project.ID, userID, model.RoleEditor, now)
+		require.NoError(t, err)
+
+		_, err = columnRepo.Update(user, column.ID, model.UpdateColumn{Title: aws.String("Doing")}, now)
+		assert.Nil(t, err)
+
+		_, err = columnRepo.Add(user, project.ID, model.AddColumn{Title: "Mine"}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		_, err = columnRepo.Remove(user, project.ID, column.ID, "", now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+		err = columnRepo.Reorder(user, project.ID, project.ColumnOrder, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+	})
+}
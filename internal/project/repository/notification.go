@@ -0,0 +1,479 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// NotificationRepository manages data access to notifications and notification
+// preferences.
+type NotificationRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewNotificationRepository returns a new NotificationRepository.
+func NewNotificationRepository(logger *zap.Logger, pg *db.PostgresDatabase) *NotificationRepository {
+	return &NotificationRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectNotification = `
+	select
+		notification_id, tenant_id, user_id, reason, event_id, event_kind, actor_id,
+		project_id, task_id, task_key, task_title, read_at, created_at
+	from notifications
+`
+
+const selectPreferences = `
+	select user_id, assigned, mentioned, commented, changed, updated_at
+	from notification_preferences
+`
+
+// reasonOrder ranks notification reasons from the most specific.
+var reasonOrder = map[string]int{
+	model.ReasonAssigned:  0,
+	model.ReasonMentioned: 1,
+	model.ReasonCommented: 2,
+	model.ReasonChanged:   3,
+}
+
+func utcNotification(n model.Notification) model.Notification {
+	n.ReadAt = utc(n.ReadAt)
+	n.CreatedAt = n.CreatedAt.UTC()
+	return n
+}
+
+// List lists a page of the notifications of the user, newest first, and counts those
+// the user has not read.
+func (nr *NotificationRepository) List(ctx context.Context, page model.InboxPage) (model.Inbox, error) {
+	inbox := model.Inbox{Notifications: make([]model.Notification, 0)}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return inbox, web.CtxErr()
+	}
+
+	conn, Close, err := nr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return inbox, err
+	}
+	defer Close()
+
+	var ns []model.Notification
+	stmt := selectNotification + `
+		where user_id = $1 and (not $2 or read_at is null)
+		order by created_at desc, notification_id
+		limit $3 offset $4
+	`
+	if err = conn.SelectContext(ctx, &ns, stmt, values.UserID, page.Unread, page.Limit, page.Offset); err != nil {
+		return inbox, fmt.Errorf("error selecting notifications: %w", err)
+	}
+
+	stmt = `select count(*) from notifications where user_id = $1 and read_at is null`
+	if err = conn.QueryRowxContext(ctx, stmt, values.UserID).Scan(&inbox.Unread); err != nil {
+		return inbox, fmt.Errorf("error counting unread notifications: %w", err)
+	}
+
+	for _, n := range ns {
+		inbox.Notifications = append(inbox.Notifications, utcNotification(n))
+	}
+	return inbox, nil
+}
+
+// Update marks a notification of the user as read or unread.
+func (nr *NotificationRepository) Update(ctx context.Context, nid string, update model.UpdateNotification, now time.Time) (model.Notification, error) {
+	var n model.Notification
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return n, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(nid); err != nil {
+		return n, fail.ErrInvalidID
+	}
+
+	read := update.Read != nil && *update.Read
+
+	err := nr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		stmt := `
+			update notifications
+			set read_at = case when $1 then coalesce(read_at, $2) end
+			where notification_id = $3 and user_id = $4
+			returning
+				notification_id, tenant_id, user_id, reason, event_id, event_kind, actor_id,
+				project_id, task_id, task_key, task_title, read_at, created_at
+		`
+		if err := tx.QueryRowxContext(ctx, stmt, read, now.Round(time.Microsecond).UTC(), nid, values.UserID).StructScan(&n); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return fmt.Errorf("error updating notification %s: %w", nid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	return utcNotification(n), nil
+}
+
+// ReadAll marks every notification of the user as read and returns how many were unread.
+func (nr *NotificationRepository) ReadAll(ctx context.Context, now time.Time) (int, error) {
+	var count int64
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return 0, web.CtxErr()
+	}
+
+	err := nr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		stmt := `update notifications set read_at = $1 where user_id = $2 and read_at is null`
+		res, err := tx.ExecContext(ctx, stmt, now.Round(time.Microsecond).UTC(), values.UserID)
+		if err != nil {
+			return fmt.Errorf("error reading notifications: %w", err)
+		}
+		count, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// Preferences retrieves the notification preferences of the user.
+func (nr *NotificationRepository) Preferences(ctx context.Context) (model.NotificationPreferences, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.NotificationPreferences{}, web.CtxErr()
+	}
+
+	conn, Close, err := nr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return model.NotificationPreferences{}, err
+	}
+	defer Close()
+
+	return preferencesOf(ctx, conn, values.UserID)
+}
+
+// UpdatePreferences updates the notification preferences of the user.
+func (nr *NotificationRepository) UpdatePreferences(ctx context.Context, update model.UpdateNotificationPreferences, now time.Time) (model.NotificationPreferences, error) {
+	var np model.NotificationPreferences
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return np, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return np, fail.ErrInvalidID
+	}
+
+	err := nr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		if np, err = preferencesOf(ctx, tx, values.UserID); err != nil {
+			return err
+		}
+
+		if update.Assigned != nil {
+			np.Assigned = *update.Assigned
+		}
+		if update.Mentioned != nil {
+			np.Mentioned = *update.Mentioned
+		}
+		if update.Commented != nil {
+			np.Commented = *update.Commented
+		}
+		if update.Changed != nil {
+			np.Changed = *update.Changed
+		}
+		np.UpdatedAt = now.Round(time.Microsecond).UTC()
+
+		stmt := `
+			insert into notification_preferences (tenant_id, user_id, assigned, mentioned, commented, changed, updated_at)
+			values ($1, $2, $3, $4, $5, $6, $7)
+			on conflict (tenant_id, user_id) do update
+			set assigned = $3, mentioned = $4, commented = $5, changed = $6, updated_at = $7
+		`
+		_, err = tx.ExecContext(ctx, stmt, values.TenantID, values.UserID, np.Assigned, np.Mentioned, np.Commented, np.Changed, np.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error updating notification preferences: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.NotificationPreferences{}, err
+	}
+
+	return np, nil
+}
+
+// preferencesOf returns the notification preferences of a user, or the defaults when the
+// user never set any.
+func preferencesOf(ctx context.Context, q sqlx.QueryerContext, userID string) (model.NotificationPreferences, error) {
+	var np model.NotificationPreferences
+
+	if err := q.QueryRowxContext(ctx, selectPreferences+` where user_id = $1`, userID).StructScan(&np); err != nil {
+		if err == sql.ErrNoRows {
+			return model.DefaultNotificationPreferences(userID), nil
+		}
+		return np, err
+	}
+
+	np.UpdatedAt = np.UpdatedAt.UTC()
+	return np, nil
+}
+
+// Dispatch turns up to limit task events of the tenant that are not notified yet into
+// notifications, oldest first, and marks them notified in the same transaction.
+//
+// Events are dispatched in order by one dispatcher at a time per tenant, so that the
+// watchers added by an event are notified of the events after it. A dispatch that finds
+// another one running for the tenant returns without dispatching.
+func (nr *NotificationRepository) Dispatch(ctx context.Context, limit int, now time.Time) (model.Dispatched, error) {
+	var d model.Dispatched
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return d, web.CtxErr()
+	}
+
+	err := nr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var locked bool
+
+		stmt := `select pg_try_advisory_xact_lock(hashtext('notifications:' || $1))`
+		if err := tx.QueryRowxContext(ctx, stmt, values.TenantID).Scan(&locked); err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		rows, err := tx.QueryxContext(ctx, selectEvent+` where not notified order by created_at, event_id limit $1`, limit)
+		if err != nil {
+			return fmt.Errorf("error selecting events to notify: %w", err)
+		}
+
+		var events []model.TaskEvent
+		for rows.Next() {
+			e, err := scanEvent(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			events = append(events, e)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(events))
+		for _, e := range events {
+			n, err := notify(ctx, tx, e, now)
+			if err != nil {
+				return err
+			}
+			d.Notifications += n
+			ids = append(ids, e.ID)
+		}
+
+		if len(ids) > 0 {
+			stmt = `update task_events set notified = true where event_id = any($1)`
+			if _, err = tx.ExecContext(ctx, stmt, pq.Array(ids)); err != nil {
+				return fmt.Errorf("error marking events notified: %w", err)
+			}
+		}
+		d.Events = len(ids)
+		return nil
+	})
+	if err != nil {
+		return model.Dispatched{}, err
+	}
+
+	return d, nil
+}
+
+// notify notifies the users concerned by a task event and returns how many it notified.
+//
+// The creator of a task and every user it is assigned to start watching it. Assignees
+// are notified of their assignment, users mentioned in new task content or in a comment
+// are notified of the mention, and watchers are notified of comments and changes. The
+// user who caused the event, users who can no longer see the project and users whose
+// preferences turn the reason off are left out.
+func notify(ctx context.Context, tx *sqlx.Tx, e model.TaskEvent, now time.Time) (int, error) {
+	var t model.Task
+
+	stmt := `select task_id, tenant_id, project_id, key, title from tasks where task_id = $1`
+	err := tx.QueryRowxContext(ctx, stmt, e.TaskID).Scan(&t.ID, &t.TenantID, &t.ProjectID, &t.Key, &t.Title)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// The task was purged since.
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	recipients := make(map[string]string)
+	add := func(userID, reason string) {
+		if userID == "" || userID == e.UserID {
+			return
+		}
+		if current, ok := recipients[userID]; ok && reasonOrder[current] <= reasonOrder[reason] {
+			return
+		}
+		recipients[userID] = reason
+	}
+
+	if e.Kind == model.TaskCreated {
+		if err = watch(ctx, tx, t, e.UserID, now); err != nil {
+			return 0, err
+		}
+	}
+	if assignee, _ := e.Changes["assignedTo"].To.(string); assignee != "" {
+		if err = watch(ctx, tx, t, assignee, now); err != nil {
+			return 0, err
+		}
+		add(assignee, model.ReasonAssigned)
+	}
+
+	mentioned, err := mentionsOf(ctx, tx, e)
+	if err != nil {
+		return 0, err
+	}
+	for _, userID := range mentioned {
+		add(userID, model.ReasonMentioned)
+	}
+
+	var watchers []string
+	if err = tx.SelectContext(ctx, &watchers, `select user_id from task_watchers where task_id = $1`, t.ID); err != nil {
+		return 0, fmt.Errorf("error selecting watchers of task %s: %w", t.ID, err)
+	}
+	reason := model.ReasonChanged
+	if e.Kind == model.TaskCommented {
+		reason = model.ReasonCommented
+	}
+	for _, userID := range watchers {
+		add(userID, reason)
+	}
+
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+
+	users := make([]string, 0, len(recipients))
+	for userID := range recipients {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+
+	var members []string
+	stmt = `select u from unnest($2::text[]) u where project_role($1, u) is not null`
+	if err = tx.SelectContext(ctx, &members, stmt, t.ProjectID, pq.Array(users)); err != nil {
+		return 0, fmt.Errorf("error selecting project members: %w", err)
+	}
+
+	count := 0
+	for _, userID := range members {
+		prefs, err := preferencesOf(ctx, tx, userID)
+		if err != nil {
+			return 0, err
+		}
+		if !prefs.Allows(recipients[userID]) {
+			continue
+		}
+
+		stmt = `
+			insert into notifications (
+				notification_id, tenant_id, user_id, reason, event_id, event_kind, actor_id,
+				project_id, task_id, task_key, task_title, created_at
+			) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			on conflict (event_id, user_id) do nothing
+		`
+		res, err := tx.ExecContext(
+			ctx,
+			stmt,
+			uuid.New().String(),
+			t.TenantID,
+			userID,
+			recipients[userID],
+			e.ID,
+			e.Kind,
+			e.UserID,
+			t.ProjectID,
+			t.ID,
+			t.Key,
+			t.Title,
+			e.CreatedAt,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("error inserting notification: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		count += int(n)
+	}
+
+	return count, nil
+}
+
+// mentionsOf returns the users newly mentioned by a task event: those mentioned in the
+// content of a task but not in its previous content, or those mentioned in a comment.
+func mentionsOf(ctx context.Context, tx *sqlx.Tx, e model.TaskEvent) ([]string, error) {
+	if c, ok := e.Changes["content"]; ok {
+		from, _ := c.From.(string)
+		to, _ := c.To.(string)
+
+		before := make(map[string]bool)
+		for _, userID := range model.Mentions(from) {
+			before[userID] = true
+		}
+
+		var users []string
+		for _, userID := range model.Mentions(to) {
+			if !before[userID] {
+				users = append(users, userID)
+			}
+		}
+		return users, nil
+	}
+
+	if e.Kind == model.TaskCommented {
+		var content string
+
+		cid, _ := e.Changes["commentId"].To.(string)
+		err := tx.QueryRowxContext(ctx, `select content from comments where comment_id = $1`, cid).Scan(&content)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				// The comment was deleted since.
+				return nil, nil
+			}
+			return nil, err
+		}
+		return model.Mentions(content), nil
+	}
+
+	return nil, nil
+}
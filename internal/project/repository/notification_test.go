@@ -0,0 +1,174 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNotificationRepository(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	columnID := testTasks[0].ColumnID
+
+	editorID := uuid.New().String()
+	viewerID := uuid.New().String()
+	outsiderID := uuid.New().String()
+
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	editor := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: editorID})
+	viewer := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: viewerID})
+	outsider := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: outsiderID})
+	dispatcher := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewNotificationRepository(zap.NewNop(), db)
+	watcherRepo := repository.NewWatcherRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	commentRepo := repository.NewCommentRepository(zap.NewNop(), db)
+	memberRepo := repository.NewMemberRepository(zap.NewNop(), db)
+
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+
+	_, err := memberRepo.Set(owner, project.ID, editorID, model.RoleEditor, now)
+	require.NoError(t, err)
+	_, err = memberRepo.Set(owner, project.ID, viewerID, model.RoleViewer, now)
+	require.NoError(t, err)
+
+	task, err := taskRepo.Create(owner, model.NewTask{Title: "Ship notifications"}, project.ID, columnID, now)
+	require.NoError(t, err)
+
+	// Pending events of the fixtures are dispatched along the way.
+	_, err = repo.Dispatch(dispatcher, 1000, now)
+	require.NoError(t, err)
+
+	t.Run("watch", func(t *testing.T) {
+		// The creator watches the task once its creation is dispatched.
+		list, err := watcherRepo.List(viewer, task.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, project.UserID, list[0].UserID)
+
+		_, err = watcherRepo.Watch(viewer, task.ID, now)
+		require.NoError(t, err)
+		_, err = watcherRepo.Watch(viewer, task.ID, now)
+		require.NoError(t, err)
+
+		_, err = watcherRepo.Watch(outsider, task.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		list, err = watcherRepo.List(owner, task.ID)
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+	})
+
+	t.Run("assign and mention", func(t *testing.T) {
+		content := "@" + viewerID + " and @" + outsiderID + " please review"
+		_, err := taskRepo.Update(owner, task.ID, model.UpdateTask{AssignedTo: aws.String(editorID), Content: aws.String(content)}, now.Add(time.Minute))
+		require.NoError(t, err)
+
+		d, err := repo.Dispatch(dispatcher, 1000, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, d.Events)
+		// The outsider cannot see the project and the owner made the change.
+		assert.Equal(t, 2, d.Notifications)
+
+		got, err := repo.List(editor, model.InboxPage{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got.Notifications, 1)
+		assert.Equal(t, model.ReasonAssigned, got.Notifications[0].Reason)
+		assert.Equal(t, project.UserID, got.Notifications[0].ActorID)
+		assert.Equal(t, task.Key, got.Notifications[0].TaskKey)
+		assert.Equal(t, 1, got.Unread)
+
+		// The viewer watches the task too but the mention is more specific.
+		got, err = repo.List(viewer, model.InboxPage{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got.Notifications, 1)
+		assert.Equal(t, model.ReasonMentioned, got.Notifications[0].Reason)
+
+		// Dispatching again finds nothing pending.
+		d, err = repo.Dispatch(dispatcher, 1000, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, model.Dispatched{}, d)
+
+		// The assignee now watches the task.
+		list, err := watcherRepo.List(owner, task.ID)
+		require.NoError(t, err)
+		assert.Len(t, list, 3)
+	})
+
+	t.Run("preferences", func(t *testing.T) {
+		prefs, err := repo.Preferences(viewer)
+		require.NoError(t, err)
+		assert.Equal(t, model.DefaultNotificationPreferences(viewerID), prefs)
+
+		prefs, err = repo.UpdatePreferences(viewer, model.UpdateNotificationPreferences{Commented: aws.Bool(false)}, now)
+		require.NoError(t, err)
+		assert.False(t, prefs.Commented)
+		assert.True(t, prefs.Mentioned)
+
+		_, err = commentRepo.Create(editor, model.NewComment{Content: "Done, @" + project.UserID}, task.ID, now.Add(2*time.Minute))
+		require.NoError(t, err)
+
+		d, err := repo.Dispatch(dispatcher, 1000, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		// The viewer turned comments off.
+		assert.Equal(t, 1, d.Notifications)
+
+		got, err := repo.List(owner, model.InboxPage{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got.Notifications, 1)
+		assert.Equal(t, model.ReasonMentioned, got.Notifications[0].Reason)
+		assert.Equal(t, model.TaskCommented, got.Notifications[0].EventKind)
+
+		got, err = repo.List(viewer, model.InboxPage{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, got.Notifications, 1)
+	})
+
+	t.Run("read", func(t *testing.T) {
+		got, err := repo.List(viewer, model.InboxPage{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got.Notifications, 1)
+		nid := got.Notifications[0].ID
+
+		_, err = repo.Update(editor, nid, model.UpdateNotification{Read: aws.Bool(true)}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		n, err := repo.Update(viewer, nid, model.UpdateNotification{Read: aws.Bool(true)}, now)
+		require.NoError(t, err)
+		require.NotNil(t, n.ReadAt)
+
+		got, err = repo.List(viewer, model.InboxPage{Unread: true, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, got.Notifications)
+		assert.Equal(t, 0, got.Unread)
+
+		n, err = repo.Update(viewer, nid, model.UpdateNotification{Read: aws.Bool(false)}, now)
+		require.NoError(t, err)
+		assert.Nil(t, n.ReadAt)
+
+		read, err := repo.ReadAll(viewer, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, read)
+	})
+
+	t.Run("unwatch", func(t *testing.T) {
+		require.NoError(t, watcherRepo.Unwatch(viewer, task.ID))
+		assert.Equal(t, fail.ErrNotFound, watcherRepo.Unwatch(viewer, task.ID))
+	})
+}
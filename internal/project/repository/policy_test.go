@@ -0,0 +1,99 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestColumnPolicies(t *testing.T) {
+	// The fixture tasks are in the To Do column of the second project.
+	project := testProjects[1]
+	inProgress := "aae5b0b4-3c31-4019-a1b5-783c01de6884"
+
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	editorID := uuid.New().String()
+	editor := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: editorID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	columnRepo := repository.NewColumnRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	memberRepo := repository.NewMemberRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	_, err := memberRepo.Set(owner, project.ID, editorID, model.RoleEditor, now)
+	require.NoError(t, err)
+
+	t.Run("only owners set policies", func(t *testing.T) {
+		policies := model.UpdateColumn{WIPLimit: aws.Int(1), EntryRules: []string{model.RuleAssignee}}
+
+		_, err := columnRepo.Update(editor, inProgress, policies, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		c, err := columnRepo.Update(owner, inProgress, policies, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *c.WIPLimit)
+		assert.Equal(t, []string{model.RuleAssignee}, c.EntryRules)
+	})
+
+	t.Run("entry rules", func(t *testing.T) {
+		_, err := taskRepo.Move(editor, testTasks[0].ID, model.MoveTask{To: inProgress}, now)
+
+		var pe *fail.PolicyError
+		require.True(t, errors.As(err, &pe))
+		assert.True(t, errors.Is(err, fail.ErrColumnPolicy))
+		require.Len(t, pe.Violations, 1)
+		assert.Equal(t, model.RuleAssignee, pe.Violations[0].Policy)
+
+		_, err = taskRepo.Update(editor, testTasks[0].ID, model.UpdateTask{AssignedTo: aws.String(editorID)}, now)
+		require.NoError(t, err)
+
+		task, err := taskRepo.Move(editor, testTasks[0].ID, model.MoveTask{To: inProgress}, now)
+		require.NoError(t, err)
+		assert.Equal(t, inProgress, task.ColumnID)
+	})
+
+	t.Run("wip limit", func(t *testing.T) {
+		_, err := taskRepo.Update(editor, testTasks[1].ID, model.UpdateTask{AssignedTo: aws.String(editorID)}, now)
+		require.NoError(t, err)
+
+		_, err = taskRepo.Move(editor, testTasks[1].ID, model.MoveTask{To: inProgress}, now)
+		var pe *fail.PolicyError
+		require.True(t, errors.As(err, &pe))
+		require.Len(t, pe.Violations, 1)
+		assert.Equal(t, model.PolicyWIPLimit, pe.Violations[0].Policy)
+
+		_, err = taskRepo.Create(editor, model.NewTask{Title: "Squeezed in"}, project.ID, inProgress, now)
+		require.True(t, errors.As(err, &pe))
+		assert.Len(t, pe.Violations, 2)
+	})
+
+	t.Run("owners override", func(t *testing.T) {
+		override := model.MoveTask{To: inProgress, Override: true}
+
+		_, err := taskRepo.Move(editor, testTasks[1].ID, override, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		_, err = taskRepo.Move(owner, testTasks[1].ID, override, now)
+		require.NoError(t, err)
+
+		c, err := columnRepo.Retrieve(owner, inProgress)
+		require.NoError(t, err)
+		assert.Equal(t, 2, c.TaskCount)
+	})
+}
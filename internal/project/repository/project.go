@@ -41,89 +41,121 @@ func (pr *ProjectRepository) RunTx(ctx context.Context, fn func(*sqlx.Tx) error)
 	return pr.runTx(ctx, fn)
 }
 
-// Retrieve retrieves an owned project from the database.
+// selectProject selects projects with their lifecycle. A project is active while it
+// is not archived.
+const selectProject = `
+	select
+		project_id, tenant_id, name, prefix, coalesce(description, ''), user_id,
+		archived_at is null as active, "public", coalesce(team_id, ''), archived_at, deleted_at,
+		column_order, updated_at, created_at
+	from projects
+`
+
+func scanProject(row interface{ Scan(...interface{}) error }) (model.Project, error) {
+	var p model.Project
+
+	err := row.Scan(
+		&p.ID,
+		&p.TenantID,
+		&p.Name,
+		&p.Prefix,
+		&p.Description,
+		&p.UserID,
+		&p.Active,
+		&p.Public,
+		&p.TeamID,
+		&p.ArchivedAt,
+		&p.DeletedAt,
+		(*pq.StringArray)(&p.ColumnOrder),
+		&p.UpdatedAt,
+		&p.CreatedAt,
+	)
+	if err != nil {
+		return p, err
+	}
+
+	p.ArchivedAt = utc(p.ArchivedAt)
+	p.DeletedAt = utc(p.DeletedAt)
+	p.CreatedAt = p.CreatedAt.UTC()
+	p.UpdatedAt = p.UpdatedAt.UTC()
+
+	return p, nil
+}
+
+// Retrieve retrieves a project the user can see from the database, unless it is in the
+// trash.
 func (pr *ProjectRepository) Retrieve(ctx context.Context, pid string) (model.Project, error) {
 	var (
 		p   model.Project
 		err error
 	)
 
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return p, web.CtxErr()
+	}
+
 	if _, err = uuid.Parse(pid); err != nil {
 		return p, fail.ErrInvalidID
 	}
 
-	conn, Close, err := pr.pg.GetConnection(ctx)
+	conn, Close, err := pr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return p, err
 	}
 	defer Close()
 
-	stmt := `
-			select 
-				project_id, tenant_id, name, prefix, description,
-				user_id, active, "public", column_order, updated_at, created_at
-			from projects
-			where project_id = $1
-		`
-	row := conn.QueryRowxContext(ctx, stmt, pid)
-	if err = row.Scan(
-		&p.ID,
-		&p.TenantID,
-		&p.Name,
-		&p.Prefix,
-		&p.Description,
-		&p.UserID,
-		&p.Active,
-		&p.Public,
-		(*pq.StringArray)(&p.ColumnOrder),
-		&p.UpdatedAt,
-		&p.CreatedAt,
-	); err != nil {
+	stmt := selectProject + ` where project_id = $1 and deleted_at is null and project_role(project_id, $2) is not null`
+
+	p, err = scanProject(conn.QueryRowxContext(ctx, stmt, pid, values.UserID))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return p, fail.ErrNotFound
 		}
 		return p, err
 	}
 
-	p.CreatedAt = p.CreatedAt.UTC()
-	p.UpdatedAt = p.UpdatedAt.UTC()
-
 	return p, nil
 }
 
-// List lists a user's projects in the database.
-func (pr *ProjectRepository) List(ctx context.Context) ([]model.Project, error) {
+// List lists the projects the user can see, leaving out archived projects unless
+// archived is set. Projects in the trash are never listed.
+func (pr *ProjectRepository) List(ctx context.Context, archived bool) ([]model.Project, error) {
 	var p model.Project
 	var ps = make([]model.Project, 0)
 
-	conn, Close, err := pr.pg.GetConnection(ctx)
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return ps, web.CtxErr()
+	}
+
+	conn, Close, err := pr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return ps, err
 	}
 	defer Close()
 
-	stmt := `select * from projects`
+	stmt := selectProject + ` where deleted_at is null and ($1 or archived_at is null) and project_role(project_id, $2) is not null`
 
-	rows, err := conn.QueryxContext(ctx, stmt)
+	rows, err := conn.QueryxContext(ctx, stmt, archived, values.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("error selecting projects :%w", err)
 	}
+	defer rows.Close()
+
 	for rows.Next() {
-		err = rows.Scan(&p.ID, &p.TenantID, &p.Name, &p.Prefix, &p.Description, &p.UserID, &p.Active, &p.Public, (*pq.StringArray)(&p.ColumnOrder), &p.UpdatedAt, &p.CreatedAt)
+		p, err = scanProject(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row into struct :%w", err)
 		}
 
-		p.CreatedAt = p.CreatedAt.UTC()
-		p.UpdatedAt = p.UpdatedAt.UTC()
-
 		ps = append(ps, p)
 	}
 
-	return ps, nil
+	return ps, rows.Err()
 }
 
-// Create creates a project in the database.
+// Create creates a project in the database, owned by the user.
 func (pr *ProjectRepository) Create(ctx context.Context, np model.NewProject, now time.Time) (model.Project, error) {
 	var (
 		p   model.Project
@@ -135,12 +167,6 @@ func (pr *ProjectRepository) Create(ctx context.Context, np model.NewProject, no
 		return p, web.CtxErr()
 	}
 
-	conn, Close, err := pr.pg.GetConnection(ctx)
-	if err != nil {
-		return p, err
-	}
-	defer Close()
-
 	if _, err = uuid.Parse(values.UserID); err != nil {
 		return p, fail.ErrInvalidID
 	}
@@ -157,45 +183,57 @@ func (pr *ProjectRepository) Create(ctx context.Context, np model.NewProject, no
 		CreatedAt:   now.Round(time.Microsecond).UTC(),
 	}
 
-	stmt := `
+	err = pr.RunTx(ctx, func(tx *sqlx.Tx) error {
+		stmt := `
 			insert into projects (
 				project_id, tenant_id, name, prefix,
 				description, user_id, column_order, updated_at, created_at
 			) values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			`
-	if _, err = conn.ExecContext(
-		ctx,
-		stmt,
-		p.ID,
-		p.TenantID,
-		p.Name,
-		p.Prefix,
-		"",
-		values.UserID,
-		pq.Array(p.ColumnOrder),
-		p.UpdatedAt,
-		p.CreatedAt,
-	); err != nil {
-		return p, fmt.Errorf("error inserting project: %+v :%w", p, err)
+		if _, err := tx.ExecContext(
+			ctx,
+			stmt,
+			p.ID,
+			p.TenantID,
+			p.Name,
+			p.Prefix,
+			"",
+			values.UserID,
+			pq.Array(p.ColumnOrder),
+			p.UpdatedAt,
+			p.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("error inserting project: %+v :%w", p, err)
+		}
+
+		return addOwner(ctx, tx, p)
+	})
+	if err != nil {
+		return model.Project{}, err
 	}
 
 	return p, nil
 }
 
-// Update updates a project in the database.
+// Update updates a project in the database. Editors update the project, while only
+// owners decide whether it is public.
 func (pr *ProjectRepository) Update(ctx context.Context, pid string, update model.UpdateProject, now time.Time) (model.Project, error) {
 	var (
 		p   model.Project
 		err error
 	)
 
-	conn, Close, err := pr.pg.GetConnection(ctx)
-	if err != nil {
-		return p, err
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return p, web.CtxErr()
 	}
-	defer Close()
 
-	p, err = pr.Retrieve(ctx, pid)
+	required := model.RoleEditor
+	if update.Public != nil {
+		required = model.RoleOwner
+	}
+
+	p, err = pr.Retrieve(db.Primary(ctx), pid)
 	if err != nil {
 		return p, err
 	}
@@ -206,48 +244,73 @@ func (pr *ProjectRepository) Update(ctx context.Context, pid string, update mode
 	if update.Description != nil {
 		p.Description = *update.Description
 	}
-	if update.Active != nil {
+	if update.Active != nil && *update.Active != p.Active {
 		p.Active = *update.Active
+		p.ArchivedAt = nil
+		if !p.Active {
+			at := now.Round(time.Microsecond).UTC()
+			p.ArchivedAt = &at
+		}
 	}
 	if update.Public != nil {
 		p.Public = *update.Public
 	}
-	if update.ColumnOrder != nil {
-		p.ColumnOrder = update.ColumnOrder
-	}
 
-	stmt := `
+	err = pr.RunTx(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, required); err != nil {
+			return err
+		}
+
+		order, err := lockColumnOrder(ctx, tx, pid)
+		if err != nil {
+			return err
+		}
+		p.ColumnOrder = order
+
+		if update.ColumnOrder != nil {
+			if err = checkColumnOrder(ctx, tx, pid, update.ColumnOrder); err != nil {
+				return err
+			}
+			p.ColumnOrder = update.ColumnOrder
+		}
+
+		stmt := `
 			update projects
 			set 
 			    name = $1,
 			    description = $2,
-				active = $3,
+				archived_at = $3,
 				public = $4,
 				column_order = $5,
 				updated_at = $6
 			where project_id = $7
 			`
 
-	_, err = conn.ExecContext(
-		ctx,
-		stmt,
-		p.Name,
-		p.Description,
-		p.Active,
-		p.Public,
-		pq.Array(p.ColumnOrder),
-		now.Round(time.Microsecond).UTC(),
-		pid,
-	)
+		if _, err = tx.ExecContext(
+			ctx,
+			stmt,
+			p.Name,
+			p.Description,
+			p.ArchivedAt,
+			p.Public,
+			pq.Array(p.ColumnOrder),
+			now.Round(time.Microsecond).UTC(),
+			pid,
+		); err != nil {
+			return fmt.Errorf("error updating project :%w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return p, fmt.Errorf("error updating project :%w", err)
+		return p, err
 	}
 
 	return p, nil
 }
 
-// Delete deletes a project, its columns and tasks from the database.
-func (pr *ProjectRepository) Delete(ctx context.Context, pid string) error {
+// Delete moves a project the user owns to the trash. Its board stays in the
+// database until the project is restored or purged.
+func (pr *ProjectRepository) Delete(ctx context.Context, pid string, now time.Time) error {
 	var err error
 
 	values, ok := web.FromContext(ctx)
@@ -263,34 +326,190 @@ func (pr *ProjectRepository) Delete(ctx context.Context, pid string) error {
 		return fail.ErrInvalidID
 	}
 
-	conn, Close, err := pr.pg.GetConnection(ctx)
+	return pr.RunTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockOwnedProject(ctx, tx, pid, values.UserID, false); err != nil {
+			return err
+		}
+
+		stmt := `update projects set deleted_at = $1 where project_id = $2`
+		if _, err := tx.ExecContext(ctx, stmt, now.Round(time.Microsecond).UTC(), pid); err != nil {
+			return fmt.Errorf("error deleting project %s :%w", pid, err)
+		}
+		return nil
+	})
+}
+
+// Restore takes a project the user owns out of the trash.
+func (pr *ProjectRepository) Restore(ctx context.Context, pid string, now time.Time) (model.Project, error) {
+	var err error
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Project{}, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return model.Project{}, fail.ErrInvalidID
+	}
+
+	err = pr.RunTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockOwnedProject(ctx, tx, pid, values.UserID, true); err != nil {
+			return err
+		}
+
+		stmt := `update projects set deleted_at = null, updated_at = $1 where project_id = $2`
+		if _, err := tx.ExecContext(ctx, stmt, now.Round(time.Microsecond).UTC(), pid); err != nil {
+			return fmt.Errorf("error restoring project %s :%w", pid, err)
+		}
+		return nil
+	})
 	if err != nil {
+		return model.Project{}, err
+	}
+
+	return pr.Retrieve(db.Primary(ctx), pid)
+}
+
+// lockOwnedProject locks a project in or out of the trash, as deleted says, and checks
+// the user owns it. Projects the user cannot see are not found.
+func lockOwnedProject(ctx context.Context, tx *sqlx.Tx, pid string, userID string, deleted bool) error {
+	var role sql.NullString
+
+	stmt := `select project_role(project_id, $3) from projects where project_id = $1 and (deleted_at is not null) = $2 for update`
+	if err := tx.QueryRowxContext(ctx, stmt, pid, deleted, userID).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
 		return err
 	}
-	defer Close()
+	if !role.Valid {
+		return fail.ErrNotFound
+	}
+	if role.String != model.RoleOwner {
+		return fail.ErrNotAuthorized
+	}
 
-	stmt := `delete from tasks where project_id = $1`
+	return nil
+}
 
-	_, err = conn.ExecContext(ctx, stmt, pid)
-	if err != nil {
-		return fmt.Errorf("error deleting tasks %s :%w", pid, err)
+// addOwner makes the creator of a new project its owner.
+func addOwner(ctx context.Context, tx *sqlx.Tx, p model.Project) error {
+	stmt := `
+		insert into project_members (project_id, user_id, tenant_id, role, updated_at, created_at)
+		values ($1, $2, $3, 'owner', $4, $4)
+	`
+	if _, err := tx.ExecContext(ctx, stmt, p.ID, p.UserID, p.TenantID, p.CreatedAt); err != nil {
+		return fmt.Errorf("error inserting project owner :%w", err)
 	}
+	return nil
+}
 
-	stmt = `delete from columns where project_id = $1`
+// Trash lists the projects in the trash and the tasks in the trash of other projects,
+// most recently deleted first. Only projects the user can see are listed.
+func (pr *ProjectRepository) Trash(ctx context.Context) (model.Trash, error) {
+	var (
+		trash = model.Trash{Projects: make([]model.Project, 0), Tasks: make([]model.Task, 0)}
+		err   error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return trash, web.CtxErr()
+	}
 
-	_, err = conn.ExecContext(ctx, stmt, pid)
+	conn, Close, err := pr.pg.GetReadConnection(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting columns %s :%w", pid, err)
+		return trash, err
 	}
+	defer Close()
 
-	stmt = `delete from projects where project_id = $1 and user_id = $2`
+	stmt := selectProject + ` where deleted_at is not null and project_role(project_id, $1) is not null order by deleted_at desc`
 
-	_, err = conn.ExecContext(ctx, stmt, pid, values.UserID)
+	rows, err := conn.QueryxContext(ctx, stmt, values.UserID)
 	if err != nil {
-		return fmt.Errorf("error deleting project %s :%w", pid, err)
+		return trash, fmt.Errorf("error selecting deleted projects :%w", err)
+	}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			rows.Close()
+			return trash, fmt.Errorf("error scanning row into struct :%w", err)
+		}
+		trash.Projects = append(trash.Projects, p)
 	}
+	rows.Close()
+
+	stmt = selectTask + `
+		where deleted_at is not null
+			and exists(select 1 from projects p where p.project_id = tasks.project_id and p.deleted_at is null)
+			and project_role(project_id, $1) is not null
+		order by deleted_at desc
+	`
+	rows, err = conn.QueryxContext(ctx, stmt, values.UserID)
+	if err != nil {
+		return trash, fmt.Errorf("error selecting deleted tasks :%w", err)
+	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return trash, fmt.Errorf("error scanning row into struct :%w", err)
+		}
+		trash.Tasks = append(trash.Tasks, t)
+	}
+
+	return trash, rows.Err()
+}
+
+// Purge permanently deletes the projects and tasks of the tenant that were moved to the
+// trash before the given time, with everything that belongs to them.
+func (pr *ProjectRepository) Purge(ctx context.Context, before time.Time) (model.Purged, error) {
+	var purged model.Purged
+
+	err := pr.RunTx(ctx, func(tx *sqlx.Tx) error {
+		var pids []string
+
+		stmt := `select project_id from projects where deleted_at < $1`
+		if err := tx.SelectContext(ctx, &pids, stmt, before.UTC()); err != nil {
+			return fmt.Errorf("error selecting expired projects :%w", err)
+		}
+
+		stmt = `delete from tasks where deleted_at < $1 or project_id = any($2)`
+		res, err := tx.ExecContext(ctx, stmt, before.UTC(), pq.Array(pids))
+		if err != nil {
+			return fmt.Errorf("error purging tasks :%w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		purged.Tasks = int(n)
+
+		if len(pids) == 0 {
+			return nil
+		}
+
+		stmt = `delete from task_events where project_id = any($1)`
+		if _, err = tx.ExecContext(ctx, stmt, pq.Array(pids)); err != nil {
+			return fmt.Errorf("error purging task events :%w", err)
+		}
+
+		stmt = `delete from columns where project_id = any($1)`
+		if _, err = tx.ExecContext(ctx, stmt, pq.Array(pids)); err != nil {
+			return fmt.Errorf("error purging columns :%w", err)
+		}
+
+		stmt = `delete from projects where project_id = any($1)`
+		if _, err = tx.ExecContext(ctx, stmt, pq.Array(pids)); err != nil {
+			return fmt.Errorf("error purging projects :%w", err)
+		}
+		purged.Projects = len(pids)
+
+		return nil
+	})
+
+	return purged, err
 }
 
 // formatPrefix generates a project prefix.
@@ -302,5 +521,7 @@ func formatPrefix(str string) string {
 	s := re.ReplaceAllString(str, substitution)
 	s = strings.ToUpper(s)
 
-	return s[:3] + "-"
+	// Short names, such as imported ones, are padded rather than sliced out of range.
+	r := []rune(s + "XXX")
+	return string(r[:3]) + "-"
 }
@@ -96,7 +96,7 @@ func TestProjectRepository_Retrieve(t *testing.T) {
 		{
 			name:      "success",
 			projectID: expectedProject.ID,
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, ctx context.Context, expected model.Project, actual model.Project, err error) {
 				assert.Nil(t, err)
 				assert.Equal(t, expected, actual)
@@ -105,7 +105,7 @@ func TestProjectRepository_Retrieve(t *testing.T) {
 		{
 			name:      "project id not UUID",
 			projectID: "mock",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, ctx context.Context, expected model.Project, actual model.Project, err error) {
 				assert.NotNil(t, err)
 				assert.Equal(t, fail.ErrInvalidID, err)
@@ -122,10 +122,18 @@ func TestProjectRepository_Retrieve(t *testing.T) {
 				assert.NotEqual(t, expected, actual)
 			},
 		},
+		{
+			name:      "not a member",
+			projectID: expectedProject.ID,
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testutils.MockUUID}),
+			expectations: func(t *testing.T, ctx context.Context, expected model.Project, actual model.Project, err error) {
+				assert.Equal(t, fail.ErrNotFound, err)
+			},
+		},
 		{
 			name:      "not found",
 			projectID: testutils.MockUUID,
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, ctx context.Context, expected model.Project, actual model.Project, err error) {
 				assert.NotNil(t, err)
 				assert.Equal(t, fail.ErrNotFound, err)
@@ -175,12 +183,20 @@ func TestProjectRepository_List(t *testing.T) {
 	}{
 		{
 			name: "success",
-			ctx:  web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:  web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			expectations: func(t *testing.T, ctx context.Context, expected []model.Project, actual []model.Project, err error) {
 				assert.Nil(t, err)
 				assert.ElementsMatch(t, expected, actual)
 			},
 		},
+		{
+			name: "not a member",
+			ctx:  web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testutils.MockUUID}),
+			expectations: func(t *testing.T, ctx context.Context, expected []model.Project, actual []model.Project, err error) {
+				assert.Nil(t, err)
+				assert.Empty(t, actual)
+			},
+		},
 		{
 			name: "data isolation between tenants",
 			ctx:  web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID}),
@@ -214,7 +230,7 @@ func TestProjectRepository_List(t *testing.T) {
 			defer Close()
 
 			repo := repository.NewProjectRepository(zap.NewNop(), db)
-			projects, err := repo.List(tc.ctx)
+			projects, err := repo.List(tc.ctx, false)
 			tc.expectations(t, tc.ctx, expectedProjects, projects, err)
 		})
 	}
@@ -233,7 +249,7 @@ func TestProjectRepository_Update(t *testing.T) {
 	}{
 		{
 			name:      "successfully updated project name",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			projectID: expectedProject.ID,
 			update: model.UpdateProject{
 				Name: aws.String("Updated"),
@@ -247,7 +263,7 @@ func TestProjectRepository_Update(t *testing.T) {
 		},
 		{
 			name:      "successfully updated project description",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			projectID: expectedProject.ID,
 			update: model.UpdateProject{
 				Description: aws.String("Updated"),
@@ -261,7 +277,7 @@ func TestProjectRepository_Update(t *testing.T) {
 		},
 		{
 			name:      "successfully updated project active field",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			projectID: expectedProject.ID,
 			update: model.UpdateProject{
 				Active: aws.Bool(false),
@@ -269,13 +285,16 @@ func TestProjectRepository_Update(t *testing.T) {
 			expectations: func(t *testing.T, ctx context.Context, expected model.Project, actual model.Project, err error) {
 				assert.Nil(t, err)
 				assert.NotEqual(t, expectedProject, actual)
+				assert.False(t, actual.Active)
+				assert.NotNil(t, actual.ArchivedAt)
 				expected.Active = actual.Active
+				expected.ArchivedAt = actual.ArchivedAt
 				assert.Equal(t, expected, actual)
 			},
 		},
 		{
 			name:      "successfully updated project public field",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			projectID: expectedProject.ID,
 			update: model.UpdateProject{
 				Public: aws.Bool(true),
@@ -289,7 +308,7 @@ func TestProjectRepository_Update(t *testing.T) {
 		},
 		{
 			name:      "successfully updated project column order",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			projectID: expectedProject.ID,
 			update: model.UpdateProject{
 				ColumnOrder: []string{"column-4", "column-3", "column-2", "column-1"},
@@ -303,7 +322,7 @@ func TestProjectRepository_Update(t *testing.T) {
 		},
 		{
 			name:      "project id not UUID",
-			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testProjects[0].UserID}),
 			projectID: "mock",
 			update:    model.UpdateProject{},
 			expectations: func(t *testing.T, ctx context.Context, expected model.Project, actual model.Project, err error) {
@@ -367,6 +386,24 @@ func TestProjectRepository_Delete(t *testing.T) {
 				assert.Equal(t, fail.ErrNotFound, err)
 			},
 		},
+		{
+			name:      "not the owner",
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: testutils.MockUUID}),
+			projectID: expectedProject.ID,
+			expectations: func(t *testing.T, ctx context.Context, repo *repository.ProjectRepository, err error) {
+				assert.Equal(t, fail.ErrNotAuthorized, err)
+				_, err = repo.Retrieve(ctx, expectedProject.ID)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			name:      "project not found",
+			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedProject.UserID}),
+			projectID: testutils.MockUUID,
+			expectations: func(t *testing.T, ctx context.Context, repo *repository.ProjectRepository, err error) {
+				assert.Equal(t, fail.ErrNotFound, err)
+			},
+		},
 		{
 			name:      "user id not UUID",
 			ctx:       web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: "mock"}),
@@ -411,7 +448,7 @@ func TestProjectRepository_Delete(t *testing.T) {
 			defer Close()
 
 			repo := repository.NewProjectRepository(zap.NewNop(), db)
-			err := repo.Delete(tc.ctx, tc.projectID)
+			err := repo.Delete(tc.ctx, tc.projectID, time.Now())
 			tc.expectations(t, tc.ctx, repo, err)
 		})
 	}
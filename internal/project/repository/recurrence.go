@@ -0,0 +1,537 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/rank"
+	"github.com/devpies/saas-core/internal/project/rrule"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// RecurrenceRepository manages data access to recurring tasks.
+type RecurrenceRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewRecurrenceRepository returns a new RecurrenceRepository.
+func NewRecurrenceRepository(logger *zap.Logger, pg *db.PostgresDatabase) *RecurrenceRepository {
+	return &RecurrenceRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectRecurrence = `
+	select
+		recurrence_id, tenant_id, project_id, column_id, title, content, priority, points, assigned_to,
+		rule, starts_at, next_run_at, last_run_at, paused, user_id, updated_at, created_at
+	from recurring_tasks
+`
+
+func utcRecurrence(r model.RecurringTask) model.RecurringTask {
+	r.StartsAt = r.StartsAt.UTC()
+	r.NextRunAt = utc(r.NextRunAt)
+	r.LastRunAt = utc(r.LastRunAt)
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	r.CreatedAt = r.CreatedAt.UTC()
+	return r
+}
+
+// nextRun returns the first occurrence of the rule of r after the given time, or nil
+// once the series has ended.
+func nextRun(r model.RecurringTask, after time.Time) (*time.Time, error) {
+	rule, err := rrule.Parse(r.Rule)
+	if err != nil {
+		return nil, err
+	}
+	next, ok := rule.Next(r.StartsAt, after)
+	if !ok {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// List lists the recurring tasks of a project.
+func (rr *RecurrenceRepository) List(ctx context.Context, pid string) ([]model.RecurringTask, error) {
+	var list = make([]model.RecurringTask, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return list, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return list, fail.ErrInvalidID
+	}
+
+	conn, Close, err := rr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return list, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleViewer); err != nil {
+		return list, err
+	}
+
+	var rs []model.RecurringTask
+	stmt := selectRecurrence + ` where project_id = $1 order by created_at`
+	if err = conn.SelectContext(ctx, &rs, stmt, pid); err != nil {
+		return list, fmt.Errorf("error selecting recurring tasks: %w", err)
+	}
+
+	for _, r := range rs {
+		list = append(list, utcRecurrence(r))
+	}
+	return list, nil
+}
+
+// Create creates a recurring task in a project. Its first run is the first occurrence
+// of its rule after now, so a series starting in the past does not create tasks for the
+// occurrences that already passed. Rules that cannot be parsed return an *rrule.Error.
+func (rr *RecurrenceRepository) Create(ctx context.Context, pid string, nr model.NewRecurringTask, now time.Time) (model.RecurringTask, error) {
+	var r model.RecurringTask
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return r, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return r, fail.ErrInvalidID
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return r, fail.ErrInvalidID
+	}
+
+	rule, err := rrule.Parse(nr.Rule)
+	if err != nil {
+		return r, err
+	}
+
+	r = model.RecurringTask{
+		ID:         uuid.New().String(),
+		TenantID:   values.TenantID,
+		ProjectID:  pid,
+		ColumnID:   nr.ColumnID,
+		Title:      nr.Title,
+		Content:    nr.Content,
+		Priority:   nr.Priority,
+		Points:     nr.Points,
+		AssignedTo: nr.AssignedTo,
+		Rule:       rule.String(),
+		StartsAt:   nr.StartsAt.Truncate(time.Second).UTC(),
+		UserID:     values.UserID,
+		UpdatedAt:  now.Round(time.Microsecond).UTC(),
+		CreatedAt:  now.Round(time.Microsecond).UTC(),
+	}
+	if r.Priority == "" {
+		r.Priority = model.PriorityNone
+	}
+	if r.NextRunAt, err = nextRun(r, now); err != nil {
+		return model.RecurringTask{}, err
+	}
+
+	err = rr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		if err := lockColumn(ctx, tx, pid, r.ColumnID); err != nil {
+			return err
+		}
+
+		stmt := `
+			insert into recurring_tasks (
+				recurrence_id, tenant_id, project_id, column_id, title, content, priority, points,
+				assigned_to, rule, starts_at, next_run_at, user_id, updated_at, created_at
+			) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`
+		_, err := tx.ExecContext(
+			ctx,
+			stmt,
+			r.ID,
+			r.TenantID,
+			r.ProjectID,
+			r.ColumnID,
+			r.Title,
+			r.Content,
+			r.Priority,
+			r.Points,
+			r.AssignedTo,
+			r.Rule,
+			r.StartsAt,
+			r.NextRunAt,
+			r.UserID,
+			r.UpdatedAt,
+			r.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("error inserting recurring task: %s: %w", nr.Title, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.RecurringTask{}, err
+	}
+
+	return r, nil
+}
+
+// Update updates a recurring task. A changed rule or start, or a resumed series, runs
+// next at its first occurrence after now.
+func (rr *RecurrenceRepository) Update(ctx context.Context, rid string, update model.UpdateRecurringTask, now time.Time) (model.RecurringTask, error) {
+	var r model.RecurringTask
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return r, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(rid); err != nil {
+		return r, fail.ErrInvalidID
+	}
+
+	err := rr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		if r, err = lockRecurrence(ctx, tx, rid, values.UserID); err != nil {
+			return err
+		}
+
+		reschedule := false
+
+		if update.ColumnID != nil && *update.ColumnID != r.ColumnID {
+			if err = lockColumn(ctx, tx, r.ProjectID, *update.ColumnID); err != nil {
+				return err
+			}
+			r.ColumnID = *update.ColumnID
+		}
+		if update.Title != nil {
+			r.Title = *update.Title
+		}
+		if update.Content != nil {
+			r.Content = *update.Content
+		}
+		if update.Priority != nil {
+			r.Priority = *update.Priority
+		}
+		if update.Points != nil {
+			r.Points = *update.Points
+		}
+		if update.AssignedTo != nil {
+			r.AssignedTo = *update.AssignedTo
+		}
+		if update.Rule != nil {
+			rule, err := rrule.Parse(*update.Rule)
+			if err != nil {
+				return err
+			}
+			r.Rule = rule.String()
+			reschedule = true
+		}
+		if update.StartsAt != nil {
+			r.StartsAt = update.StartsAt.Truncate(time.Second).UTC()
+			reschedule = true
+		}
+		if update.Paused != nil {
+			reschedule = reschedule || (r.Paused && !*update.Paused)
+			r.Paused = *update.Paused
+		}
+		if reschedule {
+			if r.NextRunAt, err = nextRun(r, now); err != nil {
+				return err
+			}
+		}
+		r.UpdatedAt = now.Round(time.Microsecond).UTC()
+
+		stmt := `
+			update recurring_tasks set
+				column_id = $1, title = $2, content = $3, priority = $4, points = $5, assigned_to = $6,
+				rule = $7, starts_at = $8, next_run_at = $9, paused = $10, updated_at = $11
+			where recurrence_id = $12
+		`
+		_, err = tx.ExecContext(
+			ctx,
+			stmt,
+			r.ColumnID,
+			r.Title,
+			r.Content,
+			r.Priority,
+			r.Points,
+			r.AssignedTo,
+			r.Rule,
+			r.StartsAt,
+			r.NextRunAt,
+			r.Paused,
+			r.UpdatedAt,
+			rid,
+		)
+		if err != nil {
+			return fmt.Errorf("error updating recurring task %s: %w", rid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.RecurringTask{}, err
+	}
+
+	return utcRecurrence(r), nil
+}
+
+// Delete deletes a recurring task. The tasks it created are kept.
+func (rr *RecurrenceRepository) Delete(ctx context.Context, rid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(rid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	return rr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := lockRecurrence(ctx, tx, rid, values.UserID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `delete from recurring_tasks where recurrence_id = $1`, rid); err != nil {
+			return fmt.Errorf("error deleting recurring task %s: %w", rid, err)
+		}
+		return nil
+	})
+}
+
+// Due lists the recurring tasks of the tenant that are due at the given time.
+func (rr *RecurrenceRepository) Due(ctx context.Context, now time.Time) ([]string, error) {
+	var ids = make([]string, 0)
+
+	conn, Close, err := rr.pg.GetConnection(ctx)
+	if err != nil {
+		return ids, err
+	}
+	defer Close()
+
+	stmt := `select recurrence_id from recurring_tasks where not paused and next_run_at <= $1 order by next_run_at`
+	if err = conn.SelectContext(ctx, &ids, stmt, now.UTC()); err != nil {
+		return ids, fmt.Errorf("error selecting due recurring tasks: %w", err)
+	}
+	return ids, nil
+}
+
+// Run creates the task of a due recurring task and schedules its next run in a single
+// transaction. After downtime only the latest occurrence that passed gets a task, and
+// the ones before it are counted as missed.
+//
+// A recurring task that another run holds or that is no longer due returns
+// fail.ErrNotFound. Runs record their occurrences, so an occurrence never creates a
+// second task. No task is created while the project is archived or in the trash, and a
+// series whose creator can no longer edit the project is paused.
+func (rr *RecurrenceRepository) Run(ctx context.Context, rid string, now time.Time) (model.RecurrenceRun, error) {
+	var run model.RecurrenceRun
+
+	now = now.UTC()
+
+	err := rr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var r model.RecurringTask
+
+		stmt := selectRecurrence + ` where recurrence_id = $1 and not paused and next_run_at <= $2 for update skip locked`
+		if err := tx.QueryRowxContext(ctx, stmt, rid, now).StructScan(&r); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return err
+		}
+		r = utcRecurrence(r)
+
+		rule, err := rrule.Parse(r.Rule)
+		if err != nil {
+			return err
+		}
+
+		run = model.RecurrenceRun{RecurrenceID: rid, OccurrenceAt: *r.NextRunAt}
+		for {
+			next, ok := rule.Next(r.StartsAt, run.OccurrenceAt)
+			if !ok || next.After(now) {
+				break
+			}
+			run.OccurrenceAt = next
+			run.Missed++
+		}
+
+		// Occurrences are skipped while the project is in the trash, so the series goes on
+		// once the project is restored.
+		var trashed bool
+		stmt = `select deleted_at is not null from projects where project_id = $1`
+		if err = tx.QueryRowxContext(ctx, stmt, r.ProjectID).Scan(&trashed); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		paused := false
+		if !trashed {
+			switch err = authorize(ctx, tx, r.ProjectID, r.UserID, model.RoleEditor); err {
+			case nil:
+				if run.Task, err = runTask(ctx, tx, r, run.OccurrenceAt, now); err != nil {
+					return err
+				}
+			case fail.ErrNotFound, fail.ErrNotAuthorized:
+				paused = true
+			default:
+				return err
+			}
+		}
+
+		var next *time.Time
+		if n, ok := rule.Next(r.StartsAt, now); ok {
+			next = &n
+		}
+
+		stmt = `update recurring_tasks set next_run_at = $1, last_run_at = $2, paused = paused or $3 where recurrence_id = $4`
+		if _, err = tx.ExecContext(ctx, stmt, next, run.OccurrenceAt, paused, rid); err != nil {
+			return fmt.Errorf("error scheduling recurring task %s: %w", rid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.RecurrenceRun{}, err
+	}
+
+	return run, nil
+}
+
+// runTask creates the task of an occurrence of a recurring task on behalf of its
+// creator. The task is placed last in the target column regardless of the policies of
+// the column, like a task created with an override. It returns nil when the occurrence
+// already has a task or the project is archived or in the trash.
+func runTask(ctx context.Context, tx *sqlx.Tx, r model.RecurringTask, occurrence time.Time, now time.Time) (*model.Task, error) {
+	var (
+		p    model.Project
+		last string
+	)
+
+	stmt := `
+		select project_id, tenant_id, prefix from projects
+		where project_id = $1 and archived_at is null and deleted_at is null
+	`
+	if err := tx.QueryRowxContext(ctx, stmt, r.ProjectID).Scan(&p.ID, &p.TenantID, &p.Prefix); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	t := model.Task{
+		ID:          uuid.New().String(),
+		Title:       r.Title,
+		TenantID:    r.TenantID,
+		Points:      r.Points,
+		UserID:      r.UserID,
+		Content:     r.Content,
+		ProjectID:   r.ProjectID,
+		ColumnID:    r.ColumnID,
+		AssignedTo:  r.AssignedTo,
+		Priority:    r.Priority,
+		Attachments: make([]string, 0),
+		Labels:      make([]model.TaskLabel, 0),
+		Fields:      make(model.FieldValues),
+		Links:       make([]model.TaskLink, 0),
+		UpdatedAt:   now.Round(time.Microsecond).UTC(),
+		CreatedAt:   now.Round(time.Microsecond).UTC(),
+	}
+
+	stmt = `
+		insert into recurrence_runs (recurrence_id, occurrence_at, tenant_id, task_id, created_at)
+		values ($1, $2, $3, $4, $5)
+		on conflict do nothing
+	`
+	res, err := tx.ExecContext(ctx, stmt, r.ID, occurrence, r.TenantID, t.ID, t.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error recording run of recurring task %s: %w", r.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, err
+	}
+
+	if err = lockColumn(ctx, tx, r.ProjectID, r.ColumnID); err != nil {
+		return nil, err
+	}
+
+	if t.Key, err = nextKey(ctx, tx, p); err != nil {
+		return nil, err
+	}
+
+	stmt = `select coalesce(max(rank), '') from tasks where column_id = $1`
+	if err = tx.QueryRowxContext(ctx, stmt, r.ColumnID).Scan(&last); err != nil {
+		return nil, err
+	}
+
+	if t.Rank, err = rank.Between(last, ""); err != nil {
+		return nil, err
+	}
+
+	stmt = `
+		insert into tasks (
+			task_id, tenant_id, key, title, content, points, priority, user_id, assigned_to,
+			attachments, project_id, column_id, rank, updated_at, created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		stmt,
+		t.ID,
+		t.TenantID,
+		t.Key,
+		t.Title,
+		t.Content,
+		t.Points,
+		t.Priority,
+		t.UserID,
+		t.AssignedTo,
+		pq.Array(t.Attachments),
+		t.ProjectID,
+		t.ColumnID,
+		t.Rank,
+		t.UpdatedAt,
+		t.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting task of recurring task %s: %w", r.ID, err)
+	}
+
+	if err = recordEvent(ctx, tx, t, t.UserID, model.TaskCreated, created(t), now); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// lockRecurrence locks a recurring task for a change, which project editors are
+// authorized to make.
+func lockRecurrence(ctx context.Context, tx *sqlx.Tx, rid string, userID string) (model.RecurringTask, error) {
+	var r model.RecurringTask
+
+	stmt := selectRecurrence + ` where recurrence_id = $1 for update`
+	if err := tx.QueryRowxContext(ctx, stmt, rid).StructScan(&r); err != nil {
+		if err == sql.ErrNoRows {
+			return r, fail.ErrNotFound
+		}
+		return r, err
+	}
+	if err := authorize(ctx, tx, r.ProjectID, userID, model.RoleEditor); err != nil {
+		return r, err
+	}
+
+	return utcRecurrence(r), nil
+}
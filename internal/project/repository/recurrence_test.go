@@ -0,0 +1,166 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/internal/project/rrule"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRecurrenceRepository(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	columnID := testTasks[0].ColumnID
+
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	viewerID := uuid.New().String()
+	viewer := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: viewerID})
+	outsider := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: uuid.New().String()})
+	scheduler := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewRecurrenceRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	memberRepo := repository.NewMemberRepository(zap.NewNop(), db)
+	projectRepo := repository.NewProjectRepository(zap.NewNop(), db)
+
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	_, err := memberRepo.Set(owner, project.ID, viewerID, model.RoleViewer, now)
+	require.NoError(t, err)
+
+	nr := model.NewRecurringTask{
+		ColumnID: columnID,
+		Title:    "Rotate certificates",
+		Priority: model.PriorityHigh,
+		Rule:     "freq=daily",
+		StartsAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	var rt model.RecurringTask
+
+	t.Run("create", func(t *testing.T) {
+		var err error
+
+		rt, err = repo.Create(owner, project.ID, nr, now)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=DAILY", rt.Rule)
+		// Occurrences before the recurring task was created are not run.
+		require.NotNil(t, rt.NextRunAt)
+		assert.Equal(t, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), *rt.NextRunAt)
+
+		_, err = repo.Create(viewer, project.ID, nr, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		_, err = repo.Create(outsider, project.ID, nr, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		invalid := nr
+		invalid.Rule = "FREQ=HOURLY"
+		_, err = repo.Create(owner, project.ID, invalid, now)
+		assert.IsType(t, &rrule.Error{}, err)
+
+		list, err := repo.List(viewer, project.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, rt.ID, list[0].ID)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		_, err := repo.Run(scheduler, rt.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		// The service was down for three days.
+		later := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+
+		due, err := repo.Due(scheduler, later)
+		require.NoError(t, err)
+		assert.Equal(t, []string{rt.ID}, due)
+
+		run, err := repo.Run(scheduler, rt.ID, later)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC), run.OccurrenceAt)
+		assert.Equal(t, 2, run.Missed)
+		require.NotNil(t, run.Task)
+		assert.Equal(t, columnID, run.Task.ColumnID)
+		assert.Equal(t, project.UserID, run.Task.UserID)
+
+		task, err := taskRepo.Retrieve(owner, run.Task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Rotate certificates", task.Title)
+		assert.Equal(t, model.PriorityHigh, task.Priority)
+		assert.Equal(t, run.Task.Key, task.Key)
+
+		// A second run at the same time finds nothing due.
+		_, err = repo.Run(scheduler, rt.ID, later)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		due, err = repo.Due(scheduler, later)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+	})
+
+	t.Run("trashed project", func(t *testing.T) {
+		require.NoError(t, projectRepo.Delete(owner, project.ID, now))
+
+		// Occurrences are skipped while the project is in the trash.
+		trashed := time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC)
+		run, err := repo.Run(scheduler, rt.ID, trashed)
+		require.NoError(t, err)
+		assert.Nil(t, run.Task)
+
+		due, err := repo.Due(scheduler, trashed)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		// The series is not paused, so it goes on once the project is restored.
+		_, err = projectRepo.Restore(owner, project.ID, now)
+		require.NoError(t, err)
+
+		restored := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)
+		run, err = repo.Run(scheduler, rt.ID, restored)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), run.OccurrenceAt)
+		require.NotNil(t, run.Task)
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		paused, err := repo.Update(owner, rt.ID, model.UpdateRecurringTask{Paused: aws.Bool(true)}, now)
+		require.NoError(t, err)
+		assert.True(t, paused.Paused)
+
+		_, err = repo.Update(viewer, rt.ID, model.UpdateRecurringTask{Paused: aws.Bool(false)}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		due, err := repo.Due(scheduler, time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		// Resuming skips the occurrences that passed while paused.
+		resumed := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+		r, err := repo.Update(owner, rt.ID, model.UpdateRecurringTask{Paused: aws.Bool(false)}, resumed)
+		require.NoError(t, err)
+		require.NotNil(t, r.NextRunAt)
+		assert.Equal(t, time.Date(2026, 3, 21, 9, 0, 0, 0, time.UTC), *r.NextRunAt)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Equal(t, fail.ErrNotAuthorized, repo.Delete(viewer, rt.ID))
+
+		require.NoError(t, repo.Delete(owner, rt.ID))
+		assert.Equal(t, fail.ErrNotFound, repo.Delete(owner, rt.ID))
+	})
+}
@@ -0,0 +1,139 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var rlsTables = []string{"projects", "columns", "tasks", "comments", "comment_likes", "task_events", "labels", "task_labels", "sprints", "task_links", "project_templates", "project_imports", "teams", "team_members", "project_members", "share_links", "saved_filters", "custom_fields", "task_field_values", "worklogs", "timers", "recurring_tasks", "recurrence_runs", "task_watchers", "notifications", "notification_preferences"}
+
+func TestRowLevelSecurity_CrossTenantReads(t *testing.T) {
+	otherTenant := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	projectRepo := repository.NewProjectRepository(zap.NewNop(), db)
+	columnRepo := repository.NewColumnRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	t.Run("projects", func(t *testing.T) {
+		list, err := projectRepo.List(otherTenant, true)
+		assert.Nil(t, err)
+		assert.Empty(t, list)
+
+		for _, p := range testProjects {
+			_, err = projectRepo.Retrieve(otherTenant, p.ID)
+			assert.Equal(t, fail.ErrNotFound, err)
+		}
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		for _, p := range testProjects {
+			list, err := columnRepo.List(otherTenant, p.ID)
+			assert.Equal(t, fail.ErrNotFound, err)
+			assert.Empty(t, list)
+		}
+		for _, c := range testColumns {
+			_, err := columnRepo.Retrieve(otherTenant, c.ID)
+			assert.Equal(t, fail.ErrNotFound, err)
+		}
+	})
+
+	t.Run("tasks", func(t *testing.T) {
+		for _, p := range testProjects {
+			list, err := taskRepo.List(otherTenant, p.ID, model.TaskFilter{})
+			assert.Nil(t, err)
+			assert.Empty(t, list)
+		}
+		for _, task := range testTasks {
+			_, err := taskRepo.Retrieve(otherTenant, task.ID)
+			assert.Equal(t, fail.ErrNotFound, err)
+		}
+	})
+
+	t.Run("tables", func(t *testing.T) {
+		conn, release, err := db.GetConnection(otherTenant)
+		require.NoError(t, err)
+		defer release()
+
+		for _, table := range rlsTables {
+			assert.Equal(t, 0, countRows(t, otherTenant, conn, table), table)
+		}
+	})
+}
+
+func TestRowLevelSecurity_TenantBinding(t *testing.T) {
+	tenantID := testProjects[0].TenantID
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: tenantID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	// A single pooled connection guarantees the next checkout reuses the same session.
+	db.TestsOnlyDBConnection().SetMaxOpenConns(1)
+
+	t.Run("connection binding is reset on release", func(t *testing.T) {
+		conn, release, err := db.GetConnection(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, len(testProjects), countRows(t, ctx, conn, "projects"))
+		require.NoError(t, release())
+
+		var count int
+		err = db.TestsOnlyDBConnection().QueryRowContext(ctx, `select count(*) from projects`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("transaction binding ends with the transaction", func(t *testing.T) {
+		err := db.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+			var count int
+			if err := tx.QueryRowxContext(ctx, `select count(*) from projects`).Scan(&count); err != nil {
+				return err
+			}
+			assert.Equal(t, len(testProjects), count)
+			return nil
+		})
+		require.NoError(t, err)
+
+		var count int
+		err = db.TestsOnlyDBConnection().QueryRowContext(ctx, `select count(*) from projects`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("transaction requires a tenant", func(t *testing.T) {
+		noTenant := web.NewContext(testutils.MockCtx, &web.Values{})
+		err := db.RunInTransaction(noTenant, func(tx *sqlx.Tx) error { return nil })
+		assert.Equal(t, fail.ErrNoTenant, err)
+	})
+
+	t.Run("tenant id is not interpolated", func(t *testing.T) {
+		injected := web.NewContext(testutils.MockCtx, &web.Values{TenantID: "x', false); select set_config('app.current_tenant', '" + tenantID})
+		conn, release, err := db.GetConnection(injected)
+		require.NoError(t, err)
+		defer release()
+
+		for _, table := range rlsTables {
+			assert.Equal(t, 0, countRows(t, injected, conn, table), table)
+		}
+	})
+}
+
+func countRows(t *testing.T, ctx context.Context, conn *sqlx.Conn, table string) int {
+	var count int
+	err := conn.QueryRowxContext(ctx, `select count(*) from `+table).Scan(&count)
+	require.NoError(t, err)
+	return count
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ShareRepository manages data access to project share links.
+type ShareRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewShareRepository returns a new ShareRepository.
+func NewShareRepository(logger *zap.Logger, pg *db.PostgresDatabase) *ShareRepository {
+	return &ShareRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectShareLink = `
+	select share_link_id, tenant_id, project_id, user_id, expires_at, revoked_at, created_at
+	from share_links
+`
+
+// utcShareLink returns the link with its times in UTC.
+func utcShareLink(s model.ShareLink) model.ShareLink {
+	if s.ExpiresAt != nil {
+		t := s.ExpiresAt.UTC()
+		s.ExpiresAt = &t
+	}
+	if s.RevokedAt != nil {
+		t := s.RevokedAt.UTC()
+		s.RevokedAt = &t
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+	return s
+}
+
+// Create creates a share link of a project owned by the user. Only the hash of its token
+// is stored.
+func (sr *ShareRepository) Create(ctx context.Context, pid string, tokenHash string, expiresAt *time.Time, now time.Time) (model.ShareLink, error) {
+	var s model.ShareLink
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return s, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return s, fail.ErrInvalidID
+	}
+
+	if expiresAt != nil {
+		t := expiresAt.Round(time.Microsecond).UTC()
+		expiresAt = &t
+	}
+
+	err := sr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		stmt := `
+			insert into share_links (share_link_id, tenant_id, project_id, token_hash, user_id, expires_at, created_at)
+			values ($1, $2, $3, $4, $5, $6, $7)
+			returning share_link_id, tenant_id, project_id, user_id, expires_at, revoked_at, created_at
+		`
+		return tx.QueryRowxContext(ctx, stmt, uuid.New().String(), values.TenantID, pid, tokenHash, values.UserID,
+			expiresAt, now.Round(time.Microsecond).UTC()).StructScan(&s)
+	})
+	if err != nil {
+		return model.ShareLink{}, err
+	}
+
+	return utcShareLink(s), nil
+}
+
+// List lists the share links of a project owned by the user, newest first.
+func (sr *ShareRepository) List(ctx context.Context, pid string) ([]model.ShareLink, error) {
+	var list = make([]model.ShareLink, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return list, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(pid); err != nil {
+		return list, fail.ErrInvalidID
+	}
+
+	conn, Close, err := sr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return list, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleOwner); err != nil {
+		return list, err
+	}
+
+	var links []model.ShareLink
+	stmt := selectShareLink + ` where project_id = $1 order by created_at desc`
+	if err = conn.SelectContext(ctx, &links, stmt, pid); err != nil {
+		return list, err
+	}
+
+	for _, s := range links {
+		list = append(list, utcShareLink(s))
+	}
+	return list, nil
+}
+
+// Revoke revokes a share link of a project owned by the user.
+func (sr *ShareRepository) Revoke(ctx context.Context, pid string, shid string, now time.Time) (model.ShareLink, error) {
+	var s model.ShareLink
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return s, web.CtxErr()
+	}
+
+	for _, id := range []string{pid, shid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return s, fail.ErrInvalidID
+		}
+	}
+
+	err := sr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleOwner); err != nil {
+			return err
+		}
+
+		stmt := `
+			update share_links set revoked_at = coalesce(revoked_at, $3)
+			where share_link_id = $1 and project_id = $2
+			returning share_link_id, tenant_id, project_id, user_id, expires_at, revoked_at, created_at
+		`
+		err := tx.QueryRowxContext(ctx, stmt, shid, pid, now.Round(time.Microsecond).UTC()).StructScan(&s)
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return model.ShareLink{}, err
+	}
+
+	return utcShareLink(s), nil
+}
+
+// Board retrieves the board of the project shared by the link with the token hash. Links
+// that are revoked or expired, and projects in the trash, are not found. Archived tasks
+// are left out. The board is read from the primary, so a revoked link stops working at
+// once rather than once the replicas catch up.
+func (sr *ShareRepository) Board(ctx context.Context, tokenHash string, now time.Time) (model.SharedBoard, error) {
+	var b model.SharedBoard
+
+	conn, Close, err := sr.pg.GetConnection(ctx)
+	if err != nil {
+		return b, err
+	}
+	defer Close()
+
+	var pid string
+	stmt := `
+		select s.project_id from share_links s
+		join projects p on p.project_id = s.project_id
+		where s.token_hash = $1 and s.revoked_at is null and (s.expires_at is null or s.expires_at > $2)
+		and p.deleted_at is null
+	`
+	if err = conn.QueryRowxContext(ctx, stmt, tokenHash, now.UTC()).Scan(&pid); err != nil {
+		if err == sql.ErrNoRows {
+			return b, fail.ErrNotFound
+		}
+		return b, err
+	}
+
+	p, err := scanProject(conn.QueryRowxContext(ctx, selectProject+` where project_id = $1`, pid))
+	if err != nil {
+		return b, err
+	}
+
+	var cs []model.Column
+	stmt = `
+		select
+			column_id, title, column_name, wip_limit,
+			array(select t.task_id from tasks t where t.column_id = columns.column_id and t.deleted_at is null and t.archived_at is null order by t.rank) as task_ids
+		from columns
+		where project_id = $1
+	`
+	rows, err := conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, err
+	}
+	for rows.Next() {
+		var c model.Column
+		if err = rows.Scan(&c.ID, &c.Title, &c.ColumnName, &c.WIPLimit, (*pq.StringArray)(&c.TaskIDS)); err != nil {
+			rows.Close()
+			return b, err
+		}
+		cs = append(cs, c)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return b, err
+	}
+
+	var ts []model.Task
+	rows, err = conn.QueryxContext(ctx, selectTask+`
+		where tasks.project_id = $1 and tasks.deleted_at is null and tasks.archived_at is null
+		order by tasks.rank
+	`, pid)
+	if err != nil {
+		return b, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return b, err
+		}
+		ts = append(ts, t)
+	}
+	if err = rows.Err(); err != nil {
+		return b, err
+	}
+
+	return model.NewSharedBoard(p, cs, ts), nil
+}
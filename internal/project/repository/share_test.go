@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestShareRepository_Board(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	user := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: uuid.New().String()})
+	anonymous := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewShareRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	t.Run("only owners share", func(t *testing.T) {
+		_, err := repo.Create(user, project.ID, "user-hash", nil, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("shared board", func(t *testing.T) {
+		s, err := repo.Create(owner, project.ID, "board-hash", nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, project.ID, s.ProjectID)
+		assert.Empty(t, s.Token)
+
+		b, err := repo.Board(anonymous, "board-hash", now)
+		require.NoError(t, err)
+		assert.Equal(t, project.Name, b.Project.Name)
+		assert.NotEmpty(t, b.Columns)
+		assert.NotEmpty(t, b.Tasks)
+
+		list, err := repo.List(owner, project.ID)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("expired link", func(t *testing.T) {
+		expiresAt := now.Add(time.Hour)
+		_, err := repo.Create(owner, project.ID, "expiring-hash", &expiresAt, now)
+		require.NoError(t, err)
+
+		_, err = repo.Board(anonymous, "expiring-hash", now)
+		assert.Nil(t, err)
+
+		_, err = repo.Board(anonymous, "expiring-hash", now.Add(2*time.Hour))
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("revoked link", func(t *testing.T) {
+		s, err := repo.Create(owner, project.ID, "revoked-hash", nil, now)
+		require.NoError(t, err)
+
+		s, err = repo.Revoke(owner, project.ID, s.ID, now)
+		require.NoError(t, err)
+		assert.NotNil(t, s.RevokedAt)
+
+		_, err = repo.Board(anonymous, "revoked-hash", now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("other tenants", func(t *testing.T) {
+		other := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID})
+
+		_, err := repo.Board(other, "board-hash", now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
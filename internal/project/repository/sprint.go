@@ -0,0 +1,638 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/burndown"
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// SprintRepository manages data access to project sprints.
+type SprintRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewSprintRepository returns a new SprintRepository.
+func NewSprintRepository(logger *zap.Logger, pg *db.PostgresDatabase) *SprintRepository {
+	return &SprintRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectSprint = `
+	select
+		sprint_id, tenant_id, project_id, name, goal, capacity, status,
+		(select coalesce(sum(t.points), 0) from tasks t where t.sprint_id = sprints.sprint_id and t.deleted_at is null) as points,
+		start_at, end_at, completed_at, updated_at, created_at
+	from sprints
+`
+
+func scanSprint(row interface{ Scan(...interface{}) error }) (model.Sprint, error) {
+	var s model.Sprint
+
+	err := row.Scan(&s.ID, &s.TenantID, &s.ProjectID, &s.Name, &s.Goal, &s.Capacity, &s.Status, &s.Points, &s.StartAt, &s.EndAt, &s.CompletedAt, &s.UpdatedAt, &s.CreatedAt)
+	if err != nil {
+		return s, err
+	}
+
+	s.StartAt = utc(s.StartAt)
+	s.EndAt = utc(s.EndAt)
+	s.CompletedAt = utc(s.CompletedAt)
+	s.UpdatedAt = s.UpdatedAt.UTC()
+	s.CreatedAt = s.CreatedAt.UTC()
+
+	return s, nil
+}
+
+// Retrieve retrieves a specific sprint of a project from the database.
+func (sr *SprintRepository) Retrieve(ctx context.Context, pid string, sid string) (model.Sprint, error) {
+	var (
+		s   model.Sprint
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return s, web.CtxErr()
+	}
+
+	for _, id := range []string{pid, sid} {
+		if _, err = uuid.Parse(id); err != nil {
+			return s, fail.ErrInvalidID
+		}
+	}
+
+	conn, Close, err := sr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return s, err
+	}
+	defer Close()
+
+	s, err = scanSprint(conn.QueryRowxContext(ctx, selectSprint+` where sprint_id = $1 and project_id = $2`, sid, pid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return s, fail.ErrNotFound
+		}
+		return s, err
+	}
+
+	if err = authorize(ctx, conn, s.ProjectID, values.UserID, model.RoleViewer); err != nil {
+		return model.Sprint{}, err
+	}
+
+	return s, nil
+}
+
+// List lists the sprints of a project, oldest first.
+func (sr *SprintRepository) List(ctx context.Context, pid string) ([]model.Sprint, error) {
+	var (
+		ss  = make([]model.Sprint, 0)
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return ss, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return ss, fail.ErrInvalidID
+	}
+
+	conn, Close, err := sr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return ss, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryxContext(ctx, selectSprint+` where project_id = $1 order by created_at`, pid)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting sprints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanSprint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		ss = append(ss, s)
+	}
+
+	return ss, rows.Err()
+}
+
+// Create creates a planned project sprint in the database.
+func (sr *SprintRepository) Create(ctx context.Context, pid string, ns model.NewSprint, now time.Time) (model.Sprint, error) {
+	var (
+		s   model.Sprint
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return s, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return s, fail.ErrInvalidID
+	}
+
+	conn, Close, err := sr.pg.GetConnection(ctx)
+	if err != nil {
+		return s, err
+	}
+	defer Close()
+
+	if err = authorize(ctx, conn, pid, values.UserID, model.RoleEditor); err != nil {
+		return s, err
+	}
+
+	s = model.Sprint{
+		ID:        uuid.New().String(),
+		TenantID:  values.TenantID,
+		ProjectID: pid,
+		Name:      ns.Name,
+		Goal:      ns.Goal,
+		Capacity:  ns.Capacity,
+		Status:    model.SprintPlanned,
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	stmt := `
+		insert into sprints (
+			sprint_id, tenant_id, project_id, name, goal, capacity, status, updated_at, created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	if _, err = conn.ExecContext(ctx, stmt, s.ID, s.TenantID, s.ProjectID, s.Name, s.Goal, s.Capacity, s.Status, s.UpdatedAt, s.CreatedAt); err != nil {
+		return model.Sprint{}, fmt.Errorf("error inserting sprint: %v: %w", ns, err)
+	}
+	db.Wrote(ctx)
+
+	return s, nil
+}
+
+// Start starts a planned sprint. A project has at most one active sprint.
+func (sr *SprintRepository) Start(ctx context.Context, pid string, sid string, ss model.StartSprint, now time.Time) (model.Sprint, error) {
+	for _, id := range []string{pid, sid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return model.Sprint{}, fail.ErrInvalidID
+		}
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Sprint{}, web.CtxErr()
+	}
+
+	start := now.Round(time.Microsecond).UTC()
+	if !ss.EndAt.After(start) {
+		return model.Sprint{}, fail.ErrSprintEnd
+	}
+
+	err := sr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		s, err := lockSprint(ctx, tx, pid, sid)
+		if err != nil {
+			return err
+		}
+		if err = authorize(ctx, tx, s.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+		if s.Status != model.SprintPlanned {
+			return fail.ErrSprintState
+		}
+
+		stmt := `update sprints set status = $1, start_at = $2, end_at = $3, updated_at = $2 where sprint_id = $4`
+		if _, err = tx.ExecContext(ctx, stmt, model.SprintActive, start, ss.EndAt.UTC(), sid); err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrSprintActive
+			}
+			return fmt.Errorf("error starting sprint %s: %w", sid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Sprint{}, err
+	}
+
+	return sr.Retrieve(db.Primary(ctx), pid, sid)
+}
+
+// Complete completes an active sprint and carries its unfinished tasks over to a
+// planned sprint of the project, or to the backlog. Like tasks added to a sprint, the tasks carried over
+// may not take the points of the planned sprint beyond its capacity, unless the capacity
+// is zero.
+func (sr *SprintRepository) Complete(ctx context.Context, pid string, sid string, cs model.CompleteSprint, now time.Time) (model.Sprint, error) {
+	for _, id := range []string{pid, sid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return model.Sprint{}, fail.ErrInvalidID
+		}
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Sprint{}, web.CtxErr()
+	}
+
+	err := sr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		s, err := lockSprint(ctx, tx, pid, sid)
+		if err != nil {
+			return err
+		}
+		if err = authorize(ctx, tx, s.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+		if s.Status != model.SprintActive {
+			return fail.ErrSprintState
+		}
+
+		var (
+			to   sql.NullString
+			next model.Sprint
+		)
+		if cs.CarryOverTo != "" {
+			if next, err = lockSprint(ctx, tx, pid, cs.CarryOverTo); err != nil {
+				return err
+			}
+			if next.Status != model.SprintPlanned {
+				return fail.ErrSprintState
+			}
+			to = sql.NullString{String: next.ID, Valid: true}
+		}
+
+		done, err := doneColumn(ctx, tx, s.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		stmt := `
+			update tasks set sprint_id = $1
+			where sprint_id = $2 and coalesce(column_id, '') <> $3
+			returning task_id, tenant_id, project_id
+		`
+		rows, err := tx.QueryxContext(ctx, stmt, to, sid, done)
+		if err != nil {
+			return fmt.Errorf("error carrying over sprint tasks %s: %w", sid, err)
+		}
+
+		var carried []model.Task
+		for rows.Next() {
+			var t model.Task
+			if err = rows.Scan(&t.ID, &t.TenantID, &t.ProjectID); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning row into struct: %w", err)
+			}
+			carried = append(carried, t)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		if to.Valid && next.Capacity > 0 {
+			var points int
+			stmt = `select coalesce(sum(points), 0) from tasks where sprint_id = $1 and deleted_at is null`
+			if err = tx.QueryRowxContext(ctx, stmt, next.ID).Scan(&points); err != nil {
+				return err
+			}
+			if points > next.Capacity {
+				return fail.ErrSprintCapacity
+			}
+		}
+
+		for _, t := range carried {
+			changes := map[string]model.FieldChange{
+				"sprintId": {From: sid, To: to.String},
+			}
+			if err = recordEvent(ctx, tx, t, values.UserID, model.TaskUpdated, changes, now); err != nil {
+				return err
+			}
+		}
+
+		stmt = `update sprints set status = $1, completed_at = $2, updated_at = $2 where sprint_id = $3`
+		if _, err = tx.ExecContext(ctx, stmt, model.SprintCompleted, now.Round(time.Microsecond).UTC(), sid); err != nil {
+			return fmt.Errorf("error completing sprint %s: %w", sid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Sprint{}, err
+	}
+
+	return sr.Retrieve(db.Primary(ctx), pid, sid)
+}
+
+// AddTasks adds tasks of the project to a planned or active sprint. The points of the
+// sprint may not exceed its capacity, unless the capacity is zero.
+func (sr *SprintRepository) AddTasks(ctx context.Context, pid string, sid string, st model.SprintTasks, now time.Time) (model.Sprint, error) {
+	for _, id := range []string{pid, sid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return model.Sprint{}, fail.ErrInvalidID
+		}
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Sprint{}, web.CtxErr()
+	}
+
+	err := sr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		s, err := lockSprint(ctx, tx, pid, sid)
+		if err != nil {
+			return err
+		}
+		if err = authorize(ctx, tx, s.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+		if s.Status == model.SprintCompleted {
+			return fail.ErrSprintState
+		}
+
+		stmt := `
+			select task_id, tenant_id, project_id, points, coalesce(sprint_id, '')
+			from tasks
+			where task_id = any($1) and deleted_at is null
+			for update
+		`
+		rows, err := tx.QueryxContext(ctx, stmt, pq.Array(st.TaskIDs))
+		if err != nil {
+			return fmt.Errorf("error selecting sprint tasks: %w", err)
+		}
+
+		var (
+			added  []model.Task
+			points = s.Points
+			found  int
+		)
+		for rows.Next() {
+			var t model.Task
+			if err = rows.Scan(&t.ID, &t.TenantID, &t.ProjectID, &t.Points, &t.SprintID); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning row into struct: %w", err)
+			}
+			found++
+			if t.ProjectID != s.ProjectID {
+				rows.Close()
+				return fail.ErrSprintProject
+			}
+			if t.SprintID != sid {
+				added = append(added, t)
+				points += t.Points
+			}
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		if found != len(st.TaskIDs) {
+			return fail.ErrNotFound
+		}
+		if s.Capacity > 0 && points > s.Capacity {
+			return fail.ErrSprintCapacity
+		}
+
+		for _, t := range added {
+			stmt = `update tasks set sprint_id = $1 where task_id = $2`
+			if _, err = tx.ExecContext(ctx, stmt, sid, t.ID); err != nil {
+				return fmt.Errorf("error adding task %s to sprint %s: %w", t.ID, sid, err)
+			}
+
+			changes := map[string]model.FieldChange{
+				"sprintId": {From: t.SprintID, To: sid},
+			}
+			if err = recordEvent(ctx, tx, t, values.UserID, model.TaskUpdated, changes, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Sprint{}, err
+	}
+
+	return sr.Retrieve(db.Primary(ctx), pid, sid)
+}
+
+// RemoveTask moves a task of a sprint back to the backlog.
+func (sr *SprintRepository) RemoveTask(ctx context.Context, pid string, sid string, tid string, now time.Time) error {
+	for _, id := range []string{pid, sid, tid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return fail.ErrInvalidID
+		}
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	return sr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		s, err := lockSprint(ctx, tx, pid, sid)
+		if err != nil {
+			return err
+		}
+		if err = authorize(ctx, tx, s.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+		if s.Status == model.SprintCompleted {
+			return fail.ErrSprintState
+		}
+
+		t := model.Task{ID: tid}
+
+		stmt := `update tasks set sprint_id = null where task_id = $1 and sprint_id = $2 returning tenant_id, project_id`
+		if err = tx.QueryRowxContext(ctx, stmt, tid, sid).Scan(&t.TenantID, &t.ProjectID); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return fmt.Errorf("error removing task %s from sprint %s: %w", tid, sid, err)
+		}
+
+		changes := map[string]model.FieldChange{
+			"sprintId": {From: sid, To: ""},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskUpdated, changes, now)
+	})
+}
+
+// Burndown returns the daily burndown of a sprint, rebuilt from the history of its tasks.
+func (sr *SprintRepository) Burndown(ctx context.Context, pid string, sid string, now time.Time) (model.Burndown, error) {
+	s, err := sr.Retrieve(ctx, pid, sid)
+	if err != nil {
+		return model.Burndown{}, err
+	}
+
+	b := model.Burndown{
+		SprintID: s.ID,
+		Capacity: s.Capacity,
+		Series:   make([]model.BurndownPoint, 0),
+	}
+	if s.StartAt == nil {
+		return b, nil
+	}
+
+	end := *s.EndAt
+	switch {
+	case s.CompletedAt != nil:
+		// Measure up to just before completion, so tasks carried over on completion
+		// still count toward the last day.
+		end = *s.CompletedAt
+		now = end.Add(-time.Microsecond)
+	case now.After(end):
+		end = now
+	}
+
+	var (
+		tasks   []burndown.Task
+		changes []burndown.Change
+	)
+
+	conn, Close, err := sr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return b, err
+	}
+	defer Close()
+
+	done, err := doneColumn(ctx, conn, s.ProjectID)
+	if err != nil {
+		return b, err
+	}
+
+	// Tasks that are in the sprint, or were at some point.
+	stmt := `
+		select task_id, points, coalesce(column_id, ''), coalesce(sprint_id, '')
+		from tasks
+		where sprint_id = $1 or task_id in (
+			select task_id from task_events
+			where project_id = $2 and (changes->'sprintId'->>'from' = $1 or changes->'sprintId'->>'to' = $1)
+		)
+	`
+	rows, err := conn.QueryxContext(ctx, stmt, sid, s.ProjectID)
+	if err != nil {
+		return b, fmt.Errorf("error selecting sprint tasks: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var t burndown.Task
+		if err = rows.Scan(&t.ID, &t.Points, &t.ColumnID, &t.SprintID); err != nil {
+			rows.Close()
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		tasks = append(tasks, t)
+		ids = append(ids, t.ID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return b, err
+	}
+
+	stmt = `
+		select task_id, changes, created_at
+		from task_events
+		where task_id = any($1) and created_at > $2
+			and (changes ? 'points' or changes ? 'columnId' or changes ? 'sprintId')
+	`
+	rows, err = conn.QueryxContext(ctx, stmt, pq.Array(ids), *s.StartAt)
+	if err != nil {
+		return b, fmt.Errorf("error selecting sprint task events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			c   burndown.Change
+			raw []byte
+			fc  map[string]model.FieldChange
+		)
+		if err = rows.Scan(&c.TaskID, &raw, &c.At); err != nil {
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		if err = json.Unmarshal(raw, &fc); err != nil {
+			return b, fmt.Errorf("error decoding task event changes: %w", err)
+		}
+		if v, ok := fc["points"]; ok {
+			f, _ := v.From.(float64)
+			points := int(f)
+			c.Points = &points
+		}
+		if v, ok := fc["columnId"]; ok {
+			col, _ := v.From.(string)
+			c.ColumnID = &col
+		}
+		if v, ok := fc["sprintId"]; ok {
+			sprint, _ := v.From.(string)
+			c.SprintID = &sprint
+		}
+		changes = append(changes, c)
+	}
+	if err = rows.Err(); err != nil {
+		return b, err
+	}
+
+	b.Series = burndown.Series(sid, done, *s.StartAt, end, now, tasks, changes)
+	return b, nil
+}
+
+// lockSprint locks a sprint of a project for the rest of the transaction. Sprints of
+// other projects are not found.
+func lockSprint(ctx context.Context, tx *sqlx.Tx, pid string, sid string) (model.Sprint, error) {
+	if _, err := uuid.Parse(sid); err != nil {
+		return model.Sprint{}, fail.ErrInvalidID
+	}
+
+	stmt := `select sprint_id, project_id, capacity, status from sprints where sprint_id = $1 and project_id = $2 for update`
+
+	var s model.Sprint
+	if err := tx.QueryRowxContext(ctx, stmt, sid, pid).Scan(&s.ID, &s.ProjectID, &s.Capacity, &s.Status); err != nil {
+		if err == sql.ErrNoRows {
+			return s, fail.ErrNotFound
+		}
+		return s, err
+	}
+
+	stmt = `select coalesce(sum(points), 0) from tasks where sprint_id = $1 and deleted_at is null`
+	if err := tx.QueryRowxContext(ctx, stmt, sid).Scan(&s.Points); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// doneColumn returns the last column of the project board, which holds finished tasks.
+func doneColumn(ctx context.Context, q sqlx.QueryerContext, pid string) (string, error) {
+	var cid string
+
+	stmt := `
+		select c.column_id
+		from projects p join columns c on c.project_id = p.project_id
+		where p.project_id = $1 and c.column_name = p.column_order[array_length(p.column_order, 1)]
+	`
+	if err := q.QueryRowxContext(ctx, stmt, pid).Scan(&cid); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return cid, nil
+}
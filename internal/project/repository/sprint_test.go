@@ -0,0 +1,172 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSprintRepository_Lifecycle(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	var done model.Column
+	for _, c := range testColumns {
+		if c.ProjectID == project.ID && c.ColumnName == project.ColumnOrder[len(project.ColumnOrder)-1] {
+			done = c
+		}
+	}
+	require.NotEmpty(t, done.ID)
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewSprintRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	start := time.Now().Add(-time.Hour)
+
+	for _, task := range testTasks {
+		_, err := taskRepo.Update(ctx, task.ID, model.UpdateTask{Points: aws.Int(3)}, start)
+		require.NoError(t, err)
+	}
+
+	sprint, err := repo.Create(ctx, project.ID, model.NewSprint{Name: "Sprint 1", Capacity: 5}, start)
+	require.NoError(t, err)
+	next, err := repo.Create(ctx, project.ID, model.NewSprint{Name: "Sprint 2"}, start)
+	require.NoError(t, err)
+
+	t.Run("sprints of another project are not found", func(t *testing.T) {
+		other := testProjects[0].ID
+
+		_, err := repo.Retrieve(ctx, other, sprint.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = repo.Start(ctx, other, sprint.ID, model.StartSprint{EndAt: start.Add(time.Hour)}, start)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = repo.Complete(ctx, other, sprint.ID, model.CompleteSprint{}, start)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = repo.AddTasks(ctx, other, sprint.ID, model.SprintTasks{TaskIDs: []string{testTasks[0].ID}}, start)
+		assert.Equal(t, fail.ErrNotFound, err)
+		err = repo.RemoveTask(ctx, other, sprint.ID, testTasks[0].ID, start)
+		assert.Equal(t, fail.ErrNotFound, err)
+		_, err = repo.Burndown(ctx, other, sprint.ID, start)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("capacity is enforced", func(t *testing.T) {
+		_, err := repo.AddTasks(ctx, project.ID, sprint.ID, model.SprintTasks{TaskIDs: []string{testTasks[0].ID, testTasks[1].ID}}, start)
+		assert.Equal(t, fail.ErrSprintCapacity, err)
+
+		actual, err := repo.AddTasks(ctx, project.ID, sprint.ID, model.SprintTasks{TaskIDs: []string{testTasks[0].ID}}, start)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, actual.Points)
+	})
+
+	t.Run("one active sprint per project", func(t *testing.T) {
+		actual, err := repo.Start(ctx, project.ID, sprint.ID, model.StartSprint{EndAt: start.Add(14 * 24 * time.Hour)}, start)
+		assert.Nil(t, err)
+		assert.Equal(t, model.SprintActive, actual.Status)
+
+		_, err = repo.Start(ctx, project.ID, next.ID, model.StartSprint{EndAt: start.Add(14 * 24 * time.Hour)}, start)
+		assert.Equal(t, fail.ErrSprintActive, err)
+	})
+
+	t.Run("unfinished tasks carry over on completion", func(t *testing.T) {
+		_, err := repo.AddTasks(ctx, project.ID, sprint.ID, model.SprintTasks{TaskIDs: []string{testTasks[1].ID}}, start)
+		assert.Equal(t, fail.ErrSprintCapacity, err)
+
+		_, err = taskRepo.Move(ctx, testTasks[0].ID, model.MoveTask{To: done.ID}, time.Now())
+		require.NoError(t, err)
+
+		actual, err := repo.Complete(ctx, project.ID, sprint.ID, model.CompleteSprint{CarryOverTo: next.ID}, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, model.SprintCompleted, actual.Status)
+		assert.Equal(t, 3, actual.Points)
+
+		_, err = repo.Complete(ctx, project.ID, sprint.ID, model.CompleteSprint{}, time.Now())
+		assert.Equal(t, fail.ErrSprintState, err)
+	})
+
+	t.Run("burndown", func(t *testing.T) {
+		actual, err := repo.Burndown(ctx, project.ID, sprint.ID, time.Now())
+		assert.Nil(t, err)
+		require.NotEmpty(t, actual.Series)
+
+		last := actual.Series[len(actual.Series)-1]
+		assert.Equal(t, 3, last.Total)
+		assert.Equal(t, 3, last.Completed)
+		assert.Equal(t, 0, last.Remaining)
+	})
+
+	t.Run("planned sprints have no burndown yet", func(t *testing.T) {
+		actual, err := repo.Burndown(ctx, project.ID, next.ID, time.Now())
+		assert.Nil(t, err)
+		assert.Empty(t, actual.Series)
+	})
+}
+
+func TestSprintRepository_CompleteCarryOverCapacity(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewSprintRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	start := time.Now().Add(-time.Hour)
+	taskIDs := []string{testTasks[0].ID, testTasks[1].ID}
+
+	for _, tid := range taskIDs {
+		_, err := taskRepo.Update(ctx, tid, model.UpdateTask{Points: aws.Int(3)}, start)
+		require.NoError(t, err)
+	}
+
+	sprint, err := repo.Create(ctx, project.ID, model.NewSprint{Name: "Sprint 1"}, start)
+	require.NoError(t, err)
+	next, err := repo.Create(ctx, project.ID, model.NewSprint{Name: "Sprint 2", Capacity: 5}, start)
+	require.NoError(t, err)
+
+	_, err = repo.AddTasks(ctx, project.ID, sprint.ID, model.SprintTasks{TaskIDs: taskIDs}, start)
+	require.NoError(t, err)
+	_, err = repo.Start(ctx, project.ID, sprint.ID, model.StartSprint{EndAt: start.Add(14 * 24 * time.Hour)}, start)
+	require.NoError(t, err)
+
+	t.Run("carry over beyond capacity is rejected", func(t *testing.T) {
+		_, err := repo.Complete(ctx, project.ID, sprint.ID, model.CompleteSprint{CarryOverTo: next.ID}, time.Now())
+		assert.Equal(t, fail.ErrSprintCapacity, err)
+
+		actual, err := repo.Retrieve(ctx, project.ID, sprint.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, model.SprintActive, actual.Status)
+		assert.Equal(t, 6, actual.Points)
+
+		actual, err = repo.Retrieve(ctx, project.ID, next.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, actual.Points)
+	})
+
+	t.Run("carry over within capacity", func(t *testing.T) {
+		_, err := taskRepo.Update(ctx, testTasks[1].ID, model.UpdateTask{Points: aws.Int(2)}, time.Now())
+		require.NoError(t, err)
+
+		actual, err := repo.Complete(ctx, project.ID, sprint.ID, model.CompleteSprint{CarryOverTo: next.ID}, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, model.SprintCompleted, actual.Status)
+
+		actual, err = repo.Retrieve(ctx, project.ID, next.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, actual.Points)
+	})
+}
@@ -4,16 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strconv"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/devpies/saas-core/internal/project/db"
 	"github.com/devpies/saas-core/internal/project/fail"
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/query"
+	"github.com/devpies/saas-core/internal/project/rank"
 	"github.com/devpies/saas-core/pkg/web"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
@@ -32,6 +34,98 @@ func NewTaskRepository(logger *zap.Logger, pg *db.PostgresDatabase) *TaskReposit
 	}
 }
 
+// selectTask selects tasks with their labels, custom field values, comment count, time spent, subtask progress and
+// links, so a whole board is read in a single query.
+const selectTask = `
+	select
+		task_id, tenant_id, key, title, points, user_id, content, assigned_to, priority, start_at, due_at, attachments,
+		coalesce((
+			select json_agg(json_build_object('id', l.label_id, 'name', l.name, 'color', l.color) order by lower(l.name))
+			from task_labels tl join labels l on l.label_id = tl.label_id
+			where tl.task_id = tasks.task_id
+		), '[]') as labels,
+		` + taskFieldValues + ` as fields,
+		(select count(*) from comments c where c.task_id = tasks.task_id) as comment_count,
+		original_estimate, remaining_estimate,
+		(select coalesce(sum(w.minutes), 0) from worklogs w where w.task_id = tasks.task_id) as time_spent,
+		project_id, coalesce(column_id, '') as column_id, rank, coalesce(sprint_id, '') as sprint_id,
+		coalesce(parent_id, '') as parent_id,
+		(select count(*) from tasks s where s.parent_id = tasks.task_id and s.deleted_at is null) as subtasks_total,
+		(select count(*) from tasks s where s.parent_id = tasks.task_id and s.deleted_at is null and task_done(s.project_id, s.column_id)) as subtasks_completed,
+		coalesce((
+			select json_agg(json_build_object('id', k.link_id, 'kind', k.kind, 'taskId', o.task_id, 'key', o.key, 'title', o.title) order by k.created_at)
+			from (
+				select link_id, kind, target_id as other_id, created_at
+				from task_links where task_id = tasks.task_id
+				union all
+				select link_id, case kind when 'blocks' then 'is_blocked_by' when 'duplicates' then 'is_duplicated_by' else kind end, task_id, created_at
+				from task_links where target_id = tasks.task_id
+			) k join tasks o on o.task_id = k.other_id
+			where o.deleted_at is null
+		), '[]') as links,
+		archived_at, deleted_at, updated_at, created_at
+	from tasks
+`
+
+// liveTask restricts task queries to tasks that are not in the trash, themselves or
+// with their project, and that the user bound to the given parameter can see.
+func liveTask(user int) string {
+	return fmt.Sprintf(`
+	tasks.deleted_at is null
+	and exists(select 1 from projects p where p.project_id = tasks.project_id and p.deleted_at is null)
+	and project_role(tasks.project_id, $%d) is not null
+`, user)
+}
+
+func scanTask(row interface{ Scan(...interface{}) error }) (model.Task, error) {
+	var t model.Task
+
+	err := row.Scan(
+		&t.ID,
+		&t.TenantID,
+		&t.Key,
+		&t.Title,
+		&t.Points,
+		&t.UserID,
+		&t.Content,
+		&t.AssignedTo,
+		&t.Priority,
+		&t.StartAt,
+		&t.DueAt,
+		(*pq.StringArray)(&t.Attachments),
+		(*jsonList[model.TaskLabel])(&t.Labels),
+		(*jsonFields)(&t.Fields),
+		&t.CommentCount,
+		&t.OriginalEstimate,
+		&t.RemainingEstimate,
+		&t.TimeSpent,
+		&t.ProjectID,
+		&t.ColumnID,
+		&t.Rank,
+		&t.SprintID,
+		&t.ParentID,
+		&t.Subtasks.Total,
+		&t.Subtasks.Completed,
+		(*jsonList[model.TaskLink])(&t.Links),
+		&t.ArchivedAt,
+		&t.DeletedAt,
+		&t.UpdatedAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		return t, err
+	}
+
+	t.StartAt = utc(t.StartAt)
+	t.DueAt = utc(t.DueAt)
+	t.ArchivedAt = utc(t.ArchivedAt)
+	t.DeletedAt = utc(t.DeletedAt)
+	t.UpdatedAt = t.UpdatedAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+
+	return t, nil
+}
+
 // Retrieve retrieves a specific task from the database.
 func (tr *TaskRepository) Retrieve(ctx context.Context, tid string) (model.Task, error) {
 	var (
@@ -39,25 +133,22 @@ func (tr *TaskRepository) Retrieve(ctx context.Context, tid string) (model.Task,
 		err error
 	)
 
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return t, web.CtxErr()
+	}
+
 	if _, err = uuid.Parse(tid); err != nil {
 		return t, fail.ErrInvalidID
 	}
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return t, err
 	}
 	defer Close()
 
-	stmt := `
-		select 
-			task_id, tenant_id, key, title, points, user_id, content, assigned_to,
-			attachments, comments, project_id, updated_at, created_at
-		from tasks
-		where task_id = $1
-	`
-
-	err = conn.QueryRowxContext(ctx, stmt, tid).Scan(&t.ID, &t.TenantID, &t.Key, &t.Title, &t.Points, &t.UserID, &t.Content, &t.AssignedTo, (*pq.StringArray)(&t.Attachments), (*pq.StringArray)(&t.Comments), &t.ProjectID, &t.UpdatedAt, &t.CreatedAt)
+	t, err = scanTask(conn.QueryRowxContext(ctx, selectTask+` where task_id = $1 and `+liveTask(2), tid, values.UserID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return t, fail.ErrNotFound
@@ -65,21 +156,57 @@ func (tr *TaskRepository) Retrieve(ctx context.Context, tid string) (model.Task,
 		return t, err
 	}
 
-	t.UpdatedAt = t.UpdatedAt.UTC()
-	t.CreatedAt = t.CreatedAt.UTC()
+	return t, nil
+}
+
+// RetrieveByKey retrieves a task of a project by its human readable key, ignoring case.
+func (tr *TaskRepository) RetrieveByKey(ctx context.Context, pid string, key string) (model.Task, error) {
+	var (
+		t   model.Task
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return t, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return t, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return t, err
+	}
+	defer Close()
+
+	t, err = scanTask(conn.QueryRowxContext(ctx, selectTask+` where project_id = $1 and upper(key) = upper($2) and `+liveTask(3), pid, key, values.UserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return t, fail.ErrNotFound
+		}
+		return t, err
+	}
 
 	return t, nil
 }
 
-// List lists all tasks asscociated to a project.
-func (tr *TaskRepository) List(ctx context.Context, pid string) ([]model.Task, error) {
+// List lists the tasks asscociated to a project that match the filter. Archived tasks
+// are left out unless the filter asks for them, and overdue tasks leave out finished ones.
+func (tr *TaskRepository) List(ctx context.Context, pid string, filter model.TaskFilter) ([]model.Task, error) {
 	var (
 		t   model.Task
 		ts  = make([]model.Task, 0)
 		err error
 	)
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return ts, web.CtxErr()
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return ts, err
 	}
@@ -89,15 +216,62 @@ func (tr *TaskRepository) List(ctx context.Context, pid string) ([]model.Task, e
 		return ts, fail.ErrInvalidID
 	}
 
-	stmt := `
-		select
-			task_id, tenant_id, key, title, points, user_id, content, assigned_to,
-			attachments, comments, project_id, updated_at, created_at
-		from tasks
-		where project_id = $1
+	stmt := selectTask + `
+		where project_id = $1 and ` + liveTask(2) + `%s
+		order by column_id, rank
 	`
 
-	rows, err := conn.QueryxContext(ctx, stmt, pid)
+	var (
+		filters string
+		args    = []interface{}{pid, values.UserID}
+	)
+
+	if filter.LabelID != "" {
+		args = append(args, filter.LabelID)
+		filters += fmt.Sprintf(" and exists(select 1 from task_labels tl where tl.task_id = tasks.task_id and tl.label_id = $%d)", len(args))
+	}
+	if filter.Priority != "" {
+		args = append(args, filter.Priority)
+		filters += fmt.Sprintf(" and priority = $%d", len(args))
+	}
+	if filter.Overdue {
+		filters += " and due_at < now() and not task_done(project_id, column_id)"
+	}
+	if !filter.Archived {
+		filters += " and archived_at is null"
+	}
+
+	cfids := make([]string, 0, len(filter.Fields))
+	for cfid := range filter.Fields {
+		cfids = append(cfids, cfid)
+	}
+	sort.Strings(cfids)
+
+	// Filters may only name custom fields of the project.
+	if len(cfids) > 0 {
+		var known int
+		stmt := `select count(*) from custom_fields where project_id = $1 and field_id = any($2)`
+		if err = conn.QueryRowxContext(ctx, stmt, pid, pq.Array(cfids)).Scan(&known); err != nil {
+			return nil, fmt.Errorf("error selecting custom fields: %w", err)
+		}
+		if known != len(cfids) {
+			return ts, fail.ErrInvalidFilter
+		}
+	}
+
+	for _, cfid := range cfids {
+		args = append(args, cfid, filter.Fields[cfid])
+		filters += fmt.Sprintf(`
+			and exists(
+				select 1 from task_field_values v
+				where v.task_id = tasks.task_id and v.field_id = $%[1]d and (
+					lower(v.text_value) = lower($%[2]d) or v.number_value::text = $%[2]d
+					or v.date_value::text = $%[2]d or $%[2]d = any(v.options)
+				)
+			)`, len(args)-1, len(args))
+	}
+
+	rows, err := conn.QueryxContext(ctx, fmt.Sprintf(stmt, filters), args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ts, nil
@@ -106,140 +280,432 @@ func (tr *TaskRepository) List(ctx context.Context, pid string) ([]model.Task, e
 	}
 
 	for rows.Next() {
-		err = rows.Scan(
-			&t.ID,
-			&t.TenantID,
-			&t.Key,
-			&t.Title,
-			&t.Points,
-			&t.UserID,
-			&t.Content,
-			&t.AssignedTo,
-			(*pq.StringArray)(&t.Attachments),
-			(*pq.StringArray)(&t.Comments),
-			&t.ProjectID,
-			&t.UpdatedAt,
-			&t.CreatedAt,
-		)
+		t, err = scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row into struct: %w", err)
 		}
 
-		t.UpdatedAt = t.UpdatedAt.UTC()
-		t.CreatedAt = t.CreatedAt.UTC()
-
 		ts = append(ts, t)
 	}
 
 	return ts, nil
 }
 
-func formatKey(lastKey string, prefix string) (string, error) {
+// Search searches task titles, content and comments of the tenant, best matches first.
+// Archived tasks, tasks in the trash and tasks of projects the user cannot see are not
+// searched.
+func (tr *TaskRepository) Search(ctx context.Context, search model.TaskSearch) ([]model.TaskSearchResult, error) {
 	var (
-		keyNumber = 1
-		err       error
+		r   model.TaskSearchResult
+		rs  = make([]model.TaskSearchResult, 0)
+		err error
 	)
 
-	if lastKey != "" {
-		ss := strings.Split(lastKey, "-")
-		keyNumber, err = strconv.Atoi(ss[1])
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return rs, web.CtxErr()
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return rs, err
+	}
+	defer Close()
+
+	var (
+		filters string
+		args    = []interface{}{search.Query, values.UserID}
+	)
+
+	filter := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		filters += fmt.Sprintf(" and "+clause, len(args))
+	}
+
+	if search.ProjectID != "" {
+		filter("t.project_id = $%d", search.ProjectID)
+	}
+	if search.AssignedTo != "" {
+		filter("t.assigned_to = $%d", search.AssignedTo)
+	}
+	if search.ColumnID != "" {
+		filter("t.column_id = $%d", search.ColumnID)
+	}
+	if search.CreatedAfter != nil {
+		filter("t.created_at >= $%d", search.CreatedAfter.UTC())
+	}
+	if search.CreatedBefore != nil {
+		filter("t.created_at < $%d", search.CreatedBefore.UTC())
+	}
+
+	args = append(args, search.Limit, search.Offset)
+
+	stmt := fmt.Sprintf(`
+		select
+			t.task_id, t.tenant_id, t.project_id, coalesce(t.column_id, '') as column_id, t.key, t.title, t.assigned_to,
+			ts_rank(t.search_vector, q) as score,
+			ts_headline('english', t.title, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') as title_highlight,
+			ts_headline('english', t.content, q, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') as content_highlight,
+			t.created_at
+		from tasks t, websearch_to_tsquery('english', $1) q
+		where t.search_vector @@ q and t.deleted_at is null and t.archived_at is null
+			and exists(select 1 from projects p where p.project_id = t.project_id and p.deleted_at is null)
+			and project_role(t.project_id, $2) is not null%s
+		order by score desc, t.created_at desc
+		limit $%d offset $%d
+	`, filters, len(args)-1, len(args))
+
+	rows, err := conn.QueryxContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(
+			&r.TaskID,
+			&r.TenantID,
+			&r.ProjectID,
+			&r.ColumnID,
+			&r.Key,
+			&r.Title,
+			&r.AssignedTo,
+			&r.Score,
+			&r.TitleHighlight,
+			&r.ContentHighlight,
+			&r.CreatedAt,
+		)
 		if err != nil {
-			return "", err
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
 		}
-		keyNumber = keyNumber + 1
+
+		r.CreatedAt = r.CreatedAt.UTC()
+
+		rs = append(rs, r)
 	}
 
-	return fmt.Sprintf("%s%d", prefix, keyNumber), nil
+	return rs, rows.Err()
 }
 
-// Create creates a project task in the database.
-func (tr *TaskRepository) Create(ctx context.Context, nt model.NewTask, pid string, now time.Time) (model.Task, error) {
-	var (
-		t    model.Task
-		last model.Task
-		p    model.Project
-		err  error
-	)
+// Query lists the tasks of the tenant matching a task query, or only those of a project
+// when the query names one. Archived tasks, tasks in the trash and tasks of projects the
+// user cannot see are left out.
+func (tr *TaskRepository) Query(ctx context.Context, q *query.Query, tq model.TaskQuery, now time.Time) ([]model.Task, error) {
+	var ts = make([]model.Task, 0)
 
 	values, ok := web.FromContext(ctx)
 	if !ok {
-		return t, web.CtxErr()
+		return ts, web.CtxErr()
 	}
 
-	pr := NewProjectRepository(tr.logger, tr.pg)
-	p, err = pr.Retrieve(ctx, pid)
-	if err != nil {
-		if err != nil {
-			return t, err
+	if tq.ProjectID != "" {
+		if _, err := uuid.Parse(tq.ProjectID); err != nil {
+			return ts, fail.ErrInvalidID
 		}
 	}
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
 	if err != nil {
-		return t, err
+		return ts, err
 	}
 	defer Close()
 
-	if _, err = uuid.Parse(values.UserID); err != nil {
-		return t, fail.ErrInvalidID
-	}
+	s := q.Compile(query.Env{UserID: values.UserID, Now: now}, 2)
+
+	args := append([]interface{}{values.UserID, tq.ProjectID}, s.Args...)
+	args = append(args, tq.Limit, tq.Offset)
 
-	stmt := `select key from tasks where project_id = $1 order by created_at desc limit 1`
+	stmt := fmt.Sprintf(selectTask+`
+		where `+liveTask(1)+` and tasks.archived_at is null and ($2 = '' or tasks.project_id = $2)
+			and %s
+		order by %s
+		limit $%d offset $%d
+	`, s.Where, s.OrderBy, len(args)-1, len(args))
 
-	err = conn.QueryRowxContext(ctx, stmt, pid).Scan(&last.Key)
+	rows, err := conn.QueryxContext(ctx, stmt, args...)
 	if err != nil {
-		if err != sql.ErrNoRows {
-			return t, err
+		return nil, fmt.Errorf("error querying tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
 		}
+		ts = append(ts, t)
 	}
 
-	key, err := formatKey(last.Key, p.Prefix)
+	return ts, rows.Err()
+}
+
+// Create creates a project task at the end of a column in the database.
+func (tr *TaskRepository) Create(ctx context.Context, nt model.NewTask, pid string, cid string, now time.Time) (model.Task, error) {
+	var (
+		t   model.Task
+		p   model.Project
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return t, web.CtxErr()
+	}
+
+	pr := NewProjectRepository(tr.logger, tr.pg)
+	p, err = pr.Retrieve(db.Primary(ctx), pid)
 	if err != nil {
-		return t, nil
+		return t, err
+	}
+
+	if _, err = uuid.Parse(cid); err != nil {
+		return t, fail.ErrInvalidID
+	}
+
+	if _, err = uuid.Parse(values.UserID); err != nil {
+		return t, fail.ErrInvalidID
 	}
 
 	t = model.Task{
 		ID:          uuid.New().String(),
-		Key:         key,
 		Title:       nt.Title,
 		TenantID:    values.TenantID,
 		UserID:      values.UserID,
 		ProjectID:   pid,
-		Comments:    make([]string, 0),
+		ColumnID:    cid,
+		Priority:    model.PriorityNone,
 		Attachments: make([]string, 0),
+		Labels:      make([]model.TaskLabel, 0),
+		Fields:      make(model.FieldValues),
+		Links:       make([]model.TaskLink, 0),
 		UpdatedAt:   now.Round(time.Microsecond).UTC(),
 		CreatedAt:   now.Round(time.Microsecond).UTC(),
 	}
 
-	stmt = `
-		insert into tasks (
-			task_id, tenant_id, key, title, content, user_id, assigned_to, 
-			attachments, comments, project_id, updated_at, created_at
-		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-	`
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var (
+			last string
+			err  error
+		)
+
+		if err = authorize(ctx, tx, pid, values.UserID, requiredRole(nt.Override)); err != nil {
+			return err
+		}
+
+		if err = lockColumn(ctx, tx, pid, cid); err != nil {
+			return err
+		}
+
+		if !nt.Override {
+			if err = checkColumnPolicies(ctx, tx, cid, t); err != nil {
+				return err
+			}
+		}
+
+		if t.Key, err = nextKey(ctx, tx, p); err != nil {
+			return err
+		}
 
-	if _, err = conn.ExecContext(
-		ctx,
-		stmt,
-		t.ID,
-		t.TenantID,
-		t.Key,
-		t.Title,
-		t.Content,
-		t.UserID,
-		t.AssignedTo,
-		pq.Array(t.Attachments),
-		pq.Array(t.Comments),
-		t.ProjectID,
-		t.UpdatedAt,
-		t.CreatedAt,
-	); err != nil {
-		return t, fmt.Errorf("error inserting tasks: %v: %w", nt, err)
+		stmt := `select coalesce(max(rank), '') from tasks where column_id = $1`
+		if err = tx.QueryRowxContext(ctx, stmt, cid).Scan(&last); err != nil {
+			return err
+		}
+
+		if t.Rank, err = rank.Between(last, ""); err != nil {
+			return err
+		}
+
+		stmt = `
+			insert into tasks (
+				task_id, tenant_id, key, title, content, user_id, assigned_to, 
+				attachments, project_id, column_id, rank, updated_at, created_at
+			) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		`
+
+		if _, err = tx.ExecContext(
+			ctx,
+			stmt,
+			t.ID,
+			t.TenantID,
+			t.Key,
+			t.Title,
+			t.Content,
+			t.UserID,
+			t.AssignedTo,
+			pq.Array(t.Attachments),
+			t.ProjectID,
+			t.ColumnID,
+			t.Rank,
+			t.UpdatedAt,
+			t.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("error inserting tasks: %v: %w", nt, err)
+		}
+
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskCreated, created(t), now)
+	})
+	if err != nil {
+		return model.Task{}, err
 	}
 
 	return t, nil
 }
 
+// nextKey allocates the next task key of a project. The sequence row stays locked until
+// commit, so concurrent creates in a project wait for each other and keys are never reused.
+func nextKey(ctx context.Context, tx *sqlx.Tx, p model.Project) (string, error) {
+	var seq int
+
+	stmt := `
+		insert into project_task_sequences (project_id, tenant_id, last_value)
+		values ($1, $2, 1)
+		on conflict (project_id) do update
+		set last_value = project_task_sequences.last_value + 1
+		returning last_value
+	`
+	if err := tx.QueryRowxContext(ctx, stmt, p.ID, p.TenantID).Scan(&seq); err != nil {
+		return "", fmt.Errorf("error incrementing task key sequence: %w", err)
+	}
+
+	return fmt.Sprintf("%s%d", p.Prefix, seq), nil
+}
+
+// Move moves a task to a position in a column in a single transaction.
+func (tr *TaskRepository) Move(ctx context.Context, tid string, mt model.MoveTask, now time.Time) (model.Task, error) {
+	var err error
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Task{}, web.CtxErr()
+	}
+
+	for _, id := range []string{tid, mt.To} {
+		if _, err = uuid.Parse(id); err != nil {
+			return model.Task{}, fail.ErrInvalidID
+		}
+	}
+	if mt.After == tid || mt.Before == tid {
+		return model.Task{}, fail.ErrInvalidMove
+	}
+
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t, err := lockTask(ctx, tx, tid)
+		if err != nil {
+			return err
+		}
+
+		if err = authorize(ctx, tx, t.ProjectID, values.UserID, requiredRole(mt.Override)); err != nil {
+			return err
+		}
+
+		if err = lockColumn(ctx, tx, t.ProjectID, mt.To); err != nil {
+			return err
+		}
+
+		if t.ColumnID != mt.To && !mt.Override {
+			stmt := `select coalesce(assigned_to, ''), coalesce(points, 0), due_at from tasks where task_id = $1`
+			if err = tx.QueryRowxContext(ctx, stmt, tid).Scan(&t.AssignedTo, &t.Points, &t.DueAt); err != nil {
+				return err
+			}
+			if err = checkColumnPolicies(ctx, tx, mt.To, t); err != nil {
+				return err
+			}
+		}
+
+		// A task cannot be finished while tasks blocking it are not.
+		if t.ColumnID != mt.To {
+			var done bool
+			stmt := `select task_done($1, $2)`
+			if err = tx.QueryRowxContext(ctx, stmt, t.ProjectID, mt.To).Scan(&done); err != nil {
+				return err
+			}
+			if done {
+				isBlocked, err := blocked(ctx, tx, tid)
+				if err != nil {
+					return err
+				}
+				if isBlocked {
+					return fail.ErrTaskBlocked
+				}
+			}
+		}
+
+		lo, hi, err := neighbours(ctx, tx, tid, mt)
+		if err != nil {
+			return err
+		}
+
+		r, err := rank.Between(lo, hi)
+		if err != nil {
+			return fail.ErrInvalidMove
+		}
+
+		stmt := `update tasks set column_id = $1, rank = $2, updated_at = $3 where task_id = $4`
+		if _, err = tx.ExecContext(ctx, stmt, mt.To, r, now.Round(time.Microsecond).UTC(), tid); err != nil {
+			return fmt.Errorf("error moving task %s: %w", tid, err)
+		}
+
+		changes := map[string]model.FieldChange{
+			"columnId": {From: t.ColumnID, To: mt.To},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskMoved, changes, now)
+	})
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return tr.Retrieve(db.Primary(ctx), tid)
+}
+
+// requiredRole returns the role needed to add a task to a column: overriding the
+// policies of the column takes a project owner.
+func requiredRole(override bool) string {
+	if override {
+		return model.RoleOwner
+	}
+	return model.RoleEditor
+}
+
+// neighbours returns the ranks the moved task is placed between. A missing neighbour
+// is taken from the tasks adjacent to the given one, so either may be omitted.
+func neighbours(ctx context.Context, tx *sqlx.Tx, tid string, mt model.MoveTask) (lo string, hi string, err error) {
+	rankOf := func(id string) (string, error) {
+		var r string
+		stmt := `select rank from tasks where task_id = $1 and column_id = $2`
+		if err := tx.QueryRowxContext(ctx, stmt, id, mt.To).Scan(&r); err != nil {
+			if err == sql.ErrNoRows {
+				return "", fail.ErrInvalidMove
+			}
+			return "", err
+		}
+		return r, nil
+	}
+
+	if mt.After != "" {
+		if lo, err = rankOf(mt.After); err != nil {
+			return "", "", err
+		}
+	}
+	if mt.Before != "" {
+		if hi, err = rankOf(mt.Before); err != nil {
+			return "", "", err
+		}
+	}
+
+	switch {
+	case mt.After != "" && mt.Before == "":
+		stmt := `select coalesce(min(rank), '') from tasks where column_id = $1 and rank > $2 and task_id <> $3`
+		err = tx.QueryRowxContext(ctx, stmt, mt.To, lo, tid).Scan(&hi)
+	case mt.After == "" && mt.Before != "":
+		stmt := `select coalesce(max(rank), '') from tasks where column_id = $1 and rank < $2 and task_id <> $3`
+		err = tx.QueryRowxContext(ctx, stmt, mt.To, hi, tid).Scan(&lo)
+	case mt.After == "" && mt.Before == "":
+		stmt := `select coalesce(max(rank), '') from tasks where column_id = $1 and task_id <> $2`
+		err = tx.QueryRowxContext(ctx, stmt, mt.To, tid).Scan(&lo)
+	}
+	return lo, hi, err
+}
+
 // Update updates a specific project task in the database.
 func (tr *TaskRepository) Update(ctx context.Context, tid string, update model.UpdateTask, now time.Time) (model.Task, error) {
 	var (
@@ -247,84 +713,414 @@ func (tr *TaskRepository) Update(ctx context.Context, tid string, update model.U
 		err error
 	)
 
-	t, err = tr.Retrieve(ctx, tid)
+	current, err := tr.Retrieve(db.Primary(ctx), tid)
 	if err != nil {
-		return t, err
+		return current, err
 	}
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return current, web.CtxErr()
+	}
+
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, current.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		// Apply the update to the locked row rather than the earlier read, so concurrent
+		// updates neither overwrite each other nor record changes they did not make.
+		before := current
+
+		stmt := `
+			select title, content, points, assigned_to, priority, start_at, due_at, attachments, original_estimate, remaining_estimate
+			from tasks where task_id = $1 for update
+		`
+		if err := tx.QueryRowxContext(ctx, stmt, tid).Scan(
+			&before.Title,
+			&before.Content,
+			&before.Points,
+			&before.AssignedTo,
+			&before.Priority,
+			&before.StartAt,
+			&before.DueAt,
+			(*pq.StringArray)(&before.Attachments),
+			&before.OriginalEstimate,
+			&before.RemainingEstimate,
+		); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return err
+		}
+		before.StartAt = utc(before.StartAt)
+		before.DueAt = utc(before.DueAt)
+
+		labels, err := labelsOf(ctx, tx, tid)
+		if err != nil {
+			return err
+		}
+		before.Labels = labels
+
+		if before.Fields, err = fieldsOf(ctx, tx, tid); err != nil {
+			return err
+		}
+
+		t = before
+
+		if update.Title != nil {
+			t.Title = *update.Title
+		}
+		if update.Content != nil {
+			t.Content = *update.Content
+		}
+		if update.Points != nil {
+			t.Points = *update.Points
+		}
+		if update.AssignedTo != nil {
+			t.AssignedTo = *update.AssignedTo
+		}
+		if update.Priority != nil {
+			t.Priority = *update.Priority
+		}
+		if update.StartAt != nil {
+			t.StartAt = utc(update.StartAt)
+		}
+		if update.ClearStartAt {
+			t.StartAt = nil
+		}
+		if update.DueAt != nil {
+			t.DueAt = utc(update.DueAt)
+		}
+		if update.ClearDueAt {
+			t.DueAt = nil
+		}
+		if update.Attachments != nil {
+			t.Attachments = update.Attachments
+		}
+		if update.OriginalEstimate != nil {
+			t.OriginalEstimate = *update.OriginalEstimate
+		}
+		if update.RemainingEstimate != nil {
+			t.RemainingEstimate = *update.RemainingEstimate
+		}
+
+		if t.StartAt != nil && t.DueAt != nil && t.StartAt.After(*t.DueAt) {
+			return fail.ErrInvalidSchedule
+		}
+
+		stmt = `
+			update tasks
+			set
+				title = $1,
+				content = $2,
+				points = $3,
+				assigned_to = $4,
+				priority = $5,
+				start_at = $6,
+				due_at = $7,
+				attachments = $8,
+				original_estimate = $9,
+				remaining_estimate = $10,
+				updated_at = $11
+			where task_id = $12
+		`
+
+		if _, err := tx.ExecContext(
+			ctx,
+			stmt,
+			t.Title,
+			t.Content,
+			t.Points,
+			t.AssignedTo,
+			t.Priority,
+			t.StartAt,
+			t.DueAt,
+			pq.Array(t.Attachments),
+			t.OriginalEstimate,
+			t.RemainingEstimate,
+			now.Round(time.Microsecond).UTC(),
+			t.ID,
+		); err != nil {
+			return fmt.Errorf("error updating task: %s: %w", tid, err)
+		}
+
+		if update.Labels != nil {
+			if t.Labels, err = setLabels(ctx, tx, t, update.Labels); err != nil {
+				return err
+			}
+		}
+		if len(update.Fields) > 0 {
+			if t.Fields, err = setFields(ctx, tx, t, update.Fields); err != nil {
+				return err
+			}
+		}
+
+		changes := diff(before, t)
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskUpdated, changes, now)
+	})
 	if err != nil {
-		return t, fail.ErrConnectionFailed
+		return model.Task{}, err
+	}
+
+	return t, nil
+}
+
+// labelsOf returns the labels of a task by name.
+func labelsOf(ctx context.Context, tx *sqlx.Tx, tid string) ([]model.TaskLabel, error) {
+	var ls = make([]model.TaskLabel, 0)
+
+	stmt := `
+		select l.label_id, l.name, l.color
+		from task_labels tl join labels l on l.label_id = tl.label_id
+		where tl.task_id = $1
+		order by lower(l.name)
+	`
+
+	rows, err := tx.QueryxContext(ctx, stmt, tid)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting task labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l model.TaskLabel
+		if err = rows.Scan(&l.ID, &l.Name, &l.Color); err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		ls = append(ls, l)
 	}
-	defer Close()
 
-	if update.Title != nil {
-		t.Title = *update.Title
+	return ls, rows.Err()
+}
+
+// setLabels replaces the labels of a task. Every label must belong to the project of the task.
+func setLabels(ctx context.Context, tx *sqlx.Tx, t model.Task, lids []string) ([]model.TaskLabel, error) {
+	stmt := `delete from task_labels where task_id = $1`
+	if _, err := tx.ExecContext(ctx, stmt, t.ID); err != nil {
+		return nil, fmt.Errorf("error removing task labels: %s: %w", t.ID, err)
 	}
-	if update.Content != nil {
-		t.Content = *update.Content
+
+	stmt = `
+		insert into task_labels (task_id, label_id, tenant_id)
+		select $1, label_id, tenant_id from labels where label_id = any($2) and project_id = $3
+	`
+	res, err := tx.ExecContext(ctx, stmt, t.ID, pq.Array(lids), t.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error adding task labels: %s: %w", t.ID, err)
 	}
-	if update.Points != nil {
-		t.Points = *update.Points
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(lids) {
+		return nil, fail.ErrInvalidLabel
+	}
+
+	return labelsOf(ctx, tx, t.ID)
+}
+
+// Delete moves a project task to the trash. Deleting a task already in the trash does nothing.
+func (tr *TaskRepository) Delete(ctx context.Context, tid string, now time.Time) error {
+	var err error
+
+	if _, err = uuid.Parse(tid); err != nil {
+		return fail.ErrInvalidID
 	}
-	if update.AssignedTo != nil {
-		t.AssignedTo = *update.AssignedTo
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
 	}
-	if update.Attachments != nil {
-		t.Attachments = update.Attachments
+
+	return tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t := model.Task{ID: tid}
+
+		stmt := `
+			update tasks set deleted_at = $1
+			where task_id = $2 and deleted_at is null
+			returning tenant_id, project_id, key, title
+		`
+		if err := tx.QueryRowxContext(ctx, stmt, now.Round(time.Microsecond).UTC(), tid).Scan(&t.TenantID, &t.ProjectID, &t.Key, &t.Title); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("error deleting task %s: %w", tid, err)
+		}
+
+		if err := authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		changes := map[string]model.FieldChange{
+			"key":   {From: t.Key},
+			"title": {From: t.Title},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskDeleted, changes, now)
+	})
+}
+
+// Restore takes a project task out of the trash. Tasks of projects in the trash are
+// restored with their project.
+func (tr *TaskRepository) Restore(ctx context.Context, tid string, now time.Time) (model.Task, error) {
+	var err error
+
+	if _, err = uuid.Parse(tid); err != nil {
+		return model.Task{}, fail.ErrInvalidID
 	}
-	if update.Comments != nil {
-		t.Comments = update.Comments
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Task{}, web.CtxErr()
 	}
 
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t := model.Task{ID: tid}
+
+		stmt := `
+			update tasks set deleted_at = null, updated_at = $1
+			where task_id = $2 and deleted_at is not null
+				and exists(select 1 from projects p where p.project_id = tasks.project_id and p.deleted_at is null)
+			returning tenant_id, project_id, key, title, coalesce(column_id, '')
+		`
+		if err := tx.QueryRowxContext(ctx, stmt, now.Round(time.Microsecond).UTC(), tid).Scan(&t.TenantID, &t.ProjectID, &t.Key, &t.Title, &t.ColumnID); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return fmt.Errorf("error restoring task %s: %w", tid, err)
+		}
+
+		if err := authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		// Tasks trashed with their column go to the end of the first column of the board.
+		if t.ColumnID == "" {
+			if err := appendToFirstColumn(ctx, tx, t.ProjectID, tid); err != nil {
+				return err
+			}
+		}
+
+		changes := map[string]model.FieldChange{
+			"key":   {To: t.Key},
+			"title": {To: t.Title},
+		}
+		return recordEvent(ctx, tx, t, values.UserID, model.TaskRestored, changes, now)
+	})
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	return tr.Retrieve(db.Primary(ctx), tid)
+}
+
+// appendToFirstColumn places a task without a column at the end of the first column of
+// its project board.
+func appendToFirstColumn(ctx context.Context, tx *sqlx.Tx, pid string, tid string) error {
+	var cid, last string
+
 	stmt := `
-		update tasks
-		set
-			title = $1,
-			content = $2,
-			assigned_to = $3,
-			comments = $4,
-			attachments = $5,
-			updated_at = $6
-		where task_id = $7
+		select c.column_id
+		from projects p
+		join columns c on c.project_id = p.project_id and c.column_name = p.column_order[1]
+		where p.project_id = $1
 	`
+	if err := tx.QueryRowxContext(ctx, stmt, pid).Scan(&cid); err != nil {
+		return fmt.Errorf("error selecting first column of project %s: %w", pid, err)
+	}
+	if err := lockColumn(ctx, tx, pid, cid); err != nil {
+		return err
+	}
 
-	if _, err = conn.ExecContext(
-		ctx,
-		stmt,
-		t.Title,
-		t.Content,
-		t.AssignedTo,
-		pq.Array(t.Comments),
-		pq.Array(t.Attachments),
-		now.Round(time.Microsecond).UTC(),
-		t.ID,
-	); err != nil {
-		return t, fmt.Errorf("error updating task: %s: %w", tid, err)
+	stmt = `select coalesce(max(rank), '') from tasks where column_id = $1`
+	if err := tx.QueryRowxContext(ctx, stmt, cid).Scan(&last); err != nil {
+		return err
+	}
+	next, err := rank.Between(last, "")
+	if err != nil {
+		return err
 	}
 
-	return t, nil
+	stmt = `update tasks set column_id = $1, rank = $2 where task_id = $3`
+	if _, err = tx.ExecContext(ctx, stmt, cid, next, tid); err != nil {
+		return fmt.Errorf("error restoring task %s to column %s: %w", tid, cid, err)
+	}
+	return nil
+}
+
+// Archive archives a project task, hiding it from the board and from listings.
+func (tr *TaskRepository) Archive(ctx context.Context, tid string, now time.Time) (model.Task, error) {
+	return tr.setArchived(ctx, tid, true, now)
+}
+
+// Unarchive puts an archived project task back on the board.
+func (tr *TaskRepository) Unarchive(ctx context.Context, tid string, now time.Time) (model.Task, error) {
+	return tr.setArchived(ctx, tid, false, now)
 }
 
-// Delete deletes a specific project task from the database.
-func (tr *TaskRepository) Delete(ctx context.Context, tid string) error {
+// setArchived archives or unarchives a task, recording an event when it changes.
+func (tr *TaskRepository) setArchived(ctx context.Context, tid string, archived bool, now time.Time) (model.Task, error) {
 	var err error
 
 	if _, err = uuid.Parse(tid); err != nil {
-		return fail.ErrInvalidID
+		return model.Task{}, fail.ErrInvalidID
 	}
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
-	if err != nil {
-		return err
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Task{}, web.CtxErr()
 	}
-	defer Close()
 
-	stmt := `delete from tasks where task_id = $1`
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		t, err := lockTask(ctx, tx, tid)
+		if err != nil {
+			return err
+		}
+
+		if err = authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		var at *time.Time
+		if archived {
+			a := now.Round(time.Microsecond).UTC()
+			at = &a
+		}
+
+		stmt := `
+			update tasks set archived_at = $1, updated_at = $2
+			where task_id = $3 and (archived_at is null) = $4
+		`
+		res, err := tx.ExecContext(ctx, stmt, at, now.Round(time.Microsecond).UTC(), tid, archived)
+		if err != nil {
+			return fmt.Errorf("error archiving task %s: %w", tid, err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
 
-	if _, err = conn.ExecContext(ctx, stmt, tid); err != nil {
-		return fmt.Errorf("error deleting task %s: %w", tid, err)
+		kind := model.TaskUnarchived
+		if archived {
+			kind = model.TaskArchived
+		}
+		return recordEvent(ctx, tx, t, values.UserID, kind, nil, now)
+	})
+	if err != nil {
+		return model.Task{}, err
 	}
 
-	return nil
+	return tr.Retrieve(db.Primary(ctx), tid)
+}
+
+// utc returns an optional time in UTC.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
 }
@@ -2,17 +2,21 @@ package repository_test
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/devpies/saas-core/internal/project/fail"
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/query"
 	"github.com/devpies/saas-core/internal/project/repository"
 	"github.com/devpies/saas-core/internal/project/res/testutils"
 	"github.com/devpies/saas-core/pkg/web"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -84,7 +88,7 @@ func TestTaskRepository_Create(t *testing.T) {
 			nt := model.NewTask{
 				Title: "Testing",
 			}
-			newTask, err := repo.Create(tc.ctx, nt, tc.projectID, time.Now())
+			newTask, err := repo.Create(tc.ctx, nt, tc.projectID, testColumns[0].ID, time.Now())
 			tc.expectations(t, tc.ctx, repo, newTask, err)
 		})
 	}
@@ -213,12 +217,46 @@ func TestTaskRepository_List(t *testing.T) {
 			defer Close()
 
 			repo := repository.NewTaskRepository(zap.NewNop(), db)
-			list, err := repo.List(tc.ctx, tc.projectID)
+			list, err := repo.List(tc.ctx, tc.projectID, model.TaskFilter{})
 			tc.expectations(t, tc.ctx, list, err)
 		})
 	}
 }
 
+func TestTaskRepository_ListOverdue(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	var done model.Column
+	for _, c := range testColumns {
+		if c.ProjectID == project.ID && c.ColumnName == project.ColumnOrder[len(project.ColumnOrder)-1] {
+			done = c
+		}
+	}
+	require.NotEmpty(t, done.ID)
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	now := time.Now()
+	due := now.Add(-24 * time.Hour)
+	for _, task := range testTasks[:2] {
+		_, err := repo.Update(ctx, task.ID, model.UpdateTask{DueAt: &due}, now)
+		require.NoError(t, err)
+	}
+
+	// Finished tasks are not overdue.
+	_, err := repo.Move(ctx, testTasks[0].ID, model.MoveTask{To: done.ID}, now)
+	require.NoError(t, err)
+
+	actual, err := repo.List(ctx, project.ID, model.TaskFilter{Overdue: true})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, testTasks[1].ID, actual[0].ID)
+}
+
 func TestTaskRepository_Update(t *testing.T) {
 	expectedTenantID := testProjects[0].TenantID
 	expectedTask := testTasks[0]
@@ -232,7 +270,7 @@ func TestTaskRepository_Update(t *testing.T) {
 	}{
 		{
 			name:   "successfully updated task title",
-			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
 			taskID: expectedTask.ID,
 			update: model.UpdateTask{
 				Title: aws.String("Updated"),
@@ -246,7 +284,7 @@ func TestTaskRepository_Update(t *testing.T) {
 		},
 		{
 			name:   "successfully updated task points",
-			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
 			taskID: expectedTask.ID,
 			update: model.UpdateTask{
 				Points: aws.Int(3),
@@ -260,7 +298,7 @@ func TestTaskRepository_Update(t *testing.T) {
 		},
 		{
 			name:   "successfully updated task content",
-			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
 			taskID: expectedTask.ID,
 			update: model.UpdateTask{
 				Content: aws.String("Updated"),
@@ -274,7 +312,7 @@ func TestTaskRepository_Update(t *testing.T) {
 		},
 		{
 			name:   "successfully updated task assigned to field",
-			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
 			taskID: expectedTask.ID,
 			update: model.UpdateTask{
 				AssignedTo: aws.String("Updated"),
@@ -288,7 +326,7 @@ func TestTaskRepository_Update(t *testing.T) {
 		},
 		{
 			name:   "successfully updated task attachments",
-			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
 			taskID: expectedTask.ID,
 			update: model.UpdateTask{
 				Attachments: []string{"Updated", "Updated"},
@@ -302,7 +340,7 @@ func TestTaskRepository_Update(t *testing.T) {
 		},
 		{
 			name:   "task id not UUID",
-			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID}),
+			ctx:    web.NewContext(testutils.MockCtx, &web.Values{TenantID: expectedTenantID, UserID: expectedTask.UserID}),
 			taskID: "mock",
 			update: model.UpdateTask{},
 			expectations: func(t *testing.T, ctx context.Context, expected model.Task, actual model.Task, err error) {
@@ -345,6 +383,63 @@ func TestTaskRepository_Update(t *testing.T) {
 	}
 }
 
+func TestTaskRepository_ConcurrentUpdates(t *testing.T) {
+	task := testTasks[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: task.TenantID, UserID: task.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+	activity := repository.NewActivityRepository(zap.NewNop(), db)
+
+	// Each update changes a different field, so none of them may be lost.
+	updates := map[string]model.UpdateTask{
+		"title":      {Title: aws.String("Concurrent title")},
+		"content":    {Content: aws.String("Concurrent content")},
+		"points":     {Points: aws.Int(8)},
+		"priority":   {Priority: aws.String(model.PriorityHigh)},
+		"assignedTo": {AssignedTo: aws.String(testutils.MockUUID)},
+	}
+
+	var wg sync.WaitGroup
+	for _, update := range updates {
+		wg.Add(1)
+		go func(update model.UpdateTask) {
+			defer wg.Done()
+			_, err := repo.Update(ctx, task.ID, update, time.Now())
+			assert.NoError(t, err)
+		}(update)
+	}
+	wg.Wait()
+
+	actual, err := repo.Retrieve(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Concurrent title", actual.Title)
+	assert.Equal(t, "Concurrent content", actual.Content)
+	assert.Equal(t, 8, actual.Points)
+	assert.Equal(t, model.PriorityHigh, actual.Priority)
+	assert.Equal(t, testutils.MockUUID, actual.AssignedTo)
+
+	// Each event records the field its update changed and nothing else.
+	events, err := activity.ListByTask(ctx, task.ID, model.ActivityPage{Limit: 100})
+	require.NoError(t, err)
+
+	changed := make(map[string]int)
+	for _, e := range events {
+		if e.Kind != model.TaskUpdated {
+			continue
+		}
+		assert.Len(t, e.Changes, 1)
+		for field := range e.Changes {
+			changed[field]++
+		}
+	}
+	for field := range updates {
+		assert.Equal(t, 1, changed[field], field)
+	}
+}
+
 func TestTaskRepository_Delete(t *testing.T) {
 	expectedTenantID := testProjects[0].TenantID
 	expectedTask := testTasks[0]
@@ -402,8 +497,231 @@ func TestTaskRepository_Delete(t *testing.T) {
 			defer Close()
 
 			repo := repository.NewTaskRepository(zap.NewNop(), db)
-			err := repo.Delete(tc.ctx, tc.taskID)
+			err := repo.Delete(tc.ctx, tc.taskID, time.Now())
 			tc.expectations(t, tc.ctx, repo, err)
 		})
 	}
 }
+
+func TestTaskRepository_Move(t *testing.T) {
+	first, second := testTasks[0], testTasks[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: first.TenantID, UserID: first.UserID})
+
+	tests := []struct {
+		name         string
+		taskID       string
+		move         model.MoveTask
+		expectations func(t *testing.T, repo *repository.TaskRepository, actual model.Task, err error)
+	}{
+		{
+			name:   "move before a task",
+			taskID: second.ID,
+			move:   model.MoveTask{To: first.ColumnID, Before: first.ID},
+			expectations: func(t *testing.T, repo *repository.TaskRepository, actual model.Task, err error) {
+				assert.Nil(t, err)
+				list, err := repo.List(ctx, first.ProjectID, model.TaskFilter{})
+				assert.Nil(t, err)
+				assert.Equal(t, []string{second.ID, first.ID}, []string{list[0].ID, list[1].ID})
+			},
+		},
+		{
+			name:   "move to another column",
+			taskID: first.ID,
+			move:   model.MoveTask{To: testColumns[5].ID},
+			expectations: func(t *testing.T, repo *repository.TaskRepository, actual model.Task, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, testColumns[5].ID, actual.ColumnID)
+			},
+		},
+		{
+			name:   "neighbour in another column",
+			taskID: first.ID,
+			move:   model.MoveTask{To: testColumns[5].ID, After: second.ID},
+			expectations: func(t *testing.T, repo *repository.TaskRepository, actual model.Task, err error) {
+				assert.Equal(t, fail.ErrInvalidMove, err)
+			},
+		},
+		{
+			name:   "neighbours out of order",
+			taskID: first.ID,
+			move:   model.MoveTask{To: first.ColumnID, After: second.ID, Before: second.ID},
+			expectations: func(t *testing.T, repo *repository.TaskRepository, actual model.Task, err error) {
+				assert.Equal(t, fail.ErrInvalidMove, err)
+			},
+		},
+		{
+			name:   "column of another project",
+			taskID: first.ID,
+			move:   model.MoveTask{To: testColumns[0].ID},
+			expectations: func(t *testing.T, repo *repository.TaskRepository, actual model.Task, err error) {
+				assert.Equal(t, fail.ErrNotFound, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, Close := dbConnect.AsNonRoot()
+			defer Close()
+
+			repo := repository.NewTaskRepository(zap.NewNop(), db)
+			actual, err := repo.Move(ctx, tc.taskID, tc.move, time.Now())
+			tc.expectations(t, repo, actual, err)
+		})
+	}
+}
+
+func TestTaskRepository_Keys(t *testing.T) {
+	project := testProjects[1]
+	column := testColumns[4]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	t.Run("keys are not reused after delete", func(t *testing.T) {
+		task, err := repo.Create(ctx, model.NewTask{Title: "Testing"}, project.ID, column.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, project.Prefix+"3", task.Key)
+
+		require.NoError(t, repo.Delete(ctx, task.ID, time.Now()))
+
+		task, err = repo.Create(ctx, model.NewTask{Title: "Testing"}, project.ID, column.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, project.Prefix+"4", task.Key)
+	})
+
+	t.Run("concurrent creates get distinct keys", func(t *testing.T) {
+		const n = 8
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			keys = make(map[string]bool)
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				task, err := repo.Create(ctx, model.NewTask{Title: "Testing"}, project.ID, column.ID, time.Now())
+				assert.NoError(t, err)
+				mu.Lock()
+				keys[task.Key] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, keys, n)
+	})
+
+	t.Run("retrieve by key ignores case", func(t *testing.T) {
+		actual, err := repo.RetrieveByKey(ctx, project.ID, strings.ToLower(testTasks[0].Key))
+		assert.Nil(t, err)
+		assert.Equal(t, testTasks[0].ID, actual.ID)
+
+		_, err = repo.RetrieveByKey(ctx, project.ID, project.Prefix+"999")
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
+
+func TestTaskRepository_Search(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+	comments := repository.NewCommentRepository(zap.NewNop(), db)
+
+	t.Run("title matches are highlighted", func(t *testing.T) {
+		actual, err := repo.Search(ctx, model.TaskSearch{Query: "design", Limit: 10})
+		assert.Nil(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, testTasks[0].ID, actual[0].TaskID)
+		assert.Equal(t, "<mark>Design</mark> it", actual[0].TitleHighlight)
+	})
+
+	t.Run("filters narrow the results", func(t *testing.T) {
+		actual, err := repo.Search(ctx, model.TaskSearch{Query: "content", ProjectID: project.ID, Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, actual, 2)
+
+		actual, err = repo.Search(ctx, model.TaskSearch{Query: "content", ProjectID: testutils.MockUUID, Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, actual, 0)
+	})
+
+	t.Run("comments are searchable", func(t *testing.T) {
+		_, err := comments.Create(ctx, model.NewComment{Content: "Blocked by the zeppelin"}, testTasks[1].ID, time.Now())
+		require.NoError(t, err)
+
+		actual, err := repo.Search(ctx, model.TaskSearch{Query: "zeppelin", Limit: 10})
+		assert.Nil(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, testTasks[1].ID, actual[0].TaskID)
+	})
+
+	t.Run("other tenants see nothing", func(t *testing.T) {
+		other := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID})
+		actual, err := repo.Search(other, model.TaskSearch{Query: "design", Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, actual, 0)
+	})
+}
+
+func TestTaskRepository_Query(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	run := func(t *testing.T, ctx context.Context, s string, tq model.TaskQuery) []model.Task {
+		q, err := query.Parse(s)
+		require.NoError(t, err)
+
+		tq.Limit = 10
+		actual, err := repo.Query(ctx, q, tq, now)
+		require.NoError(t, err)
+		return actual
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		actual := run(t, ctx, "title ~ it order by created asc", model.TaskQuery{})
+		require.Len(t, actual, 2)
+		assert.Equal(t, testTasks[0].ID, actual[0].ID)
+		assert.Equal(t, testTasks[1].ID, actual[1].ID)
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		_, err := repo.Update(ctx, testTasks[1].ID, model.UpdateTask{AssignedTo: aws.String(project.UserID), Points: aws.Int(5)}, now)
+		require.NoError(t, err)
+
+		actual := run(t, ctx, "assignee = me and points > 3 and column = 'to do'", model.TaskQuery{})
+		require.Len(t, actual, 1)
+		assert.Equal(t, testTasks[1].ID, actual[0].ID)
+
+		actual = run(t, ctx, "not (assignee = me) or label is not empty", model.TaskQuery{})
+		require.Len(t, actual, 1)
+		assert.Equal(t, testTasks[0].ID, actual[0].ID)
+	})
+
+	t.Run("project", func(t *testing.T) {
+		actual := run(t, ctx, "", model.TaskQuery{ProjectID: testutils.MockUUID})
+		assert.Len(t, actual, 0)
+	})
+
+	t.Run("other tenants see nothing", func(t *testing.T) {
+		other := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID, UserID: project.UserID})
+		actual := run(t, other, "", model.TaskQuery{})
+		assert.Len(t, actual, 0)
+	})
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// TeamRepository manages data access to tenant teams.
+type TeamRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewTeamRepository returns a new TeamRepository.
+func NewTeamRepository(logger *zap.Logger, pg *db.PostgresDatabase) *TeamRepository {
+	return &TeamRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// selectTeam selects teams with their members and the projects they are assigned to.
+const selectTeam = `
+	select
+		team_id, tenant_id, name, user_id,
+		array(select user_id from team_members m where m.team_id = teams.team_id order by m.created_at) as members,
+		array(select project_id from projects p where p.team_id = teams.team_id and p.deleted_at is null order by p.created_at) as projects,
+		updated_at, created_at
+	from teams
+`
+
+func scanTeam(row interface{ Scan(...interface{}) error }) (model.Team, error) {
+	var t model.Team
+
+	err := row.Scan(
+		&t.ID,
+		&t.TenantID,
+		&t.Name,
+		&t.UserID,
+		(*pq.StringArray)(&t.Members),
+		(*pq.StringArray)(&t.Projects),
+		&t.UpdatedAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		return t, err
+	}
+
+	t.UpdatedAt = t.UpdatedAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+
+	return t, nil
+}
+
+// List lists the teams of the tenant by name.
+func (tr *TeamRepository) List(ctx context.Context) ([]model.Team, error) {
+	var ts = make([]model.Team, 0)
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return ts, err
+	}
+	defer Close()
+
+	rows, err := conn.QueryxContext(ctx, selectTeam+` order by lower(name)`)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting teams :%w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row into struct :%w", err)
+		}
+		ts = append(ts, t)
+	}
+
+	return ts, rows.Err()
+}
+
+// Retrieve retrieves a team from the database.
+func (tr *TeamRepository) Retrieve(ctx context.Context, teamID string) (model.Team, error) {
+	var (
+		t   model.Team
+		err error
+	)
+
+	if _, err = uuid.Parse(teamID); err != nil {
+		return t, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return t, err
+	}
+	defer Close()
+
+	t, err = scanTeam(conn.QueryRowxContext(ctx, selectTeam+` where team_id = $1`, teamID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return t, fail.ErrNotFound
+		}
+		return t, err
+	}
+
+	return t, nil
+}
+
+// Create creates a team in the database. The user creating the team is its first member.
+func (tr *TeamRepository) Create(ctx context.Context, nt model.NewTeam, now time.Time) (model.Team, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Team{}, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return model.Team{}, fail.ErrInvalidID
+	}
+
+	t := model.Team{
+		ID:        uuid.New().String(),
+		TenantID:  values.TenantID,
+		Name:      nt.Name,
+		UserID:    values.UserID,
+		Members:   []string{values.UserID},
+		Projects:  make([]string, 0),
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err := tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		stmt := `
+			insert into teams (team_id, tenant_id, name, user_id, updated_at, created_at)
+			values ($1, $2, $3, $4, $5, $6)
+		`
+		if _, err := tx.ExecContext(ctx, stmt, t.ID, t.TenantID, t.Name, t.UserID, t.UpdatedAt, t.CreatedAt); err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrDuplicateTeam
+			}
+			return fmt.Errorf("error inserting team: %s: %w", nt.Name, err)
+		}
+
+		stmt = `insert into team_members (team_id, user_id, tenant_id, created_at) values ($1, $2, $3, $4)`
+		if _, err := tx.ExecContext(ctx, stmt, t.ID, t.UserID, t.TenantID, t.CreatedAt); err != nil {
+			return fmt.Errorf("error inserting team member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Team{}, err
+	}
+
+	return t, nil
+}
+
+// Delete deletes a team managed by the user. Projects assigned the team are left
+// without one.
+func (tr *TeamRepository) Delete(ctx context.Context, teamID string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(teamID); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	return tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := lockManagedTeam(ctx, tx, teamID, values.UserID); err != nil {
+			return err
+		}
+
+		stmt := `delete from teams where team_id = $1`
+		if _, err := tx.ExecContext(ctx, stmt, teamID); err != nil {
+			return fmt.Errorf("error deleting team %s :%w", teamID, err)
+		}
+		return nil
+	})
+}
+
+// AddMember adds a user to a team managed by the user. Adding a member twice does nothing.
+func (tr *TeamRepository) AddMember(ctx context.Context, teamID string, uid string, now time.Time) (model.Team, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Team{}, web.CtxErr()
+	}
+
+	for _, id := range []string{teamID, uid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return model.Team{}, fail.ErrInvalidID
+		}
+	}
+
+	err := tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := lockManagedTeam(ctx, tx, teamID, values.UserID); err != nil {
+			return err
+		}
+
+		stmt := `
+			insert into team_members (team_id, user_id, tenant_id, created_at)
+			values ($1, $2, $3, $4)
+			on conflict do nothing
+		`
+		if _, err := tx.ExecContext(ctx, stmt, teamID, uid, values.TenantID, now.Round(time.Microsecond).UTC()); err != nil {
+			return fmt.Errorf("error inserting team member %s :%w", uid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Team{}, err
+	}
+
+	return tr.Retrieve(db.Primary(ctx), teamID)
+}
+
+// RemoveMember removes a user from a team. The manager of the team removes anyone,
+// other members only themselves.
+func (tr *TeamRepository) RemoveMember(ctx context.Context, teamID string, uid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	for _, id := range []string{teamID, uid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return fail.ErrInvalidID
+		}
+	}
+
+	return tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if uid != values.UserID {
+			if err := lockManagedTeam(ctx, tx, teamID, values.UserID); err != nil {
+				return err
+			}
+		}
+
+		stmt := `delete from team_members where team_id = $1 and user_id = $2`
+		res, err := tx.ExecContext(ctx, stmt, teamID, uid)
+		if err != nil {
+			return fmt.Errorf("error removing team member %s :%w", uid, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fail.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// lockManagedTeam locks a team and checks the user manages it.
+func lockManagedTeam(ctx context.Context, tx *sqlx.Tx, teamID string, userID string) error {
+	var manager string
+
+	stmt := `select user_id from teams where team_id = $1 for update`
+	if err := tx.QueryRowxContext(ctx, stmt, teamID).Scan(&manager); err != nil {
+		if err == sql.ErrNoRows {
+			return fail.ErrNotFound
+		}
+		return err
+	}
+	if manager != userID {
+		return fail.ErrNotAuthorized
+	}
+
+	return nil
+}
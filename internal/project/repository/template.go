@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/rank"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// TemplateRepository manages data access to tenant project templates.
+type TemplateRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewTemplateRepository returns a new TemplateRepository.
+func NewTemplateRepository(logger *zap.Logger, pg *db.PostgresDatabase) *TemplateRepository {
+	return &TemplateRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectTemplate = `
+	select template_id, tenant_id, name, description, definition, user_id, updated_at, created_at
+	from project_templates
+`
+
+func scanTemplate(row interface{ Scan(...interface{}) error }) (model.Template, error) {
+	var (
+		t   model.Template
+		def []byte
+	)
+
+	if err := row.Scan(&t.ID, &t.TenantID, &t.Name, &t.Description, &def, &t.UserID, &t.UpdatedAt, &t.CreatedAt); err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(def, &t.TemplateDefinition); err != nil {
+		return t, fmt.Errorf("error decoding template definition: %w", err)
+	}
+
+	t.UpdatedAt = t.UpdatedAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+
+	return t, nil
+}
+
+// Retrieve retrieves a tenant template from the database.
+func (tr *TemplateRepository) Retrieve(ctx context.Context, tmid string) (model.Template, error) {
+	var (
+		t   model.Template
+		err error
+	)
+
+	if _, err = uuid.Parse(tmid); err != nil {
+		return t, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return t, err
+	}
+	defer Close()
+
+	t, err = scanTemplate(conn.QueryRowxContext(ctx, selectTemplate+` where template_id = $1`, tmid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return t, fail.ErrNotFound
+		}
+		return t, err
+	}
+
+	return t, nil
+}
+
+// List lists the tenant templates in the database by name.
+func (tr *TemplateRepository) List(ctx context.Context) ([]model.Template, error) {
+	var ts = make([]model.Template, 0)
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return ts, err
+	}
+	defer Close()
+
+	rows, err := conn.QueryxContext(ctx, selectTemplate+` order by lower(name)`)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting templates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		ts = append(ts, t)
+	}
+
+	return ts, rows.Err()
+}
+
+// Create creates a tenant template in the database.
+func (tr *TemplateRepository) Create(ctx context.Context, nt model.NewTemplate, now time.Time) (model.Template, error) {
+	var (
+		t   model.Template
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return t, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(values.UserID); err != nil {
+		return t, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetConnection(ctx)
+	if err != nil {
+		return t, err
+	}
+	defer Close()
+
+	t = model.Template{
+		ID:                 uuid.New().String(),
+		TenantID:           values.TenantID,
+		Name:               nt.Name,
+		Description:        nt.Description,
+		TemplateDefinition: nt.TemplateDefinition,
+		UserID:             values.UserID,
+		UpdatedAt:          now.Round(time.Microsecond).UTC(),
+		CreatedAt:          now.Round(time.Microsecond).UTC(),
+	}
+
+	def, err := json.Marshal(t.TemplateDefinition)
+	if err != nil {
+		return model.Template{}, fmt.Errorf("error encoding template definition: %w", err)
+	}
+
+	stmt := `
+		insert into project_templates (
+			template_id, tenant_id, name, description, definition, user_id, updated_at, created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err = conn.ExecContext(ctx, stmt, t.ID, t.TenantID, t.Name, t.Description, def, t.UserID, t.UpdatedAt, t.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return model.Template{}, fail.ErrDuplicateTemplate
+		}
+		return model.Template{}, fmt.Errorf("error inserting template: %s: %w", nt.Name, err)
+	}
+	db.Wrote(ctx)
+
+	return t, nil
+}
+
+// Delete deletes a tenant template from the database.
+func (tr *TemplateRepository) Delete(ctx context.Context, tmid string) error {
+	var err error
+
+	if _, err = uuid.Parse(tmid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer Close()
+
+	stmt := `delete from project_templates where template_id = $1`
+
+	if _, err = conn.ExecContext(ctx, stmt, tmid); err != nil {
+		return fmt.Errorf("error deleting template %s: %w", tmid, err)
+	}
+	db.Wrote(ctx)
+
+	return nil
+}
+
+// Apply creates the columns, labels and seed tasks of a template on a new project board
+// in a single transaction.
+func (tr *TemplateRepository) Apply(ctx context.Context, pid string, td model.TemplateDefinition, now time.Time) error {
+	var err error
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	pr := NewProjectRepository(tr.logger, tr.pg)
+	p, err := pr.Retrieve(db.Primary(ctx), pid)
+	if err != nil {
+		return err
+	}
+
+	at := now.Round(time.Microsecond).UTC()
+
+	return tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := lockColumnOrder(ctx, tx, pid); err != nil {
+			return err
+		}
+
+		var exists bool
+		stmt := `select exists(select 1 from columns where project_id = $1)`
+		if err := tx.QueryRowxContext(ctx, stmt, pid).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("error applying template: project %s already has columns", pid)
+		}
+
+		columns := make([]string, len(td.Columns))
+		for i, c := range td.Columns {
+			columns[i] = uuid.New().String()
+
+			stmt = `
+				insert into columns (
+					column_id, tenant_id, title, column_name, wip_limit,
+					project_id, updated_at, created_at
+				) values ($1, $2, $3, $4, $5, $6, $7, $8)
+			`
+			if _, err := tx.ExecContext(ctx, stmt, columns[i], p.TenantID, c.Title, model.ColumnName(i), c.WIPLimit, pid, at, at); err != nil {
+				return fmt.Errorf("error inserting column: %+v :%w", c, err)
+			}
+		}
+
+		stmt = `update projects set column_order = $1, updated_at = $2 where project_id = $3`
+		if _, err := tx.ExecContext(ctx, stmt, pq.Array(td.ColumnOrder()), at, pid); err != nil {
+			return fmt.Errorf("error updating column order :%w", err)
+		}
+
+		labels := make(map[string]string, len(td.Labels))
+		for _, l := range td.Labels {
+			lid := uuid.New().String()
+			labels[strings.ToLower(l.Name)] = lid
+
+			stmt = `
+				insert into labels (label_id, tenant_id, project_id, name, color, updated_at, created_at)
+				values ($1, $2, $3, $4, $5, $6, $7)
+			`
+			if _, err := tx.ExecContext(ctx, stmt, lid, p.TenantID, pid, l.Name, l.Color, at, at); err != nil {
+				if isUniqueViolation(err) {
+					return fail.ErrDuplicateLabel
+				}
+				return fmt.Errorf("error inserting label: %s: %w", l.Name, err)
+			}
+		}
+
+		last := make([]string, len(columns))
+		for _, st := range td.Tasks {
+			if st.Column < 0 || st.Column >= len(columns) {
+				return fail.ErrInvalidTemplate
+			}
+
+			t := model.Task{
+				ID:        uuid.New().String(),
+				TenantID:  p.TenantID,
+				Title:     st.Title,
+				Content:   st.Content,
+				Points:    st.Points,
+				Priority:  st.Priority,
+				UserID:    values.UserID,
+				ProjectID: pid,
+				ColumnID:  columns[st.Column],
+			}
+			if t.Priority == "" {
+				t.Priority = model.PriorityNone
+			}
+
+			var err error
+			if t.Key, err = nextKey(ctx, tx, p); err != nil {
+				return err
+			}
+			if t.Rank, err = rank.Between(last[st.Column], ""); err != nil {
+				return err
+			}
+			last[st.Column] = t.Rank
+
+			stmt = `
+				insert into tasks (
+					task_id, tenant_id, key, title, content, user_id, assigned_to, attachments,
+					project_id, column_id, rank, priority, points, updated_at, created_at
+				) values ($1, $2, $3, $4, $5, $6, '', '{}', $7, $8, $9, $10, $11, $12, $13)
+			`
+			if _, err = tx.ExecContext(
+				ctx,
+				stmt,
+				t.ID,
+				t.TenantID,
+				t.Key,
+				t.Title,
+				t.Content,
+				t.UserID,
+				t.ProjectID,
+				t.ColumnID,
+				t.Rank,
+				t.Priority,
+				t.Points,
+				at,
+				at,
+			); err != nil {
+				return fmt.Errorf("error inserting tasks: %+v: %w", st, err)
+			}
+
+			for _, name := range st.Labels {
+				lid, ok := labels[strings.ToLower(name)]
+				if !ok {
+					return fail.ErrInvalidTemplate
+				}
+
+				stmt = `insert into task_labels (task_id, label_id, tenant_id) values ($1, $2, $3)`
+				if _, err = tx.ExecContext(ctx, stmt, t.ID, lid, t.TenantID); err != nil {
+					return fmt.Errorf("error adding task labels: %s: %w", t.ID, err)
+				}
+			}
+
+			if err = recordEvent(ctx, tx, t, values.UserID, model.TaskCreated, created(t), now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Snapshot returns the definition of a template recreating a project board: its columns
+// in board order, its labels and up to model.MaxTemplateTasks of its tasks.
+func (tr *TemplateRepository) Snapshot(ctx context.Context, pid string) (model.TemplateDefinition, error) {
+	var (
+		td = model.TemplateDefinition{
+			Columns: make([]model.TemplateColumn, 0),
+			Labels:  make([]model.TemplateLabel, 0),
+			Tasks:   make([]model.TemplateTask, 0),
+		}
+		err error
+	)
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return td, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return td, err
+	}
+	defer Close()
+
+	var exists bool
+	stmt := `select exists(select 1 from projects where project_id = $1)`
+	if err = conn.QueryRowxContext(ctx, stmt, pid).Scan(&exists); err != nil {
+		return td, err
+	}
+	if !exists {
+		return td, fail.ErrNotFound
+	}
+
+	stmt = `
+		select c.title, c.wip_limit
+		from projects p
+		cross join unnest(p.column_order) with ordinality as o(column_name, n)
+		join columns c on c.project_id = p.project_id and c.column_name = o.column_name
+		where p.project_id = $1
+		order by o.n
+	`
+	rows, err := conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return td, fmt.Errorf("error selecting columns: %w", err)
+	}
+	for rows.Next() {
+		var c model.TemplateColumn
+		if err = rows.Scan(&c.Title, &c.WIPLimit); err != nil {
+			rows.Close()
+			return td, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		td.Columns = append(td.Columns, c)
+	}
+	rows.Close()
+
+	stmt = `select name, color from labels where project_id = $1 order by lower(name)`
+	rows, err = conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return td, fmt.Errorf("error selecting labels: %w", err)
+	}
+	for rows.Next() {
+		var l model.TemplateLabel
+		if err = rows.Scan(&l.Name, &l.Color); err != nil {
+			rows.Close()
+			return td, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		td.Labels = append(td.Labels, l)
+	}
+	rows.Close()
+
+	stmt = `
+		select
+			t.title, coalesce(t.content, ''), t.priority, coalesce(t.points, 0),
+			array_position(p.column_order, c.column_name::text) - 1 as position,
+			array(
+				select l.name from task_labels tl join labels l on l.label_id = tl.label_id
+				where tl.task_id = t.task_id
+				order by lower(l.name)
+			) as labels
+		from tasks t
+		join columns c on c.column_id = t.column_id
+		join projects p on p.project_id = t.project_id
+		where t.project_id = $1 and t.deleted_at is null and t.archived_at is null
+			and array_position(p.column_order, c.column_name::text) is not null
+		order by position, t.rank
+		limit $2
+	`
+	rows, err = conn.QueryxContext(ctx, stmt, pid, model.MaxTemplateTasks)
+	if err != nil {
+		return td, fmt.Errorf("error selecting tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.TemplateTask
+		if err = rows.Scan(&t.Title, &t.Content, &t.Priority, &t.Points, &t.Column, (*pq.StringArray)(&t.Labels)); err != nil {
+			return td, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		td.Tasks = append(td.Tasks, t)
+	}
+
+	return td, rows.Err()
+}
@@ -0,0 +1,90 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTemplateRepository_ApplyAndSnapshot(t *testing.T) {
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTemplateRepository(zap.NewNop(), db)
+	projectRepo := repository.NewProjectRepository(zap.NewNop(), db)
+	columnRepo := repository.NewColumnRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	var scrum model.Template
+	for _, tmpl := range model.BuiltinTemplates() {
+		if tmpl.ID == model.TemplateScrum {
+			scrum = tmpl
+		}
+	}
+	scrum.Tasks[1].Labels = []string{"story"}
+
+	now := time.Now()
+
+	p, err := projectRepo.Create(ctx, model.NewProject{Name: "Templated"}, now)
+	require.NoError(t, err)
+
+	t.Run("apply creates the board", func(t *testing.T) {
+		err := repo.Apply(ctx, p.ID, scrum.TemplateDefinition, now)
+		assert.Nil(t, err)
+
+		actual, err := projectRepo.Retrieve(ctx, p.ID)
+		require.NoError(t, err)
+		assert.Equal(t, scrum.ColumnOrder(), actual.ColumnOrder)
+
+		columns, err := columnRepo.List(ctx, p.ID)
+		require.NoError(t, err)
+		assert.Len(t, columns, len(scrum.Columns))
+
+		tasks, err := taskRepo.List(ctx, p.ID, model.TaskFilter{})
+		require.NoError(t, err)
+		require.Len(t, tasks, len(scrum.Tasks))
+		assert.Equal(t, model.PriorityNone, tasks[0].Priority)
+		assert.ElementsMatch(t, []string{p.Prefix + "1", p.Prefix + "2"}, []string{tasks[0].Key, tasks[1].Key})
+	})
+
+	t.Run("a board is applied once", func(t *testing.T) {
+		err := repo.Apply(ctx, p.ID, scrum.TemplateDefinition, now)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("snapshot saves the board", func(t *testing.T) {
+		td, err := repo.Snapshot(ctx, p.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, scrum.Columns, td.Columns)
+		assert.ElementsMatch(t, scrum.Labels, td.Labels)
+		require.Len(t, td.Tasks, len(scrum.Tasks))
+		assert.Equal(t, []string{"Story"}, td.Tasks[1].Labels)
+
+		saved, err := repo.Create(ctx, model.NewTemplate{Name: "Our scrum", TemplateDefinition: td}, now)
+		assert.Nil(t, err)
+
+		actual, err := repo.Retrieve(ctx, saved.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, saved.TemplateDefinition, actual.TemplateDefinition)
+
+		_, err = repo.Create(ctx, model.NewTemplate{Name: "OUR SCRUM", TemplateDefinition: td}, now)
+		assert.Equal(t, fail.ErrDuplicateTemplate, err)
+	})
+
+	t.Run("snapshot of a missing project", func(t *testing.T) {
+		_, err := repo.Snapshot(ctx, testutils.MockUUID)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,615 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/bundle"
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/rank"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// TransferRepository manages data access to project exports and imports.
+type TransferRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewTransferRepository returns a new TransferRepository.
+func NewTransferRepository(logger *zap.Logger, pg *db.PostgresDatabase) *TransferRepository {
+	return &TransferRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// Export reads a project board into a bundle. People are identified by user id, since
+// the project service does not know their emails.
+func (tr *TransferRepository) Export(ctx context.Context, pid string, now time.Time) (bundle.Bundle, error) {
+	var (
+		b = bundle.Bundle{
+			Version:    bundle.Version,
+			ExportedAt: now.Round(time.Microsecond).UTC(),
+			Columns:    make([]bundle.Column, 0),
+			Labels:     make([]bundle.Label, 0),
+			Tasks:      make([]bundle.Task, 0),
+		}
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return b, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(pid); err != nil {
+		return b, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return b, err
+	}
+	defer Close()
+
+	stmt := `
+		select name, coalesce(description, '') from projects
+		where project_id = $1 and deleted_at is null and project_role(project_id, $2) is not null
+	`
+	if err = conn.QueryRowxContext(ctx, stmt, pid, values.UserID).Scan(&b.Project.Name, &b.Project.Description); err != nil {
+		if err == sql.ErrNoRows {
+			return b, fail.ErrNotFound
+		}
+		return b, err
+	}
+
+	stmt = `
+		select c.title, c.wip_limit
+		from projects p
+		cross join unnest(p.column_order) with ordinality as o(column_name, n)
+		join columns c on c.project_id = p.project_id and c.column_name = o.column_name
+		where p.project_id = $1
+		order by o.n
+	`
+	rows, err := conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, fmt.Errorf("error selecting columns: %w", err)
+	}
+	for rows.Next() {
+		var c bundle.Column
+		if err = rows.Scan(&c.Title, &c.WIPLimit); err != nil {
+			rows.Close()
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		b.Columns = append(b.Columns, c)
+	}
+	rows.Close()
+
+	stmt = `select name, color from labels where project_id = $1 order by lower(name)`
+	rows, err = conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, fmt.Errorf("error selecting labels: %w", err)
+	}
+	for rows.Next() {
+		var l bundle.Label
+		if err = rows.Scan(&l.Name, &l.Color); err != nil {
+			rows.Close()
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		b.Labels = append(b.Labels, l)
+	}
+	rows.Close()
+
+	stmt = `select name, kind, options from custom_fields where project_id = $1 order by lower(name)`
+	rows, err = conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, fmt.Errorf("error selecting custom fields: %w", err)
+	}
+	for rows.Next() {
+		var f bundle.Field
+		if err = rows.Scan(&f.Name, &f.Kind, (*pq.StringArray)(&f.Options)); err != nil {
+			rows.Close()
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		b.Fields = append(b.Fields, f)
+	}
+	rows.Close()
+
+	// Tasks outside the board order cannot be placed in a column and are left out, as
+	// are tasks in the trash.
+	stmt = `
+		select
+			t.task_id, coalesce(t.key, ''), t.title, coalesce(t.content, ''),
+			array_position(p.column_order, c.column_name::text) - 1 as position,
+			t.priority, coalesce(t.points, 0), coalesce(t.assigned_to, ''), t.user_id,
+			array(
+				select l.name from task_labels tl join labels l on l.label_id = tl.label_id
+				where tl.task_id = t.task_id
+				order by lower(l.name)
+			) as labels,
+			t.start_at, t.due_at, t.created_at
+		from tasks t
+		join columns c on c.column_id = t.column_id
+		join projects p on p.project_id = t.project_id
+		where t.project_id = $1 and t.deleted_at is null
+			and array_position(p.column_order, c.column_name::text) is not null
+		order by position, t.rank
+	`
+	rows, err = conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, fmt.Errorf("error selecting tasks: %w", err)
+	}
+
+	tasks := make(map[string]int)
+	for rows.Next() {
+		var (
+			id string
+			t  = bundle.Task{Comments: make([]bundle.Comment, 0)}
+		)
+		err = rows.Scan(
+			&id,
+			&t.Key,
+			&t.Title,
+			&t.Content,
+			&t.Column,
+			&t.Priority,
+			&t.Points,
+			&t.Assignee,
+			&t.Reporter,
+			(*pq.StringArray)(&t.Labels),
+			&t.StartAt,
+			&t.DueAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		t.StartAt = utc(t.StartAt)
+		t.DueAt = utc(t.DueAt)
+		t.CreatedAt = t.CreatedAt.UTC()
+
+		tasks[id] = len(b.Tasks)
+		b.Tasks = append(b.Tasks, t)
+	}
+	rows.Close()
+
+	stmt = `
+		select v.task_id, f.name, ` + fieldValue + `
+		from task_field_values v join custom_fields f on f.field_id = v.field_id
+		where f.project_id = $1
+	`
+	rows, err = conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, fmt.Errorf("error selecting custom field values: %w", err)
+	}
+	for rows.Next() {
+		var (
+			tid, name string
+			raw       []byte
+		)
+		if err = rows.Scan(&tid, &name, &raw); err != nil {
+			rows.Close()
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		if i, ok := tasks[tid]; ok {
+			if b.Tasks[i].Fields == nil {
+				b.Tasks[i].Fields = make(map[string]json.RawMessage)
+			}
+			b.Tasks[i].Fields[name] = raw
+		}
+	}
+	rows.Close()
+
+	stmt = `
+		select cm.task_id, cm.user_id, coalesce(cm.content, ''), cm.created_at
+		from comments cm join tasks t on t.task_id = cm.task_id
+		where t.project_id = $1
+		order by cm.created_at
+	`
+	rows, err = conn.QueryxContext(ctx, stmt, pid)
+	if err != nil {
+		return b, fmt.Errorf("error selecting comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			tid string
+			c   bundle.Comment
+		)
+		if err = rows.Scan(&tid, &c.Author, &c.Content, &c.CreatedAt); err != nil {
+			return b, fmt.Errorf("error scanning row into struct: %w", err)
+		}
+		c.CreatedAt = c.CreatedAt.UTC()
+
+		if i, ok := tasks[tid]; ok {
+			b.Tasks[i].Comments = append(b.Tasks[i].Comments, c)
+		}
+	}
+
+	return b, rows.Err()
+}
+
+// Import creates a project from a bundle in a single transaction, allocating new task keys.
+// People are mapped through users, keyed by lower case handle; unmapped authors become the
+// importing user. Assignees that are unmapped or cannot edit the project are dropped, and
+// the keys of the tasks that had one are returned. Custom fields are not imported.
+func (tr *TransferRepository) Import(ctx context.Context, b bundle.Bundle, users map[string]string, now time.Time) (model.Project, []string, error) {
+	var (
+		unassigned = make([]string, 0)
+		err        error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Project{}, nil, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(values.UserID); err != nil {
+		return model.Project{}, nil, fail.ErrInvalidID
+	}
+
+	if err = b.Check(); err != nil {
+		return model.Project{}, nil, err
+	}
+
+	at := now.Round(time.Microsecond).UTC()
+	user := func(handle string) string {
+		return users[strings.ToLower(strings.TrimSpace(handle))]
+	}
+	author := func(handle string) string {
+		if id := user(handle); id != "" {
+			return id
+		}
+		return values.UserID
+	}
+
+	p := model.Project{
+		ID:          uuid.New().String(),
+		TenantID:    values.TenantID,
+		Name:        b.Project.Name,
+		Prefix:      formatPrefix(b.Project.Name),
+		Description: b.Project.Description,
+		Active:      true,
+		UserID:      values.UserID,
+		ColumnOrder: make([]string, len(b.Columns)),
+		UpdatedAt:   at,
+		CreatedAt:   at,
+	}
+	for i := range b.Columns {
+		p.ColumnOrder[i] = model.ColumnName(i)
+	}
+
+	err = tr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		stmt := `
+			insert into projects (
+				project_id, tenant_id, name, prefix,
+				description, user_id, column_order, updated_at, created_at
+			) values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+		if _, err := tx.ExecContext(ctx, stmt, p.ID, p.TenantID, p.Name, p.Prefix, p.Description, p.UserID, pq.Array(p.ColumnOrder), at, at); err != nil {
+			return fmt.Errorf("error inserting project: %+v :%w", p, err)
+		}
+
+		if err := addOwner(ctx, tx, p); err != nil {
+			return err
+		}
+
+		columns := make([]string, len(b.Columns))
+		for i, c := range b.Columns {
+			columns[i] = uuid.New().String()
+
+			stmt = `
+				insert into columns (
+					column_id, tenant_id, title, column_name, wip_limit,
+					project_id, updated_at, created_at
+				) values ($1, $2, $3, $4, $5, $6, $7, $8)
+			`
+			if _, err := tx.ExecContext(ctx, stmt, columns[i], p.TenantID, c.Title, p.ColumnOrder[i], c.WIPLimit, p.ID, at, at); err != nil {
+				return fmt.Errorf("error inserting column: %+v :%w", c, err)
+			}
+		}
+
+		labels := make(map[string]string, len(b.Labels))
+		for _, l := range b.Labels {
+			lid := uuid.New().String()
+			labels[strings.ToLower(l.Name)] = lid
+
+			stmt = `
+				insert into labels (label_id, tenant_id, project_id, name, color, updated_at, created_at)
+				values ($1, $2, $3, $4, $5, $6, $7)
+			`
+			if _, err := tx.ExecContext(ctx, stmt, lid, p.TenantID, p.ID, l.Name, l.Color, at, at); err != nil {
+				return fmt.Errorf("error inserting label: %s: %w", l.Name, err)
+			}
+		}
+
+		// Tasks are only assigned to members of the project who may work on it.
+		unassigned = unassigned[:0]
+		members := make(map[string]bool)
+		assignee := func(handle string) (string, error) {
+			id := user(handle)
+			if id == "" {
+				return "", nil
+			}
+			member, ok := members[id]
+			if !ok {
+				var role sql.NullString
+				if err := tx.QueryRowxContext(ctx, `select project_role($1, $2)`, p.ID, id).Scan(&role); err != nil {
+					return "", err
+				}
+				member = role.Valid && model.RoleAllows(role.String, model.RoleEditor)
+				members[id] = member
+			}
+			if !member {
+				return "", nil
+			}
+			return id, nil
+		}
+
+		last := make([]string, len(columns))
+		for _, bt := range b.Tasks {
+			assignedTo, err := assignee(bt.Assignee)
+			if err != nil {
+				return fmt.Errorf("error checking assignee: %s: %w", bt.Title, err)
+			}
+
+			t := model.Task{
+				ID:         uuid.New().String(),
+				TenantID:   p.TenantID,
+				Title:      bt.Title,
+				Content:    bt.Content,
+				Points:     bt.Points,
+				Priority:   bt.Priority,
+				AssignedTo: assignedTo,
+				UserID:     author(bt.Reporter),
+				ProjectID:  p.ID,
+				ColumnID:   columns[bt.Column],
+				StartAt:    utc(bt.StartAt),
+				DueAt:      utc(bt.DueAt),
+				CreatedAt:  bt.CreatedAt.UTC(),
+			}
+			if t.Priority == "" {
+				t.Priority = model.PriorityNone
+			}
+			if t.CreatedAt.IsZero() {
+				t.CreatedAt = at
+			}
+			if t.StartAt != nil && t.DueAt != nil && t.StartAt.After(*t.DueAt) {
+				t.StartAt = nil
+			}
+
+			if t.Key, err = nextKey(ctx, tx, p); err != nil {
+				return err
+			}
+			if bt.Assignee != "" && t.AssignedTo == "" {
+				unassigned = append(unassigned, t.Key)
+			}
+			if t.Rank, err = rank.Between(last[bt.Column], ""); err != nil {
+				return err
+			}
+			last[bt.Column] = t.Rank
+
+			stmt = `
+				insert into tasks (
+					task_id, tenant_id, key, title, content, user_id, assigned_to, attachments,
+					project_id, column_id, rank, priority, points, start_at, due_at, updated_at, created_at
+				) values ($1, $2, $3, $4, $5, $6, $7, '{}', $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			`
+			if _, err = tx.ExecContext(
+				ctx,
+				stmt,
+				t.ID,
+				t.TenantID,
+				t.Key,
+				t.Title,
+				t.Content,
+				t.UserID,
+				t.AssignedTo,
+				t.ProjectID,
+				t.ColumnID,
+				t.Rank,
+				t.Priority,
+				t.Points,
+				t.StartAt,
+				t.DueAt,
+				at,
+				t.CreatedAt,
+			); err != nil {
+				return fmt.Errorf("error inserting tasks: %s: %w", bt.Title, err)
+			}
+
+			added := make(map[string]bool, len(bt.Labels))
+			for _, name := range bt.Labels {
+				lid := labels[strings.ToLower(name)]
+				if added[lid] {
+					continue
+				}
+				added[lid] = true
+
+				stmt = `insert into task_labels (task_id, label_id, tenant_id) values ($1, $2, $3)`
+				if _, err = tx.ExecContext(ctx, stmt, t.ID, lid, t.TenantID); err != nil {
+					return fmt.Errorf("error adding task labels: %s: %w", t.ID, err)
+				}
+			}
+
+			for _, c := range bt.Comments {
+				createdAt := c.CreatedAt.UTC()
+				if createdAt.IsZero() {
+					createdAt = at
+				}
+
+				stmt = `
+					insert into comments (
+						comment_id, task_id, tenant_id, content, likes, user_id, edited, updated_at, created_at
+					) values ($1, $2, $3, $4, 0, $5, false, $6, $7)
+				`
+				if _, err = tx.ExecContext(ctx, stmt, uuid.New().String(), t.ID, t.TenantID, c.Content, author(c.Author), createdAt, createdAt); err != nil {
+					return fmt.Errorf("error inserting comment: %s: %w", t.ID, err)
+				}
+			}
+
+			if err = recordEvent(ctx, tx, t, values.UserID, model.TaskCreated, created(t), now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Project{}, nil, err
+	}
+
+	return p, unassigned, nil
+}
+
+const selectImport = `
+	select
+		import_id, tenant_id, user_id, format, status, coalesce(project_id, ''), tasks, error,
+		unassigned, completed_at, updated_at, created_at
+	from project_imports
+`
+
+func scanImport(row interface{ Scan(...interface{}) error }) (model.Import, error) {
+	var im model.Import
+
+	err := row.Scan(
+		&im.ID,
+		&im.TenantID,
+		&im.UserID,
+		&im.Format,
+		&im.Status,
+		&im.ProjectID,
+		&im.Tasks,
+		&im.Error,
+		pq.Array(&im.Unassigned),
+		&im.CompletedAt,
+		&im.UpdatedAt,
+		&im.CreatedAt,
+	)
+	if err != nil {
+		return im, err
+	}
+
+	im.CompletedAt = utc(im.CompletedAt)
+	im.UpdatedAt = im.UpdatedAt.UTC()
+	im.CreatedAt = im.CreatedAt.UTC()
+
+	return im, nil
+}
+
+// RetrieveImport retrieves a project import from the database.
+func (tr *TransferRepository) RetrieveImport(ctx context.Context, imid string) (model.Import, error) {
+	var (
+		im  model.Import
+		err error
+	)
+
+	if _, err = uuid.Parse(imid); err != nil {
+		return im, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return im, err
+	}
+	defer Close()
+
+	im, err = scanImport(conn.QueryRowxContext(ctx, selectImport+` where import_id = $1`, imid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return im, fail.ErrNotFound
+		}
+		return im, err
+	}
+
+	return im, nil
+}
+
+// CreateImport records a pending project import in the database.
+func (tr *TransferRepository) CreateImport(ctx context.Context, format string, tasks int, now time.Time) (model.Import, error) {
+	var (
+		im  model.Import
+		err error
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return im, web.CtxErr()
+	}
+
+	if _, err = uuid.Parse(values.UserID); err != nil {
+		return im, fail.ErrInvalidID
+	}
+
+	conn, Close, err := tr.pg.GetConnection(ctx)
+	if err != nil {
+		return im, err
+	}
+	defer Close()
+
+	im = model.Import{
+		ID:         uuid.New().String(),
+		TenantID:   values.TenantID,
+		UserID:     values.UserID,
+		Format:     format,
+		Status:     model.ImportPending,
+		Tasks:      tasks,
+		Unassigned: make([]string, 0),
+		UpdatedAt:  now.Round(time.Microsecond).UTC(),
+		CreatedAt:  now.Round(time.Microsecond).UTC(),
+	}
+
+	stmt := `
+		insert into project_imports (
+			import_id, tenant_id, user_id, format, status, tasks, updated_at, created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err = conn.ExecContext(ctx, stmt, im.ID, im.TenantID, im.UserID, im.Format, im.Status, im.Tasks, im.UpdatedAt, im.CreatedAt); err != nil {
+		return model.Import{}, fmt.Errorf("error inserting import: %w", err)
+	}
+	db.Wrote(ctx)
+
+	return im, nil
+}
+
+// UpdateImport records the status, project, error and unassigned tasks of a project import
+// in the database.
+func (tr *TransferRepository) UpdateImport(ctx context.Context, im model.Import, now time.Time) error {
+	conn, Close, err := tr.pg.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer Close()
+
+	stmt := `
+		update project_imports
+		set
+			status = $1,
+			project_id = nullif($2, ''),
+			error = $3,
+			unassigned = $4,
+			completed_at = $5,
+			updated_at = $6
+		where import_id = $7
+	`
+	if _, err = conn.ExecContext(ctx, stmt, im.Status, im.ProjectID, im.Error, pq.Array(im.Unassigned), im.CompletedAt, now.Round(time.Microsecond).UTC(), im.ID); err != nil {
+		return fmt.Errorf("error updating import %s: %w", im.ID, err)
+	}
+	db.Wrote(ctx)
+
+	return nil
+}
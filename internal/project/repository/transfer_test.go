@@ -0,0 +1,108 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTransferRepository_ExportImport(t *testing.T) {
+	project := testProjects[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTransferRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	columnRepo := repository.NewColumnRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	t.Run("export unknown project", func(t *testing.T) {
+		_, err := repo.Export(ctx, uuid.New().String(), now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	b, err := repo.Export(ctx, project.ID, now)
+	require.NoError(t, err)
+	require.NoError(t, b.Check())
+	assert.Equal(t, project.Name, b.Project.Name)
+	assert.Len(t, b.Columns, len(project.ColumnOrder))
+
+	t.Run("import allocates new keys", func(t *testing.T) {
+		b.Project.Name = "Apple copy"
+		for i := range b.Tasks {
+			b.Tasks[i].Assignee = "ada@example.com"
+		}
+
+		p, unassigned, err := repo.Import(ctx, b, map[string]string{"ada@example.com": project.UserID}, now)
+		assert.Nil(t, err)
+		assert.Empty(t, unassigned)
+		assert.Equal(t, "APP-", p.Prefix)
+		assert.Equal(t, project.UserID, p.UserID)
+
+		columns, err := columnRepo.List(ctx, p.ID)
+		require.NoError(t, err)
+		assert.Len(t, columns, len(b.Columns))
+
+		tasks, err := taskRepo.List(ctx, p.ID, model.TaskFilter{})
+		require.NoError(t, err)
+		require.Len(t, tasks, len(b.Tasks))
+		for _, task := range tasks {
+			assert.Equal(t, project.UserID, task.AssignedTo)
+			assert.Equal(t, p.ID, task.ProjectID)
+		}
+	})
+
+	t.Run("assignees who are not members are dropped", func(t *testing.T) {
+		require.NotEmpty(t, b.Tasks)
+		for i := range b.Tasks {
+			b.Tasks[i].Assignee = "bob@example.com"
+		}
+
+		p, unassigned, err := repo.Import(ctx, b, map[string]string{"bob@example.com": uuid.New().String()}, now)
+		assert.Nil(t, err)
+
+		tasks, err := taskRepo.List(ctx, p.ID, model.TaskFilter{})
+		require.NoError(t, err)
+		require.Len(t, tasks, len(b.Tasks))
+
+		keys := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			assert.Empty(t, task.AssignedTo)
+			keys = append(keys, task.Key)
+		}
+		assert.ElementsMatch(t, keys, unassigned)
+	})
+
+	t.Run("import records its status", func(t *testing.T) {
+		im, err := repo.CreateImport(ctx, model.FormatBundle, len(b.Tasks), now)
+		require.NoError(t, err)
+		assert.Equal(t, model.ImportPending, im.Status)
+
+		at := now.Round(time.Microsecond).UTC()
+		im.Status = model.ImportFailed
+		im.Error = "import failed: bundle: invalid board"
+		im.CompletedAt = &at
+		im.Unassigned = []string{"APP-1"}
+		require.NoError(t, repo.UpdateImport(ctx, im, now))
+
+		actual, err := repo.RetrieveImport(ctx, im.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, im.Status, actual.Status)
+		assert.Equal(t, im.Error, actual.Error)
+		assert.Equal(t, im.Unassigned, actual.Unassigned)
+		assert.Equal(t, at, *actual.CompletedAt)
+	})
+}
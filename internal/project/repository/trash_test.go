@@ -0,0 +1,137 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTaskRepository_Lifecycle(t *testing.T) {
+	task := testTasks[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: task.TenantID, UserID: task.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewTaskRepository(zap.NewNop(), db)
+	projectRepo := repository.NewProjectRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	t.Run("archived tasks are listed on request", func(t *testing.T) {
+		archived, err := repo.Archive(ctx, task.ID, now)
+		assert.Nil(t, err)
+		assert.NotNil(t, archived.ArchivedAt)
+
+		list, err := repo.List(ctx, task.ProjectID, model.TaskFilter{})
+		require.NoError(t, err)
+		for _, tk := range list {
+			assert.NotEqual(t, task.ID, tk.ID)
+		}
+
+		list, err = repo.List(ctx, task.ProjectID, model.TaskFilter{Archived: true})
+		require.NoError(t, err)
+		assert.Contains(t, taskIDs(list), task.ID)
+
+		unarchived, err := repo.Unarchive(ctx, task.ID, now)
+		assert.Nil(t, err)
+		assert.Nil(t, unarchived.ArchivedAt)
+	})
+
+	t.Run("deleted tasks go to the trash", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, task.ID, now))
+
+		_, err := repo.Retrieve(ctx, task.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		trash, err := projectRepo.Trash(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, taskIDs(trash.Tasks), task.ID)
+
+		restored, err := repo.Restore(ctx, task.ID, now)
+		assert.Nil(t, err)
+		assert.Nil(t, restored.DeletedAt)
+
+		_, err = repo.Restore(ctx, task.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+
+	t.Run("purge removes expired trash", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, task.ID, now))
+
+		purged, err := projectRepo.Purge(ctx, now.Add(-time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, model.Purged{}, purged)
+
+		purged, err = projectRepo.Purge(ctx, now.Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, purged.Tasks)
+
+		_, err = repo.Restore(ctx, task.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
+
+func TestProjectRepository_Lifecycle(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewProjectRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+
+	now := time.Now()
+
+	require.NoError(t, repo.Delete(ctx, project.ID, now))
+
+	t.Run("tasks of a deleted project are hidden", func(t *testing.T) {
+		_, err := taskRepo.Retrieve(ctx, testTasks[0].ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		trash, err := repo.Trash(ctx)
+		require.NoError(t, err)
+		require.Len(t, trash.Projects, 1)
+		assert.Equal(t, project.ID, trash.Projects[0].ID)
+		assert.Empty(t, trash.Tasks)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		restored, err := repo.Restore(ctx, project.ID, now)
+		assert.Nil(t, err)
+		assert.Nil(t, restored.DeletedAt)
+
+		_, err = taskRepo.Retrieve(ctx, testTasks[0].ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("purge removes the board", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, project.ID, now))
+
+		purged, err := repo.Purge(ctx, now.Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, purged.Projects)
+
+		_, err = repo.Restore(ctx, project.ID, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+	})
+}
+
+func taskIDs(ts []model.Task) []string {
+	ids := make([]string, len(ts))
+	for i, t := range ts {
+		ids[i] = t.ID
+	}
+	return ids
+}
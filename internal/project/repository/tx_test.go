@@ -0,0 +1,55 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInTransaction_SerializationFailure(t *testing.T) {
+	task := testTasks[0]
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: task.TenantID, UserID: task.UserID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	var attempts int
+	err := db.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		attempts++
+
+		var title string
+		if err := tx.QueryRowxContext(ctx, `select title from tasks where task_id = $1`, task.ID).Scan(&title); err != nil {
+			return err
+		}
+
+		// A transaction committed after the first one read the task makes its update
+		// fail with a serialization failure.
+		if attempts == 1 {
+			err := db.RunInTransaction(ctx, func(other *sqlx.Tx) error {
+				_, err := other.ExecContext(ctx, `update tasks set title = 'Concurrent' where task_id = $1`, task.ID)
+				return err
+			})
+			if err != nil {
+				return errors.New("concurrent update failed: " + err.Error())
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `update tasks set title = title || ' retried' where task_id = $1`, task.ID)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	var title string
+	conn, release, err := db.GetConnection(ctx)
+	require.NoError(t, err)
+	defer release()
+	require.NoError(t, conn.QueryRowxContext(ctx, `select title from tasks where task_id = $1`, task.ID).Scan(&title))
+	assert.Equal(t, "Concurrent retried", title)
+}
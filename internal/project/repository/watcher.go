@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// WatcherRepository manages data access to task watchers.
+type WatcherRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewWatcherRepository returns a new WatcherRepository.
+func NewWatcherRepository(logger *zap.Logger, pg *db.PostgresDatabase) *WatcherRepository {
+	return &WatcherRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+// List lists the watchers of a task the user can see, in the order they started watching.
+func (wr *WatcherRepository) List(ctx context.Context, tid string) ([]model.Watcher, error) {
+	var list = make([]model.Watcher, 0)
+
+	tr := NewTaskRepository(wr.logger, wr.pg)
+	if _, err := tr.Retrieve(ctx, tid); err != nil {
+		return list, err
+	}
+
+	conn, Close, err := wr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return list, err
+	}
+	defer Close()
+
+	var ws []model.Watcher
+	stmt := `select task_id, user_id, created_at from task_watchers where task_id = $1 order by created_at, user_id`
+	if err = conn.SelectContext(ctx, &ws, stmt, tid); err != nil {
+		return list, fmt.Errorf("error selecting watchers: %w", err)
+	}
+
+	for _, w := range ws {
+		w.CreatedAt = w.CreatedAt.UTC()
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+// Watch makes the user watch a task the user can see. Watching a task twice changes
+// nothing.
+func (wr *WatcherRepository) Watch(ctx context.Context, tid string, now time.Time) (model.Watcher, error) {
+	var w model.Watcher
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return w, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return w, fail.ErrInvalidID
+	}
+
+	tr := NewTaskRepository(wr.logger, wr.pg)
+	t, err := tr.Retrieve(db.Primary(ctx), tid)
+	if err != nil {
+		return w, err
+	}
+
+	w = model.Watcher{
+		TaskID:    t.ID,
+		UserID:    values.UserID,
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err = wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		return watch(ctx, tx, t, values.UserID, now)
+	})
+	if err != nil {
+		return model.Watcher{}, err
+	}
+
+	return w, nil
+}
+
+// Unwatch stops the user from watching a task.
+func (wr *WatcherRepository) Unwatch(ctx context.Context, tid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return fail.ErrInvalidID
+	}
+
+	return wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, `delete from task_watchers where task_id = $1 and user_id = $2`, tid, values.UserID)
+		if err != nil {
+			return fmt.Errorf("error deleting watcher of task %s: %w", tid, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fail.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// watch adds a watcher to a task unless the user already watches it.
+func watch(ctx context.Context, tx *sqlx.Tx, t model.Task, userID string, now time.Time) error {
+	stmt := `
+		insert into task_watchers (task_id, user_id, tenant_id, created_at)
+		values ($1, $2, $3, $4)
+		on conflict do nothing
+	`
+	if _, err := tx.ExecContext(ctx, stmt, t.ID, userID, t.TenantID, now.Round(time.Microsecond).UTC()); err != nil {
+		return fmt.Errorf("error inserting watcher of task %s: %w", t.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,514 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/db"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// WorklogRepository manages data access to the time logged on tasks and to timers.
+type WorklogRepository struct {
+	logger *zap.Logger
+	pg     *db.PostgresDatabase
+}
+
+// NewWorklogRepository returns a new WorklogRepository.
+func NewWorklogRepository(logger *zap.Logger, pg *db.PostgresDatabase) *WorklogRepository {
+	return &WorklogRepository{
+		logger: logger,
+		pg:     pg,
+	}
+}
+
+const selectWorklog = `
+	select worklog_id, tenant_id, task_id, project_id, user_id, started_at, minutes, note, updated_at, created_at
+	from worklogs w
+`
+
+// visibleWorklog restricts queries of worklogs w to worklogs of tasks that are not in the
+// trash, in projects the user bound to the given parameter can see.
+func visibleWorklog(user int) string {
+	return fmt.Sprintf(`
+	exists(
+		select 1 from tasks t join projects p on p.project_id = t.project_id
+		where t.task_id = w.task_id and t.deleted_at is null and p.deleted_at is null
+			and project_role(p.project_id, $%d) is not null
+	)
+`, user)
+}
+
+func utcWorklog(w model.Worklog) model.Worklog {
+	w.StartedAt = w.StartedAt.UTC()
+	w.UpdatedAt = w.UpdatedAt.UTC()
+	w.CreatedAt = w.CreatedAt.UTC()
+	return w
+}
+
+// List lists the worklogs of a task, latest first.
+func (wr *WorklogRepository) List(ctx context.Context, tid string) ([]model.Worklog, error) {
+	var list = make([]model.Worklog, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return list, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(tid); err != nil {
+		return list, fail.ErrInvalidID
+	}
+
+	conn, Close, err := wr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return list, err
+	}
+	defer Close()
+
+	var ws []model.Worklog
+	stmt := selectWorklog + ` where task_id = $1 and ` + visibleWorklog(2) + ` order by started_at desc, worklog_id`
+	if err = conn.SelectContext(ctx, &ws, stmt, tid, values.UserID); err != nil {
+		return list, fmt.Errorf("error selecting worklogs: %w", err)
+	}
+
+	for _, w := range ws {
+		list = append(list, utcWorklog(w))
+	}
+	return list, nil
+}
+
+// Create logs time of the user on a task, which needs an editor of the project. The
+// remaining estimate of the task is lowered by the time logged, down to zero.
+func (wr *WorklogRepository) Create(ctx context.Context, tid string, nw model.NewWorklog, now time.Time) (model.Worklog, error) {
+	var w model.Worklog
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return w, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return w, fail.ErrInvalidID
+	}
+
+	tr := NewTaskRepository(wr.logger, wr.pg)
+	t, err := tr.Retrieve(db.Primary(ctx), tid)
+	if err != nil {
+		return w, err
+	}
+
+	w = model.Worklog{
+		ID:        uuid.New().String(),
+		TenantID:  values.TenantID,
+		TaskID:    t.ID,
+		ProjectID: t.ProjectID,
+		UserID:    values.UserID,
+		StartedAt: nw.StartedAt.Round(time.Microsecond).UTC(),
+		Minutes:   nw.Minutes,
+		Note:      nw.Note,
+		UpdatedAt: now.Round(time.Microsecond).UTC(),
+		CreatedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err = wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+		return insertWorklog(ctx, tx, w)
+	})
+	if err != nil {
+		return model.Worklog{}, err
+	}
+
+	return w, nil
+}
+
+// Update updates a worklog. Only the user who logged the time can change it.
+func (wr *WorklogRepository) Update(ctx context.Context, tid string, wid string, update model.UpdateWorklog, now time.Time) (model.Worklog, error) {
+	var w model.Worklog
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return w, web.CtxErr()
+	}
+
+	for _, id := range []string{tid, wid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return w, fail.ErrInvalidID
+		}
+	}
+
+	err := wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		if w, err = lockWorklog(ctx, tx, tid, wid, values.UserID); err != nil {
+			return err
+		}
+		if w.UserID != values.UserID {
+			return fail.ErrNotAuthorized
+		}
+
+		if update.StartedAt != nil {
+			w.StartedAt = update.StartedAt.Round(time.Microsecond).UTC()
+		}
+		if update.Minutes != nil {
+			w.Minutes = *update.Minutes
+		}
+		if update.Note != nil {
+			w.Note = *update.Note
+		}
+		w.UpdatedAt = now.Round(time.Microsecond).UTC()
+
+		stmt := `update worklogs set started_at = $1, minutes = $2, note = $3, updated_at = $4 where worklog_id = $5`
+		if _, err = tx.ExecContext(ctx, stmt, w.StartedAt, w.Minutes, w.Note, w.UpdatedAt, wid); err != nil {
+			return fmt.Errorf("error updating worklog %s: %w", wid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Worklog{}, err
+	}
+
+	return utcWorklog(w), nil
+}
+
+// Delete deletes a worklog. The user who logged the time and the owners of the project
+// can delete it.
+func (wr *WorklogRepository) Delete(ctx context.Context, tid string, wid string) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	for _, id := range []string{tid, wid} {
+		if _, err := uuid.Parse(id); err != nil {
+			return fail.ErrInvalidID
+		}
+	}
+
+	return wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		w, err := lockWorklog(ctx, tx, tid, wid, values.UserID)
+		if err != nil {
+			return err
+		}
+		if w.UserID != values.UserID {
+			if err = authorize(ctx, tx, w.ProjectID, values.UserID, model.RoleOwner); err != nil {
+				return err
+			}
+		}
+
+		if _, err = tx.ExecContext(ctx, `delete from worklogs where worklog_id = $1`, wid); err != nil {
+			return fmt.Errorf("error deleting worklog %s: %w", wid, err)
+		}
+		return nil
+	})
+}
+
+// Timer retrieves the running timer of the user.
+func (wr *WorklogRepository) Timer(ctx context.Context) (model.Timer, error) {
+	var tm model.Timer
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return tm, web.CtxErr()
+	}
+
+	conn, Close, err := wr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return tm, err
+	}
+	defer Close()
+
+	stmt := `select user_id, tenant_id, task_id, started_at from timers where user_id = $1`
+	if err = conn.QueryRowxContext(ctx, stmt, values.UserID).StructScan(&tm); err != nil {
+		if err == sql.ErrNoRows {
+			return tm, fail.ErrNotFound
+		}
+		return tm, err
+	}
+	tm.StartedAt = tm.StartedAt.UTC()
+
+	return tm, nil
+}
+
+// StartTimer starts a timer of the user on a task, which needs an editor of the project.
+// A user runs one timer at a time.
+func (wr *WorklogRepository) StartTimer(ctx context.Context, tid string, now time.Time) (model.Timer, error) {
+	var tm model.Timer
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return tm, web.CtxErr()
+	}
+
+	if _, err := uuid.Parse(values.UserID); err != nil {
+		return tm, fail.ErrInvalidID
+	}
+
+	tr := NewTaskRepository(wr.logger, wr.pg)
+	t, err := tr.Retrieve(db.Primary(ctx), tid)
+	if err != nil {
+		return tm, err
+	}
+
+	tm = model.Timer{
+		UserID:    values.UserID,
+		TenantID:  values.TenantID,
+		TaskID:    t.ID,
+		StartedAt: now.Round(time.Microsecond).UTC(),
+	}
+
+	err = wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := authorize(ctx, tx, t.ProjectID, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		stmt := `insert into timers (user_id, tenant_id, task_id, started_at) values ($1, $2, $3, $4)`
+		if _, err := tx.ExecContext(ctx, stmt, tm.UserID, tm.TenantID, tm.TaskID, tm.StartedAt); err != nil {
+			if isUniqueViolation(err) {
+				return fail.ErrTimerRunning
+			}
+			return fmt.Errorf("error starting timer on task %s: %w", tid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Timer{}, err
+	}
+
+	return tm, nil
+}
+
+// StopTimer stops the running timer of the user and logs the time since it started,
+// rounded to the minute. At least a minute and at most MaxWorklogMinutes are logged.
+func (wr *WorklogRepository) StopTimer(ctx context.Context, st model.StopTimer, now time.Time) (model.Worklog, error) {
+	var w model.Worklog
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return w, web.CtxErr()
+	}
+
+	err := wr.pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		var tm model.Timer
+
+		stmt := `delete from timers where user_id = $1 returning user_id, tenant_id, task_id, started_at`
+		if err := tx.QueryRowxContext(ctx, stmt, values.UserID).StructScan(&tm); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return err
+		}
+
+		var pid string
+		stmt = `select project_id from tasks where task_id = $1 and ` + liveTask(2)
+		if err := tx.QueryRowxContext(ctx, stmt, tm.TaskID, values.UserID).Scan(&pid); err != nil {
+			if err == sql.ErrNoRows {
+				return fail.ErrNotFound
+			}
+			return err
+		}
+		if err := authorize(ctx, tx, pid, values.UserID, model.RoleEditor); err != nil {
+			return err
+		}
+
+		minutes := int(math.Round(now.Sub(tm.StartedAt).Minutes()))
+		if minutes < 1 {
+			minutes = 1
+		}
+		if minutes > model.MaxWorklogMinutes {
+			minutes = model.MaxWorklogMinutes
+		}
+
+		w = model.Worklog{
+			ID:        uuid.New().String(),
+			TenantID:  values.TenantID,
+			TaskID:    tm.TaskID,
+			ProjectID: pid,
+			UserID:    values.UserID,
+			StartedAt: tm.StartedAt.UTC(),
+			Minutes:   minutes,
+			Note:      st.Note,
+			UpdatedAt: now.Round(time.Microsecond).UTC(),
+			CreatedAt: now.Round(time.Microsecond).UTC(),
+		}
+		return insertWorklog(ctx, tx, w)
+	})
+	if err != nil {
+		return model.Worklog{}, err
+	}
+
+	return w, nil
+}
+
+// DiscardTimer stops the running timer of the user without logging time.
+func (wr *WorklogRepository) DiscardTimer(ctx context.Context) error {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return web.CtxErr()
+	}
+
+	conn, Close, err := wr.pg.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer Close()
+
+	res, err := conn.ExecContext(ctx, `delete from timers where user_id = $1`, values.UserID)
+	if err != nil {
+		return fmt.Errorf("error discarding timer: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fail.ErrNotFound
+	}
+	db.Wrote(ctx)
+
+	return nil
+}
+
+// Report totals the time logged in projects the user can see, per project, user and day.
+func (wr *WorklogRepository) Report(ctx context.Context, filter model.WorklogFilter) (model.TimeReport, error) {
+	var r = model.TimeReport{
+		From:     filter.From.UTC(),
+		To:       filter.To.UTC(),
+		Projects: make([]model.TimeReportProject, 0),
+		Users:    make([]model.TimeReportUser, 0),
+		Rows:     make([]model.TimeReportRow, 0),
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return r, web.CtxErr()
+	}
+
+	conn, Close, err := wr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return r, err
+	}
+	defer Close()
+
+	stmt := `
+		select w.project_id, p.name as project_name, w.user_id,
+			to_char(w.started_at at time zone 'UTC', 'YYYY-MM-DD') as date, sum(w.minutes) as minutes
+		from worklogs w join projects p on p.project_id = w.project_id
+		where ` + worklogFilter + ` and ` + visibleWorklog(1) + `
+		group by w.project_id, p.name, w.user_id, date
+		order by date, p.name, w.user_id
+	`
+	if err = conn.SelectContext(ctx, &r.Rows, stmt, values.UserID, filter.ProjectID, filter.UserID, filter.From, filter.To); err != nil {
+		return r, fmt.Errorf("error selecting time report: %w", err)
+	}
+
+	projects := make(map[string]int)
+	users := make(map[string]int)
+	for _, row := range r.Rows {
+		r.Minutes += row.Minutes
+
+		if i, ok := projects[row.ProjectID]; ok {
+			r.Projects[i].Minutes += row.Minutes
+		} else {
+			projects[row.ProjectID] = len(r.Projects)
+			r.Projects = append(r.Projects, model.TimeReportProject{ProjectID: row.ProjectID, ProjectName: row.ProjectName, Minutes: row.Minutes})
+		}
+
+		if i, ok := users[row.UserID]; ok {
+			r.Users[i].Minutes += row.Minutes
+		} else {
+			users[row.UserID] = len(r.Users)
+			r.Users = append(r.Users, model.TimeReportUser{UserID: row.UserID, Minutes: row.Minutes})
+		}
+	}
+
+	return r, nil
+}
+
+// Entries lists the worklogs in projects the user can see with their project and task,
+// oldest first, for invoicing.
+func (wr *WorklogRepository) Entries(ctx context.Context, filter model.WorklogFilter) ([]model.WorklogEntry, error) {
+	var list = make([]model.WorklogEntry, 0)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return list, web.CtxErr()
+	}
+
+	conn, Close, err := wr.pg.GetReadConnection(ctx)
+	if err != nil {
+		return list, err
+	}
+	defer Close()
+
+	stmt := `
+		select
+			w.worklog_id, w.tenant_id, w.task_id, w.project_id, w.user_id, w.started_at, w.minutes, w.note,
+			w.updated_at, w.created_at, p.name as project_name, coalesce(t.key, '') as task_key, t.title as task_title
+		from worklogs w
+		join projects p on p.project_id = w.project_id
+		join tasks t on t.task_id = w.task_id
+		where ` + worklogFilter + ` and ` + visibleWorklog(1) + `
+		order by w.started_at, w.worklog_id
+	`
+	var es []model.WorklogEntry
+	if err = conn.SelectContext(ctx, &es, stmt, values.UserID, filter.ProjectID, filter.UserID, filter.From, filter.To); err != nil {
+		return list, fmt.Errorf("error selecting worklogs: %w", err)
+	}
+
+	for _, e := range es {
+		e.Worklog = utcWorklog(e.Worklog)
+		list = append(list, e)
+	}
+	return list, nil
+}
+
+// worklogFilter restricts worklogs w to a project, a user and a range of start times,
+// bound to the second to fifth parameters.
+const worklogFilter = `
+	($2 = '' or w.project_id = $2) and ($3 = '' or w.user_id = $3)
+	and w.started_at >= $4 and w.started_at < $5
+`
+
+// lockWorklog locks a worklog of a task the user can see for a change.
+func lockWorklog(ctx context.Context, tx *sqlx.Tx, tid string, wid string, userID string) (model.Worklog, error) {
+	var w model.Worklog
+
+	stmt := selectWorklog + ` where worklog_id = $1 and task_id = $2 and ` + visibleWorklog(3) + ` for update`
+	if err := tx.QueryRowxContext(ctx, stmt, wid, tid, userID).StructScan(&w); err != nil {
+		if err == sql.ErrNoRows {
+			return w, fail.ErrNotFound
+		}
+		return w, err
+	}
+
+	return utcWorklog(w), nil
+}
+
+// insertWorklog inserts a worklog and lowers the remaining estimate of its task by the
+// time logged, down to zero.
+func insertWorklog(ctx context.Context, tx *sqlx.Tx, w model.Worklog) error {
+	stmt := `
+		insert into worklogs (worklog_id, tenant_id, task_id, project_id, user_id, started_at, minutes, note, updated_at, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := tx.ExecContext(ctx, stmt, w.ID, w.TenantID, w.TaskID, w.ProjectID, w.UserID, w.StartedAt, w.Minutes, w.Note, w.UpdatedAt, w.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting worklog on task %s: %w", w.TaskID, err)
+	}
+
+	stmt = `update tasks set remaining_estimate = greatest(remaining_estimate - $1, 0) where task_id = $2`
+	if _, err = tx.ExecContext(ctx, stmt, w.Minutes, w.TaskID); err != nil {
+		return fmt.Errorf("error updating remaining estimate of task %s: %w", w.TaskID, err)
+	}
+	return nil
+}
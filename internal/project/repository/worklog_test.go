@@ -0,0 +1,149 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/repository"
+	"github.com/devpies/saas-core/internal/project/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWorklogRepository(t *testing.T) {
+	// The fixture tasks belong to the second project.
+	project := testProjects[1]
+	task := testTasks[0]
+
+	owner := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: project.UserID})
+	editorID := uuid.New().String()
+	editor := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: editorID})
+	outsider := web.NewContext(testutils.MockCtx, &web.Values{TenantID: project.TenantID, UserID: uuid.New().String()})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	repo := repository.NewWorklogRepository(zap.NewNop(), db)
+	taskRepo := repository.NewTaskRepository(zap.NewNop(), db)
+	memberRepo := repository.NewMemberRepository(zap.NewNop(), db)
+
+	now := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+
+	_, err := memberRepo.Set(owner, project.ID, editorID, model.RoleEditor, now)
+	require.NoError(t, err)
+
+	_, err = taskRepo.Update(owner, task.ID, model.UpdateTask{OriginalEstimate: aws.Int(120), RemainingEstimate: aws.Int(120)}, now)
+	require.NoError(t, err)
+
+	var logged model.Worklog
+
+	t.Run("log time", func(t *testing.T) {
+		var err error
+
+		logged, err = repo.Create(editor, task.ID, model.NewWorklog{StartedAt: now.Add(-3 * time.Hour), Minutes: 90, Note: "Review"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, project.ID, logged.ProjectID)
+
+		_, err = repo.Create(outsider, task.ID, model.NewWorklog{StartedAt: now, Minutes: 30}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		retrieved, err := taskRepo.Retrieve(owner, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 120, retrieved.OriginalEstimate)
+		assert.Equal(t, 30, retrieved.RemainingEstimate)
+		assert.Equal(t, 90, retrieved.TimeSpent)
+
+		// The remaining estimate does not go below zero.
+		_, err = repo.Create(owner, task.ID, model.NewWorklog{StartedAt: now.Add(-time.Hour), Minutes: 45}, now)
+		require.NoError(t, err)
+
+		retrieved, err = taskRepo.Retrieve(owner, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, retrieved.RemainingEstimate)
+		assert.Equal(t, 135, retrieved.TimeSpent)
+
+		list, err := repo.List(editor, task.ID)
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+	})
+
+	t.Run("only authors change worklogs", func(t *testing.T) {
+		_, err := repo.Update(owner, task.ID, logged.ID, model.UpdateWorklog{Minutes: aws.Int(60)}, now)
+		assert.Equal(t, fail.ErrNotAuthorized, err)
+
+		w, err := repo.Update(editor, task.ID, logged.ID, model.UpdateWorklog{Minutes: aws.Int(60)}, now)
+		require.NoError(t, err)
+		assert.Equal(t, 60, w.Minutes)
+		assert.Equal(t, "Review", w.Note)
+	})
+
+	t.Run("timer", func(t *testing.T) {
+		_, err := repo.Timer(editor)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		tm, err := repo.StartTimer(editor, task.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, task.ID, tm.TaskID)
+
+		_, err = repo.StartTimer(editor, testTasks[1].ID, now)
+		assert.Equal(t, fail.ErrTimerRunning, err)
+
+		w, err := repo.StopTimer(editor, model.StopTimer{Note: "Pairing"}, now.Add(25*time.Minute+40*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 26, w.Minutes)
+		assert.Equal(t, now, w.StartedAt)
+
+		_, err = repo.StopTimer(editor, model.StopTimer{}, now)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		_, err = repo.StartTimer(editor, task.ID, now)
+		require.NoError(t, err)
+		require.NoError(t, repo.DiscardTimer(editor))
+		assert.Equal(t, fail.ErrNotFound, repo.DiscardTimer(editor))
+	})
+
+	t.Run("report", func(t *testing.T) {
+		filter := model.WorklogFilter{
+			ProjectID: project.ID,
+			From:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:        time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		}
+
+		r, err := repo.Report(owner, filter)
+		require.NoError(t, err)
+		assert.Equal(t, 60+45+26, r.Minutes)
+		require.Len(t, r.Projects, 1)
+		assert.Equal(t, project.Name, r.Projects[0].ProjectName)
+		require.Len(t, r.Users, 2)
+		require.Len(t, r.Rows, 2)
+		assert.Equal(t, "2026-03-02", r.Rows[0].Date)
+
+		filter.UserID = editorID
+		entries, err := repo.Entries(owner, filter)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, task.Title, entries[0].TaskTitle)
+		assert.Equal(t, "Review", entries[0].Note)
+
+		// Users only see time logged in projects they can see.
+		r, err = repo.Report(outsider, filter)
+		require.NoError(t, err)
+		assert.Zero(t, r.Minutes)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := repo.Delete(outsider, task.ID, logged.ID)
+		assert.Equal(t, fail.ErrNotFound, err)
+
+		// Owners delete the time others logged.
+		require.NoError(t, repo.Delete(owner, task.ID, logged.ID))
+		assert.Equal(t, fail.ErrNotFound, repo.Delete(owner, task.ID, logged.ID))
+	})
+}
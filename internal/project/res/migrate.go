@@ -5,6 +5,8 @@ import (
 	"embed"
 	"errors"
 
+	"github.com/devpies/saas-core/internal/tenantdb"
+
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" // required for golang-migrate
 	_ "github.com/golang-migrate/migrate/v4/source/file"       // required for golang-migrate
@@ -15,14 +17,21 @@ import (
 //go:embed migrations/*.sql
 var content embed.FS
 
+// createServiceRole is the statement of the first migration creating the service role.
+const createServiceRole = `CREATE USER user_a WITH PASSWORD 'postgres';`
+
 // MigrateUp applies the latest database migration.
 func MigrateUp(databaseURL string) error {
 	d, err := iofs.New(content, "migrations")
 	if err != nil {
 		return err
 	}
+	src, err := tenantdb.SharedRoleSource(databaseURL, d, "user_a", createServiceRole)
+	if err != nil {
+		return err
+	}
 
-	m, err := migrate.NewWithSourceInstance("iofs", d, databaseURL)
+	m, err := migrate.NewWithSourceInstance("iofs", src, databaseURL)
 	if err != nil {
 		println(databaseURL)
 		return err
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS comment_likes;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_task_id_fkey;
+ALTER TABLE comments ALTER COLUMN likes DROP NOT NULL;
+ALTER TABLE comments ALTER COLUMN likes DROP DEFAULT;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS comments TEXT[];
+UPDATE tasks SET comments = (
+    SELECT array_agg(c.content ORDER BY c.created_at) FROM comments c WHERE c.task_id = tasks.task_id
+);
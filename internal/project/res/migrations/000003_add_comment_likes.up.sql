@@ -0,0 +1,44 @@
+-- Comments kept on the task move to the comments table, in their order on the task.
+-- Entries naming an existing comment were references to it and are left out.
+INSERT INTO comments (comment_id, task_id, tenant_id, content, likes, user_id, edited, updated_at, created_at)
+SELECT
+    uuid_in(md5(t.task_id || ':' || e.n)::cstring)::text,
+    t.task_id,
+    t.tenant_id,
+    e.entry,
+    0,
+    t.user_id,
+    FALSE,
+    t.created_at + e.n * INTERVAL '1 microsecond',
+    t.created_at + e.n * INTERVAL '1 microsecond'
+FROM tasks t, unnest(t.comments) WITH ORDINALITY AS e(entry, n)
+WHERE coalesce(e.entry, '') <> ''
+    AND NOT EXISTS (SELECT 1 FROM comments c WHERE c.comment_id = e.entry);
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS comments;
+
+-- Comments of tasks that no longer exist would break the foreign key.
+DELETE FROM comments c WHERE NOT EXISTS (SELECT 1 FROM tasks t WHERE t.task_id = c.task_id);
+
+UPDATE comments SET likes = 0 WHERE likes IS NULL;
+ALTER TABLE comments ALTER COLUMN likes SET DEFAULT 0;
+ALTER TABLE comments ALTER COLUMN likes SET NOT NULL;
+ALTER TABLE comments
+    ADD CONSTRAINT comments_task_id_fkey FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS comment_likes (
+    comment_id VARCHAR(36) NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    FOREIGN KEY (comment_id) REFERENCES comments (comment_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_like_tenant ON comment_likes(tenant_id);
+
+ALTER TABLE comment_likes ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY comment_likes_isolation_policy ON comment_likes
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON comment_likes TO user_a;
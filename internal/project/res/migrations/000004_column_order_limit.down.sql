@@ -0,0 +1,4 @@
+ALTER TABLE columns ALTER COLUMN column_name TYPE VARCHAR(8);
+DROP INDEX IF EXISTS idx_column_project_name;
+
+ALTER TABLE projects ALTER COLUMN column_order TYPE TEXT ARRAY[10];
@@ -0,0 +1,7 @@
+-- The column limit is validated by the service; ARRAY[10] was never enforced by postgres.
+ALTER TABLE projects ALTER COLUMN column_order TYPE TEXT[];
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_column_project_name ON columns(project_id, column_name);
+
+-- Boards hold up to ten columns, named column-1 to column-10.
+ALTER TABLE columns ALTER COLUMN column_name TYPE VARCHAR(10);
@@ -0,0 +1,12 @@
+ALTER TABLE columns ADD COLUMN IF NOT EXISTS task_ids TEXT[];
+
+UPDATE columns c
+SET task_ids = coalesce(
+    (SELECT array_agg(t.task_id ORDER BY t.rank) FROM tasks t WHERE t.column_id = c.column_id),
+    '{}'
+);
+
+DROP INDEX IF EXISTS idx_task_column_rank;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_column_id_fkey;
+ALTER TABLE tasks DROP COLUMN IF EXISTS rank;
+ALTER TABLE tasks DROP COLUMN IF EXISTS column_id;
@@ -0,0 +1,29 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS column_id VARCHAR(36);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+-- Tasks keep their position from the column task_ids arrays.
+UPDATE tasks t
+SET column_id = c.column_id, rank = lpad(c.pos::text, 6, '0') || 'V'
+FROM (
+    SELECT column_id, u.task_id, u.pos
+    FROM columns, unnest(task_ids) WITH ORDINALITY AS u(task_id, pos)
+) c
+WHERE t.task_id = c.task_id;
+
+-- Tasks missing from every column go to the end of the first column of their project.
+UPDATE tasks t
+SET column_id = o.column_id, rank = 'z' || lpad(o.pos::text, 6, '0') || 'V'
+FROM (
+    SELECT t.task_id, c.column_id, row_number() OVER (PARTITION BY c.column_id ORDER BY t.created_at) AS pos
+    FROM tasks t
+    JOIN projects p ON p.project_id = t.project_id
+    JOIN columns c ON c.project_id = p.project_id AND c.column_name = p.column_order[1]
+    WHERE t.column_id IS NULL
+) o
+WHERE t.task_id = o.task_id;
+
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_column_id_fkey FOREIGN KEY (column_id) REFERENCES columns (column_id) ON DELETE CASCADE;
+CREATE INDEX idx_task_column_rank ON tasks(column_id, rank);
+
+ALTER TABLE columns DROP COLUMN IF EXISTS task_ids;
@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_project_id_key_key;
+
+DROP TABLE IF EXISTS project_task_sequences;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS project_task_sequences (
+    project_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    last_value INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_task_sequence_tenant ON project_task_sequences(tenant_id);
+
+-- Start every sequence after the highest key handed out so far.
+INSERT INTO project_task_sequences (project_id, tenant_id, last_value)
+SELECT p.project_id, p.tenant_id, coalesce(max(substring(t.key from '-(\d+)$')::int), 0)
+FROM projects p
+LEFT JOIN tasks t ON t.project_id = p.project_id
+GROUP BY p.project_id, p.tenant_id;
+
+-- Give tasks that were created with a duplicate key a fresh one.
+WITH duplicates AS (
+    SELECT task_id, project_id,
+        row_number() OVER (PARTITION BY project_id, key ORDER BY created_at, task_id) AS copy
+    FROM tasks
+), renumbered AS (
+    SELECT d.task_id, d.project_id,
+        row_number() OVER (PARTITION BY d.project_id ORDER BY d.task_id) AS n
+    FROM duplicates d
+    WHERE d.copy > 1
+)
+UPDATE tasks t
+SET key = p.prefix || (s.last_value + r.n)
+FROM renumbered r
+JOIN projects p ON p.project_id = r.project_id
+JOIN project_task_sequences s ON s.project_id = r.project_id
+WHERE t.task_id = r.task_id;
+
+UPDATE project_task_sequences s
+SET last_value = m.last_value
+FROM (
+    SELECT project_id, max(substring(key from '-(\d+)$')::int) AS last_value
+    FROM tasks
+    GROUP BY project_id
+) m
+WHERE s.project_id = m.project_id AND m.last_value > s.last_value;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_project_id_key_key UNIQUE (project_id, key);
+
+ALTER TABLE project_task_sequences ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY project_task_sequences_isolation_policy ON project_task_sequences
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON project_task_sequences TO user_a;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_task_search;
+
+DROP TRIGGER IF EXISTS comments_search_update ON comments;
+DROP TRIGGER IF EXISTS tasks_search_update ON tasks;
+DROP FUNCTION IF EXISTS comments_search_trigger();
+DROP FUNCTION IF EXISTS tasks_search_trigger();
+DROP FUNCTION IF EXISTS task_search_vector(TEXT, TEXT, VARCHAR);
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+
+CREATE INDEX idx_task_title ON tasks(title);
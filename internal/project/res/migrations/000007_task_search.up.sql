@@ -0,0 +1,48 @@
+DROP INDEX IF EXISTS idx_task_title;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Titles rank above content, which ranks above comments.
+CREATE OR REPLACE FUNCTION task_search_vector(p_title TEXT, p_content TEXT, p_task_id VARCHAR) RETURNS TSVECTOR
+LANGUAGE sql STABLE AS $$
+    SELECT setweight(to_tsvector('english', coalesce(p_title, '')), 'A')
+        || setweight(to_tsvector('english', coalesce(p_content, '')), 'B')
+        || setweight(to_tsvector('english', coalesce(
+            (SELECT string_agg(c.content, ' ') FROM comments c WHERE c.task_id = p_task_id), ''
+        )), 'C')
+$$;
+
+CREATE OR REPLACE FUNCTION tasks_search_trigger() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.search_vector := task_search_vector(NEW.title, NEW.content, NEW.task_id);
+    RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION comments_search_trigger() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    tid VARCHAR(36);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        tid := OLD.task_id;
+    ELSE
+        tid := NEW.task_id;
+    END IF;
+    UPDATE tasks SET search_vector = task_search_vector(title, content, task_id) WHERE task_id = tid;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER tasks_search_update
+    BEFORE INSERT OR UPDATE OF title, content ON tasks
+    FOR EACH ROW EXECUTE FUNCTION tasks_search_trigger();
+
+CREATE TRIGGER comments_search_update
+    AFTER INSERT OR UPDATE OF content OR DELETE ON comments
+    FOR EACH ROW EXECUTE FUNCTION comments_search_trigger();
+
+UPDATE tasks SET search_vector = task_search_vector(title, content, task_id);
+
+CREATE INDEX idx_task_search ON tasks USING GIN (search_vector);
@@ -0,0 +1,20 @@
+-- Events outlive their task, so task_id deliberately has no foreign key.
+CREATE TABLE IF NOT EXISTS task_events (
+    event_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    kind TEXT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX idx_task_event_task ON task_events(task_id, created_at DESC);
+CREATE INDEX idx_task_event_project ON task_events(project_id, created_at DESC);
+
+ALTER TABLE task_events ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY task_events_isolation_policy ON task_events
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON task_events TO user_a;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS task_labels;
+DROP TABLE IF EXISTS labels;
+
+DROP INDEX IF EXISTS idx_task_due;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_schedule_check;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_priority_check;
+ALTER TABLE tasks DROP COLUMN IF EXISTS due_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS start_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS priority;
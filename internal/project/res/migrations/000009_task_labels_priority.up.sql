@@ -0,0 +1,41 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'none';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS start_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_priority_check CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent'));
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_schedule_check CHECK (start_at IS NULL OR due_at IS NULL OR start_at <= due_at);
+CREATE INDEX idx_task_due ON tasks(project_id, due_at) WHERE due_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS labels (
+    label_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    name VARCHAR(30) NOT NULL,
+    color VARCHAR(7) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_label_project_name ON labels(project_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id VARCHAR(36) NOT NULL,
+    label_id VARCHAR(36) NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels (label_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_task_label_label ON task_labels(label_id);
+
+ALTER TABLE labels ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_labels ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY labels_isolation_policy ON labels
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY task_labels_isolation_policy ON task_labels
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON labels TO user_a;
+GRANT ALL ON task_labels TO user_a;
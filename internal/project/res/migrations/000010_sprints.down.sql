@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_task_sprint;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_sprint_id_fkey;
+ALTER TABLE tasks DROP COLUMN IF EXISTS sprint_id;
+
+DROP TABLE IF EXISTS sprints;
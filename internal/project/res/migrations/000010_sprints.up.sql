@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS sprints (
+    sprint_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    capacity INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'planned',
+    start_at TIMESTAMPTZ,
+    end_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CHECK (status IN ('planned', 'active', 'completed')),
+    CHECK (capacity >= 0),
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_sprint_project ON sprints(project_id, created_at);
+-- A project runs at most one sprint at a time.
+CREATE UNIQUE INDEX idx_sprint_active ON sprints(project_id) WHERE status = 'active';
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sprint_id VARCHAR(36);
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_sprint_id_fkey FOREIGN KEY (sprint_id) REFERENCES sprints (sprint_id) ON DELETE SET NULL;
+CREATE INDEX idx_task_sprint ON tasks(sprint_id);
+
+ALTER TABLE sprints ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY sprints_isolation_policy ON sprints
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON sprints TO user_a;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS task_links;
+
+DROP INDEX IF EXISTS idx_task_parent;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_parent_check;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_parent_id_fkey;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
+
+DROP FUNCTION IF EXISTS task_done(VARCHAR, VARCHAR);
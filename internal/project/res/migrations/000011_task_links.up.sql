@@ -0,0 +1,41 @@
+-- A task is done while it is in the last column of its project board.
+CREATE OR REPLACE FUNCTION task_done(p_project_id VARCHAR, p_column_id VARCHAR) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM projects p JOIN columns c ON c.project_id = p.project_id
+        WHERE p.project_id = p_project_id
+            AND c.column_id = p_column_id
+            AND c.column_name = p.column_order[array_length(p.column_order, 1)]
+    )
+$$;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id VARCHAR(36);
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES tasks (task_id) ON DELETE SET NULL;
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_parent_check CHECK (parent_id <> task_id);
+CREATE INDEX idx_task_parent ON tasks(parent_id);
+
+-- Inverse kinds such as is_blocked_by are stored as the forward kind with the tasks swapped.
+CREATE TABLE IF NOT EXISTS task_links (
+    link_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    target_id VARCHAR(36) NOT NULL,
+    kind TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CHECK (kind IN ('blocks', 'relates_to', 'duplicates')),
+    CHECK (task_id <> target_id),
+    UNIQUE (task_id, target_id, kind),
+    FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE,
+    FOREIGN KEY (target_id) REFERENCES tasks (task_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_task_link_target ON task_links(target_id, kind);
+
+ALTER TABLE task_links ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY task_links_isolation_policy ON task_links
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON task_links TO user_a;
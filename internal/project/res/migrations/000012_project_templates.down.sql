@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS project_templates;
+
+ALTER TABLE columns DROP CONSTRAINT IF EXISTS columns_wip_limit_check;
+ALTER TABLE columns DROP COLUMN IF EXISTS wip_limit;
@@ -0,0 +1,24 @@
+-- 000004 widens it as well; this covers databases that ran 000004 before it did.
+ALTER TABLE columns ALTER COLUMN column_name TYPE VARCHAR(10);
+ALTER TABLE columns ADD COLUMN IF NOT EXISTS wip_limit INTEGER;
+ALTER TABLE columns ADD CONSTRAINT columns_wip_limit_check CHECK (wip_limit > 0);
+
+-- Built-in templates are defined by the service; only tenant templates are stored.
+CREATE TABLE IF NOT EXISTS project_templates (
+    template_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    name VARCHAR(36) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    definition JSONB NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX idx_template_name ON project_templates(tenant_id, lower(name));
+
+ALTER TABLE project_templates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY project_templates_isolation_policy ON project_templates
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON project_templates TO user_a;
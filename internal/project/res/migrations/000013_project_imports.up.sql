@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS project_imports (
+    import_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    format TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    project_id VARCHAR(36),
+    tasks INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CHECK (format IN ('bundle', 'trello', 'jira')),
+    CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+CREATE INDEX idx_import_tenant ON project_imports(tenant_id, created_at DESC);
+
+ALTER TABLE project_imports ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY project_imports_isolation_policy ON project_imports
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON project_imports TO user_a;
@@ -0,0 +1,12 @@
+DROP FUNCTION IF EXISTS trashed_tenants(TIMESTAMPTZ);
+
+DROP INDEX IF EXISTS idx_task_trash;
+DROP INDEX IF EXISTS idx_project_trash;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS archived_at;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS active BOOLEAN DEFAULT TRUE;
+UPDATE projects SET active = archived_at IS NULL;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,22 @@
+-- Projects are archived or moved to the trash instead of being deactivated or deleted.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+UPDATE projects SET archived_at = updated_at WHERE active = FALSE;
+ALTER TABLE projects DROP COLUMN IF EXISTS active;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_project_trash ON projects(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_task_trash ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- The purge job runs outside any tenant, so it asks which tenants have expired trash
+-- through a function that bypasses row level security and returns tenant ids only.
+CREATE OR REPLACE FUNCTION trashed_tenants(p_before TIMESTAMPTZ) RETURNS SETOF VARCHAR
+LANGUAGE sql STABLE SECURITY DEFINER AS $$
+    SELECT tenant_id FROM projects WHERE deleted_at < p_before
+    UNION
+    SELECT tenant_id FROM tasks WHERE deleted_at < p_before
+$$;
+
+GRANT EXECUTE ON FUNCTION trashed_tenants(TIMESTAMPTZ) TO user_a;
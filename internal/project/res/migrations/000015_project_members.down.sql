@@ -0,0 +1,5 @@
+DROP FUNCTION IF EXISTS project_role(VARCHAR, VARCHAR);
+ALTER TABLE projects DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS project_members;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
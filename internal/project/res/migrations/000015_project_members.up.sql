@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS teams (
+    team_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    name TEXT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX idx_team_name ON teams(tenant_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (team_id, user_id),
+    FOREIGN KEY (team_id) REFERENCES teams (team_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_team_member_user ON team_members(user_id);
+
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    role TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (project_id, user_id),
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE,
+    CHECK (role IN ('owner', 'editor', 'viewer'))
+);
+CREATE INDEX idx_project_member_user ON project_members(user_id);
+
+-- A project may be assigned one team, whose members edit the project.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS team_id VARCHAR(36) REFERENCES teams (team_id) ON DELETE SET NULL;
+
+-- Project creators own their projects.
+INSERT INTO project_members (project_id, user_id, tenant_id, role, updated_at, created_at)
+SELECT project_id, user_id, tenant_id, 'owner', created_at, created_at FROM projects
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE teams ENABLE ROW LEVEL SECURITY;
+ALTER TABLE team_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE project_members ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY teams_isolation_policy ON teams
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY team_members_isolation_policy ON team_members
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY project_members_isolation_policy ON project_members
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+-- project_role returns the role of a user in a project: the role of a direct member,
+-- editor through the team of the project, viewer of a public project, or null when the
+-- user cannot see the project. It runs with row level security of the caller.
+CREATE OR REPLACE FUNCTION project_role(p_project_id VARCHAR, p_user_id VARCHAR) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT coalesce(
+        (SELECT role FROM project_members WHERE project_id = p_project_id AND user_id = p_user_id),
+        (SELECT 'editor' FROM projects p JOIN team_members tm ON tm.team_id = p.team_id
+            WHERE p.project_id = p_project_id AND tm.user_id = p_user_id),
+        (SELECT 'viewer' FROM projects WHERE project_id = p_project_id AND public)
+    )
+$$;
+
+GRANT ALL ON teams TO user_a;
+GRANT ALL ON team_members TO user_a;
+GRANT ALL ON project_members TO user_a;
+GRANT EXECUTE ON FUNCTION project_role(VARCHAR, VARCHAR) TO user_a;
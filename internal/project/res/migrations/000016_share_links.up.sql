@@ -0,0 +1,21 @@
+-- Share links give anyone holding their token read-only access to a project board. Only
+-- a hash of the token is stored.
+CREATE TABLE IF NOT EXISTS share_links (
+    share_link_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_id VARCHAR(36) NOT NULL,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_share_link_project ON share_links(project_id);
+
+ALTER TABLE share_links ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY share_links_isolation_policy ON share_links
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON share_links TO user_a;
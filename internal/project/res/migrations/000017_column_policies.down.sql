@@ -0,0 +1,2 @@
+ALTER TABLE columns DROP CONSTRAINT IF EXISTS columns_entry_rules_check;
+ALTER TABLE columns DROP COLUMN IF EXISTS entry_rules;
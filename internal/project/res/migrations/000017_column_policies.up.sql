@@ -0,0 +1,4 @@
+-- Tasks entering a column must meet its entry rules.
+ALTER TABLE columns ADD COLUMN IF NOT EXISTS entry_rules TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE columns ADD CONSTRAINT columns_entry_rules_check
+    CHECK (entry_rules <@ ARRAY['assignee', 'points', 'due_date']::TEXT[]);
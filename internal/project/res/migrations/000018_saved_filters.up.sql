@@ -0,0 +1,23 @@
+-- Saved filters are named task queries. Filters without a project query every project
+-- of the tenant, and shared filters are visible to everyone in the tenant.
+CREATE TABLE IF NOT EXISTS saved_filters (
+    filter_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    name VARCHAR(50) NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    user_id VARCHAR(36) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_saved_filter_name ON saved_filters(tenant_id, user_id, lower(name));
+CREATE INDEX idx_saved_filter_project ON saved_filters(project_id);
+
+ALTER TABLE saved_filters ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY saved_filters_isolation_policy ON saved_filters
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON saved_filters TO user_a;
@@ -0,0 +1,40 @@
+-- Custom fields are defined per project. Their values on tasks are stored by type, and
+-- are removed with the task or the field.
+CREATE TABLE IF NOT EXISTS custom_fields (
+    field_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    name VARCHAR(30) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE,
+    CONSTRAINT custom_fields_kind_check CHECK (kind IN ('text', 'number', 'select', 'multi_select', 'date', 'user'))
+);
+CREATE UNIQUE INDEX idx_custom_field_name ON custom_fields(project_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS task_field_values (
+    task_id VARCHAR(36) NOT NULL,
+    field_id VARCHAR(36) NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    text_value TEXT,
+    number_value DOUBLE PRECISION,
+    date_value DATE,
+    options TEXT[],
+    PRIMARY KEY (task_id, field_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_fields (field_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_task_field_value_field ON task_field_values(field_id);
+
+ALTER TABLE custom_fields ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_field_values ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY custom_fields_isolation_policy ON custom_fields
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY task_field_values_isolation_policy ON task_field_values
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON custom_fields TO user_a;
+GRANT ALL ON task_field_values TO user_a;
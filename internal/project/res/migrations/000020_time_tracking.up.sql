@@ -0,0 +1,42 @@
+-- Time is tracked in minutes. Tasks carry their original and remaining estimates, and
+-- each worklog entry records time a user spent on a task.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS original_estimate INT NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS remaining_estimate INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS worklogs (
+    worklog_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    minutes INT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE,
+    CONSTRAINT worklogs_minutes_check CHECK (minutes > 0)
+);
+CREATE INDEX idx_worklog_task ON worklogs(task_id);
+CREATE INDEX idx_worklog_project_started ON worklogs(project_id, started_at);
+CREATE INDEX idx_worklog_user_started ON worklogs(user_id, started_at);
+
+-- A user runs at most one timer at a time.
+CREATE TABLE IF NOT EXISTS timers (
+    user_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE
+);
+
+ALTER TABLE worklogs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE timers ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY worklogs_isolation_policy ON worklogs
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY timers_isolation_policy ON timers
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON worklogs TO user_a;
+GRANT ALL ON timers TO user_a;
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS scheduled_tenants(TIMESTAMPTZ);
+DROP TABLE IF EXISTS recurrence_runs;
+DROP TABLE IF EXISTS recurring_tasks;
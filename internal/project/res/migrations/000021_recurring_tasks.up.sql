@@ -0,0 +1,57 @@
+-- Recurring tasks are task templates with a recurrence rule. The scheduler creates a task
+-- in the target column for each due occurrence, and next_run_at is null once the series
+-- has ended.
+CREATE TABLE IF NOT EXISTS recurring_tasks (
+    recurrence_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    column_id VARCHAR(36) NOT NULL,
+    title VARCHAR(75) NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    priority VARCHAR(10) NOT NULL DEFAULT 'none',
+    points INT NOT NULL DEFAULT 0,
+    assigned_to VARCHAR(36) NOT NULL DEFAULT '',
+    rule TEXT NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    next_run_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    user_id VARCHAR(36) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects (project_id) ON DELETE CASCADE,
+    FOREIGN KEY (column_id) REFERENCES columns (column_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_recurring_task_project ON recurring_tasks(project_id);
+CREATE INDEX idx_recurring_task_due ON recurring_tasks(next_run_at) WHERE NOT paused;
+
+-- Every occurrence creates one task at most, however often the scheduler runs it.
+CREATE TABLE IF NOT EXISTS recurrence_runs (
+    recurrence_id VARCHAR(36) NOT NULL,
+    occurrence_at TIMESTAMPTZ NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (recurrence_id, occurrence_at),
+    FOREIGN KEY (recurrence_id) REFERENCES recurring_tasks (recurrence_id) ON DELETE CASCADE
+);
+
+ALTER TABLE recurring_tasks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE recurrence_runs ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY recurring_tasks_isolation_policy ON recurring_tasks
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY recurrence_runs_isolation_policy ON recurrence_runs
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON recurring_tasks TO user_a;
+GRANT ALL ON recurrence_runs TO user_a;
+
+-- The scheduler runs outside any tenant, so like the purge job it asks which tenants have
+-- due recurring tasks through a function that returns tenant ids only.
+CREATE OR REPLACE FUNCTION scheduled_tenants(p_now TIMESTAMPTZ) RETURNS SETOF VARCHAR
+LANGUAGE sql STABLE SECURITY DEFINER AS $$
+    SELECT DISTINCT tenant_id FROM recurring_tasks WHERE NOT paused AND next_run_at <= p_now
+$$;
+
+GRANT EXECUTE ON FUNCTION scheduled_tenants(TIMESTAMPTZ) TO user_a;
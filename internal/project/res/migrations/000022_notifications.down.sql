@@ -0,0 +1,7 @@
+DROP FUNCTION IF EXISTS notifiable_tenants();
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS task_watchers;
+
+DROP INDEX IF EXISTS idx_task_event_pending;
+ALTER TABLE task_events DROP COLUMN IF EXISTS notified;
@@ -0,0 +1,74 @@
+-- Notifications are driven by task events. The dispatcher turns every event that is not
+-- notified yet into notifications for the users it concerns. Events recorded before
+-- notifications existed are considered notified.
+ALTER TABLE task_events ADD COLUMN IF NOT EXISTS notified BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE task_events ALTER COLUMN notified SET DEFAULT FALSE;
+CREATE INDEX idx_task_event_pending ON task_events(created_at) WHERE NOT notified;
+
+-- Watchers are told about the activity of a task.
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    tenant_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (task_id, user_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_task_watcher_user ON task_watchers(user_id);
+
+-- Notifications copy the key and title of their task, so they read the same after the
+-- task changes or is purged. An event notifies a user once.
+CREATE TABLE IF NOT EXISTS notifications (
+    notification_id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    event_kind TEXT NOT NULL,
+    actor_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    task_key TEXT NOT NULL DEFAULT '',
+    task_title TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (event_id, user_id)
+);
+CREATE INDEX idx_notification_user ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notification_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Users without preferences receive every notification.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    tenant_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    assigned BOOLEAN NOT NULL DEFAULT TRUE,
+    mentioned BOOLEAN NOT NULL DEFAULT TRUE,
+    commented BOOLEAN NOT NULL DEFAULT TRUE,
+    changed BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (tenant_id, user_id)
+);
+
+ALTER TABLE task_watchers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notification_preferences ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY task_watchers_isolation_policy ON task_watchers
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY notifications_isolation_policy ON notifications
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+CREATE POLICY notification_preferences_isolation_policy ON notification_preferences
+    USING (tenant_id = (SELECT current_setting('app.current_tenant')));
+
+GRANT ALL ON task_watchers TO user_a;
+GRANT ALL ON notifications TO user_a;
+GRANT ALL ON notification_preferences TO user_a;
+
+-- The dispatcher runs outside any tenant, so like the purge job it asks which tenants
+-- have events to notify through a function that returns tenant ids only.
+CREATE OR REPLACE FUNCTION notifiable_tenants() RETURNS SETOF VARCHAR
+LANGUAGE sql STABLE SECURITY DEFINER AS $$
+    SELECT DISTINCT tenant_id FROM task_events WHERE NOT notified
+$$;
+
+GRANT EXECUTE ON FUNCTION notifiable_tenants() TO user_a;
@@ -0,0 +1 @@
+ALTER TABLE project_imports DROP COLUMN IF EXISTS unassigned;
@@ -0,0 +1 @@
+ALTER TABLE project_imports ADD COLUMN IF NOT EXISTS unassigned TEXT[] NOT NULL DEFAULT '{}';
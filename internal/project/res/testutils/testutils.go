@@ -131,6 +131,10 @@ func prepareEnvironment(port string) {
 			key:   "PROJECT_DB_DISABLE_TLS",
 			value: "true",
 		},
+		{
+			key:   "PROJECT_DB_SILO_ADMIN_PASSWORD",
+			value: "postgres",
+		},
 		{
 			key:   "PROJECT_COGNITO_USER_POOL_ID",
 			value: "mock",
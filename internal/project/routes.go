@@ -3,9 +3,11 @@ package project
 import (
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/devpies/saas-core/internal/project/config"
 	"github.com/devpies/saas-core/internal/project/handler"
+	"github.com/devpies/saas-core/internal/tenantdb"
 	"github.com/devpies/saas-core/pkg/web"
 	"github.com/devpies/saas-core/pkg/web/mid"
 
@@ -21,16 +23,34 @@ func Routes(
 	taskHandler *handler.TaskHandler,
 	columnHandler *handler.ColumnHandler,
 	projectHandler *handler.ProjectHandler,
+	commentHandler *handler.CommentHandler,
+	activityHandler *handler.ActivityHandler,
+	labelHandler *handler.LabelHandler,
+	sprintHandler *handler.SprintHandler,
+	templateHandler *handler.TemplateHandler,
+	transferHandler *handler.TransferHandler,
+	memberHandler *handler.MemberHandler,
+	teamHandler *handler.TeamHandler,
+	streamHandler *handler.StreamHandler,
+	shareHandler *handler.ShareHandler,
+	filterHandler *handler.FilterHandler,
+	fieldHandler *handler.FieldHandler,
+	worklogHandler *handler.WorklogHandler,
+	recurrenceHandler *handler.RecurrenceHandler,
+	notificationHandler *handler.NotificationHandler,
 	config config.Config,
 ) http.Handler {
 	mux := chi.NewRouter()
 	mux.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://devpie.local:3000", "https://devpie.io"},
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "BasePath"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "BasePath", "Last-Event-ID", handler.ShareTokenHeader, tenantdb.PrimaryUntilHeader},
+		ExposedHeaders:   []string{tenantdb.PrimaryUntilHeader},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	mux.Use(streamToken)
+	mux.Use(tenantdb.ReadYourWrites(config.DB.ReadYourWritesWindow))
 
 	middleware := []web.Middleware{
 		mid.Logger(log),
@@ -41,17 +61,122 @@ func Routes(
 
 	app := web.NewApp(mux, shutdown, log, middleware...)
 
+	// Shared boards are read by anyone holding a share token, without signing in. The token
+	// travels in a header, so it stays out of the request logs.
+	public := web.NewApp(mux, shutdown, log, mid.Logger(log), mid.Errors(log), mid.Panics(log))
+	public.Handle(http.MethodGet, "/projects/shared", shareHandler.Board)
+
 	app.Handle(http.MethodGet, "/projects", projectHandler.List)
 	app.Handle(http.MethodPost, "/projects", projectHandler.Create)
+	app.Handle(http.MethodGet, "/projects/search", taskHandler.Search)
+	app.Handle(http.MethodGet, "/projects/tasks", taskHandler.Query)
+	app.Handle(http.MethodGet, "/projects/filters", filterHandler.List)
+	app.Handle(http.MethodPost, "/projects/filters", filterHandler.Create)
+	app.Handle(http.MethodGet, "/projects/filters/{fid}", filterHandler.Retrieve)
+	app.Handle(http.MethodPatch, "/projects/filters/{fid}", filterHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/filters/{fid}", filterHandler.Delete)
+	app.Handle(http.MethodGet, "/projects/trash", projectHandler.Trash)
+	app.Handle(http.MethodGet, "/projects/templates", templateHandler.List)
+	app.Handle(http.MethodPost, "/projects/templates", templateHandler.Create)
+	app.Handle(http.MethodGet, "/projects/templates/{tmid}", templateHandler.Retrieve)
+	app.Handle(http.MethodDelete, "/projects/templates/{tmid}", templateHandler.Delete)
+	app.Handle(http.MethodPost, "/projects/imports", transferHandler.Import)
+	app.Handle(http.MethodGet, "/projects/imports/{imid}", transferHandler.RetrieveImport)
+	app.Handle(http.MethodGet, "/projects/teams", teamHandler.List)
+	app.Handle(http.MethodPost, "/projects/teams", teamHandler.Create)
+	app.Handle(http.MethodGet, "/projects/teams/{tmid}", teamHandler.Retrieve)
+	app.Handle(http.MethodDelete, "/projects/teams/{tmid}", teamHandler.Delete)
+	app.Handle(http.MethodPost, "/projects/teams/{tmid}/members", teamHandler.AddMember)
+	app.Handle(http.MethodDelete, "/projects/teams/{tmid}/members/{uid}", teamHandler.RemoveMember)
 	app.Handle(http.MethodGet, "/projects/{pid}", projectHandler.Retrieve)
 	app.Handle(http.MethodPatch, "/projects/{pid}", projectHandler.Update)
 	app.Handle(http.MethodDelete, "/projects/{pid}", projectHandler.Delete)
+	app.Handle(http.MethodPatch, "/projects/{pid}/restore", projectHandler.Restore)
+	app.Handle(http.MethodPost, "/projects/{pid}/template", templateHandler.SaveProject)
+	app.Handle(http.MethodGet, "/projects/{pid}/members", memberHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/members", memberHandler.Add)
+	app.Handle(http.MethodPatch, "/projects/{pid}/members/{uid}", memberHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/{pid}/members/{uid}", memberHandler.Remove)
+	app.Handle(http.MethodPatch, "/projects/{pid}/team", memberHandler.AssignTeam)
+	app.Handle(http.MethodGet, "/projects/{pid}/export", transferHandler.Export)
+	app.Handle(http.MethodGet, "/projects/{pid}/stream", streamHandler.Stream)
+	app.Handle(http.MethodGet, "/projects/{pid}/shares", shareHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/shares", shareHandler.Create)
+	app.Handle(http.MethodDelete, "/projects/{pid}/shares/{shid}", shareHandler.Revoke)
 	app.Handle(http.MethodGet, "/projects/{pid}/columns", columnHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/columns", columnHandler.Create)
+	app.Handle(http.MethodPatch, "/projects/{pid}/columns/order", columnHandler.Reorder)
+	app.Handle(http.MethodPatch, "/projects/{pid}/columns/{cid}", columnHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/{pid}/columns/{cid}", columnHandler.Delete)
 	app.Handle(http.MethodGet, "/projects/{pid}/tasks", taskHandler.List)
+	app.Handle(http.MethodGet, "/projects/{pid}/activity", activityHandler.ListByProject)
+	app.Handle(http.MethodGet, "/projects/{pid}/labels", labelHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/labels", labelHandler.Create)
+	app.Handle(http.MethodPatch, "/projects/{pid}/labels/{lid}", labelHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/{pid}/labels/{lid}", labelHandler.Delete)
+	app.Handle(http.MethodGet, "/projects/{pid}/fields", fieldHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/fields", fieldHandler.Create)
+	app.Handle(http.MethodPatch, "/projects/{pid}/fields/{cfid}", fieldHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/{pid}/fields/{cfid}", fieldHandler.Delete)
+	app.Handle(http.MethodGet, "/projects/{pid}/recurring", recurrenceHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/recurring", recurrenceHandler.Create)
+	app.Handle(http.MethodPatch, "/projects/recurring/{rid}", recurrenceHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/recurring/{rid}", recurrenceHandler.Delete)
+	app.Handle(http.MethodGet, "/projects/{pid}/sprints", sprintHandler.List)
+	app.Handle(http.MethodPost, "/projects/{pid}/sprints", sprintHandler.Create)
+	app.Handle(http.MethodGet, "/projects/{pid}/sprints/{sid}", sprintHandler.Retrieve)
+	app.Handle(http.MethodPatch, "/projects/{pid}/sprints/{sid}/start", sprintHandler.Start)
+	app.Handle(http.MethodPatch, "/projects/{pid}/sprints/{sid}/complete", sprintHandler.Complete)
+	app.Handle(http.MethodPost, "/projects/{pid}/sprints/{sid}/tasks", sprintHandler.AddTasks)
+	app.Handle(http.MethodDelete, "/projects/{pid}/sprints/{sid}/tasks/{tid}", sprintHandler.RemoveTask)
+	app.Handle(http.MethodGet, "/projects/{pid}/sprints/{sid}/burndown", sprintHandler.Burndown)
+	app.Handle(http.MethodGet, "/projects/{pid}/tasks/by-key/{key}", taskHandler.RetrieveByKey)
 	app.Handle(http.MethodPost, "/projects/{pid}/columns/{cid}/tasks", taskHandler.Create)
 	app.Handle(http.MethodPatch, "/projects/tasks/{tid}", taskHandler.Update)
 	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/move", taskHandler.Move)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/archive", taskHandler.Archive)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/unarchive", taskHandler.Unarchive)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/restore", taskHandler.Restore)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/parent", taskHandler.SetParent)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/links", taskHandler.Link)
+	app.Handle(http.MethodDelete, "/projects/tasks/{tid}/links/{lkid}", taskHandler.Unlink)
+	app.Handle(http.MethodGet, "/projects/tasks/{tid}/activity", activityHandler.ListByTask)
+	app.Handle(http.MethodGet, "/projects/tasks/{tid}/worklogs", worklogHandler.List)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/worklogs", worklogHandler.Create)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/worklogs/{wid}", worklogHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/tasks/{tid}/worklogs/{wid}", worklogHandler.Delete)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/timer", worklogHandler.StartTimer)
+	app.Handle(http.MethodGet, "/projects/timer", worklogHandler.Timer)
+	app.Handle(http.MethodPatch, "/projects/timer/stop", worklogHandler.StopTimer)
+	app.Handle(http.MethodDelete, "/projects/timer", worklogHandler.DiscardTimer)
+	app.Handle(http.MethodGet, "/projects/worklogs/report", worklogHandler.Report)
+	app.Handle(http.MethodGet, "/projects/worklogs/export", worklogHandler.Export)
 	app.Handle(http.MethodDelete, "/projects/columns/{cid}/tasks/{tid}", taskHandler.Delete)
+	app.Handle(http.MethodGet, "/projects/tasks/{tid}/watchers", notificationHandler.Watchers)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/watchers", notificationHandler.Watch)
+	app.Handle(http.MethodDelete, "/projects/tasks/{tid}/watchers", notificationHandler.Unwatch)
+	app.Handle(http.MethodGet, "/projects/tasks/{tid}/comments", commentHandler.List)
+	app.Handle(http.MethodPost, "/projects/tasks/{tid}/comments", commentHandler.Create)
+	app.Handle(http.MethodPatch, "/projects/tasks/{tid}/comments/{cmid}", commentHandler.Update)
+	app.Handle(http.MethodDelete, "/projects/tasks/{tid}/comments/{cmid}", commentHandler.Delete)
+	app.Handle(http.MethodGet, "/notifications", notificationHandler.List)
+	app.Handle(http.MethodPatch, "/notifications/read", notificationHandler.ReadAll)
+	app.Handle(http.MethodGet, "/notifications/preferences", notificationHandler.Preferences)
+	app.Handle(http.MethodPatch, "/notifications/preferences", notificationHandler.UpdatePreferences)
+	app.Handle(http.MethodPatch, "/notifications/{nid}", notificationHandler.Update)
 
 	return app
 }
+
+// streamToken passes the access_token query parameter of stream requests on as a bearer
+// token, since browsers cannot set headers on EventSource connections.
+func streamToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/stream") && r.Header.Get("Authorization") == "" {
+			if token := r.URL.Query().Get("access_token"); token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,450 @@
+// Package rrule parses and expands the subset of iCalendar recurrence rules (RFC 5545)
+// that recurring tasks support, for example
+//
+//	FREQ=MONTHLY;INTERVAL=1;BYDAY=1MO
+//
+// Rules take FREQ (DAILY, WEEKLY, MONTHLY or YEARLY) along with INTERVAL, COUNT or UNTIL,
+// BYDAY, BYMONTHDAY and BYMONTH. Weeks start on Monday, and BYDAY ordinals such as 1MO or
+// -1FR count weekdays within a month, so they are limited to monthly and yearly rules. In
+// yearly rules BYDAY and BYMONTHDAY need BYMONTH and apply within each of its months.
+//
+// Occurrences are computed in UTC and keep the time of day of the start of the series.
+// The start itself is an occurrence only when it matches the rule.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCount is the largest COUNT and INTERVAL of a rule.
+const MaxCount = 1000
+
+// maxPeriods is the most periods expanded while looking for an occurrence, so that rules
+// that can never match, like the 30th of February, end.
+const maxPeriods = 5000
+
+// Frequencies.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Error represents a rule that cannot be parsed.
+type Error struct {
+	Msg string
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func errorf(format string, args ...interface{}) *Error {
+	return &Error{Msg: fmt.Sprintf(format, args...)}
+}
+
+// Day represents a BYDAY value. A non-zero Nth selects the nth such weekday of the month,
+// counted from its end when negative.
+type Day struct {
+	Nth     int
+	Weekday time.Weekday
+}
+
+// Rule represents a parsed recurrence rule.
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []Day
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// Parse parses a recurrence rule. An RRULE: prefix is allowed, and parts and values are
+// case-insensitive.
+func Parse(s string) (Rule, error) {
+	r := Rule{Interval: 1}
+
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return r, errorf("rule is empty")
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return r, errorf("part %q must be NAME=VALUE", part)
+		}
+		if seen[name] {
+			return r, errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			switch value {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = value
+			default:
+				err = errorf("FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+			}
+		case "INTERVAL":
+			r.Interval, err = number(name, value, 1, MaxCount)
+		case "COUNT":
+			r.Count, err = number(name, value, 1, MaxCount)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseDays(value)
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				var d int
+				if d, err = number(name, v, -31, 31); err != nil {
+					break
+				}
+				if d == 0 {
+					err = errorf("BYMONTHDAY must be between -31 and 31 and not 0")
+					break
+				}
+				r.ByMonthDay = append(r.ByMonthDay, d)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(value, ",") {
+				var m int
+				if m, err = number(name, v, 1, 12); err != nil {
+					break
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			if value != "MO" {
+				err = errorf("weeks start on Monday, so WKST must be MO")
+			}
+		default:
+			err = errorf("%s is not supported", name)
+		}
+		if err != nil {
+			return r, err
+		}
+	}
+
+	return r, r.validate()
+}
+
+func (r Rule) validate() error {
+	if r.Freq == "" {
+		return errorf("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errorf("COUNT and UNTIL cannot both be given")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return errorf("BYMONTHDAY cannot be used in weekly rules")
+	}
+	for _, d := range r.ByDay {
+		if d.Nth == 0 {
+			continue
+		}
+		if r.Freq != Monthly && r.Freq != Yearly {
+			return errorf("BYDAY ordinals need a monthly or yearly rule")
+		}
+	}
+	if r.Freq == Yearly && (len(r.ByDay) > 0 || len(r.ByMonthDay) > 0) && len(r.ByMonth) == 0 {
+		return errorf("BYDAY and BYMONTHDAY need BYMONTH in yearly rules")
+	}
+	return nil
+}
+
+func number(name, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, errorf("%s must be a number between %d and %d", name, min, max)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, errorf("UNTIL must be a UTC date like 20261231 or 20261231T235959Z")
+}
+
+func parseDays(value string) ([]Day, error) {
+	var days []Day
+
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return nil, errorf("BYDAY %q is not a weekday like MO or 1MO", v)
+		}
+		wd, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, errorf("BYDAY %q is not a weekday like MO or 1MO", v)
+		}
+
+		d := Day{Weekday: wd}
+		if ord := v[:len(v)-2]; ord != "" {
+			n, err := strconv.Atoi(ord)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, errorf("BYDAY ordinal of %q must be between -5 and 5 and not 0", v)
+			}
+			d.Nth = n
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// String returns the rule in its canonical form.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByMonth) > 0 {
+		values := make([]string, 0, len(r.ByMonth))
+		for _, m := range r.ByMonth {
+			values = append(values, strconv.Itoa(int(m)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(values, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		values := make([]string, 0, len(r.ByMonthDay))
+		for _, d := range r.ByMonthDay {
+			values = append(values, strconv.Itoa(d))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(values, ","))
+	}
+	if len(r.ByDay) > 0 {
+		values := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			day := strings.ToUpper(d.Weekday.String()[:2])
+			if d.Nth != 0 {
+				day = strconv.Itoa(d.Nth) + day
+			}
+			values = append(values, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(values, ","))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence of the series starting at start that comes after
+// the given time. It reports false when the series has ended.
+func (r Rule) Next(start, after time.Time) (time.Time, bool) {
+	start = start.UTC().Truncate(time.Second)
+	after = after.UTC()
+
+	n := 0
+	first := 0
+	if r.Count == 0 && after.After(start) {
+		// Without a count, the periods before the one of after cannot matter.
+		first = r.periodsBetween(start, after) - 1
+		if first < 0 {
+			first = 0
+		}
+	}
+
+	for p := first; p < first+maxPeriods; p++ {
+		for _, t := range r.period(start, p) {
+			if t.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return time.Time{}, false
+			}
+			n++
+			if r.Count > 0 && n > r.Count {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// periodsBetween returns how many whole periods of the rule separate start and t.
+func (r Rule) periodsBetween(start, t time.Time) int {
+	var units int
+
+	switch r.Freq {
+	case Daily:
+		units = int(t.Sub(start) / (24 * time.Hour))
+	case Weekly:
+		units = int(t.Sub(start) / (7 * 24 * time.Hour))
+	case Monthly:
+		units = (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+	case Yearly:
+		units = t.Year() - start.Year()
+	}
+	return units / r.Interval
+}
+
+// period returns the sorted candidate occurrences of the pth period of the series.
+func (r Rule) period(start time.Time, p int) []time.Time {
+	var days []time.Time
+
+	y, m, d := start.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+	}
+
+	switch r.Freq {
+	case Daily:
+		day := at(y, m, d+p*r.Interval)
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		monday := at(y, m, d-(int(start.Weekday())+6)%7+7*p*r.Interval)
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			if r.matchesMonth(day.Month()) && r.weeklyDay(start, day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		first := at(y, m+time.Month(p*r.Interval), 1)
+		if r.matchesMonth(first.Month()) {
+			days = r.monthDays(start, first)
+		}
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+		for _, month := range months {
+			days = append(days, r.monthDays(start, at(y+p*r.Interval, month, 1))...)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// monthDays returns the candidate occurrences within the month starting at first.
+func (r Rule) monthDays(start, first time.Time) []time.Time {
+	var days []time.Time
+
+	last := first.AddDate(0, 1, -1).Day()
+	for d := 1; d <= last; d++ {
+		day := first.AddDate(0, 0, d-1)
+
+		if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+			// Months without the day of the start are skipped.
+			if d == start.Day() {
+				days = append(days, day)
+			}
+			continue
+		}
+		if r.matchesMonthDay(day) && r.matchesNthWeekday(day, last) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r Rule) weeklyDay(start, day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return day.Weekday() == start.Weekday()
+	}
+	return r.matchesWeekday(day)
+}
+
+func (r Rule) matchesMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range r.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || (d < 0 && last+d+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNthWeekday reports whether day matches BYDAY within a month of last days.
+func (r Rule) matchesNthWeekday(day time.Time, last int) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday != day.Weekday() {
+			continue
+		}
+		switch {
+		case d.Nth == 0:
+			return true
+		case d.Nth > 0 && (day.Day()-1)/7+1 == d.Nth:
+			return true
+		case d.Nth < 0 && (last-day.Day())/7+1 == -d.Nth:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package rrule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/rrule"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		want string
+		err  string
+	}{
+		{name: "daily", rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix and case", rule: "rrule:freq=weekly;byday=mo,fr", want: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{name: "monthly ordinal", rule: "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR", want: "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR"},
+		{name: "until date", rule: "FREQ=DAILY;UNTIL=20261231", want: "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{name: "yearly", rule: "FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=-1;COUNT=5", want: "FREQ=YEARLY;COUNT=5;BYMONTH=3;BYMONTHDAY=-1"},
+		{name: "empty", rule: " ", err: "rule is empty"},
+		{name: "missing frequency", rule: "INTERVAL=2", err: "FREQ is required"},
+		{name: "unknown frequency", rule: "FREQ=HOURLY", err: "FREQ must be"},
+		{name: "unsupported part", rule: "FREQ=DAILY;BYHOUR=9", err: "BYHOUR is not supported"},
+		{name: "malformed part", rule: "FREQ=DAILY;COUNT", err: "must be NAME=VALUE"},
+		{name: "repeated part", rule: "FREQ=DAILY;FREQ=WEEKLY", err: "more than once"},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0", err: "INTERVAL must be a number"},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20261231", err: "cannot both be given"},
+		{name: "bad weekday", rule: "FREQ=WEEKLY;BYDAY=XX", err: "not a weekday"},
+		{name: "weekly ordinal", rule: "FREQ=WEEKLY;BYDAY=1MO", err: "ordinals need a monthly or yearly rule"},
+		{name: "zero month day", rule: "FREQ=MONTHLY;BYMONTHDAY=0", err: "not 0"},
+		{name: "weekly month day", rule: "FREQ=WEEKLY;BYMONTHDAY=1", err: "cannot be used in weekly rules"},
+		{name: "yearly without month", rule: "FREQ=YEARLY;BYDAY=MO", err: "need BYMONTH"},
+		{name: "bad until", rule: "FREQ=DAILY;UNTIL=tomorrow", err: "UNTIL must be"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := rrule.Parse(tc.rule)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.IsType(t, &rrule.Error{}, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, r.String())
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	// Monday, 5 January 2026 at 9:00.
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  []time.Time
+		ended bool
+	}{
+		{
+			name:  "starts with the start",
+			rule:  "FREQ=DAILY",
+			after: start.Add(-time.Second),
+			want:  []time.Time{date(2026, 1, 5), date(2026, 1, 6), date(2026, 1, 7)},
+		},
+		{
+			name:  "every other day",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			after: start,
+			want:  []time.Time{date(2026, 1, 7), date(2026, 1, 9)},
+		},
+		{
+			name:  "weekdays",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			after: date(2026, 1, 9),
+			want:  []time.Time{date(2026, 1, 12), date(2026, 1, 14), date(2026, 1, 16)},
+		},
+		{
+			name:  "every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+			after: start,
+			want:  []time.Time{date(2026, 1, 6), date(2026, 1, 20), date(2026, 2, 3)},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY",
+			after: start,
+			want:  []time.Time{date(2026, 2, 5), date(2026, 3, 5)},
+		},
+		{
+			name:  "last day of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			after: start,
+			want:  []time.Time{date(2026, 1, 31), date(2026, 2, 28), date(2026, 3, 31)},
+		},
+		{
+			name:  "first monday",
+			rule:  "FREQ=MONTHLY;BYDAY=1MO",
+			after: start,
+			want:  []time.Time{date(2026, 2, 2), date(2026, 3, 2)},
+		},
+		{
+			name:  "last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			after: start,
+			want:  []time.Time{date(2026, 1, 30), date(2026, 2, 27)},
+		},
+		{
+			name:  "quarterly",
+			rule:  "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1",
+			after: start,
+			want:  []time.Time{date(2026, 4, 1), date(2026, 7, 1)},
+		},
+		{
+			name:  "yearly in march and september",
+			rule:  "FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=15",
+			after: start,
+			want:  []time.Time{date(2026, 3, 15), date(2026, 9, 15), date(2027, 3, 15)},
+		},
+		{
+			name:  "count",
+			rule:  "FREQ=WEEKLY;COUNT=2",
+			after: start.Add(-time.Second),
+			want:  []time.Time{date(2026, 1, 5), date(2026, 1, 12)},
+			ended: true,
+		},
+		{
+			name:  "until",
+			rule:  "FREQ=DAILY;UNTIL=20260107",
+			after: start,
+			want:  []time.Time{date(2026, 1, 6), date(2026, 1, 7)},
+			ended: true,
+		},
+		{
+			name:  "long after the start",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			after: date(2030, 1, 1),
+			want:  []time.Time{date(2030, 1, 2), date(2030, 1, 5)},
+		},
+		{
+			name:  "never matches",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			after: start,
+			want:  nil,
+			ended: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := rrule.Parse(tc.rule)
+			require.NoError(t, err)
+
+			var got []time.Time
+			after := tc.after
+			for {
+				next, ok := r.Next(start, after)
+				if !ok || len(got) == len(tc.want) {
+					break
+				}
+				got = append(got, next)
+				after = next
+			}
+			assert.Equal(t, tc.want, got)
+
+			_, ok := r.Next(start, after)
+			assert.Equal(t, !tc.ended, ok)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type activityRepository interface {
+	ListByTask(ctx context.Context, tid string, page model.ActivityPage) ([]model.TaskEvent, error)
+	ListByProject(ctx context.Context, pid string, page model.ActivityPage) ([]model.TaskEvent, error)
+}
+
+// ActivityService is responsible for managing task activity business logic.
+type ActivityService struct {
+	logger *zap.Logger
+	repo   activityRepository
+}
+
+// NewActivityService returns an ActivityService.
+func NewActivityService(logger *zap.Logger, repo activityRepository) *ActivityService {
+	return &ActivityService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// ListByTask lists the activity of a task.
+func (as *ActivityService) ListByTask(ctx context.Context, taskID string, page model.ActivityPage) ([]model.TaskEvent, error) {
+	return as.repo.ListByTask(ctx, taskID, page)
+}
+
+// ListByProject lists the activity of every task in a project.
+func (as *ActivityService) ListByProject(ctx context.Context, projectID string, page model.ActivityPage) ([]model.TaskEvent, error) {
+	return as.repo.ListByProject(ctx, projectID, page)
+}
@@ -2,10 +2,10 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/tenantdb"
 
 	"go.uber.org/zap"
 )
@@ -16,46 +16,34 @@ type columnRepository interface {
 	List(ctx context.Context, pid string) ([]model.Column, error)
 	Update(ctx context.Context, cid string, uc model.UpdateColumn, now time.Time) (model.Column, error)
 	Delete(ctx context.Context, cid string) error
+	Add(ctx context.Context, pid string, ac model.AddColumn, now time.Time) (model.Column, error)
+	Remove(ctx context.Context, pid string, cid string, moveTo string, now time.Time) ([]model.Task, error)
+	Reorder(ctx context.Context, pid string, order []string, now time.Time) error
 }
 
 // ColumnService is responsible for managing column business logic.
 type ColumnService struct {
-	logger *zap.Logger
-	repo   columnRepository
+	logger   *zap.Logger
+	repo     columnRepository
+	notifier changeNotifier
 }
 
 // NewColumnService returns a new ColumnService.
-func NewColumnService(logger *zap.Logger, repo columnRepository) *ColumnService {
+func NewColumnService(logger *zap.Logger, repo columnRepository, notifier changeNotifier) *ColumnService {
 	return &ColumnService{
-		logger: logger,
-		repo:   repo,
+		logger:   logger,
+		repo:     repo,
+		notifier: notifier,
 	}
 }
 
-// CreateColumns creates new project columns.
-func (cs *ColumnService) CreateColumns(ctx context.Context, pid string, now time.Time) error {
-	titles := [4]string{"To Do", "In Progress", "Review", "Done"}
-
-	for i, title := range titles {
-		nc := model.NewColumn{
-			ProjectID:  pid,
-			Title:      title,
-			ColumnName: fmt.Sprintf(`column-%d`, i+1),
-		}
-		_, err := cs.repo.Create(ctx, nc, now)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // Create creates a new project column.
 func (cs *ColumnService) Create(ctx context.Context, nc model.NewColumn, now time.Time) (model.Column, error) {
 	column, err := cs.repo.Create(ctx, nc, now)
 	if err != nil {
 		return model.Column{}, err
 	}
+	cs.notify(ctx, column.ProjectID, column.ID, model.ActionCreated, now)
 	return column, nil
 }
 
@@ -71,10 +59,76 @@ func (cs *ColumnService) Retrieve(ctx context.Context, columnID string) (model.C
 
 // Update updates a project column.
 func (cs *ColumnService) Update(ctx context.Context, columnID string, update model.UpdateColumn, now time.Time) (model.Column, error) {
-	return cs.repo.Update(ctx, columnID, update, now)
+	column, err := cs.repo.Update(ctx, columnID, update, now)
+	if err != nil {
+		return column, err
+	}
+	cs.notify(ctx, column.ProjectID, column.ID, model.ActionUpdated, now)
+	return column, nil
 }
 
 // Delete deletes a project column.
 func (cs *ColumnService) Delete(ctx context.Context, columnID string) error {
-	return cs.repo.Delete(ctx, columnID)
+	column, err := cs.repo.Retrieve(tenantdb.WithPrimary(ctx), columnID)
+	if err != nil {
+		return err
+	}
+	if err = cs.repo.Delete(ctx, columnID); err != nil {
+		return err
+	}
+	cs.notify(ctx, column.ProjectID, column.ID, model.ActionDeleted, time.Now())
+	return nil
+}
+
+// AddColumn adds a column to the end of a project board.
+func (cs *ColumnService) AddColumn(ctx context.Context, projectID string, ac model.AddColumn, now time.Time) (model.Column, error) {
+	column, err := cs.repo.Add(ctx, projectID, ac, now)
+	if err != nil {
+		return column, err
+	}
+	cs.notify(ctx, projectID, column.ID, model.ActionCreated, now)
+	return column, nil
+}
+
+// RemoveColumn removes a column from a project board, moving its tasks or moving them
+// to the trash.
+func (cs *ColumnService) RemoveColumn(ctx context.Context, projectID string, columnID string, rc model.RemoveColumn, now time.Time) error {
+	tasks, err := cs.repo.Remove(ctx, projectID, columnID, rc.MoveTo, now)
+	if err != nil {
+		return err
+	}
+
+	action := model.ActionDeleted
+	if rc.MoveTo != "" {
+		action = model.ActionMoved
+	}
+	for _, t := range tasks {
+		cs.notifier.Notify(ctx, model.Change{
+			ProjectID: projectID,
+			Kind:      model.ChangeTask,
+			Action:    action,
+			EntityID:  t.ID,
+		}, now)
+	}
+	cs.notify(ctx, projectID, columnID, model.ActionDeleted, now)
+	return nil
+}
+
+// ReorderColumns changes the column order of a project board.
+func (cs *ColumnService) ReorderColumns(ctx context.Context, projectID string, rc model.ReorderColumns, now time.Time) error {
+	if err := cs.repo.Reorder(ctx, projectID, rc.ColumnOrder, now); err != nil {
+		return err
+	}
+	cs.notify(ctx, projectID, "", model.ActionReordered, now)
+	return nil
+}
+
+// notify pushes a column change to the users watching the board.
+func (cs *ColumnService) notify(ctx context.Context, projectID string, columnID string, action string, now time.Time) {
+	cs.notifier.Notify(ctx, model.Change{
+		ProjectID: projectID,
+		Kind:      model.ChangeColumn,
+		Action:    action,
+		EntityID:  columnID,
+	}, now)
 }
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/tenantdb"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type commentRepository interface {
+	Create(ctx context.Context, nc model.NewComment, tid string, now time.Time) (model.Comment, error)
+	Retrieve(ctx context.Context, cmid string) (model.Comment, error)
+	List(ctx context.Context, tid string) ([]model.Comment, error)
+	Update(ctx context.Context, tid, cmid string, update model.UpdateComment, now time.Time) (model.Comment, error)
+	Delete(ctx context.Context, tid, cmid string) error
+}
+
+type taskRetriever interface {
+	Retrieve(ctx context.Context, tid string) (model.Task, error)
+}
+
+// CommentService is responsible for managing comment business logic.
+type CommentService struct {
+	logger   *zap.Logger
+	repo     commentRepository
+	tasks    taskRetriever
+	notifier changeNotifier
+}
+
+// NewCommentService returns a CommentService.
+func NewCommentService(logger *zap.Logger, repo commentRepository, tasks taskRetriever, notifier changeNotifier) *CommentService {
+	return &CommentService{
+		logger:   logger,
+		repo:     repo,
+		tasks:    tasks,
+		notifier: notifier,
+	}
+}
+
+// Create creates a comment on a task.
+func (cs *CommentService) Create(ctx context.Context, nc model.NewComment, taskID string, now time.Time) (model.Comment, error) {
+	c, err := cs.repo.Create(ctx, nc, taskID, now)
+	if err != nil {
+		return c, err
+	}
+	cs.notify(ctx, c, model.ActionCreated, now)
+	return c, nil
+}
+
+// List lists the comments on a task.
+func (cs *CommentService) List(ctx context.Context, taskID string) ([]model.Comment, error) {
+	return cs.repo.List(ctx, taskID)
+}
+
+// Retrieve retrieves a comment.
+func (cs *CommentService) Retrieve(ctx context.Context, commentID string) (model.Comment, error) {
+	return cs.repo.Retrieve(ctx, commentID)
+}
+
+// Update updates a comment on a task. Anyone may like a comment, but only its author may
+// edit it.
+func (cs *CommentService) Update(ctx context.Context, taskID, commentID string, update model.UpdateComment, now time.Time) (model.Comment, error) {
+	if update.Content != nil {
+		if _, err := cs.authorize(ctx, taskID, commentID); err != nil {
+			return model.Comment{}, err
+		}
+	}
+	c, err := cs.repo.Update(ctx, taskID, commentID, update, now)
+	if err != nil {
+		return c, err
+	}
+	cs.notify(ctx, c, model.ActionUpdated, now)
+	return c, nil
+}
+
+// Delete deletes a comment on a task. Only its author may delete it.
+func (cs *CommentService) Delete(ctx context.Context, taskID, commentID string) error {
+	c, err := cs.authorize(ctx, taskID, commentID)
+	if err != nil {
+		return err
+	}
+	if err = cs.repo.Delete(ctx, taskID, commentID); err != nil {
+		return err
+	}
+	cs.notify(ctx, c, model.ActionDeleted, time.Now())
+	return nil
+}
+
+// authorize checks that the requesting user wrote the comment on the task.
+func (cs *CommentService) authorize(ctx context.Context, taskID, commentID string) (model.Comment, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Comment{}, web.CtxErr()
+	}
+
+	c, err := cs.repo.Retrieve(tenantdb.WithPrimary(ctx), commentID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	if c.TaskID != taskID {
+		return model.Comment{}, fail.ErrNotFound
+	}
+	if c.UserID != values.UserID {
+		return model.Comment{}, fail.ErrNotAuthorized
+	}
+	return c, nil
+}
+
+// notify pushes a comment change to the users watching the board of the commented task.
+// The comment is already saved, so a failure to find the task is logged.
+func (cs *CommentService) notify(ctx context.Context, c model.Comment, action string, now time.Time) {
+	t, err := cs.tasks.Retrieve(tenantdb.WithPrimary(ctx), c.TaskID)
+	if err != nil {
+		cs.logger.Error("error publishing board change", zap.String("taskID", c.TaskID), zap.Error(err))
+		return
+	}
+
+	cs.notifier.Notify(ctx, model.Change{
+		ProjectID: t.ProjectID,
+		Kind:      model.ChangeComment,
+		Action:    action,
+		EntityID:  c.ID,
+		TaskID:    c.TaskID,
+	}, now)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type dispatchRepository interface {
+	Dispatch(ctx context.Context, limit int, now time.Time) (model.Dispatched, error)
+}
+
+type notifiableTenants interface {
+	NotifiableTenants(ctx context.Context) ([]string, error)
+}
+
+// DispatchService is responsible for turning task events into notifications.
+type DispatchService struct {
+	logger    *zap.Logger
+	tenants   notifiableTenants
+	repo      dispatchRepository
+	batchSize int
+}
+
+// NewDispatchService returns a DispatchService that dispatches up to batchSize events of
+// a tenant per transaction.
+func NewDispatchService(logger *zap.Logger, tenants notifiableTenants, repo dispatchRepository, batchSize int) *DispatchService {
+	return &DispatchService{
+		logger:    logger,
+		tenants:   tenants,
+		repo:      repo,
+		batchSize: batchSize,
+	}
+}
+
+// Run dispatches the pending task events every interval until ctx is done.
+func (ds *DispatchService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := ds.Dispatch(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			ds.logger.Error("error dispatching notifications", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch turns the pending task events of every tenant into notifications. Each tenant
+// runs with its own tenant context, and a tenant that fails is logged and skipped, so it
+// cannot hold back the others. Replicas may run concurrently, since a tenant dispatched
+// by one of them is skipped by the others.
+func (ds *DispatchService) Dispatch(ctx context.Context, now time.Time) (model.Dispatched, error) {
+	var total model.Dispatched
+
+	tenants, err := ds.tenants.NotifiableTenants(ctx)
+	if err != nil {
+		return total, err
+	}
+
+	for _, tenantID := range tenants {
+		tenantCtx := web.NewContext(ctx, &web.Values{TenantID: tenantID})
+
+		for {
+			if ctx.Err() != nil {
+				return total, ctx.Err()
+			}
+
+			d, err := ds.repo.Dispatch(tenantCtx, ds.batchSize, now)
+			if err != nil {
+				ds.logger.Error("error dispatching notifications", zap.String("tenantID", tenantID), zap.Error(err))
+				break
+			}
+			total.Events += d.Events
+			total.Notifications += d.Notifications
+
+			if d.Events < ds.batchSize {
+				break
+			}
+		}
+	}
+
+	if total.Notifications > 0 {
+		ds.logger.Info("dispatched notifications", zap.Int("events", total.Events), zap.Int("notifications", total.Notifications))
+	}
+
+	return total, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type fieldRepository interface {
+	List(ctx context.Context, pid string) ([]model.CustomField, error)
+	Create(ctx context.Context, pid string, nf model.NewCustomField, now time.Time) (model.CustomField, error)
+	Update(ctx context.Context, pid string, cfid string, update model.UpdateCustomField, now time.Time) (model.CustomField, error)
+	Delete(ctx context.Context, pid string, cfid string) error
+}
+
+// FieldService is responsible for managing custom field business logic.
+type FieldService struct {
+	logger *zap.Logger
+	repo   fieldRepository
+}
+
+// NewFieldService returns a FieldService.
+func NewFieldService(logger *zap.Logger, repo fieldRepository) *FieldService {
+	return &FieldService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// List lists the custom fields of a project.
+func (fs *FieldService) List(ctx context.Context, projectID string) ([]model.CustomField, error) {
+	return fs.repo.List(ctx, projectID)
+}
+
+// Create creates a project custom field.
+func (fs *FieldService) Create(ctx context.Context, projectID string, nf model.NewCustomField, now time.Time) (model.CustomField, error) {
+	return fs.repo.Create(ctx, projectID, nf, now)
+}
+
+// Update updates a custom field.
+func (fs *FieldService) Update(ctx context.Context, projectID string, fieldID string, update model.UpdateCustomField, now time.Time) (model.CustomField, error) {
+	return fs.repo.Update(ctx, projectID, fieldID, update, now)
+}
+
+// Delete deletes a custom field along with its values on the project tasks.
+func (fs *FieldService) Delete(ctx context.Context, projectID string, fieldID string) error {
+	return fs.repo.Delete(ctx, projectID, fieldID)
+}
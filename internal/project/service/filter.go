@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/query"
+
+	"go.uber.org/zap"
+)
+
+type filterRepository interface {
+	Retrieve(ctx context.Context, fid string) (model.SavedFilter, error)
+	List(ctx context.Context, pid string) ([]model.SavedFilter, error)
+	Create(ctx context.Context, nf model.NewSavedFilter, now time.Time) (model.SavedFilter, error)
+	Update(ctx context.Context, fid string, update model.UpdateSavedFilter, now time.Time) (model.SavedFilter, error)
+	Delete(ctx context.Context, fid string) error
+}
+
+// FilterService is responsible for managing saved task filter business logic.
+type FilterService struct {
+	logger *zap.Logger
+	repo   filterRepository
+}
+
+// NewFilterService returns a FilterService.
+func NewFilterService(logger *zap.Logger, repo filterRepository) *FilterService {
+	return &FilterService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Retrieve retrieves a saved filter.
+func (fs *FilterService) Retrieve(ctx context.Context, filterID string) (model.SavedFilter, error) {
+	return fs.repo.Retrieve(ctx, filterID)
+}
+
+// List lists the saved filters, or those of a project when one is given.
+func (fs *FilterService) List(ctx context.Context, projectID string) ([]model.SavedFilter, error) {
+	return fs.repo.List(ctx, projectID)
+}
+
+// Create creates a saved filter. Queries that cannot be parsed return a *query.Error.
+func (fs *FilterService) Create(ctx context.Context, nf model.NewSavedFilter, now time.Time) (model.SavedFilter, error) {
+	if _, err := query.Parse(nf.Query); err != nil {
+		return model.SavedFilter{}, err
+	}
+	return fs.repo.Create(ctx, nf, now)
+}
+
+// Update updates a saved filter. Queries that cannot be parsed return a *query.Error.
+func (fs *FilterService) Update(ctx context.Context, filterID string, update model.UpdateSavedFilter, now time.Time) (model.SavedFilter, error) {
+	if update.Query != nil {
+		if _, err := query.Parse(*update.Query); err != nil {
+			return model.SavedFilter{}, err
+		}
+	}
+	return fs.repo.Update(ctx, filterID, update, now)
+}
+
+// Delete deletes a saved filter.
+func (fs *FilterService) Delete(ctx context.Context, filterID string) error {
+	return fs.repo.Delete(ctx, filterID)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type labelRepository interface {
+	Retrieve(ctx context.Context, lid string) (model.Label, error)
+	List(ctx context.Context, pid string) ([]model.Label, error)
+	Create(ctx context.Context, pid string, nl model.NewLabel, now time.Time) (model.Label, error)
+	Update(ctx context.Context, lid string, update model.UpdateLabel, now time.Time) (model.Label, error)
+	Delete(ctx context.Context, lid string) error
+}
+
+// LabelService is responsible for managing label business logic.
+type LabelService struct {
+	logger *zap.Logger
+	repo   labelRepository
+}
+
+// NewLabelService returns a LabelService.
+func NewLabelService(logger *zap.Logger, repo labelRepository) *LabelService {
+	return &LabelService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// List lists the labels of a project.
+func (ls *LabelService) List(ctx context.Context, projectID string) ([]model.Label, error) {
+	return ls.repo.List(ctx, projectID)
+}
+
+// Create creates a project label.
+func (ls *LabelService) Create(ctx context.Context, projectID string, nl model.NewLabel, now time.Time) (model.Label, error) {
+	return ls.repo.Create(ctx, projectID, nl, now)
+}
+
+// Update updates a label.
+func (ls *LabelService) Update(ctx context.Context, labelID string, update model.UpdateLabel, now time.Time) (model.Label, error) {
+	return ls.repo.Update(ctx, labelID, update, now)
+}
+
+// Delete deletes a label from the project and its tasks.
+func (ls *LabelService) Delete(ctx context.Context, labelID string) error {
+	return ls.repo.Delete(ctx, labelID)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/msg"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type publisher interface {
+	Publish(subject string, message []byte)
+}
+
+type memberRepository interface {
+	List(ctx context.Context, pid string) ([]model.ProjectMember, error)
+	Set(ctx context.Context, pid string, uid string, role string, now time.Time) (model.ProjectMember, error)
+	Remove(ctx context.Context, pid string, uid string) error
+	AssignTeam(ctx context.Context, pid string, teamID string, now time.Time) (model.Project, error)
+	Membership(ctx context.Context, pid string) (model.Membership, error)
+}
+
+// MemberService is responsible for managing project members and the teams assigned to
+// projects.
+type MemberService struct {
+	logger *zap.Logger
+	js     publisher
+	repo   memberRepository
+}
+
+// NewMemberService returns a MemberService.
+func NewMemberService(logger *zap.Logger, js publisher, repo memberRepository) *MemberService {
+	return &MemberService{
+		logger: logger,
+		js:     js,
+		repo:   repo,
+	}
+}
+
+// List lists the members of a project.
+func (ms *MemberService) List(ctx context.Context, projectID string) ([]model.ProjectMember, error) {
+	return ms.repo.List(ctx, projectID)
+}
+
+// Add adds a user to a project.
+func (ms *MemberService) Add(ctx context.Context, projectID string, nm model.NewProjectMember, now time.Time) (model.ProjectMember, error) {
+	m, err := ms.repo.Set(ctx, projectID, nm.UserID, nm.Role, now)
+	if err != nil {
+		return m, err
+	}
+	publishMembership(ctx, ms.logger, ms.js, ms.repo, projectID, now)
+	return m, nil
+}
+
+// Update changes the role of a project member.
+func (ms *MemberService) Update(ctx context.Context, projectID string, userID string, um model.UpdateProjectMember, now time.Time) (model.ProjectMember, error) {
+	m, err := ms.repo.Set(ctx, projectID, userID, um.Role, now)
+	if err != nil {
+		return m, err
+	}
+	publishMembership(ctx, ms.logger, ms.js, ms.repo, projectID, now)
+	return m, nil
+}
+
+// Remove removes a user from a project.
+func (ms *MemberService) Remove(ctx context.Context, projectID string, userID string, now time.Time) error {
+	if err := ms.repo.Remove(ctx, projectID, userID); err != nil {
+		return err
+	}
+	publishMembership(ctx, ms.logger, ms.js, ms.repo, projectID, now)
+	return nil
+}
+
+// AssignTeam assigns a team to a project.
+func (ms *MemberService) AssignTeam(ctx context.Context, projectID string, at model.AssignTeam, now time.Time) (model.Project, error) {
+	p, err := ms.repo.AssignTeam(ctx, projectID, at.TeamID, now)
+	if err != nil {
+		return p, err
+	}
+	publishMembership(ctx, ms.logger, ms.js, ms.repo, projectID, now)
+	return p, nil
+}
+
+type membershipRepository interface {
+	Membership(ctx context.Context, pid string) (model.Membership, error)
+}
+
+// publishMembership publishes who has access to a project after its members or its team
+// changed. The change is already committed, so a failure is logged rather than returned.
+func publishMembership(ctx context.Context, logger *zap.Logger, js publisher, repo membershipRepository, projectID string, now time.Time) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		logger.Error("error publishing project membership", zap.Error(web.CtxErr()))
+		return
+	}
+
+	ms, err := repo.Membership(ctx, projectID)
+	if err != nil {
+		logger.Error("error publishing project membership", zap.String("projectID", projectID), zap.Error(err))
+		return
+	}
+
+	event := newProjectTeamAssignedEvent(values, ms, now)
+	bytes, err := event.Marshal()
+	if err != nil {
+		logger.Error("error publishing project membership", zap.String("projectID", projectID), zap.Error(err))
+		return
+	}
+
+	js.Publish(msg.SubjectProjectTeamAssigned, bytes)
+}
+
+func newProjectTeamAssignedEvent(values *web.Values, ms model.Membership, now time.Time) msg.ProjectTeamAssignedEvent {
+	members := make([]msg.ProjectMemberRecord, 0, len(ms.Members)+len(ms.TeamMembers))
+
+	direct := make(map[string]bool, len(ms.Members))
+	for _, m := range ms.Members {
+		direct[m.UserID] = true
+		members = append(members, msg.ProjectMemberRecord{UserID: m.UserID, Role: m.Role})
+	}
+	// Team members edit the project unless they were given a role of their own.
+	for _, uid := range ms.TeamMembers {
+		if !direct[uid] {
+			members = append(members, msg.ProjectMemberRecord{UserID: uid, Role: model.RoleEditor})
+		}
+	}
+
+	return msg.ProjectTeamAssignedEvent{
+		Metadata: msg.Metadata{
+			TraceID:  values.TraceID,
+			UserID:   values.UserID,
+			TenantID: values.TenantID,
+		},
+		Type: msg.TypeProjectTeamAssigned,
+		Data: msg.ProjectTeamAssignedEventData{
+			ProjectID: ms.ProjectID,
+			TeamID:    ms.TeamID,
+			Members:   members,
+			UpdatedAt: now.UTC().String(),
+		},
+	}
+}
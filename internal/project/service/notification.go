@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type notificationRepository interface {
+	List(ctx context.Context, page model.InboxPage) (model.Inbox, error)
+	Update(ctx context.Context, nid string, update model.UpdateNotification, now time.Time) (model.Notification, error)
+	ReadAll(ctx context.Context, now time.Time) (int, error)
+	Preferences(ctx context.Context) (model.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, update model.UpdateNotificationPreferences, now time.Time) (model.NotificationPreferences, error)
+}
+
+type watcherRepository interface {
+	List(ctx context.Context, tid string) ([]model.Watcher, error)
+	Watch(ctx context.Context, tid string, now time.Time) (model.Watcher, error)
+	Unwatch(ctx context.Context, tid string) error
+}
+
+// NotificationService is responsible for managing the notifications of users and the
+// watchers of tasks.
+type NotificationService struct {
+	logger   *zap.Logger
+	repo     notificationRepository
+	watchers watcherRepository
+}
+
+// NewNotificationService returns a NotificationService.
+func NewNotificationService(logger *zap.Logger, repo notificationRepository, watchers watcherRepository) *NotificationService {
+	return &NotificationService{
+		logger:   logger,
+		repo:     repo,
+		watchers: watchers,
+	}
+}
+
+// List lists a page of the notifications of the user.
+func (ns *NotificationService) List(ctx context.Context, page model.InboxPage) (model.Inbox, error) {
+	return ns.repo.List(ctx, page)
+}
+
+// Update marks a notification of the user as read or unread.
+func (ns *NotificationService) Update(ctx context.Context, notificationID string, update model.UpdateNotification, now time.Time) (model.Notification, error) {
+	return ns.repo.Update(ctx, notificationID, update, now)
+}
+
+// ReadAll marks every notification of the user as read.
+func (ns *NotificationService) ReadAll(ctx context.Context, now time.Time) (int, error) {
+	return ns.repo.ReadAll(ctx, now)
+}
+
+// Preferences retrieves the notification preferences of the user.
+func (ns *NotificationService) Preferences(ctx context.Context) (model.NotificationPreferences, error) {
+	return ns.repo.Preferences(ctx)
+}
+
+// UpdatePreferences updates the notification preferences of the user.
+func (ns *NotificationService) UpdatePreferences(ctx context.Context, update model.UpdateNotificationPreferences, now time.Time) (model.NotificationPreferences, error) {
+	return ns.repo.UpdatePreferences(ctx, update, now)
+}
+
+// Watchers lists the watchers of a task.
+func (ns *NotificationService) Watchers(ctx context.Context, taskID string) ([]model.Watcher, error) {
+	return ns.watchers.List(ctx, taskID)
+}
+
+// Watch makes the user watch a task.
+func (ns *NotificationService) Watch(ctx context.Context, taskID string, now time.Time) (model.Watcher, error) {
+	return ns.watchers.Watch(ctx, taskID, now)
+}
+
+// Unwatch stops the user from watching a task.
+func (ns *NotificationService) Unwatch(ctx context.Context, taskID string) error {
+	return ns.watchers.Unwatch(ctx, taskID)
+}
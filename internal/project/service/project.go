@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/devpies/saas-core/internal/project/model"
@@ -15,36 +16,69 @@ import (
 type projectRepository interface {
 	RunTx(ctx context.Context, fn func(*sqlx.Tx) error) error
 	Retrieve(ctx context.Context, pid string) (model.Project, error)
-	List(ctx context.Context) ([]model.Project, error)
+	List(ctx context.Context, archived bool) ([]model.Project, error)
 	Create(ctx context.Context, np model.NewProject, now time.Time) (model.Project, error)
 	Update(ctx context.Context, pid string, update model.UpdateProject, now time.Time) (model.Project, error)
-	Delete(ctx context.Context, pid string) error
+	Delete(ctx context.Context, pid string, now time.Time) error
+	Restore(ctx context.Context, pid string, now time.Time) (model.Project, error)
+	Trash(ctx context.Context) (model.Trash, error)
 }
 
 // ProjectService is responsible for managing project related business logic.
 type ProjectService struct {
 	logger      *zap.Logger
 	projectRepo projectRepository
+	fanOut      FanOut
 }
 
-// NewProjectService returns a new ProjectService.
-func NewProjectService(logger *zap.Logger, projectRepo projectRepository) *ProjectService {
+// NewProjectService returns a new ProjectService that lists projects across tenants
+// within the bounds of fanOut.
+func NewProjectService(logger *zap.Logger, projectRepo projectRepository, fanOut FanOut) *ProjectService {
 	return &ProjectService{
 		logger:      logger,
 		projectRepo: projectRepo,
+		fanOut:      fanOut,
 	}
 }
 
-// List retrieves projects across tenant accounts for the authenticated user.
-func (ps *ProjectService) List(ctx context.Context, all bool) ([]model.Project, error) {
+// List retrieves the projects of the tenant, or of every tenant of the authenticated
+// user when all is set. Archived projects are included when archived is set. Projects
+// listed across tenants are sorted by name, and the tenants that fail are reported
+// instead of failing the list.
+func (ps *ProjectService) List(ctx context.Context, all bool, archived bool) (model.ProjectList, error) {
+	list := model.ProjectList{Failures: make([]model.TenantFailure, 0)}
+
 	values, ok := web.FromContext(ctx)
 	if !ok {
-		return nil, web.CtxErr()
+		return list, web.CtxErr()
 	}
-	if all {
-		return forEachT(ctx, values.TenantMap, ps.projectRepo.List)
+
+	if !all {
+		projects, err := ps.projectRepo.List(ctx, archived)
+		if err != nil {
+			return list, err
+		}
+		list.Projects = projects
+		return list, nil
 	}
-	return ps.projectRepo.List(ctx)
+
+	projects, failures, err := forEachT(ctx, ps.logger, values.TenantMap, ps.fanOut, func(ctx context.Context) ([]model.Project, error) {
+		return ps.projectRepo.List(ctx, archived)
+	})
+	if err != nil {
+		return list, err
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return projects[i].ID < projects[j].ID
+	})
+
+	list.Projects = projects
+	list.Failures = failures
+	return list, nil
 }
 
 // Retrieve retrieves an owned project.
@@ -62,7 +96,17 @@ func (ps *ProjectService) Update(ctx context.Context, projectID string, update m
 	return ps.projectRepo.Update(ctx, projectID, update, now)
 }
 
-// Delete deletes a project.
-func (ps *ProjectService) Delete(ctx context.Context, projectID string) error {
-	return ps.projectRepo.Delete(ctx, projectID)
+// Delete moves a project to the trash.
+func (ps *ProjectService) Delete(ctx context.Context, projectID string, now time.Time) error {
+	return ps.projectRepo.Delete(ctx, projectID, now)
+}
+
+// Restore takes a project out of the trash.
+func (ps *ProjectService) Restore(ctx context.Context, projectID string, now time.Time) (model.Project, error) {
+	return ps.projectRepo.Restore(ctx, projectID, now)
+}
+
+// Trash lists the projects and tasks in the trash.
+func (ps *ProjectService) Trash(ctx context.Context) (model.Trash, error) {
+	return ps.projectRepo.Trash(ctx)
 }
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type purgeRepository interface {
+	Purge(ctx context.Context, before time.Time) (model.Purged, error)
+}
+
+type trashedTenants interface {
+	TrashedTenants(ctx context.Context, before time.Time) ([]string, error)
+}
+
+// PurgeService is responsible for permanently deleting projects and tasks that stayed
+// in the trash longer than the retention period.
+type PurgeService struct {
+	logger    *zap.Logger
+	tenants   trashedTenants
+	repo      purgeRepository
+	retention time.Duration
+}
+
+// NewPurgeService returns a PurgeService.
+func NewPurgeService(logger *zap.Logger, tenants trashedTenants, repo purgeRepository, retention time.Duration) *PurgeService {
+	return &PurgeService{
+		logger:    logger,
+		tenants:   tenants,
+		repo:      repo,
+		retention: retention,
+	}
+}
+
+// Run purges the trash every interval until ctx is done.
+func (ps *PurgeService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := ps.Purge(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			ps.logger.Error("error purging trash", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge permanently deletes what every tenant moved to the trash before the retention
+// period. A tenant that fails is logged and skipped, so it cannot hold back the others.
+func (ps *PurgeService) Purge(ctx context.Context, now time.Time) (model.Purged, error) {
+	var total model.Purged
+
+	before := now.Add(-ps.retention)
+
+	tenants, err := ps.tenants.TrashedTenants(ctx, before)
+	if err != nil {
+		return total, err
+	}
+
+	for _, tenantID := range tenants {
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+
+		purged, err := ps.repo.Purge(web.NewContext(ctx, &web.Values{TenantID: tenantID}), before)
+		if err != nil {
+			ps.logger.Error("error purging tenant trash", zap.String("tenantID", tenantID), zap.Error(err))
+			continue
+		}
+
+		total.Projects += purged.Projects
+		total.Tasks += purged.Tasks
+	}
+
+	if total.Projects > 0 || total.Tasks > 0 {
+		ps.logger.Info("purged trash", zap.Int("projects", total.Projects), zap.Int("tasks", total.Tasks))
+	}
+
+	return total, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type recurrenceRepository interface {
+	List(ctx context.Context, pid string) ([]model.RecurringTask, error)
+	Create(ctx context.Context, pid string, nr model.NewRecurringTask, now time.Time) (model.RecurringTask, error)
+	Update(ctx context.Context, rid string, update model.UpdateRecurringTask, now time.Time) (model.RecurringTask, error)
+	Delete(ctx context.Context, rid string) error
+}
+
+// RecurrenceService is responsible for managing recurring task business logic.
+type RecurrenceService struct {
+	logger *zap.Logger
+	repo   recurrenceRepository
+}
+
+// NewRecurrenceService returns a RecurrenceService.
+func NewRecurrenceService(logger *zap.Logger, repo recurrenceRepository) *RecurrenceService {
+	return &RecurrenceService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// List lists the recurring tasks of a project.
+func (rs *RecurrenceService) List(ctx context.Context, projectID string) ([]model.RecurringTask, error) {
+	return rs.repo.List(ctx, projectID)
+}
+
+// Create creates a recurring task. Rules that cannot be parsed return an *rrule.Error.
+func (rs *RecurrenceService) Create(ctx context.Context, projectID string, nr model.NewRecurringTask, now time.Time) (model.RecurringTask, error) {
+	return rs.repo.Create(ctx, projectID, nr, now)
+}
+
+// Update updates a recurring task. Rules that cannot be parsed return an *rrule.Error.
+func (rs *RecurrenceService) Update(ctx context.Context, recurrenceID string, update model.UpdateRecurringTask, now time.Time) (model.RecurringTask, error) {
+	return rs.repo.Update(ctx, recurrenceID, update, now)
+}
+
+// Delete deletes a recurring task.
+func (rs *RecurrenceService) Delete(ctx context.Context, recurrenceID string) error {
+	return rs.repo.Delete(ctx, recurrenceID)
+}
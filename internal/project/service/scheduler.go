@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type scheduleRepository interface {
+	Due(ctx context.Context, now time.Time) ([]string, error)
+	Run(ctx context.Context, rid string, now time.Time) (model.RecurrenceRun, error)
+}
+
+type scheduledTenants interface {
+	ScheduledTenants(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// SchedulerService is responsible for creating the tasks of recurring tasks when they
+// are due.
+type SchedulerService struct {
+	logger   *zap.Logger
+	tenants  scheduledTenants
+	repo     scheduleRepository
+	notifier changeNotifier
+}
+
+// NewSchedulerService returns a SchedulerService.
+func NewSchedulerService(logger *zap.Logger, tenants scheduledTenants, repo scheduleRepository, notifier changeNotifier) *SchedulerService {
+	return &SchedulerService{
+		logger:   logger,
+		tenants:  tenants,
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+// Run runs the due recurring tasks every interval until ctx is done.
+func (ss *SchedulerService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := ss.RunDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			ss.logger.Error("error running recurring tasks", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue creates a task for every recurring task of every tenant that is due at the
+// given time, and returns how many it created. Each tenant runs with its own tenant
+// context, and a tenant or recurring task that fails is logged and skipped, so it cannot
+// hold back the others. Replicas may run concurrently, since a recurring task run by
+// one of them is skipped by the others.
+func (ss *SchedulerService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	var created int
+
+	tenants, err := ss.tenants.ScheduledTenants(ctx, now)
+	if err != nil {
+		return created, err
+	}
+
+	for _, tenantID := range tenants {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+
+		tenantCtx := web.NewContext(ctx, &web.Values{TenantID: tenantID})
+
+		due, err := ss.repo.Due(tenantCtx, now)
+		if err != nil {
+			ss.logger.Error("error listing due recurring tasks", zap.String("tenantID", tenantID), zap.Error(err))
+			continue
+		}
+
+		for _, rid := range due {
+			run, err := ss.repo.Run(tenantCtx, rid, now)
+			if err != nil {
+				if err != fail.ErrNotFound {
+					ss.logger.Error("error running recurring task", zap.String("tenantID", tenantID), zap.String("recurrenceID", rid), zap.Error(err))
+				}
+				continue
+			}
+			if run.Missed > 0 {
+				ss.logger.Info("skipped missed occurrences of recurring task", zap.String("recurrenceID", rid), zap.Int("missed", run.Missed))
+			}
+			if run.Task == nil {
+				continue
+			}
+
+			created++
+			// The change is attributed to the creator of the recurring task.
+			userCtx := web.NewContext(ctx, &web.Values{TenantID: tenantID, UserID: run.Task.UserID})
+			ss.notifier.Notify(userCtx, model.Change{
+				ProjectID: run.Task.ProjectID,
+				Kind:      model.ChangeTask,
+				Action:    model.ActionCreated,
+				EntityID:  run.Task.ID,
+			}, now)
+		}
+	}
+
+	if created > 0 {
+		ss.logger.Info("created recurring tasks", zap.Int("tasks", created))
+	}
+
+	return created, nil
+}
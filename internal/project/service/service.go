@@ -2,24 +2,112 @@ package service
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/devpies/saas-core/internal/project/model"
 	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+// FanOut bounds the work spread across the tenants of the user. Concurrency is the
+// number of tenants queried at once and Timeout the time given to each of them.
+type FanOut struct {
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// Tenant failure messages. The underlying errors are logged rather than returned, since
+// they may describe the database of a tenant.
+const (
+	tenantTimedOut    = "timed out"
+	tenantUnavailable = "unavailable"
 )
 
+// forEachT runs the handler once per tenant of the map, at most fo.Concurrency at a
+// time, and merges the results in no particular order. Each run acts for the requesting
+// user within the tenant and is cancelled after fo.Timeout. A tenant that fails is left
+// out of the results and reported among the failures, ordered by tenant, so it cannot
+// fail the others.
 func forEachT[T any](
 	ctx context.Context,
+	logger *zap.Logger,
 	tmap web.TenantConnectionMap,
+	fo FanOut,
 	handler func(ctx context.Context) ([]T, error),
-) ([]T, error) {
-	var results []T
+) ([]T, []model.TenantFailure, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		results  = make([]T, 0)
+		failures = make([]model.TenantFailure, 0)
+	)
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return nil, nil, web.CtxErr()
+	}
+
+	concurrency := fo.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
 
+	// No more tenants are started once the request went away.
+launch:
 	for _, v := range tmap {
-		ctx = web.NewContext(ctx, &web.Values{TenantID: v.TenantID})
-		result, err := handler(ctx)
-		if err != nil {
-			return nil, err
+		tenantID, company := v.TenantID, v.CompanyName
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break launch
 		}
-		results = append(results, result...)
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			tctx := web.NewContext(ctx, &web.Values{TraceID: values.TraceID, UserID: values.UserID, TenantID: tenantID})
+			if fo.Timeout > 0 {
+				var cancel context.CancelFunc
+				tctx, cancel = context.WithTimeout(tctx, fo.Timeout)
+				defer cancel()
+			}
+
+			result, err := handler(tctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				logger.Error("error querying tenant", zap.String("tenantID", tenantID), zap.String("traceID", values.TraceID), zap.Error(err))
+
+				msg := tenantUnavailable
+				if errors.Is(err, context.DeadlineExceeded) || errors.Is(tctx.Err(), context.DeadlineExceeded) {
+					msg = tenantTimedOut
+				}
+				failures = append(failures, model.TenantFailure{TenantID: tenantID, CompanyName: company, Error: msg})
+				return
+			}
+			results = append(results, result...)
+		}()
+	}
+	wg.Wait()
+
+	// A request that went away fails as a whole rather than as every one of its tenants.
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
 	}
-	return results, nil
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].TenantID < failures[j].TenantID
+	})
+	return results, failures, nil
 }
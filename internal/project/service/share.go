@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type shareRepository interface {
+	Create(ctx context.Context, pid string, tokenHash string, expiresAt *time.Time, now time.Time) (model.ShareLink, error)
+	List(ctx context.Context, pid string) ([]model.ShareLink, error)
+	Revoke(ctx context.Context, pid string, shid string, now time.Time) (model.ShareLink, error)
+	Board(ctx context.Context, tokenHash string, now time.Time) (model.SharedBoard, error)
+}
+
+// ShareService is responsible for the links sharing project boards outside the tenant.
+// Only a hash of a share token is stored.
+type ShareService struct {
+	logger *zap.Logger
+	repo   shareRepository
+}
+
+// NewShareService returns a ShareService.
+func NewShareService(logger *zap.Logger, repo shareRepository) *ShareService {
+	return &ShareService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Create creates a share link of a project. The token is only returned here.
+func (ss *ShareService) Create(ctx context.Context, projectID string, ns model.NewShareLink, now time.Time) (model.ShareLink, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.ShareLink{}, web.CtxErr()
+	}
+
+	if ns.ExpiresAt != nil && !ns.ExpiresAt.After(now) {
+		return model.ShareLink{}, fail.ErrInvalidExpiry
+	}
+
+	token, err := model.NewShareToken(values.TenantID)
+	if err != nil {
+		return model.ShareLink{}, err
+	}
+
+	s, err := ss.repo.Create(ctx, projectID, hashShareToken(token), ns.ExpiresAt, now)
+	if err != nil {
+		return s, err
+	}
+	s.Token = token
+	return s, nil
+}
+
+// List lists the share links of a project.
+func (ss *ShareService) List(ctx context.Context, projectID string) ([]model.ShareLink, error) {
+	return ss.repo.List(ctx, projectID)
+}
+
+// Revoke revokes a share link of a project.
+func (ss *ShareService) Revoke(ctx context.Context, projectID string, shareID string, now time.Time) (model.ShareLink, error) {
+	return ss.repo.Revoke(ctx, projectID, shareID, now)
+}
+
+// Board retrieves the board shared by a token. The request has no tenant, so it takes
+// the tenant of the token.
+func (ss *ShareService) Board(ctx context.Context, token string, now time.Time) (model.SharedBoard, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.SharedBoard{}, web.CtxErr()
+	}
+
+	tenantID, ok := model.ShareTokenTenant(token)
+	if !ok {
+		return model.SharedBoard{}, fail.ErrNotFound
+	}
+
+	ctx = web.NewContext(ctx, &web.Values{
+		TraceID:  values.TraceID,
+		Start:    values.Start,
+		TenantID: tenantID,
+	})
+
+	return ss.repo.Board(ctx, hashShareToken(token), now)
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type sprintRepository interface {
+	Retrieve(ctx context.Context, pid string, sid string) (model.Sprint, error)
+	List(ctx context.Context, pid string) ([]model.Sprint, error)
+	Create(ctx context.Context, pid string, ns model.NewSprint, now time.Time) (model.Sprint, error)
+	Start(ctx context.Context, pid string, sid string, ss model.StartSprint, now time.Time) (model.Sprint, error)
+	Complete(ctx context.Context, pid string, sid string, cs model.CompleteSprint, now time.Time) (model.Sprint, error)
+	AddTasks(ctx context.Context, pid string, sid string, st model.SprintTasks, now time.Time) (model.Sprint, error)
+	RemoveTask(ctx context.Context, pid string, sid string, tid string, now time.Time) error
+	Burndown(ctx context.Context, pid string, sid string, now time.Time) (model.Burndown, error)
+}
+
+// SprintService is responsible for managing sprint business logic.
+type SprintService struct {
+	logger *zap.Logger
+	repo   sprintRepository
+}
+
+// NewSprintService returns a SprintService.
+func NewSprintService(logger *zap.Logger, repo sprintRepository) *SprintService {
+	return &SprintService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Retrieve retrieves a sprint.
+func (ss *SprintService) Retrieve(ctx context.Context, projectID string, sprintID string) (model.Sprint, error) {
+	return ss.repo.Retrieve(ctx, projectID, sprintID)
+}
+
+// List lists the sprints of a project.
+func (ss *SprintService) List(ctx context.Context, projectID string) ([]model.Sprint, error) {
+	return ss.repo.List(ctx, projectID)
+}
+
+// Create plans a sprint.
+func (ss *SprintService) Create(ctx context.Context, projectID string, ns model.NewSprint, now time.Time) (model.Sprint, error) {
+	return ss.repo.Create(ctx, projectID, ns, now)
+}
+
+// Start starts a planned sprint.
+func (ss *SprintService) Start(ctx context.Context, projectID string, sprintID string, start model.StartSprint, now time.Time) (model.Sprint, error) {
+	return ss.repo.Start(ctx, projectID, sprintID, start, now)
+}
+
+// Complete completes an active sprint and carries over its unfinished tasks.
+func (ss *SprintService) Complete(ctx context.Context, projectID string, sprintID string, cs model.CompleteSprint, now time.Time) (model.Sprint, error) {
+	return ss.repo.Complete(ctx, projectID, sprintID, cs, now)
+}
+
+// AddTasks adds tasks to a sprint.
+func (ss *SprintService) AddTasks(ctx context.Context, projectID string, sprintID string, st model.SprintTasks, now time.Time) (model.Sprint, error) {
+	return ss.repo.AddTasks(ctx, projectID, sprintID, st, now)
+}
+
+// RemoveTask moves a sprint task back to the backlog.
+func (ss *SprintService) RemoveTask(ctx context.Context, projectID string, sprintID string, taskID string, now time.Time) error {
+	return ss.repo.RemoveTask(ctx, projectID, sprintID, taskID, now)
+}
+
+// Burndown returns the daily burndown of a sprint.
+func (ss *SprintService) Burndown(ctx context.Context, projectID string, sprintID string, now time.Time) (model.Burndown, error) {
+	return ss.repo.Burndown(ctx, projectID, sprintID, now)
+}
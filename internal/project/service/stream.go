@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/stream"
+	"github.com/devpies/saas-core/pkg/msg"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type changeNotifier interface {
+	Notify(ctx context.Context, c model.Change, now time.Time)
+}
+
+type projectRetriever interface {
+	Retrieve(ctx context.Context, pid string) (model.Project, error)
+}
+
+// StreamService is responsible for pushing board changes to the users watching a board.
+// Changes are published to NATS so that every replica can push them to its own clients.
+type StreamService struct {
+	logger   *zap.Logger
+	js       publisher
+	hub      *stream.Hub
+	projects projectRetriever
+}
+
+// NewStreamService returns a StreamService.
+func NewStreamService(logger *zap.Logger, js publisher, hub *stream.Hub, projects projectRetriever) *StreamService {
+	return &StreamService{
+		logger:   logger,
+		js:       js,
+		hub:      hub,
+		projects: projects,
+	}
+}
+
+// Subscribe subscribes to the changes of a project board made after the change with the
+// given id. The user must be able to see the project.
+func (ss *StreamService) Subscribe(ctx context.Context, projectID string, lastEventID string) (*stream.Subscription, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return nil, web.CtxErr()
+	}
+
+	if _, err := ss.projects.Retrieve(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return ss.hub.Subscribe(values.TenantID, projectID, lastEventID), nil
+}
+
+// Notify publishes a board change. The change is already committed, so a failure is
+// logged rather than returned.
+func (ss *StreamService) Notify(ctx context.Context, c model.Change, now time.Time) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		ss.logger.Error("error publishing board change", zap.Error(web.CtxErr()))
+		return
+	}
+
+	event := msg.ProjectChangedEvent{
+		Metadata: msg.Metadata{
+			TraceID:  values.TraceID,
+			UserID:   values.UserID,
+			TenantID: values.TenantID,
+		},
+		Type: msg.TypeProjectChanged,
+		Data: msg.ProjectChangedEventData{
+			ID:         uuid.New().String(),
+			ProjectID:  c.ProjectID,
+			Kind:       c.Kind,
+			Action:     c.Action,
+			EntityID:   c.EntityID,
+			TaskID:     c.TaskID,
+			OccurredAt: now.UTC().Format(time.RFC3339Nano),
+		},
+	}
+
+	bytes, err := event.Marshal()
+	if err != nil {
+		ss.logger.Error("error publishing board change", zap.String("projectID", c.ProjectID), zap.Error(err))
+		return
+	}
+
+	ss.js.Publish(msg.SubjectProjectChanged, bytes)
+}
+
+// BroadcastFromEvent pushes a board change from a message to the clients of this replica.
+func (ss *StreamService) BroadcastFromEvent(ctx context.Context, message interface{}) error {
+	m, err := msg.Bytes(message)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	event, err := msg.UnmarshalProjectChangedEvent(m)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339Nano, event.Data.OccurredAt)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	ss.hub.Broadcast(model.Change{
+		ID:         event.Data.ID,
+		TenantID:   event.Metadata.TenantID,
+		ProjectID:  event.Data.ProjectID,
+		Kind:       event.Data.Kind,
+		Action:     event.Data.Action,
+		EntityID:   event.Data.EntityID,
+		TaskID:     event.Data.TaskID,
+		UserID:     event.Metadata.UserID,
+		OccurredAt: occurredAt,
+	})
+	return nil
+}
@@ -2,43 +2,67 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/query"
+	"github.com/devpies/saas-core/internal/tenantdb"
+	"github.com/devpies/saas-core/pkg/web"
 
 	"go.uber.org/zap"
 )
 
 type taskRepository interface {
-	Create(ctx context.Context, nt model.NewTask, pid string, now time.Time) (model.Task, error)
+	Create(ctx context.Context, nt model.NewTask, pid string, cid string, now time.Time) (model.Task, error)
 	Retrieve(ctx context.Context, tid string) (model.Task, error)
-	List(ctx context.Context, pid string) ([]model.Task, error)
+	RetrieveByKey(ctx context.Context, pid string, key string) (model.Task, error)
+	List(ctx context.Context, pid string, filter model.TaskFilter) ([]model.Task, error)
 	Update(ctx context.Context, tid string, update model.UpdateTask, now time.Time) (model.Task, error)
-	Delete(ctx context.Context, tid string) error
+	Delete(ctx context.Context, tid string, now time.Time) error
+	Restore(ctx context.Context, tid string, now time.Time) (model.Task, error)
+	Archive(ctx context.Context, tid string, now time.Time) (model.Task, error)
+	Unarchive(ctx context.Context, tid string, now time.Time) (model.Task, error)
+	Move(ctx context.Context, tid string, mt model.MoveTask, now time.Time) (model.Task, error)
+	Search(ctx context.Context, search model.TaskSearch) ([]model.TaskSearchResult, error)
+	Query(ctx context.Context, q *query.Query, tq model.TaskQuery, now time.Time) ([]model.Task, error)
+	SetParent(ctx context.Context, tid string, sp model.SetParent, now time.Time) (model.Task, error)
+	Link(ctx context.Context, tid string, nl model.NewTaskLink, now time.Time) (model.Task, error)
+	Unlink(ctx context.Context, tid string, lkid string, now time.Time) error
 }
 
 // TaskService is responsible for managing task business logic.
 type TaskService struct {
-	logger *zap.Logger
-	repo   taskRepository
+	logger   *zap.Logger
+	repo     taskRepository
+	notifier changeNotifier
+	fanOut   FanOut
 }
 
-// NewTaskService returns a TaskService.
-func NewTaskService(logger *zap.Logger, repo taskRepository) *TaskService {
+// NewTaskService returns a TaskService that searches tasks across tenants within the
+// bounds of fanOut.
+func NewTaskService(logger *zap.Logger, repo taskRepository, notifier changeNotifier, fanOut FanOut) *TaskService {
 	return &TaskService{
-		logger: logger,
-		repo:   repo,
+		logger:   logger,
+		repo:     repo,
+		notifier: notifier,
+		fanOut:   fanOut,
 	}
 }
 
-// Create creates a task.
-func (ts *TaskService) Create(ctx context.Context, task model.NewTask, projectID string, now time.Time) (model.Task, error) {
-	return ts.repo.Create(ctx, task, projectID, now)
+// Create creates a task at the end of a column.
+func (ts *TaskService) Create(ctx context.Context, task model.NewTask, projectID string, columnID string, now time.Time) (model.Task, error) {
+	t, err := ts.repo.Create(ctx, task, projectID, columnID, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionCreated, now)
+	return t, nil
 }
 
-// List lists a task.
-func (ts *TaskService) List(ctx context.Context, projectID string) ([]model.Task, error) {
-	return ts.repo.List(ctx, projectID)
+// List lists the tasks of a project that match the filter.
+func (ts *TaskService) List(ctx context.Context, projectID string, filter model.TaskFilter) ([]model.Task, error) {
+	return ts.repo.List(ctx, projectID, filter)
 }
 
 // Retrieve retrieves a task.
@@ -46,12 +70,178 @@ func (ts *TaskService) Retrieve(ctx context.Context, taskID string) (model.Task,
 	return ts.repo.Retrieve(ctx, taskID)
 }
 
+// RetrieveByKey retrieves a task of a project by its key.
+func (ts *TaskService) RetrieveByKey(ctx context.Context, projectID string, key string) (model.Task, error) {
+	return ts.repo.RetrieveByKey(ctx, projectID, key)
+}
+
 // Update updates a task.
 func (ts *TaskService) Update(ctx context.Context, taskID string, update model.UpdateTask, now time.Time) (model.Task, error) {
-	return ts.repo.Update(ctx, taskID, update, now)
+	t, err := ts.repo.Update(ctx, taskID, update, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionUpdated, now)
+	return t, nil
+}
+
+// Delete moves a task to the trash.
+func (ts *TaskService) Delete(ctx context.Context, taskID string, now time.Time) error {
+	t, err := ts.repo.Retrieve(tenantdb.WithPrimary(ctx), taskID)
+	if err != nil {
+		return err
+	}
+	if err = ts.repo.Delete(ctx, taskID, now); err != nil {
+		return err
+	}
+	ts.notify(ctx, t, model.ActionDeleted, now)
+	return nil
+}
+
+// Restore takes a task out of the trash.
+func (ts *TaskService) Restore(ctx context.Context, taskID string, now time.Time) (model.Task, error) {
+	t, err := ts.repo.Restore(ctx, taskID, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionRestored, now)
+	return t, nil
+}
+
+// Archive archives a task.
+func (ts *TaskService) Archive(ctx context.Context, taskID string, now time.Time) (model.Task, error) {
+	t, err := ts.repo.Archive(ctx, taskID, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionArchived, now)
+	return t, nil
+}
+
+// Unarchive unarchives a task.
+func (ts *TaskService) Unarchive(ctx context.Context, taskID string, now time.Time) (model.Task, error) {
+	t, err := ts.repo.Unarchive(ctx, taskID, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionUnarchived, now)
+	return t, nil
+}
+
+// Move moves a task to a position in a column.
+func (ts *TaskService) Move(ctx context.Context, taskID string, mt model.MoveTask, now time.Time) (model.Task, error) {
+	t, err := ts.repo.Move(ctx, taskID, mt, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionMoved, now)
+	return t, nil
+}
+
+// SetParent makes a task a subtask of another task.
+func (ts *TaskService) SetParent(ctx context.Context, taskID string, sp model.SetParent, now time.Time) (model.Task, error) {
+	t, err := ts.repo.SetParent(ctx, taskID, sp, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionUpdated, now)
+	return t, nil
 }
 
-// Delete deletes a task.
-func (ts *TaskService) Delete(ctx context.Context, taskID string) error {
-	return ts.repo.Delete(ctx, taskID)
+// Link links a task to another task.
+func (ts *TaskService) Link(ctx context.Context, taskID string, nl model.NewTaskLink, now time.Time) (model.Task, error) {
+	t, err := ts.repo.Link(ctx, taskID, nl, now)
+	if err != nil {
+		return t, err
+	}
+	ts.notify(ctx, t, model.ActionLinked, now)
+	return t, nil
+}
+
+// Unlink removes a link from a task.
+func (ts *TaskService) Unlink(ctx context.Context, taskID string, linkID string, now time.Time) error {
+	t, err := ts.repo.Retrieve(tenantdb.WithPrimary(ctx), taskID)
+	if err != nil {
+		return err
+	}
+	if err = ts.repo.Unlink(ctx, taskID, linkID, now); err != nil {
+		return err
+	}
+	ts.notify(ctx, t, model.ActionUnlinked, now)
+	return nil
+}
+
+// notify pushes a task change to the users watching the board of the task.
+func (ts *TaskService) notify(ctx context.Context, t model.Task, action string, now time.Time) {
+	ts.notifier.Notify(ctx, model.Change{
+		ProjectID: t.ProjectID,
+		Kind:      model.ChangeTask,
+		Action:    action,
+		EntityID:  t.ID,
+	}, now)
+}
+
+// Search searches the tasks of the tenant, or of every tenant of the user when all is set.
+// The tenants that fail a search across tenants are reported instead of failing the
+// search.
+func (ts *TaskService) Search(ctx context.Context, search model.TaskSearch, all bool) (model.TaskSearchResults, error) {
+	page := model.TaskSearchResults{Failures: make([]model.TenantFailure, 0)}
+
+	if !all {
+		results, err := ts.repo.Search(ctx, search)
+		if err != nil {
+			return page, err
+		}
+		page.Results = results
+		return page, nil
+	}
+
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return page, web.CtxErr()
+	}
+
+	// Every tenant must return enough results to fill the requested page once merged.
+	merged := search
+	merged.Limit = search.Offset + search.Limit
+	merged.Offset = 0
+
+	results, failures, err := forEachT(ctx, ts.logger, values.TenantMap, ts.fanOut, func(ctx context.Context) ([]model.TaskSearchResult, error) {
+		return ts.repo.Search(ctx, merged)
+	})
+	if err != nil {
+		return page, err
+	}
+	page.Failures = failures
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.After(results[j].CreatedAt)
+		}
+		return results[i].TaskID < results[j].TaskID
+	})
+
+	if search.Offset >= len(results) {
+		page.Results = make([]model.TaskSearchResult, 0)
+		return page, nil
+	}
+	results = results[search.Offset:]
+	if len(results) > search.Limit {
+		results = results[:search.Limit]
+	}
+	page.Results = results
+	return page, nil
+}
+
+// Query lists the tasks matching a query of the task query language. Queries that cannot
+// be parsed return a *query.Error.
+func (ts *TaskService) Query(ctx context.Context, tq model.TaskQuery, now time.Time) ([]model.Task, error) {
+	q, err := query.Parse(tq.Query)
+	if err != nil {
+		return nil, err
+	}
+	return ts.repo.Query(ctx, q, tq, now)
 }
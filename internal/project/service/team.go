@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type teamRepository interface {
+	List(ctx context.Context) ([]model.Team, error)
+	Retrieve(ctx context.Context, teamID string) (model.Team, error)
+	Create(ctx context.Context, nt model.NewTeam, now time.Time) (model.Team, error)
+	Delete(ctx context.Context, teamID string) error
+	AddMember(ctx context.Context, teamID string, uid string, now time.Time) (model.Team, error)
+	RemoveMember(ctx context.Context, teamID string, uid string) error
+}
+
+// TeamService is responsible for managing tenant teams.
+type TeamService struct {
+	logger      *zap.Logger
+	js          publisher
+	repo        teamRepository
+	memberships membershipRepository
+}
+
+// NewTeamService returns a TeamService.
+func NewTeamService(logger *zap.Logger, js publisher, repo teamRepository, memberships membershipRepository) *TeamService {
+	return &TeamService{
+		logger:      logger,
+		js:          js,
+		repo:        repo,
+		memberships: memberships,
+	}
+}
+
+// List lists the teams of the tenant.
+func (ts *TeamService) List(ctx context.Context) ([]model.Team, error) {
+	return ts.repo.List(ctx)
+}
+
+// Retrieve retrieves a team.
+func (ts *TeamService) Retrieve(ctx context.Context, teamID string) (model.Team, error) {
+	return ts.repo.Retrieve(ctx, teamID)
+}
+
+// Create creates a team.
+func (ts *TeamService) Create(ctx context.Context, nt model.NewTeam, now time.Time) (model.Team, error) {
+	return ts.repo.Create(ctx, nt, now)
+}
+
+// Delete deletes a team, unassigning it from its projects.
+func (ts *TeamService) Delete(ctx context.Context, teamID string, now time.Time) error {
+	t, err := ts.repo.Retrieve(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if err = ts.repo.Delete(ctx, teamID); err != nil {
+		return err
+	}
+	ts.publish(ctx, t.Projects, now)
+	return nil
+}
+
+// AddMember adds a user to a team.
+func (ts *TeamService) AddMember(ctx context.Context, teamID string, nm model.NewTeamMember, now time.Time) (model.Team, error) {
+	t, err := ts.repo.AddMember(ctx, teamID, nm.UserID, now)
+	if err != nil {
+		return t, err
+	}
+	ts.publish(ctx, t.Projects, now)
+	return t, nil
+}
+
+// RemoveMember removes a user from a team.
+func (ts *TeamService) RemoveMember(ctx context.Context, teamID string, userID string, now time.Time) error {
+	if err := ts.repo.RemoveMember(ctx, teamID, userID); err != nil {
+		return err
+	}
+	t, err := ts.repo.Retrieve(ctx, teamID)
+	if err != nil {
+		ts.logger.Error("error publishing team projects", zap.String("teamID", teamID), zap.Error(err))
+		return nil
+	}
+	ts.publish(ctx, t.Projects, now)
+	return nil
+}
+
+// publish publishes the membership of every project the team changed access to.
+func (ts *TeamService) publish(ctx context.Context, projectIDs []string, now time.Time) {
+	for _, pid := range projectIDs {
+		publishMembership(ctx, ts.logger, ts.js, ts.memberships, pid, now)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type templateRepository interface {
+	Retrieve(ctx context.Context, tmid string) (model.Template, error)
+	List(ctx context.Context) ([]model.Template, error)
+	Create(ctx context.Context, nt model.NewTemplate, now time.Time) (model.Template, error)
+	Delete(ctx context.Context, tmid string) error
+	Apply(ctx context.Context, pid string, td model.TemplateDefinition, now time.Time) error
+	Snapshot(ctx context.Context, pid string) (model.TemplateDefinition, error)
+}
+
+// TemplateService is responsible for managing project template business logic.
+type TemplateService struct {
+	logger *zap.Logger
+	repo   templateRepository
+}
+
+// NewTemplateService returns a TemplateService.
+func NewTemplateService(logger *zap.Logger, repo templateRepository) *TemplateService {
+	return &TemplateService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// List lists the built-in templates followed by the templates of the tenant.
+func (ts *TemplateService) List(ctx context.Context) ([]model.Template, error) {
+	list, err := ts.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(model.BuiltinTemplates(), list...), nil
+}
+
+// Retrieve retrieves a built-in or tenant template. An empty id retrieves the default template.
+func (ts *TemplateService) Retrieve(ctx context.Context, templateID string) (model.Template, error) {
+	if templateID == "" {
+		templateID = model.TemplateKanban
+	}
+	for _, t := range model.BuiltinTemplates() {
+		if t.ID == templateID {
+			return t, nil
+		}
+	}
+	return ts.repo.Retrieve(ctx, templateID)
+}
+
+// Create creates a tenant template.
+func (ts *TemplateService) Create(ctx context.Context, nt model.NewTemplate, now time.Time) (model.Template, error) {
+	if err := checkTemplate(nt.TemplateDefinition); err != nil {
+		return model.Template{}, err
+	}
+	return ts.repo.Create(ctx, nt, now)
+}
+
+// SaveProject saves the board of a project as a new tenant template.
+func (ts *TemplateService) SaveProject(ctx context.Context, projectID string, st model.SaveTemplate, now time.Time) (model.Template, error) {
+	td, err := ts.repo.Snapshot(ctx, projectID)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	nt := model.NewTemplate{
+		Name:               st.Name,
+		Description:        st.Description,
+		TemplateDefinition: td,
+	}
+	return ts.repo.Create(ctx, nt, now)
+}
+
+// Delete deletes a tenant template.
+func (ts *TemplateService) Delete(ctx context.Context, templateID string) error {
+	for _, t := range model.BuiltinTemplates() {
+		if t.ID == templateID {
+			return fail.ErrBuiltinTemplate
+		}
+	}
+	return ts.repo.Delete(ctx, templateID)
+}
+
+// Apply creates the board of a new project from a template.
+func (ts *TemplateService) Apply(ctx context.Context, projectID string, t model.Template, now time.Time) error {
+	return ts.repo.Apply(ctx, projectID, t.TemplateDefinition, now)
+}
+
+// checkTemplate checks the seed tasks of a template refer to its columns and labels.
+func checkTemplate(td model.TemplateDefinition) error {
+	labels := make(map[string]bool, len(td.Labels))
+	for _, l := range td.Labels {
+		name := strings.ToLower(l.Name)
+		if labels[name] {
+			return fail.ErrDuplicateLabel
+		}
+		labels[name] = true
+	}
+
+	for _, t := range td.Tasks {
+		if t.Column >= len(td.Columns) {
+			return fail.ErrInvalidTemplate
+		}
+		for _, name := range t.Labels {
+			if !labels[strings.ToLower(name)] {
+				return fail.ErrInvalidTemplate
+			}
+		}
+	}
+
+	return nil
+}
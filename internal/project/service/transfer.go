@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/bundle"
+	"github.com/devpies/saas-core/internal/project/fail"
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type transferRepository interface {
+	Export(ctx context.Context, projectID string, now time.Time) (bundle.Bundle, error)
+	Import(ctx context.Context, b bundle.Bundle, users map[string]string, now time.Time) (model.Project, []string, error)
+	RetrieveImport(ctx context.Context, importID string) (model.Import, error)
+	CreateImport(ctx context.Context, format string, tasks int, now time.Time) (model.Import, error)
+	UpdateImport(ctx context.Context, im model.Import, now time.Time) error
+}
+
+// importJob is an import waiting for a worker.
+type importJob struct {
+	ctx   context.Context
+	im    model.Import
+	b     bundle.Bundle
+	users map[string]string
+}
+
+// TransferService is responsible for managing project export and import business logic.
+// Imports run in the background on a fixed number of workers.
+type TransferService struct {
+	logger *zap.Logger
+	repo   transferRepository
+	wg     sync.WaitGroup
+
+	// slots is reserved by every queued or running import, so jobs never blocks.
+	slots chan struct{}
+	jobs  chan importJob
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewTransferService returns a TransferService running imports on workers, with up to
+// queue more imports waiting for one.
+func NewTransferService(logger *zap.Logger, repo transferRepository, workers, queue int) *TransferService {
+	if workers < 1 {
+		workers = 1
+	}
+	if queue < 0 {
+		queue = 0
+	}
+
+	ts := &TransferService{
+		logger: logger,
+		repo:   repo,
+		slots:  make(chan struct{}, workers+queue),
+		jobs:   make(chan importJob, workers+queue),
+	}
+
+	ts.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go ts.work()
+	}
+
+	return ts
+}
+
+// work runs queued imports until the service shuts down.
+func (ts *TransferService) work() {
+	defer ts.wg.Done()
+	for job := range ts.jobs {
+		ts.run(job.ctx, job.im, job.b, job.users)
+		<-ts.slots
+	}
+}
+
+// Export exports a project board as a bundle.
+func (ts *TransferService) Export(ctx context.Context, projectID string, now time.Time) (bundle.Bundle, error) {
+	return ts.repo.Export(ctx, projectID, now)
+}
+
+// RetrieveImport retrieves a project import.
+func (ts *TransferService) RetrieveImport(ctx context.Context, importID string) (model.Import, error) {
+	return ts.repo.RetrieveImport(ctx, importID)
+}
+
+// Import parses the source of a new import and records a pending import, which then
+// runs in the background. A source that cannot be parsed fails with ErrInvalidImport,
+// and an import started while the queue is full fails with ErrImportsBusy.
+func (ts *TransferService) Import(ctx context.Context, ni model.NewImport, now time.Time) (model.Import, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		return model.Import{}, web.CtxErr()
+	}
+
+	var (
+		b   bundle.Bundle
+		err error
+		src = strings.NewReader(ni.Source)
+	)
+	switch ni.Format {
+	case model.FormatBundle:
+		b, err = bundle.Decode(src)
+	case model.FormatTrello:
+		b, err = bundle.FromTrello(src)
+	case model.FormatJira:
+		b, err = bundle.FromJira(src)
+	default:
+		return model.Import{}, fail.ErrInvalidImport
+	}
+	if err != nil {
+		ts.logger.Info("rejected project import", zap.String("format", ni.Format), zap.Error(err))
+		return model.Import{}, fail.ErrInvalidImport
+	}
+	if ni.Name != "" {
+		b.Project.Name = ni.Name
+	}
+
+	users := make(map[string]string, len(ni.Users))
+	for handle, id := range ni.Users {
+		users[strings.ToLower(strings.TrimSpace(handle))] = id
+	}
+
+	// Imports are recorded and queued one at a time, so none is recorded that cannot run:
+	// a slot is reserved first, and the queue stays open until the import is in it.
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.closed {
+		return model.Import{}, fail.ErrImportsBusy
+	}
+	select {
+	case ts.slots <- struct{}{}:
+	default:
+		return model.Import{}, fail.ErrImportsBusy
+	}
+
+	im, err := ts.repo.CreateImport(ctx, ni.Format, len(b.Tasks), now)
+	if err != nil {
+		<-ts.slots
+		return model.Import{}, err
+	}
+
+	// The import outlives the request, so it runs with a context carrying only the
+	// tenant and user the database needs.
+	bg := web.NewContext(context.Background(), &web.Values{
+		TenantID: values.TenantID,
+		UserID:   values.UserID,
+	})
+	ts.jobs <- importJob{ctx: bg, im: im, b: b, users: users}
+
+	return im, nil
+}
+
+func (ts *TransferService) run(ctx context.Context, im model.Import, b bundle.Bundle, users map[string]string) {
+	im.Status = model.ImportRunning
+	if err := ts.repo.UpdateImport(ctx, im, time.Now()); err != nil {
+		ts.logger.Error("error starting project import", zap.String("import", im.ID), zap.Error(err))
+		return
+	}
+
+	p, unassigned, err := ts.repo.Import(ctx, b, users, time.Now())
+
+	now := time.Now().Round(time.Microsecond).UTC()
+	im.CompletedAt = &now
+	if err != nil {
+		ts.logger.Error("error importing project", zap.String("import", im.ID), zap.Error(err))
+		im.Status = model.ImportFailed
+		im.Error = fmt.Sprintf("import failed: %s", importError(err))
+	} else {
+		im.Status = model.ImportCompleted
+		im.ProjectID = p.ID
+		im.Unassigned = unassigned
+	}
+
+	if err = ts.repo.UpdateImport(ctx, im, now); err != nil {
+		ts.logger.Error("error finishing project import", zap.String("import", im.ID), zap.Error(err))
+	}
+}
+
+// importError returns the error of a failed import as shown to the user, hiding
+// database details.
+func importError(err error) string {
+	switch err {
+	case bundle.ErrInvalid, bundle.ErrVersion, fail.ErrInvalidID:
+		return err.Error()
+	default:
+		return "the board could not be saved"
+	}
+}
+
+// Shutdown stops taking imports and waits for queued and running ones to finish or for
+// ctx to be done.
+func (ts *TransferService) Shutdown(ctx context.Context) error {
+	ts.mu.Lock()
+	if !ts.closed {
+		ts.closed = true
+		close(ts.jobs)
+	}
+	ts.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		ts.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
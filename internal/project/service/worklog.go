@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devpies/saas-core/internal/project/model"
+
+	"go.uber.org/zap"
+)
+
+type worklogRepository interface {
+	List(ctx context.Context, tid string) ([]model.Worklog, error)
+	Create(ctx context.Context, tid string, nw model.NewWorklog, now time.Time) (model.Worklog, error)
+	Update(ctx context.Context, tid string, wid string, update model.UpdateWorklog, now time.Time) (model.Worklog, error)
+	Delete(ctx context.Context, tid string, wid string) error
+	Timer(ctx context.Context) (model.Timer, error)
+	StartTimer(ctx context.Context, tid string, now time.Time) (model.Timer, error)
+	StopTimer(ctx context.Context, st model.StopTimer, now time.Time) (model.Worklog, error)
+	DiscardTimer(ctx context.Context) error
+	Report(ctx context.Context, filter model.WorklogFilter) (model.TimeReport, error)
+	Entries(ctx context.Context, filter model.WorklogFilter) ([]model.WorklogEntry, error)
+}
+
+// WorklogService is responsible for managing time tracking business logic.
+type WorklogService struct {
+	logger *zap.Logger
+	repo   worklogRepository
+}
+
+// NewWorklogService returns a WorklogService.
+func NewWorklogService(logger *zap.Logger, repo worklogRepository) *WorklogService {
+	return &WorklogService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// List lists the worklogs of a task.
+func (ws *WorklogService) List(ctx context.Context, taskID string) ([]model.Worklog, error) {
+	return ws.repo.List(ctx, taskID)
+}
+
+// Create logs time of the user on a task.
+func (ws *WorklogService) Create(ctx context.Context, taskID string, nw model.NewWorklog, now time.Time) (model.Worklog, error) {
+	return ws.repo.Create(ctx, taskID, nw, now)
+}
+
+// Update updates a worklog of the user.
+func (ws *WorklogService) Update(ctx context.Context, taskID string, worklogID string, update model.UpdateWorklog, now time.Time) (model.Worklog, error) {
+	return ws.repo.Update(ctx, taskID, worklogID, update, now)
+}
+
+// Delete deletes a worklog.
+func (ws *WorklogService) Delete(ctx context.Context, taskID string, worklogID string) error {
+	return ws.repo.Delete(ctx, taskID, worklogID)
+}
+
+// Timer retrieves the running timer of the user.
+func (ws *WorklogService) Timer(ctx context.Context) (model.Timer, error) {
+	return ws.repo.Timer(ctx)
+}
+
+// StartTimer starts a timer of the user on a task.
+func (ws *WorklogService) StartTimer(ctx context.Context, taskID string, now time.Time) (model.Timer, error) {
+	return ws.repo.StartTimer(ctx, taskID, now)
+}
+
+// StopTimer stops the running timer of the user and logs its time.
+func (ws *WorklogService) StopTimer(ctx context.Context, st model.StopTimer, now time.Time) (model.Worklog, error) {
+	return ws.repo.StopTimer(ctx, st, now)
+}
+
+// DiscardTimer stops the running timer of the user without logging time.
+func (ws *WorklogService) DiscardTimer(ctx context.Context) error {
+	return ws.repo.DiscardTimer(ctx)
+}
+
+// Report totals the time logged per project, user and day.
+func (ws *WorklogService) Report(ctx context.Context, filter model.WorklogFilter) (model.TimeReport, error) {
+	return ws.repo.Report(ctx, filter)
+}
+
+// Entries lists the worklogs matching the filter for invoicing.
+func (ws *WorklogService) Entries(ctx context.Context, filter model.WorklogFilter) ([]model.WorklogEntry, error) {
+	return ws.repo.Entries(ctx, filter)
+}
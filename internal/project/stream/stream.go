@@ -0,0 +1,195 @@
+// Package stream fans project board changes out to the users watching the board.
+//
+// Every replica of the service receives every change, so a hub only serves the
+// connections of its own replica. Each board keeps its most recent changes, letting a
+// client that reconnects resume from the last change it saw. A subscriber that does not
+// keep up is dropped instead of slowing down the others, and resumes once it reconnects.
+package stream
+
+import (
+	"sync"
+
+	"github.com/devpies/saas-core/internal/project/model"
+)
+
+// Subscription receives the changes of a project board.
+type Subscription struct {
+	// C delivers the changes after the replay. It is closed when the subscriber falls
+	// behind, is closed or the hub shuts down.
+	C <-chan model.Change
+	// Replay holds the changes missed since the last change the subscriber saw.
+	Replay []model.Change
+	// Reset reports the last change is no longer known, so the board must be reloaded.
+	Reset bool
+
+	c     chan model.Change
+	hub   *Hub
+	key   string
+	once  sync.Once
+	mu    sync.Mutex
+	ended bool
+}
+
+// Close unsubscribes from the board.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// end closes the channel of the subscription once. The hub holds its lock.
+func (s *Subscription) end() {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.ended = true
+		s.mu.Unlock()
+		close(s.c)
+	})
+}
+
+// Dropped reports whether the subscription ended because it fell behind or the hub shut
+// down, rather than because it was closed.
+func (s *Subscription) Dropped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ended
+}
+
+type board struct {
+	subs   map[*Subscription]struct{}
+	recent []model.Change
+}
+
+// Hub fans changes out to the subscribers of each board.
+type Hub struct {
+	mu       sync.Mutex
+	buffer   int
+	history  int
+	maxIdle  int
+	boards   map[string]*board
+	closed   bool
+	sequence []string
+}
+
+// NewHub returns a hub buffering up to buffer changes per subscriber and keeping the
+// last history changes of every board.
+func NewHub(buffer int, history int) *Hub {
+	return &Hub{
+		buffer:  buffer,
+		history: history,
+		maxIdle: 1024,
+		boards:  make(map[string]*board),
+	}
+}
+
+func key(tenantID string, projectID string) string {
+	return tenantID + "/" + projectID
+}
+
+// Subscribe subscribes to the changes of a tenant project made after the change with
+// the given id. An empty id subscribes to new changes only.
+func (h *Hub) Subscribe(tenantID string, projectID string, lastID string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c := make(chan model.Change, h.buffer)
+	s := &Subscription{C: c, c: c, hub: h, key: key(tenantID, projectID)}
+
+	if h.closed {
+		s.end()
+		return s
+	}
+
+	b := h.board(s.key)
+	b.subs[s] = struct{}{}
+
+	if lastID != "" {
+		s.Reset = true
+		for i := len(b.recent) - 1; i >= 0; i-- {
+			if b.recent[i].ID == lastID {
+				s.Replay = append([]model.Change(nil), b.recent[i+1:]...)
+				s.Reset = false
+				break
+			}
+		}
+	}
+
+	return s
+}
+
+// board returns the board with the key, creating it. Idle boards are forgotten once
+// there are too many of them.
+func (h *Hub) board(k string) *board {
+	if b, ok := h.boards[k]; ok {
+		return b
+	}
+
+	if len(h.sequence) >= h.maxIdle {
+		kept := h.sequence[:0]
+		for _, old := range h.sequence {
+			if len(h.boards[old].subs) == 0 {
+				delete(h.boards, old)
+				continue
+			}
+			kept = append(kept, old)
+		}
+		h.sequence = kept
+	}
+
+	b := &board{subs: make(map[*Subscription]struct{})}
+	h.boards[k] = b
+	h.sequence = append(h.sequence, k)
+	return b
+}
+
+// Broadcast sends a change to the subscribers of its board. Subscribers whose buffer
+// is full are dropped.
+func (h *Hub) Broadcast(change model.Change) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	b := h.board(key(change.TenantID, change.ProjectID))
+
+	b.recent = append(b.recent, change)
+	if len(b.recent) > h.history {
+		b.recent = append(b.recent[:0], b.recent[len(b.recent)-h.history:]...)
+	}
+
+	for s := range b.subs {
+		select {
+		case s.c <- change:
+		default:
+			delete(b.subs, s)
+			s.end()
+		}
+	}
+}
+
+// unsubscribe removes a subscriber from its board.
+func (h *Hub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if b, ok := h.boards[s.key]; ok {
+		if _, ok := b.subs[s]; ok {
+			delete(b.subs, s)
+			s.once.Do(func() { close(s.c) })
+		}
+	}
+}
+
+// Close ends every subscription. Later subscriptions end immediately.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, b := range h.boards {
+		for s := range b.subs {
+			delete(b.subs, s)
+			s.end()
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package stream_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/project/model"
+	"github.com/devpies/saas-core/internal/project/stream"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func change(id int, project string) model.Change {
+	return model.Change{ID: strconv.Itoa(id), TenantID: "tenant", ProjectID: project}
+}
+
+func ids(changes []model.Change) []string {
+	var list []string
+	for _, c := range changes {
+		list = append(list, c.ID)
+	}
+	return list
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	tests := []struct {
+		name   string
+		lastID string
+		replay []string
+		reset  bool
+	}{
+		{name: "new changes only", lastID: ""},
+		{name: "resume", lastID: "2", replay: []string{"3", "4"}},
+		{name: "resume from latest", lastID: "4"},
+		{name: "unknown change", lastID: "1", reset: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hub := stream.NewHub(4, 3)
+			for i := 1; i <= 4; i++ {
+				hub.Broadcast(change(i, "p1"))
+			}
+
+			sub := hub.Subscribe("tenant", "p1", tc.lastID)
+			defer sub.Close()
+
+			assert.Equal(t, tc.replay, ids(sub.Replay))
+			assert.Equal(t, tc.reset, sub.Reset)
+		})
+	}
+}
+
+func TestHub_Broadcast(t *testing.T) {
+	hub := stream.NewHub(2, 8)
+
+	sub := hub.Subscribe("tenant", "p1", "")
+	other := hub.Subscribe("tenant", "p2", "")
+	defer other.Close()
+
+	hub.Broadcast(change(1, "p1"))
+	require.Len(t, sub.C, 1)
+	assert.Len(t, other.C, 0)
+
+	hub.Broadcast(change(2, "p1"))
+	hub.Broadcast(change(3, "p1"))
+
+	var got []model.Change
+	for c := range sub.C {
+		got = append(got, c)
+	}
+	assert.Equal(t, []string{"1", "2"}, ids(got))
+	assert.True(t, sub.Dropped())
+
+	resumed := hub.Subscribe("tenant", "p1", "2")
+	defer resumed.Close()
+	assert.Equal(t, []string{"3"}, ids(resumed.Replay))
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := stream.NewHub(2, 8)
+
+	sub := hub.Subscribe("tenant", "p1", "")
+	sub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	assert.False(t, sub.Dropped())
+
+	sub = hub.Subscribe("tenant", "p1", "")
+	hub.Close()
+	_, ok = <-sub.C
+	assert.False(t, ok)
+	assert.True(t, sub.Dropped())
+
+	sub = hub.Subscribe("tenant", "p1", "")
+	_, ok = <-sub.C
+	assert.False(t, ok)
+}
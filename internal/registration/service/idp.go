@@ -101,7 +101,7 @@ func (idps *IDPService) GetPlanBasedUserPool(ctx context.Context, tenant model.N
 		return "", err
 	}
 	// Publish siloed tenant config
-	event := newCreateTenantSiloedEvent(values, tenant.Company, userPoolClient.ClientId, userPool.Id)
+	event := newCreateTenantSiloedEvent(values, tenant.ID, tenant.Company, userPoolClient.ClientId, userPool.Id)
 	bytes, err := event.Marshal()
 	if err != nil {
 		return "", err
@@ -110,11 +110,12 @@ func (idps *IDPService) GetPlanBasedUserPool(ctx context.Context, tenant model.N
 	return *userPool.Id, nil
 }
 
-func newCreateTenantSiloedEvent(values *web.Values, path string, clientID, userPoolID *string) msg.TenantSiloedEvent {
+func newCreateTenantSiloedEvent(values *web.Values, tenantID, path string, clientID, userPoolID *string) msg.TenantSiloedEvent {
 	return msg.TenantSiloedEvent{
 		Metadata: msg.Metadata{
-			TraceID: values.TraceID,
-			UserID:  values.UserID,
+			TraceID:  values.TraceID,
+			UserID:   values.UserID,
+			TenantID: tenantID,
 		},
 		Type: msg.TypeTenantSiloed,
 		Data: msg.TenantSiloedEventData{
@@ -95,7 +95,7 @@ func (cr *CustomerRepository) GetCustomer(ctx context.Context, tenantID string)
 		c   model.Customer
 		err error
 	)
-	conn, Close, err := cr.pg.GetConnection(ctx)
+	conn, Close, err := cr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return c, err
 	}
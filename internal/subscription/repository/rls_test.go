@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/subscription/db"
+	"github.com/devpies/saas-core/internal/subscription/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTenantID = "439f70c8-def5-4419-9f17-986a45339e86"
+
+// The service role is a member of the table owner, which policies do not apply to, so
+// these tests check the tenant each session is bound to rather than the rows it sees.
+func TestRowLevelSecurity_TenantBinding(t *testing.T) {
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testTenantID})
+
+	pg, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	// A single pooled connection guarantees the next checkout reuses the same session.
+	pg.TestsOnlyDBConnection().SetMaxOpenConns(1)
+
+	t.Run("connection binding is reset on release", func(t *testing.T) {
+		conn, release, err := pg.GetConnection(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, testTenantID, currentTenant(t, ctx, conn))
+		require.NoError(t, release())
+
+		assert.Equal(t, "", currentTenant(t, ctx, pg.TestsOnlyDBConnection()))
+	})
+
+	t.Run("transaction binding ends with the transaction", func(t *testing.T) {
+		err := pg.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+			assert.Equal(t, testTenantID, currentTenant(t, ctx, tx))
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "", currentTenant(t, ctx, pg.TestsOnlyDBConnection()))
+	})
+
+	t.Run("machine to machine role ends with the transaction", func(t *testing.T) {
+		m2m := web.NewContext(testutils.MockCtx, &web.Values{IsM2MClient: true})
+		err := pg.RunInTransaction(m2m, func(tx *sqlx.Tx) error {
+			assert.Equal(t, "postgres", currentRole(t, m2m, tx))
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, "postgres", currentRole(t, ctx, pg.TestsOnlyDBConnection()))
+	})
+
+	t.Run("transaction requires a tenant", func(t *testing.T) {
+		noTenant := web.NewContext(testutils.MockCtx, &web.Values{})
+		err := pg.RunInTransaction(noTenant, func(tx *sqlx.Tx) error { return nil })
+		assert.Equal(t, db.ErrNoTenantID, err)
+	})
+
+	t.Run("tenant id is not interpolated", func(t *testing.T) {
+		tenantID := "x', false); select set_config('app.current_tenant', '" + testTenantID
+		injected := web.NewContext(testutils.MockCtx, &web.Values{TenantID: tenantID})
+		conn, release, err := pg.GetConnection(injected)
+		require.NoError(t, err)
+		defer release()
+
+		assert.Equal(t, tenantID, currentTenant(t, injected, conn))
+	})
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func currentTenant(t *testing.T, ctx context.Context, q queryer) string {
+	var tenantID string
+	err := q.QueryRowContext(ctx, `select coalesce(current_setting('app.current_tenant', true), '')`).Scan(&tenantID)
+	require.NoError(t, err)
+	return tenantID
+}
+
+func currentRole(t *testing.T, ctx context.Context, q queryer) string {
+	var role string
+	err := q.QueryRowContext(ctx, `select current_user`).Scan(&role)
+	require.NoError(t, err)
+	return role
+}
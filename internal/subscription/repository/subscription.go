@@ -91,7 +91,7 @@ func (sr *SubscriptionRepository) GetTenantSubscription(ctx context.Context, ten
 		err error
 	)
 
-	conn, Close, err := sr.pg.GetConnection(ctx)
+	conn, Close, err := sr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return s, err
 	}
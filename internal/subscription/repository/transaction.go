@@ -101,7 +101,7 @@ func (tr *TransactionRepository) GetTransaction(ctx context.Context, subID strin
 		err error
 	)
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return t, err
 	}
@@ -151,7 +151,7 @@ func (tr *TransactionRepository) GetAllTransactions(ctx context.Context, tenantI
 		err error
 	)
 
-	conn, Close, err := tr.pg.GetConnection(ctx)
+	conn, Close, err := tr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return ts, err
 	}
@@ -36,8 +36,8 @@ type DatabaseClient struct {
 }
 
 // NewDatabaseClient returns a DatabaseClient and a close method to clean up the setup connection.
-func NewDatabaseClient() (*DatabaseClient, func() error) {
-	prepareEnvironment()
+func NewDatabaseClient(port string) (*DatabaseClient, func() error) {
+	prepareEnvironment(port)
 
 	cfg, err := config.NewConfig()
 	if err != nil {
@@ -113,30 +113,42 @@ func (client *DatabaseClient) setFixtures(db *sql.DB) {
 	client.fixtures = fixtures
 }
 
-// prepareEnvironment prepares environment for integration testing with postgres while mocking cognito.
-func prepareEnvironment() {
+// prepareEnvironment prepares environment for integration testing with postgres while mocking cognito and stripe.
+func prepareEnvironment(port string) {
 	env := []struct {
 		key   string
 		value string
 	}{
 		{
-			key:   "PROJECT_DB_NAME",
-			value: "project_test",
+			key:   "SUBSCRIPTION_DB_NAME",
+			value: "subscription_test",
 		},
 		{
-			key:   "PROJECT_DB_PORT",
-			value: "30019",
+			key:   "SUBSCRIPTION_DB_PORT",
+			value: port,
 		},
 		{
-			key:   "PROJECT_DB_DISABLE_TLS",
+			key:   "SUBSCRIPTION_DB_DISABLE_TLS",
 			value: "true",
 		},
 		{
-			key:   "PROJECT_COGNITO_USER_POOL_ID",
+			key:   "SUBSCRIPTION_DB_SILO_ADMIN_PASSWORD",
+			value: "postgres",
+		},
+		{
+			key:   "SUBSCRIPTION_COGNITO_SHARED_USER_POOL_ID",
+			value: "mock",
+		},
+		{
+			key:   "SUBSCRIPTION_COGNITO_REGION",
+			value: "mock",
+		},
+		{
+			key:   "SUBSCRIPTION_STRIPE_KEY",
 			value: "mock",
 		},
 		{
-			key:   "PROJECT_COGNITO_REGION",
+			key:   "SUBSCRIPTION_STRIPE_SECRET",
 			value: "mock",
 		},
 	}
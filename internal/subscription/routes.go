@@ -6,6 +6,7 @@ import (
 
 	"github.com/devpies/saas-core/internal/subscription/config"
 	"github.com/devpies/saas-core/internal/subscription/handler"
+	"github.com/devpies/saas-core/internal/tenantdb"
 	"github.com/devpies/saas-core/pkg/web"
 	"github.com/devpies/saas-core/pkg/web/mid"
 
@@ -25,10 +26,12 @@ func Routes(
 	mux.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://admin.devpie.local", "https://admin.devpie.io", "https://devpie.local:3000", "https://devpie.io"},
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "BasePath"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "BasePath", tenantdb.PrimaryUntilHeader},
+		ExposedHeaders:   []string{tenantdb.PrimaryUntilHeader},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	mux.Use(tenantdb.ReadYourWrites(config.DB.ReadYourWritesWindow))
 
 	middleware := []web.Middleware{
 		mid.Logger(log),
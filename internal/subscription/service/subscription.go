@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/devpies/saas-core/internal/subscription/model"
+	"github.com/devpies/saas-core/internal/tenantdb"
 	"github.com/devpies/saas-core/pkg/web"
 
 	"github.com/jmoiron/sqlx"
@@ -270,7 +271,7 @@ func (ss *SubscriptionService) Refund(ctx context.Context, subID string) error {
 		err error
 	)
 
-	t, err = ss.transactionRepo.GetTransaction(ctx, subID)
+	t, err = ss.transactionRepo.GetTransaction(tenantdb.WithPrimary(ctx), subID)
 	if err != nil {
 		return err
 	}
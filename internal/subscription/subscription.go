@@ -17,7 +17,9 @@ import (
 	"github.com/devpies/saas-core/internal/subscription/service"
 	"github.com/devpies/saas-core/internal/subscription/stripe"
 	"github.com/devpies/saas-core/pkg/log"
+	"github.com/devpies/saas-core/pkg/msg"
 
+	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 )
 
@@ -62,6 +64,8 @@ func Run() error {
 		}
 	}
 
+	jetstream := msg.NewStreamContext(logger, shutdown, cfg.Nats.Address, cfg.Nats.Port)
+
 	stripeClient := stripe.NewStripeClient(logger, cfg.Stripe.Key, cfg.Stripe.Secret)
 
 	// Initialize 3-layered architecture.
@@ -70,9 +74,22 @@ func Run() error {
 	customerRepository := repository.NewCustomerRepository(logger, pg)
 
 	subscriptionService := service.NewSubscriptionService(logger, stripeClient, subscriptionRepository, customerRepository, transactionRepository)
+	siloService := service.NewSiloService(logger, pg)
 
 	subscriptionHandler := handler.NewSubscriptionHandler(logger, subscriptionService)
 
+	// Route siloed tenants to their dedicated databases.
+	opts := []nats.SubOpt{nats.DeliverAll(), nats.ManualAck()}
+
+	go func() {
+		jetstream.Listen(
+			string(msg.TypeTenantSiloed),
+			msg.SubjectTenantSiloed,
+			"subscription_silo_consumer",
+			siloService.RegisterSiloFromEvent,
+			opts...)
+	}()
+
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Web.Port),
 		WriteTimeout: cfg.Web.WriteTimeout,
@@ -0,0 +1,52 @@
+package tenantdb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/jmoiron/sqlx"
+)
+
+// SharedRoleSource returns the migration source of a service for the database at
+// databaseURL. Roles belong to the database server rather than to a database, so stmt,
+// the statement creating the service role in the first migration, fails in a database
+// created on a server where the role exists, like a silo database next to the pooled one.
+// The statement is left out of the migrations served to such a database.
+func SharedRoleSource(databaseURL string, src source.Driver, role, stmt string) (source.Driver, error) {
+	db, err := sqlx.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var exists bool
+	if err = db.QueryRowx(`select exists (select 1 from pg_roles where rolname = $1)`, role).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return src, nil
+	}
+	return &roleSource{Driver: src, stmt: []byte(stmt)}, nil
+}
+
+// roleSource serves migrations without the statement creating a role.
+type roleSource struct {
+	source.Driver
+	stmt []byte
+}
+
+// ReadUp reads an up migration without the statement creating the role.
+func (rs *roleSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	r, identifier, err := rs.Driver.ReadUp(version)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+	return io.NopCloser(bytes.NewReader(bytes.ReplaceAll(body, rs.stmt, nil))), identifier, nil
+}
@@ -0,0 +1,38 @@
+package tenantdb
+
+import (
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleSource_ReadUp(t *testing.T) {
+	const stmt = `CREATE USER user_a WITH PASSWORD 'postgres';`
+
+	fsys := fstest.MapFS{
+		"migrations/000001_init_db.up.sql":      {Data: []byte("CREATE TABLE t (id int);\n" + stmt + "\nGRANT ALL ON t TO user_a;")},
+		"migrations/000001_init_db.down.sql":    {Data: []byte("DROP TABLE t;")},
+		"migrations/000002_add_column.up.sql":   {Data: []byte("ALTER TABLE t ADD COLUMN name text;")},
+		"migrations/000002_add_column.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN name;")},
+	}
+	d, err := iofs.New(fsys, "migrations")
+	require.NoError(t, err)
+
+	rs := &roleSource{Driver: d, stmt: []byte(stmt)}
+
+	read := func(version uint) string {
+		r, _, err := rs.ReadUp(version)
+		require.NoError(t, err)
+		defer r.Close()
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, "CREATE TABLE t (id int);\n\nGRANT ALL ON t TO user_a;", read(1))
+	assert.Equal(t, "ALTER TABLE t ADD COLUMN name text;", read(2))
+}
@@ -0,0 +1,220 @@
+package tenantdb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// replicationLag returns whether a standby streams from the primary and its replay lag
+// in seconds. A streaming standby that has replayed everything it received is not
+// lagging, even when the primary is idle. A standby that lost its primary has replayed
+// everything it received too, so it only counts as caught up while it streams.
+const replicationLag = `
+	select
+		exists (select 1 from pg_stat_wal_receiver where status = 'streaming'),
+		case
+			when pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() then 0
+			else coalesce(extract(epoch from now() - pg_last_xact_replay_timestamp()), 0)
+		end
+`
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads are always served by the primary database.
+// Use it when a read feeds a write in the same request.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func primaryRequired(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v || recentWrite(ctx)
+}
+
+// PrimaryUntilHeader carries the time until which a client reads from the primary
+// database. Responses to requests that wrote set it, and clients send it back with
+// the requests that follow.
+const PrimaryUntilHeader = "X-Primary-Until"
+
+type writesKey struct{}
+
+// writes tracks the read your writes window of a client during a request.
+type writes struct {
+	window time.Duration
+	w      http.ResponseWriter
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+// ReadYourWrites returns middleware that keeps the reads of a client on the primary
+// database for a window after each of its writes. The window travels with the client in
+// the PrimaryUntilHeader rather than in the memory of a replica, so it holds whichever
+// replica serves the next request. Windows sent by clients are capped to the window.
+func ReadYourWrites(window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := &writes{window: window, w: w}
+			if until, err := time.Parse(time.RFC3339Nano, r.Header.Get(PrimaryUntilHeader)); err == nil {
+				if limit := time.Now().Add(window); until.After(limit) {
+					until = limit
+				}
+				s.until = until
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), writesKey{}, s)))
+		})
+	}
+}
+
+// MarkWrite starts the read your writes window of the client making the request once
+// a write is committed. It must be called before the response is written, and does
+// nothing outside of a request.
+func MarkWrite(ctx context.Context) {
+	s, ok := ctx.Value(writesKey{}).(*writes)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.until = time.Now().Add(s.window)
+	s.w.Header().Set(PrimaryUntilHeader, s.until.UTC().Format(time.RFC3339Nano))
+}
+
+// recentWrite reports whether the client making the request wrote within the window.
+func recentWrite(ctx context.Context) bool {
+	s, ok := ctx.Value(writesKey{}).(*writes)
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.until)
+}
+
+// ReplicaConfig configures a ReplicaSet.
+type ReplicaConfig struct {
+	// MaxLag is the replication lag above which a replica stops serving reads.
+	MaxLag time.Duration
+	// CheckInterval is how often replication lag is measured.
+	CheckInterval time.Duration
+}
+
+type replica struct {
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// ReplicaSet routes reads to healthy read replicas of a primary database.
+type ReplicaSet struct {
+	logger   *zap.Logger
+	replicas []*replica
+	cfg      ReplicaConfig
+	next     atomic.Uint32
+}
+
+// NewReplicaSet opens a pool for each replica url. Replicas only serve reads once
+// their lag has been measured.
+func NewReplicaSet(logger *zap.Logger, urls []string, cfg ReplicaConfig) (*ReplicaSet, error) {
+	rs := ReplicaSet{
+		logger: logger,
+		cfg:    cfg,
+	}
+
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		db, err := sqlx.Open("postgres", u)
+		if err != nil {
+			_ = rs.Close()
+			return nil, err
+		}
+		rs.replicas = append(rs.replicas, &replica{db: db})
+	}
+
+	return &rs, nil
+}
+
+// Monitor measures replication lag until the context is cancelled.
+func (rs *ReplicaSet) Monitor(ctx context.Context) {
+	if len(rs.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(rs.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		rs.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check marks each replica healthy when it streams from the primary and its lag is
+// within bounds.
+func (rs *ReplicaSet) check(ctx context.Context) {
+	for i, r := range rs.replicas {
+		var (
+			streaming bool
+			lag       float64
+		)
+
+		cctx, cancel := context.WithTimeout(ctx, rs.cfg.CheckInterval)
+		err := r.db.QueryRowxContext(cctx, replicationLag).Scan(&streaming, &lag)
+		cancel()
+
+		healthy := err == nil && rs.healthy(streaming, lag)
+		if r.healthy.Swap(healthy) != healthy {
+			rs.logger.Info("replica health changed", zap.Int("replica", i), zap.Bool("healthy", healthy), zap.Bool("streaming", streaming), zap.Float64("lag", lag), zap.Error(err))
+		}
+	}
+}
+
+// healthy reports whether a replica may serve reads given its streaming state and its
+// lag in seconds.
+func (rs *ReplicaSet) healthy(streaming bool, lag float64) bool {
+	return streaming && time.Duration(lag*float64(time.Second)) <= rs.cfg.MaxLag
+}
+
+// Read returns a healthy replica to serve a read. It reports false when the read must
+// go to the primary: no replica is healthy, the client wrote recently, or the context
+// requires the primary.
+func (rs *ReplicaSet) Read(ctx context.Context) (*sqlx.DB, bool) {
+	if len(rs.replicas) == 0 || primaryRequired(ctx) {
+		return nil, false
+	}
+
+	n := len(rs.replicas)
+	start := int(rs.next.Add(1))
+	for i := 0; i < n; i++ {
+		r := rs.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r.db, true
+		}
+	}
+	return nil, false
+}
+
+// Close closes every replica pool.
+func (rs *ReplicaSet) Close() error {
+	var err error
+	for _, r := range rs.replicas {
+		if cErr := r.db.Close(); cErr != nil {
+			err = cErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package tenantdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq" // The database driver in use.
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const mockTenantID = "ac7b523d-1eb9-43f3-bd33-c3e8106c2e70"
+
+func newTestReplicaSet(t *testing.T, urls ...string) *ReplicaSet {
+	rs, err := NewReplicaSet(zap.NewNop(), urls, ReplicaConfig{
+		MaxLag:        time.Second,
+		CheckInterval: time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rs.Close() })
+	return rs
+}
+
+func TestReplicaSet_Read(t *testing.T) {
+	tests := []struct {
+		name     string
+		urls     []string
+		healthy  []bool
+		ctx      context.Context
+		expected bool
+	}{
+		{
+			name:     "no replicas",
+			ctx:      context.Background(),
+			expected: false,
+		},
+		{
+			name:     "healthy replica",
+			urls:     []string{"postgres://replica-1/project"},
+			healthy:  []bool{true},
+			ctx:      context.Background(),
+			expected: true,
+		},
+		{
+			name:     "lagging replicas fall back to primary",
+			urls:     []string{"postgres://replica-1/project", "postgres://replica-2/project"},
+			healthy:  []bool{false, false},
+			ctx:      context.Background(),
+			expected: false,
+		},
+		{
+			name:     "one healthy replica is enough",
+			urls:     []string{"postgres://replica-1/project", "postgres://replica-2/project"},
+			healthy:  []bool{false, true},
+			ctx:      context.Background(),
+			expected: true,
+		},
+		{
+			name:     "context requires primary",
+			urls:     []string{"postgres://replica-1/project"},
+			healthy:  []bool{true},
+			ctx:      WithPrimary(context.Background()),
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := newTestReplicaSet(t, tc.urls...)
+			for i, h := range tc.healthy {
+				rs.replicas[i].healthy.Store(h)
+			}
+			db, ok := rs.Read(tc.ctx)
+			assert.Equal(t, tc.expected, ok)
+			if ok {
+				assert.NotNil(t, db)
+			}
+		})
+	}
+}
+
+func TestReplicaSet_Healthy(t *testing.T) {
+	tests := []struct {
+		name      string
+		streaming bool
+		lag       float64
+		expected  bool
+	}{
+		{
+			name:      "caught up",
+			streaming: true,
+			expected:  true,
+		},
+		{
+			name:      "lag within bounds",
+			streaming: true,
+			lag:       0.5,
+			expected:  true,
+		},
+		{
+			name:      "lagging",
+			streaming: true,
+			lag:       3,
+			expected:  false,
+		},
+		{
+			name:      "disconnected replicas are not caught up",
+			streaming: false,
+			expected:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := newTestReplicaSet(t)
+			assert.Equal(t, tc.expected, rs.healthy(tc.streaming, tc.lag))
+		})
+	}
+}
+
+func TestReadYourWrites(t *testing.T) {
+	const window = time.Minute
+
+	rs := newTestReplicaSet(t, "postgres://replica-1/project")
+	rs.replicas[0].healthy.Store(true)
+
+	// serve runs a request sending the header and reports whether its reads went to a
+	// replica before and after it wrote, along with the header of the response.
+	serve := func(header string, write bool) (before bool, after bool, sent string) {
+		h := ReadYourWrites(window)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, before = rs.Read(r.Context())
+			if write {
+				MarkWrite(r.Context())
+			}
+			_, after = rs.Read(r.Context())
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+		if header != "" {
+			req.Header.Set(PrimaryUntilHeader, header)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return before, after, rr.Header().Get(PrimaryUntilHeader)
+	}
+
+	t.Run("writes move the rest of the request to the primary", func(t *testing.T) {
+		before, after, sent := serve("", true)
+		assert.True(t, before)
+		assert.False(t, after)
+
+		until, err := time.Parse(time.RFC3339Nano, sent)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(window), until, time.Second)
+	})
+
+	t.Run("reads do not start the window", func(t *testing.T) {
+		_, after, sent := serve("", false)
+		assert.True(t, after)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("clients within the window read from the primary", func(t *testing.T) {
+		before, _, _ := serve(time.Now().Add(window/2).Format(time.RFC3339Nano), false)
+		assert.False(t, before)
+	})
+
+	t.Run("the window closes", func(t *testing.T) {
+		before, _, _ := serve(time.Now().Add(-time.Second).Format(time.RFC3339Nano), false)
+		assert.True(t, before)
+	})
+
+	t.Run("windows sent by clients are capped", func(t *testing.T) {
+		h := ReadYourWrites(window)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := r.Context().Value(writesKey{}).(*writes)
+			assert.WithinDuration(t, time.Now().Add(window), s.until, time.Second)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+		req.Header.Set(PrimaryUntilHeader, time.Now().Add(24*time.Hour).Format(time.RFC3339Nano))
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	})
+
+	t.Run("invalid headers are ignored", func(t *testing.T) {
+		before, _, _ := serve("soon", false)
+		assert.True(t, before)
+	})
+
+	t.Run("writes outside of a request are ignored", func(t *testing.T) {
+		MarkWrite(context.Background())
+		_, ok := rs.Read(context.Background())
+		assert.True(t, ok)
+	})
+}
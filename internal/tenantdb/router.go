@@ -0,0 +1,306 @@
+package tenantdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const duplicateDatabase = "42P04"
+
+// defaultMaxPooled is the number of pooled tenants a Router remembers by default.
+const defaultMaxPooled = 10000
+
+// ErrNoSilo is returned by a SiloStore when a tenant uses the pooled database.
+var ErrNoSilo = errors.New("tenant is not siloed")
+
+// Silo describes the dedicated database of a siloed tenant.
+type Silo struct {
+	TenantID  string    `db:"tenant_id"`
+	Host      string    `db:"host"`
+	Port      int       `db:"port"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SiloStore stores silo configuration for tenants.
+type SiloStore interface {
+	Lookup(ctx context.Context, tenantID string) (Silo, error)
+	Save(ctx context.Context, silo Silo) error
+}
+
+// RouterConfig configures the silo pools managed by a Router.
+type RouterConfig struct {
+	// URL returns the connection string the service uses for a silo.
+	URL func(silo Silo) string
+	// Provision prepares the database of a silo before it is first used, usually with a
+	// function returned by Provisioner. It runs once per silo at a time and is run again
+	// after a failure.
+	Provision func(ctx context.Context, silo Silo) error
+	// MaxOpenConns bounds the pool of each silo.
+	MaxOpenConns int
+	// MaxIdleConns bounds the idle connections kept for each silo.
+	MaxIdleConns int
+	// PooledTTL is how long a tenant is remembered as pooled before the store is asked again.
+	PooledTTL time.Duration
+	// MaxPooled bounds the pooled tenants remembered, since tenant ids may come from
+	// unauthenticated requests. It defaults to 10000.
+	MaxPooled int
+}
+
+// Router routes tenants to the shared pooled database or to their silo database.
+type Router struct {
+	logger *zap.Logger
+	pooled *sqlx.DB
+	store  SiloStore
+	cfg    RouterConfig
+
+	mu          sync.RWMutex
+	silos       map[string]*sqlx.DB
+	opening     map[string]*opening
+	pooledUntil map[string]time.Time
+}
+
+// opening represents a silo being provisioned. Lookups of the silo wait for done.
+type opening struct {
+	done chan struct{}
+	db   *sqlx.DB
+	err  error
+}
+
+// NewRouter returns a new Router. Tenants without silo configuration use the pooled database.
+func NewRouter(logger *zap.Logger, pooled *sqlx.DB, store SiloStore, cfg RouterConfig) *Router {
+	if cfg.MaxPooled <= 0 {
+		cfg.MaxPooled = defaultMaxPooled
+	}
+	return &Router{
+		logger:      logger,
+		pooled:      pooled,
+		store:       store,
+		cfg:         cfg,
+		silos:       make(map[string]*sqlx.DB),
+		opening:     make(map[string]*opening),
+		pooledUntil: make(map[string]time.Time),
+	}
+}
+
+// DB returns the database serving the tenant.
+func (r *Router) DB(ctx context.Context, tenantID string) (*sqlx.DB, error) {
+	r.mu.RLock()
+	db, ok := r.silos[tenantID]
+	until, pooled := r.pooledUntil[tenantID]
+	r.mu.RUnlock()
+
+	if ok {
+		return db, nil
+	}
+	if pooled && time.Now().Before(until) {
+		return r.pooled, nil
+	}
+
+	silo, err := r.store.Lookup(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, ErrNoSilo) {
+			r.rememberPooled(tenantID, time.Now())
+			return r.pooled, nil
+		}
+		r.logger.Error("silo lookup failed", zap.String("tenantID", tenantID), zap.Error(err))
+		return nil, err
+	}
+
+	return r.open(ctx, silo)
+}
+
+// rememberPooled remembers a tenant as pooled for PooledTTL. When MaxPooled tenants are
+// remembered, expired tenants are forgotten first, then any tenant, which only costs it
+// another lookup.
+func (r *Router) rememberPooled(tenantID string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pooledUntil[tenantID]; !ok && len(r.pooledUntil) >= r.cfg.MaxPooled {
+		for id, until := range r.pooledUntil {
+			if !now.Before(until) {
+				delete(r.pooledUntil, id)
+			}
+		}
+		for id := range r.pooledUntil {
+			if len(r.pooledUntil) < r.cfg.MaxPooled {
+				break
+			}
+			delete(r.pooledUntil, id)
+		}
+	}
+	r.pooledUntil[tenantID] = now.Add(r.cfg.PooledTTL)
+}
+
+// Register saves silo configuration for a tenant, migrates the silo database and
+// routes the tenant to it from now on.
+func (r *Router) Register(ctx context.Context, silo Silo) error {
+	if err := r.store.Save(ctx, silo); err != nil {
+		return err
+	}
+	_, err := r.open(ctx, silo)
+	return err
+}
+
+// open returns the pool of a silo, provisioning it on first use. The silo is provisioned
+// without holding the lock, so the lookups of other tenants do not wait for it, while
+// concurrent lookups of the same silo share a single provisioning.
+func (r *Router) open(ctx context.Context, silo Silo) (*sqlx.DB, error) {
+	r.mu.Lock()
+	if db, ok := r.silos[silo.TenantID]; ok {
+		r.mu.Unlock()
+		return db, nil
+	}
+	if o, ok := r.opening[silo.TenantID]; ok {
+		r.mu.Unlock()
+		select {
+		case <-o.done:
+			return o.db, o.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	o := &opening{done: make(chan struct{})}
+	r.opening[silo.TenantID] = o
+	r.mu.Unlock()
+
+	// Provisioning outlives the request that started it, since other requests wait for it.
+	o.db, o.err = r.provision(context.WithoutCancel(ctx), silo)
+
+	r.mu.Lock()
+	delete(r.opening, silo.TenantID)
+	if o.err == nil {
+		r.silos[silo.TenantID] = o.db
+		delete(r.pooledUntil, silo.TenantID)
+	}
+	r.mu.Unlock()
+	close(o.done)
+
+	if o.err != nil {
+		return nil, o.err
+	}
+	r.logger.Info("routing tenant to silo database", zap.String("tenantID", silo.TenantID), zap.String("database", silo.Name))
+	return o.db, nil
+}
+
+// provision prepares the database of a silo and opens its pool.
+func (r *Router) provision(ctx context.Context, silo Silo) (*sqlx.DB, error) {
+	if r.cfg.Provision != nil {
+		if err := r.cfg.Provision(ctx, silo); err != nil {
+			r.logger.Error("silo provisioning failed", zap.String("tenantID", silo.TenantID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	db, err := sqlx.Open("postgres", r.cfg.URL(silo))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(r.cfg.MaxOpenConns)
+	db.SetMaxIdleConns(r.cfg.MaxIdleConns)
+	return db, nil
+}
+
+// Close closes every silo pool. The pooled database is owned by the caller.
+func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	for id, db := range r.silos {
+		if cErr := db.Close(); cErr != nil {
+			err = cErr
+		}
+		delete(r.silos, id)
+	}
+	return err
+}
+
+// Provisioner returns a Provision function that creates the database of a silo unless it
+// exists and applies the service migrations to it. Both connect with the connection
+// string adminURL returns for a database of the silo server, which must belong to a role
+// allowed to create databases. Databases are created from the maintenance database.
+func Provisioner(adminURL func(silo Silo, database string) string, migrate func(databaseURL string) error) func(ctx context.Context, silo Silo) error {
+	return func(ctx context.Context, silo Silo) error {
+		admin, err := sqlx.Open("postgres", adminURL(silo, "postgres"))
+		if err != nil {
+			return err
+		}
+		defer admin.Close()
+
+		if err = CreateDatabase(ctx, admin, silo.Name); err != nil {
+			return err
+		}
+		return migrate(adminURL(silo, silo.Name))
+	}
+}
+
+// CreateDatabase creates a database unless it exists. A database created concurrently by
+// another process is not an error.
+func CreateDatabase(ctx context.Context, db *sqlx.DB, name string) error {
+	var exists bool
+
+	stmt := `select exists (select 1 from pg_database where datname = $1)`
+	if err := db.QueryRowxContext(ctx, stmt, name).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	// Database names cannot be passed as parameters.
+	if _, err := db.ExecContext(ctx, `create database `+pq.QuoteIdentifier(name)); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == duplicateDatabase {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// PostgresSiloStore stores silo configuration in the tenant_silos table of the pooled database.
+type PostgresSiloStore struct {
+	db *sqlx.DB
+}
+
+// NewPostgresSiloStore returns a new PostgresSiloStore.
+func NewPostgresSiloStore(db *sqlx.DB) *PostgresSiloStore {
+	return &PostgresSiloStore{db: db}
+}
+
+// Lookup retrieves the silo configuration of a tenant.
+func (ps *PostgresSiloStore) Lookup(ctx context.Context, tenantID string) (Silo, error) {
+	var s Silo
+
+	stmt := `select tenant_id, host, port, name, created_at from tenant_silos where tenant_id = $1`
+
+	if err := ps.db.QueryRowxContext(ctx, stmt, tenantID).StructScan(&s); err != nil {
+		if err == sql.ErrNoRows {
+			return s, ErrNoSilo
+		}
+		return s, err
+	}
+	return s, nil
+}
+
+// Save stores the silo configuration of a tenant.
+func (ps *PostgresSiloStore) Save(ctx context.Context, silo Silo) error {
+	stmt := `
+		insert into tenant_silos (tenant_id, host, port, name, created_at)
+		values ($1, $2, $3, $4, $5)
+		on conflict (tenant_id) do update
+		set host = excluded.host, port = excluded.port, name = excluded.name
+	`
+
+	_, err := ps.db.ExecContext(ctx, stmt, silo.TenantID, silo.Host, silo.Port, silo.Name, silo.CreatedAt.UTC())
+	return err
+}
@@ -0,0 +1,228 @@
+package tenantdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const otherTenantID = "4a9e1f63-0f3c-4f1e-9d1c-2b3f0e7c8a51"
+
+// fakeSiloStore keeps silo configuration in memory and counts lookups.
+type fakeSiloStore struct {
+	mu      sync.Mutex
+	silos   map[string]Silo
+	lookups int
+}
+
+func newFakeSiloStore(silos ...Silo) *fakeSiloStore {
+	s := &fakeSiloStore{silos: make(map[string]Silo)}
+	for _, silo := range silos {
+		s.silos[silo.TenantID] = silo
+	}
+	return s
+}
+
+func (s *fakeSiloStore) Lookup(_ context.Context, tenantID string) (Silo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lookups++
+	silo, ok := s.silos[tenantID]
+	if !ok {
+		return Silo{}, ErrNoSilo
+	}
+	return silo, nil
+}
+
+func (s *fakeSiloStore) Save(_ context.Context, silo Silo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.silos[silo.TenantID] = silo
+	return nil
+}
+
+func newTestRouter(t *testing.T, store SiloStore, provision func(ctx context.Context, silo Silo) error) (*Router, *sqlx.DB) {
+	pooled, err := sqlx.Open("postgres", "postgres://primary/project")
+	require.NoError(t, err)
+
+	r := NewRouter(zap.NewNop(), pooled, store, RouterConfig{
+		URL:          func(silo Silo) string { return "postgres://" + silo.Host + "/" + silo.Name },
+		Provision:    provision,
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		PooledTTL:    time.Minute,
+	})
+	t.Cleanup(func() {
+		_ = r.Close()
+		_ = pooled.Close()
+	})
+	return r, pooled
+}
+
+func testSilo(tenantID string) Silo {
+	return Silo{TenantID: tenantID, Host: "silo", Port: 5432, Name: "project_" + tenantID[:8]}
+}
+
+func TestRouter_DB(t *testing.T) {
+	t.Run("pooled tenants are remembered", func(t *testing.T) {
+		store := newFakeSiloStore()
+		r, pooled := newTestRouter(t, store, nil)
+
+		for i := 0; i < 3; i++ {
+			db, err := r.DB(context.Background(), mockTenantID)
+			require.NoError(t, err)
+			assert.Same(t, pooled, db)
+		}
+		assert.Equal(t, 1, store.lookups)
+	})
+
+	t.Run("pooled tenants remembered are bounded", func(t *testing.T) {
+		store := newFakeSiloStore()
+		r, _ := newTestRouter(t, store, nil)
+		r.cfg.MaxPooled = 2
+
+		for _, id := range []string{mockTenantID, otherTenantID, "9d3c2b1a-5e4f-4a7b-8c6d-0e1f2a3b4c5d"} {
+			_, err := r.DB(context.Background(), id)
+			require.NoError(t, err)
+		}
+		assert.Len(t, r.pooledUntil, 2)
+		assert.Equal(t, 3, store.lookups)
+	})
+
+	t.Run("siloed tenants are provisioned once", func(t *testing.T) {
+		var provisioned int32
+		store := newFakeSiloStore(testSilo(mockTenantID))
+		r, pooled := newTestRouter(t, store, func(ctx context.Context, silo Silo) error {
+			atomic.AddInt32(&provisioned, 1)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+
+		var (
+			wg  sync.WaitGroup
+			dbs = make([]*sqlx.DB, 10)
+		)
+		for i := range dbs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				db, err := r.DB(context.Background(), mockTenantID)
+				assert.NoError(t, err)
+				dbs[i] = db
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&provisioned))
+		assert.NotSame(t, pooled, dbs[0])
+		for _, db := range dbs {
+			assert.Same(t, dbs[0], db)
+		}
+	})
+
+	t.Run("provisioning does not block other tenants", func(t *testing.T) {
+		release := make(chan struct{})
+		store := newFakeSiloStore(testSilo(mockTenantID))
+		r, pooled := newTestRouter(t, store, func(ctx context.Context, silo Silo) error {
+			<-release
+			return nil
+		})
+
+		siloed := make(chan error, 1)
+		go func() {
+			_, err := r.DB(context.Background(), mockTenantID)
+			siloed <- err
+		}()
+
+		done := make(chan *sqlx.DB, 1)
+		go func() {
+			db, err := r.DB(context.Background(), otherTenantID)
+			assert.NoError(t, err)
+			done <- db
+		}()
+
+		select {
+		case db := <-done:
+			assert.Same(t, pooled, db)
+		case <-time.After(time.Second):
+			t.Fatal("pooled tenant waited for a silo being provisioned")
+		}
+
+		close(release)
+		assert.NoError(t, <-siloed)
+	})
+
+	t.Run("waiting lookups give up with their context", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		store := newFakeSiloStore(testSilo(mockTenantID))
+		r, _ := newTestRouter(t, store, func(ctx context.Context, silo Silo) error {
+			<-release
+			return nil
+		})
+
+		go func() { _, _ = r.DB(context.Background(), mockTenantID) }()
+		require.Eventually(t, func() bool {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.opening[mockTenantID] != nil
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := r.DB(ctx, mockTenantID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("failed provisioning is retried", func(t *testing.T) {
+		var calls int32
+		store := newFakeSiloStore(testSilo(mockTenantID))
+		r, pooled := newTestRouter(t, store, func(ctx context.Context, silo Silo) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return errors.New("database unavailable")
+			}
+			return nil
+		})
+
+		_, err := r.DB(context.Background(), mockTenantID)
+		assert.Error(t, err)
+
+		db, err := r.DB(context.Background(), mockTenantID)
+		require.NoError(t, err)
+		assert.NotSame(t, pooled, db)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestRouter_Register(t *testing.T) {
+	store := newFakeSiloStore()
+	var provisioned []string
+	r, pooled := newTestRouter(t, store, func(ctx context.Context, silo Silo) error {
+		provisioned = append(provisioned, silo.Name)
+		return nil
+	})
+
+	// The tenant is first remembered as pooled.
+	db, err := r.DB(context.Background(), mockTenantID)
+	require.NoError(t, err)
+	require.Same(t, pooled, db)
+
+	silo := testSilo(mockTenantID)
+	require.NoError(t, r.Register(context.Background(), silo))
+
+	db, err = r.DB(context.Background(), mockTenantID)
+	require.NoError(t, err)
+	assert.NotSame(t, pooled, db)
+	assert.Equal(t, []string{silo.Name}, provisioned)
+	assert.Equal(t, silo, store.silos[mockTenantID])
+}
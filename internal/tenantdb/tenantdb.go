@@ -0,0 +1,148 @@
+// Package tenantdb binds pooled postgres connections and transactions to a tenant.
+//
+// Row level security policies read the tenant from the app.current_tenant setting.
+// The setting is always passed as a query parameter, scoped to the transaction when
+// possible, and cleared before a connection is returned to the pool.
+package tenantdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	bindSession = `select set_config('app.current_tenant', $1, false)`
+	bindLocal   = `select set_config('app.current_tenant', $1, true)`
+	resetConn   = `select set_config('app.current_tenant', '', false); reset role`
+
+	// releaseTimeout bounds the reset performed when a connection is released.
+	releaseTimeout = 5 * time.Second
+
+	// maxAttempts bounds how often a conflicting transaction is run.
+	maxAttempts = 3
+
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// Conn acquires a connection bound to the tenant. The returned release function clears
+// the binding and any role change before the connection goes back to the pool.
+// A connection that cannot be cleared is discarded.
+func Conn(ctx context.Context, logger *zap.Logger, db *sqlx.DB, tenantID string) (*sqlx.Conn, func() error, error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		logger.Error("connection failed", zap.Error(err))
+		return nil, nil, err
+	}
+
+	release := func() error {
+		return releaseConn(logger, conn)
+	}
+
+	if _, err = conn.ExecContext(ctx, bindSession, tenantID); err != nil {
+		logger.Error("setting session variable failed", zap.Error(err))
+		_ = release()
+		return nil, nil, err
+	}
+
+	return conn, release, nil
+}
+
+// releaseConn clears session state and returns the connection to the pool.
+func releaseConn(logger *zap.Logger, conn *sqlx.Conn) error {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	if _, err := conn.ExecContext(ctx, resetConn); err != nil {
+		logger.Error("resetting session failed, discarding connection", zap.Error(err))
+		// Returning driver.ErrBadConn from Raw removes the connection from the pool.
+		_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		return err
+	}
+	return conn.Close()
+}
+
+// BeginTx starts a transaction bound to the tenant. The binding ends with the transaction.
+func BeginTx(ctx context.Context, logger *zap.Logger, db *sqlx.DB, tenantID string, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	tx, err := db.BeginTxx(ctx, opts)
+	if err != nil {
+		logger.Error("begin transaction failed", zap.Error(err))
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, bindLocal, tenantID); err != nil {
+		logger.Error("setting transaction variable failed", zap.Error(err))
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Run runs fn in the transaction. The transaction is committed when fn succeeds
+// and rolled back when fn fails or panics.
+func Run(logger *zap.Logger, tx *sqlx.Tx, fn func(*sqlx.Tx) error) error {
+	defer func() {
+		if err := recover(); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				logger.Info("tx.Rollback panicked", zap.Error(rbErr))
+			}
+			panic(err)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logger.Info("tx.Rollback failed", zap.Error(rbErr))
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// RunInTx runs fn in a new transaction bound to the tenant.
+func RunInTx(ctx context.Context, logger *zap.Logger, db *sqlx.DB, tenantID string, opts *sql.TxOptions, fn func(*sqlx.Tx) error) error {
+	tx, err := BeginTx(ctx, logger, db, tenantID, opts)
+	if err != nil {
+		return err
+	}
+	return Run(logger, tx, fn)
+}
+
+// RetryInTx runs fn in a new transaction bound to the tenant like RunInTx. Transactions
+// that lose a serialization conflict or deadlock with a concurrent one are run again,
+// so fn must be safe to call more than once.
+func RetryInTx(ctx context.Context, logger *zap.Logger, db *sqlx.DB, tenantID string, opts *sql.TxOptions, fn func(*sqlx.Tx) error) error {
+	return retry(logger, func() error {
+		return RunInTx(ctx, logger, db, tenantID, opts, fn)
+	})
+}
+
+// retry calls run until it succeeds, fails for a reason other than a concurrent
+// transaction or has been called maxAttempts times.
+func retry(logger *zap.Logger, run func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = run(); !retryable(err) {
+			return err
+		}
+		logger.Info("retrying conflicting transaction", zap.Int("attempt", attempt), zap.Error(err))
+	}
+	return err
+}
+
+// retryable reports whether a transaction failed only because of a concurrent one.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected
+	}
+	return false
+}
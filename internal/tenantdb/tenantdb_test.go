@@ -0,0 +1,62 @@
+package tenantdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRetry(t *testing.T) {
+	conflict := &pq.Error{Code: serializationFailure}
+
+	tests := []struct {
+		name     string
+		errs     []error
+		expected error
+		attempts int
+	}{
+		{
+			name:     "success",
+			errs:     []error{nil},
+			attempts: 1,
+		},
+		{
+			name:     "serialization failure is retried",
+			errs:     []error{conflict, nil},
+			attempts: 2,
+		},
+		{
+			name:     "deadlock is retried",
+			errs:     []error{&pq.Error{Code: deadlockDetected}, nil},
+			attempts: 2,
+		},
+		{
+			name:     "other errors are not retried",
+			errs:     []error{errors.New("boom"), nil},
+			expected: errors.New("boom"),
+			attempts: 1,
+		},
+		{
+			name:     "attempts are bounded",
+			errs:     []error{conflict, conflict, conflict, nil},
+			expected: conflict,
+			attempts: maxAttempts,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int
+			err := retry(zap.NewNop(), func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			})
+			assert.Equal(t, tc.expected, err)
+			assert.Equal(t, tc.attempts, attempts)
+		})
+	}
+}
@@ -30,6 +30,22 @@ type Config struct {
 		Port       int    `conf:"default:5432,noprint"`
 		Name       string `conf:"default:user,noprint"`
 		DisableTLS bool   `conf:"default:false"`
+		// Siloed tenants get a dedicated database on SiloHost named after the tenant. It is
+		// created and migrated by SiloAdminUser, which must be allowed to create databases.
+		SiloHost          string        `conf:"noprint"`
+		SiloPort          int           `conf:"default:5432,noprint"`
+		SiloAdminUser     string        `conf:"default:postgres,noprint"`
+		SiloAdminPassword string        `conf:"required,noprint"`
+		SiloMaxOpenConns  int           `conf:"default:5"`
+		SiloMaxIdleConns  int           `conf:"default:2"`
+		SiloLookupTTL     time.Duration `conf:"default:1m"`
+		// Replicas lists optional read replica urls separated by semicolons. After writing, a
+		// client reads from the primary for ReadYourWritesWindow, as long as it sends back the
+		// X-Primary-Until header.
+		Replicas             []string      `conf:"noprint"`
+		ReplicaMaxLag        time.Duration `conf:"default:2s"`
+		ReplicaCheckInterval time.Duration `conf:"default:1s"`
+		ReadYourWritesWindow time.Duration `conf:"default:5s"`
 	}
 	Dynamodb struct {
 		ConnectionTable string `conf:"required"`
@@ -6,8 +6,13 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
+	"github.com/devpies/saas-core/internal/tenantdb"
 	"github.com/devpies/saas-core/internal/user/config"
+	"github.com/devpies/saas-core/internal/user/fail"
+	"github.com/devpies/saas-core/internal/user/res"
 	"github.com/devpies/saas-core/pkg/web"
 
 	"github.com/jmoiron/sqlx"
@@ -18,13 +23,74 @@ import (
 
 // PostgresDatabase represents a database connection.
 type PostgresDatabase struct {
-	dB     *sqlx.DB
-	logger *zap.Logger
-	URL    url.URL
+	dB       *sqlx.DB
+	router   *tenantdb.Router
+	replicas *tenantdb.ReplicaSet
+	cfg      config.Config
+	logger   *zap.Logger
+	URL      url.URL
 }
 
 // NewPostgresDatabase creates a new postgres database.
 func NewPostgresDatabase(logger *zap.Logger, cfg config.Config) (*PostgresDatabase, func() error, error) {
+	u := databaseURL(cfg, url.UserPassword(cfg.DB.User, cfg.DB.Password), cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
+
+	db, err := sqlx.Open("postgres", u.String())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "connecting to database")
+	}
+
+	router := tenantdb.NewRouter(logger, db, tenantdb.NewPostgresSiloStore(db), tenantdb.RouterConfig{
+		URL: func(silo tenantdb.Silo) string {
+			su := databaseURL(cfg, url.UserPassword(cfg.DB.User, cfg.DB.Password), silo.Host, silo.Port, silo.Name)
+			return su.String()
+		},
+		Provision: tenantdb.Provisioner(func(silo tenantdb.Silo, database string) string {
+			au := databaseURL(cfg, url.UserPassword(cfg.DB.SiloAdminUser, cfg.DB.SiloAdminPassword), silo.Host, silo.Port, database)
+			return au.String()
+		}, res.MigrateUp),
+		MaxOpenConns: cfg.DB.SiloMaxOpenConns,
+		MaxIdleConns: cfg.DB.SiloMaxIdleConns,
+		PooledTTL:    cfg.DB.SiloLookupTTL,
+	})
+
+	replicas, err := tenantdb.NewReplicaSet(logger, cfg.DB.Replicas, tenantdb.ReplicaConfig{
+		MaxLag:        cfg.DB.ReplicaMaxLag,
+		CheckInterval: cfg.DB.ReplicaCheckInterval,
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, errors.Wrap(err, "connecting to replicas")
+	}
+
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	go replicas.Monitor(monitorCtx)
+
+	r := &PostgresDatabase{
+		logger:   logger,
+		dB:       db,
+		router:   router,
+		replicas: replicas,
+		cfg:      cfg,
+		URL:      u,
+	}
+
+	Close := func() error {
+		stopMonitor()
+		if err := replicas.Close(); err != nil {
+			logger.Error("closing replica databases failed", zap.Error(err))
+		}
+		if err := router.Close(); err != nil {
+			logger.Error("closing silo databases failed", zap.Error(err))
+		}
+		return db.Close()
+	}
+
+	return r, Close, nil
+}
+
+// databaseURL returns the connection url for a database.
+func databaseURL(cfg config.Config, user *url.Userinfo, host string, port int, name string) url.URL {
 	sslMode := "require"
 	if cfg.DB.DisableTLS {
 		sslMode = "disable"
@@ -34,29 +100,54 @@ func NewPostgresDatabase(logger *zap.Logger, cfg config.Config) (*PostgresDataba
 	q.Set("sslmode", sslMode)
 	q.Set("timezone", "utc")
 
-	u := url.URL{
+	return url.URL{
 		Scheme:   "postgres",
-		User:     url.UserPassword(cfg.DB.User, cfg.DB.Password),
-		Host:     fmt.Sprintf("%s:%d", cfg.DB.Host, cfg.DB.Port),
-		Path:     cfg.DB.Name,
+		User:     user,
+		Host:     fmt.Sprintf("%s:%d", host, port),
+		Path:     name,
 		RawQuery: q.Encode(),
 	}
+}
 
-	db, err := sqlx.Open("postgres", u.String())
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "connecting to database")
+// RegisterSilo routes a siloed tenant to its dedicated database and migrates it.
+func (pg *PostgresDatabase) RegisterSilo(ctx context.Context, tenantID string, now time.Time) error {
+	host := pg.cfg.DB.SiloHost
+	if host == "" {
+		host = pg.cfg.DB.Host
 	}
 
-	r := &PostgresDatabase{
-		logger: logger,
-		dB:     db,
-		URL:    u,
+	silo := tenantdb.Silo{
+		TenantID:  tenantID,
+		Host:      host,
+		Port:      pg.cfg.DB.SiloPort,
+		Name:      fmt.Sprintf("%s_%s", pg.cfg.DB.Name, strings.ReplaceAll(tenantID, "-", "")),
+		CreatedAt: now,
 	}
+	return pg.router.Register(ctx, silo)
+}
+
+// Primary returns a context whose reads are served by the primary database.
+func Primary(ctx context.Context) context.Context {
+	return tenantdb.WithPrimary(ctx)
+}
 
-	return r, db.Close, nil
+// Wrote starts the read your writes window of the client once a statement run on a
+// connection returned by GetConnection has changed data. Transactions run by
+// RunInTransaction start it themselves.
+func Wrote(ctx context.Context) {
+	tenantdb.MarkWrite(ctx)
+}
+
+// tenantDB returns the database serving the tenant.
+func (pg *PostgresDatabase) tenantDB(ctx context.Context, tenantID string) (*sqlx.DB, error) {
+	db, err := pg.router.DB(ctx, tenantID)
+	if err != nil {
+		return nil, fail.ErrConnectionFailed
+	}
+	return db, nil
 }
 
-// GetConnection returns a tenant aware connection.
+// GetConnection returns a tenant aware connection to the primary database.
 func (pg *PostgresDatabase) GetConnection(ctx context.Context) (*sqlx.Conn, func() error, error) {
 	values, ok := web.FromContext(ctx)
 	if !ok {
@@ -64,56 +155,74 @@ func (pg *PostgresDatabase) GetConnection(ctx context.Context) (*sqlx.Conn, func
 		return nil, nil, web.CtxErr()
 	}
 
-	conn, err := pg.dB.Connx(ctx)
+	if values.TenantID == "" {
+		return nil, nil, fail.ErrNoTenant
+	}
+
+	db, err := pg.tenantDB(ctx, values.TenantID)
 	if err != nil {
-		pg.logger.Error("connection failed", zap.Error(err))
-		_ = conn.Close()
 		return nil, nil, err
 	}
 
-	stmt := fmt.Sprintf("select set_config('app.current_tenant', '%s', false);", values.TenantID)
-	_, err = conn.ExecContext(ctx, stmt)
+	return tenantdb.Conn(ctx, pg.logger, db, values.TenantID)
+}
+
+// GetReadConnection returns a tenant aware connection for read only queries.
+// Reads of pooled tenants go to a healthy replica unless the client wrote recently
+// or the context requires the primary.
+func (pg *PostgresDatabase) GetReadConnection(ctx context.Context) (*sqlx.Conn, func() error, error) {
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		pg.logger.Error("invalid context values")
+		return nil, nil, web.CtxErr()
+	}
+
+	if values.TenantID == "" {
+		return nil, nil, fail.ErrNoTenant
+	}
+
+	db, err := pg.tenantDB(ctx, values.TenantID)
 	if err != nil {
-		pg.logger.Error("setting session variable failed", zap.Error(err))
-		_ = conn.Close()
 		return nil, nil, err
 	}
-	return conn, conn.Close, nil
+
+	if db == pg.dB {
+		if replica, ok := pg.replicas.Read(ctx); ok {
+			db = replica
+		}
+	}
+
+	return tenantdb.Conn(ctx, pg.logger, db, values.TenantID)
+}
+
+// TestsOnlyDBConnection returns a database connection for tests.
+func (pg *PostgresDatabase) TestsOnlyDBConnection() *sql.DB {
+	return pg.dB.DB
 }
 
-// RunInTransaction runs callback function in a transaction.
+// RunInTransaction runs callback function in a tenant aware transaction. A committed
+// transaction starts the read your writes window of the client.
 func (pg *PostgresDatabase) RunInTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error {
-	conn, Close, err := pg.GetConnection(ctx)
-	if err != nil {
-		return err
+	values, ok := web.FromContext(ctx)
+	if !ok {
+		pg.logger.Error("invalid context values")
+		return web.CtxErr()
 	}
-	defer Close()
 
-	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if values.TenantID == "" {
+		return fail.ErrNoTenant
+	}
+
+	db, err := pg.tenantDB(ctx, values.TenantID)
 	if err != nil {
 		return err
 	}
 
-	return pg.txRun(tx, fn)
-}
-
-func (pg *PostgresDatabase) txRun(tx *sqlx.Tx, fn func(*sqlx.Tx) error) error {
-	defer func() {
-		if err := recover(); err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				pg.logger.Info("tx.Rollback panicked", zap.Error(rbErr))
-			}
-			panic(err)
-		}
-	}()
-
-	if err := fn(tx); err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			pg.logger.Info("tx.Rollback failed", zap.Error(rbErr))
-		}
+	if err = tenantdb.RunInTx(ctx, pg.logger, db, values.TenantID, &sql.TxOptions{Isolation: sql.LevelSerializable}, fn); err != nil {
 		return err
 	}
-	return tx.Commit()
+	Wrote(ctx)
+	return nil
 }
 
 // StatusCheck returns nil if it can successfully talk to the database. It returns a non-nil error otherwise.
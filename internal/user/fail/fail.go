@@ -10,6 +10,8 @@ var (
 	ErrInvalidID = errors.New("id provided was not a valid UUID")
 	// ErrInvalidEmail represents an invalid email.
 	ErrInvalidEmail = errors.New("email was not valid")
+	// ErrNoTenant represents a failure to retrieve the tenant.
+	ErrNoTenant = errors.New("missing tenant id")
 	// ErrConnectionFailed represents a failed connection attempt.
 	ErrConnectionFailed = errors.New("connection failed")
 	// ErrUserAlreadyAdded represents a failed attempt to add the same user a second time.
@@ -69,6 +69,7 @@ func (ir *InviteRepository) Create(ctx context.Context, ni model.NewInvite, now
 	); err != nil {
 		return i, err
 	}
+	db.Wrote(ctx)
 
 	return i, nil
 }
@@ -92,7 +93,7 @@ func (ir *InviteRepository) RetrieveInvite(ctx context.Context, iid string) (mod
 		return i, fail.ErrInvalidID
 	}
 
-	conn, Close, err := ir.pg.GetConnection(ctx)
+	conn, Close, err := ir.pg.GetReadConnection(ctx)
 	if err != nil {
 		return i, fail.ErrConnectionFailed
 	}
@@ -132,7 +133,7 @@ func (ir *InviteRepository) RetrieveInvites(ctx context.Context) ([]model.Invite
 		return is, fail.ErrInvalidID
 	}
 
-	conn, Close, err := ir.pg.GetConnection(ctx)
+	conn, Close, err := ir.pg.GetReadConnection(ctx)
 	if err != nil {
 		return is, fail.ErrConnectionFailed
 	}
@@ -171,7 +172,7 @@ func (ir *InviteRepository) Update(ctx context.Context, update model.UpdateInvit
 		return i, web.CtxErr()
 	}
 
-	i, err = ir.RetrieveInvite(ctx, iid)
+	i, err = ir.RetrieveInvite(db.Primary(ctx), iid)
 	if err != nil {
 		return i, fail.ErrNotFound
 	}
@@ -198,6 +199,7 @@ func (ir *InviteRepository) Update(ctx context.Context, update model.UpdateInvit
 	if err != nil {
 		return i, err
 	}
+	db.Wrote(ctx)
 
 	return i, nil
 }
@@ -0,0 +1,22 @@
+package repository_test
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/user/res/testutils"
+)
+
+var dbConnect *testutils.DatabaseClient
+
+var port = flag.String("port", "5432", "database port to use in integration tests")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	db, dbClose := testutils.NewDatabaseClient(*port)
+	dbConnect = db
+	defer dbClose()
+
+	os.Exit(m.Run())
+}
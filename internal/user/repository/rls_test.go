@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/user/fail"
+	"github.com/devpies/saas-core/internal/user/repository"
+	"github.com/devpies/saas-core/internal/user/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testTenantID = "f24d653a-f465-11ec-bfa4-26b2e5d16858"
+
+var rlsTables = []string{"users", "invites"}
+
+func TestRowLevelSecurity_CrossTenantReads(t *testing.T) {
+	otherTenant := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testutils.MockUUID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	t.Run("invites", func(t *testing.T) {
+		list, err := repository.NewInviteRepository(zap.NewNop(), db).RetrieveInvites(otherTenant)
+		assert.Nil(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("tables", func(t *testing.T) {
+		conn, release, err := db.GetConnection(otherTenant)
+		require.NoError(t, err)
+		defer release()
+
+		for _, table := range rlsTables {
+			assert.Equal(t, 0, countRows(t, otherTenant, conn, table), table)
+		}
+	})
+}
+
+func TestRowLevelSecurity_TenantBinding(t *testing.T) {
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testTenantID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	// A single pooled connection guarantees the next checkout reuses the same session.
+	db.TestsOnlyDBConnection().SetMaxOpenConns(1)
+
+	t.Run("connection binding is reset on release", func(t *testing.T) {
+		conn, release, err := db.GetConnection(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, countRows(t, ctx, conn, "users"))
+		require.NoError(t, release())
+
+		var count int
+		err = db.TestsOnlyDBConnection().QueryRowContext(ctx, `select count(*) from users`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("transaction binding ends with the transaction", func(t *testing.T) {
+		err := db.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+			var count int
+			if err := tx.QueryRowxContext(ctx, `select count(*) from users`).Scan(&count); err != nil {
+				return err
+			}
+			assert.Equal(t, 2, count)
+			return nil
+		})
+		require.NoError(t, err)
+
+		var count int
+		err = db.TestsOnlyDBConnection().QueryRowContext(ctx, `select count(*) from users`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("transaction requires a tenant", func(t *testing.T) {
+		noTenant := web.NewContext(testutils.MockCtx, &web.Values{})
+		err := db.RunInTransaction(noTenant, func(tx *sqlx.Tx) error { return nil })
+		assert.Equal(t, fail.ErrNoTenant, err)
+	})
+
+	t.Run("tenant id is not interpolated", func(t *testing.T) {
+		injected := web.NewContext(testutils.MockCtx, &web.Values{TenantID: "x', false); select set_config('app.current_tenant', '" + testTenantID})
+		conn, release, err := db.GetConnection(injected)
+		require.NoError(t, err)
+		defer release()
+
+		for _, table := range rlsTables {
+			assert.Equal(t, 0, countRows(t, injected, conn, table), table)
+		}
+	})
+}
+
+func countRows(t *testing.T, ctx context.Context, conn *sqlx.Conn, table string) int {
+	var count int
+	err := conn.QueryRowxContext(ctx, `select count(*) from `+table).Scan(&count)
+	require.NoError(t, err)
+	return count
+}
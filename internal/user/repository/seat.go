@@ -56,7 +56,7 @@ func (sr *SeatRepository) FindSeatsAvailable(ctx context.Context) (model.Seats,
 		err error
 	)
 
-	conn, Close, err := sr.pg.GetConnection(ctx)
+	conn, Close, err := sr.pg.GetReadConnection(ctx)
 	if err != nil {
 		return s, fail.ErrConnectionFailed
 	}
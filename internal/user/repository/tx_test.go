@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devpies/saas-core/internal/user/res/testutils"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInTransaction_SerializationFailure(t *testing.T) {
+	const userID = "0ef64d03-8a91-4513-907c-dd1fcfcfeb46"
+	ctx := web.NewContext(testutils.MockCtx, &web.Values{TenantID: testTenantID})
+
+	db, Close := dbConnect.AsNonRoot()
+	defer Close()
+
+	var attempts int
+	err := db.RunInTransaction(ctx, func(tx *sqlx.Tx) error {
+		attempts++
+
+		var count int
+		if err := tx.QueryRowxContext(ctx, `select count(*) from users where user_id = $1`, userID).Scan(&count); err != nil {
+			return err
+		}
+
+		// A transaction committed after the first one read the user makes its update
+		// fail with a serialization failure.
+		err := db.RunInTransaction(ctx, func(other *sqlx.Tx) error {
+			_, err := other.ExecContext(ctx, `update users set created_at = now() where user_id = $1`, userID)
+			return err
+		})
+		if err != nil {
+			return errors.New("concurrent update failed: " + err.Error())
+		}
+
+		_, err = tx.ExecContext(ctx, `update users set created_at = now() where user_id = $1`, userID)
+		return err
+	})
+
+	// Transactions of the user service are not run again.
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	assert.Equal(t, pq.ErrorCode("40001"), pqErr.Code)
+	assert.Equal(t, 1, attempts)
+}
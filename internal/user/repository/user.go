@@ -113,6 +113,7 @@ func (ur *UserRepository) CreateUserProfile(ctx context.Context, nu model.NewUse
 	); err != nil {
 		return u, err
 	}
+	db.Wrote(ctx)
 
 	return u, nil
 }
@@ -168,7 +169,7 @@ func (ur *UserRepository) List(ctx context.Context) ([]model.User, error) {
 		err error
 	)
 
-	conn, Close, err := ur.pg.GetConnection(ctx)
+	conn, Close, err := ur.pg.GetReadConnection(ctx)
 	if err != nil {
 		return us, fail.ErrConnectionFailed
 	}
@@ -202,7 +203,7 @@ func (ur *UserRepository) RetrieveIDByEmail(ctx context.Context, email string) (
 		return "", fail.ErrInvalidEmail
 	}
 
-	conn, Close, err := ur.pg.GetConnection(ctx)
+	conn, Close, err := ur.pg.GetReadConnection(ctx)
 	if err != nil {
 		return "", fail.ErrConnectionFailed
 	}
@@ -228,7 +229,7 @@ func (ur *UserRepository) RetrieveByEmail(ctx context.Context, email string) (mo
 		return u, fail.ErrInvalidEmail
 	}
 
-	conn, Close, err := ur.pg.GetConnection(ctx)
+	conn, Close, err := ur.pg.GetReadConnection(ctx)
 	if err != nil {
 		return u, fail.ErrConnectionFailed
 	}
@@ -267,7 +268,7 @@ func (ur *UserRepository) RetrieveMe(ctx context.Context) (model.User, error) {
 		return u, fail.ErrInvalidID
 	}
 
-	conn, Close, err := ur.pg.GetConnection(ctx)
+	conn, Close, err := ur.pg.GetReadConnection(ctx)
 	if err != nil {
 		return u, fail.ErrConnectionFailed
 	}
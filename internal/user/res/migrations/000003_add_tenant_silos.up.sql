@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS tenant_silos (
+    tenant_id VARCHAR(36) PRIMARY KEY,
+    host TEXT NOT NULL,
+    port INT NOT NULL DEFAULT 5432,
+    name VARCHAR(63) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+GRANT ALL ON tenant_silos TO user_a;
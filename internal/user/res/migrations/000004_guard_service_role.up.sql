@@ -0,0 +1,10 @@
+-- Roles are shared by every database of the server, so 000001 only creates user_a on a
+-- server where it is missing and leaves the statement out elsewhere, like in silo
+-- databases created next to the pooled one. Make sure the role exists either way.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'user_a') THEN
+        CREATE USER user_a WITH PASSWORD 'postgres';
+    END IF;
+END
+$$;
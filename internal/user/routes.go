@@ -3,7 +3,9 @@ package user
 import (
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/devpies/saas-core/internal/tenantdb"
 	"github.com/devpies/saas-core/internal/user/handler"
 	"github.com/devpies/saas-core/pkg/web"
 	"github.com/devpies/saas-core/pkg/web/mid"
@@ -19,6 +21,7 @@ func Routes(
 	shutdown chan os.Signal,
 	region string,
 	sharedUserPoolID string,
+	readYourWritesWindow time.Duration,
 	userHandler *handler.UserHandler,
 	inviteHandler *handler.InviteHandler,
 ) http.Handler {
@@ -26,10 +29,12 @@ func Routes(
 	mux.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://devpie.local:3000", "https://devpie.io"},
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "BasePath"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "BasePath", tenantdb.PrimaryUntilHeader},
+		ExposedHeaders:   []string{tenantdb.PrimaryUntilHeader},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	mux.Use(tenantdb.ReadYourWrites(readYourWritesWindow))
 
 	middleware := []web.Middleware{
 		mid.Logger(log),
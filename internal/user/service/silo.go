@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/devpies/saas-core/pkg/msg"
+	"github.com/devpies/saas-core/pkg/web"
+
+	"go.uber.org/zap"
+)
+
+type siloRegistry interface {
+	RegisterSilo(ctx context.Context, tenantID string, now time.Time) error
+}
+
+// SiloService is responsible for routing siloed tenants to dedicated databases.
+type SiloService struct {
+	logger   *zap.Logger
+	registry siloRegistry
+}
+
+// NewSiloService returns a new SiloService.
+func NewSiloService(logger *zap.Logger, registry siloRegistry) *SiloService {
+	return &SiloService{
+		logger:   logger,
+		registry: registry,
+	}
+}
+
+// RegisterSiloFromEvent provisions the dedicated database of a siloed tenant from a message.
+func (ss *SiloService) RegisterSiloFromEvent(ctx context.Context, message interface{}) error {
+	m, err := msg.Bytes(message)
+	if err != nil {
+		return err
+	}
+
+	event, err := msg.UnmarshalTenantSiloedEvent(m)
+	if err != nil {
+		return err
+	}
+
+	if event.Metadata.TenantID == "" {
+		ss.logger.Error("siloed tenant event is missing a tenant id", zap.String("tenantName", event.Data.TenantName))
+		return web.NewRequestError(errors.New("missing tenant id"), http.StatusBadRequest)
+	}
+
+	return ss.registry.RegisterSilo(ctx, event.Metadata.TenantID, time.Now())
+}
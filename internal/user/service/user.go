@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/devpies/saas-core/internal/tenantdb"
 	"github.com/devpies/saas-core/internal/user/model"
 	"github.com/devpies/saas-core/pkg/msg"
 	"github.com/devpies/saas-core/pkg/web"
@@ -103,7 +104,7 @@ func (us *UserService) AddUser(ctx context.Context, nu model.NewUser, now time.T
 	}
 
 	// Verify user doesn't belong to the tenant already.
-	_, err = us.userRepo.RetrieveByEmail(ctx, nu.Email)
+	_, err = us.userRepo.RetrieveByEmail(tenantdb.WithPrimary(ctx), nu.Email)
 	if err == nil {
 		us.logger.Info("user already connected to tenant")
 		return web.NewRequestError(fmt.Errorf("user already added"), http.StatusBadRequest)
@@ -82,6 +82,7 @@ func Run() error {
 
 	userService := service.NewUserService(logger, userRepo, seatRepo, cognitoClient, connections, cfg.Cognito.SharedUserPoolID)
 	inviteService := service.NewInviteService(logger, inviteRepo)
+	siloService := service.NewSiloService(logger, pg)
 
 	userHandler := handler.NewUserHandler(logger, userService)
 	inviteHandler := handler.NewInviteHandler(logger, inviteService)
@@ -96,11 +97,21 @@ func Run() error {
 			opts...)
 	}()
 
+	// Route siloed tenants to their dedicated databases.
+	go func() {
+		jetstream.Listen(
+			string(msg.TypeTenantSiloed),
+			msg.SubjectTenantSiloed,
+			"user_silo_consumer",
+			siloService.RegisterSiloFromEvent,
+			opts...)
+	}()
+
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Web.Port),
 		WriteTimeout: cfg.Web.WriteTimeout,
 		ReadTimeout:  cfg.Web.ReadTimeout,
-		Handler:      Routes(logger, shutdown, cfg.Cognito.Region, cfg.Cognito.SharedUserPoolID, userHandler, inviteHandler),
+		Handler:      Routes(logger, shutdown, cfg.Cognito.Region, cfg.Cognito.SharedUserPoolID, cfg.DB.ReadYourWritesWindow, userHandler, inviteHandler),
 	}
 
 	go func() {
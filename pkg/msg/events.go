@@ -40,6 +40,30 @@ func (m *TenantRegisteredEvent) Marshal() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// UnmarshalProjectTeamAssignedEvent parses the JSON-encoded data and returns ProjectTeamAssignedEvent.
+func UnmarshalProjectTeamAssignedEvent(data []byte) (ProjectTeamAssignedEvent, error) {
+	var m ProjectTeamAssignedEvent
+	err := json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Marshal JSON encodes ProjectTeamAssignedEvent.
+func (m *ProjectTeamAssignedEvent) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// UnmarshalProjectChangedEvent parses the JSON-encoded data and returns ProjectChangedEvent.
+func UnmarshalProjectChangedEvent(data []byte) (ProjectChangedEvent, error) {
+	var m ProjectChangedEvent
+	err := json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Marshal JSON encodes ProjectChangedEvent.
+func (m *ProjectChangedEvent) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
 // TenantRegistered is a valid MessageType.
 const TenantRegistered MessageType = "TenantRegistered"
 
@@ -119,3 +143,62 @@ type TenantIdentityCreatedEventData struct {
 	Plan      string `json:"plan"`
 	CreatedAt string `json:"createdAt"`
 }
+
+// ProjectTeamAssigned is a valid MessageType.
+const ProjectTeamAssigned MessageType = "ProjectTeamAssigned"
+
+const (
+	// TypeProjectTeamAssigned represents a concrete value for the ProjectTeamAssignedType.
+	TypeProjectTeamAssigned ProjectTeamAssignedType = "ProjectTeamAssigned"
+)
+
+// ProjectTeamAssignedType represents a ProjectTeamAssigned Message.
+type ProjectTeamAssignedType string
+
+// ProjectTeamAssignedEvent is published whenever the team or the members of a project change.
+type ProjectTeamAssignedEvent struct {
+	Metadata Metadata                     `json:"metadata"`
+	Type     ProjectTeamAssignedType      `json:"type"`
+	Data     ProjectTeamAssignedEventData `json:"data"`
+}
+
+type ProjectTeamAssignedEventData struct {
+	ProjectID string                `json:"projectId"`
+	TeamID    string                `json:"teamId"`
+	Members   []ProjectMemberRecord `json:"members"`
+	UpdatedAt string                `json:"updatedAt"`
+}
+
+// ProjectMemberRecord represents a project member and its role.
+type ProjectMemberRecord struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// ProjectChanged is a valid MessageType.
+const ProjectChanged MessageType = "ProjectChanged"
+
+const (
+	// TypeProjectChanged represents a concrete value for the ProjectChangedType.
+	TypeProjectChanged ProjectChangedType = "ProjectChanged"
+)
+
+// ProjectChangedType represents a ProjectChanged Message.
+type ProjectChangedType string
+
+// ProjectChangedEvent is published whenever a task, column or comment of a project board changes.
+type ProjectChangedEvent struct {
+	Metadata Metadata                `json:"metadata"`
+	Type     ProjectChangedType      `json:"type"`
+	Data     ProjectChangedEventData `json:"data"`
+}
+
+type ProjectChangedEventData struct {
+	ID         string `json:"id"`
+	ProjectID  string `json:"projectId"`
+	Kind       string `json:"kind"`
+	Action     string `json:"action"`
+	EntityID   string `json:"entityId"`
+	TaskID     string `json:"taskId"`
+	OccurredAt string `json:"occurredAt"`
+}
@@ -23,6 +23,7 @@ const (
 	SubjectProjectUpdated      = "PROJECTS.updated"
 	SubjectProjectDeleted      = "PROJECTS.deleted"
 	SubjectProjectTeamAssigned = "PROJECTS.assigned"
+	SubjectProjectChanged      = "PROJECTS.changed"
 )
 
 // UnmarshalMsg parses the JSON-encoded data and returns Msg.